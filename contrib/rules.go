@@ -0,0 +1,5 @@
+// Package contrib links the contributed rules into the binary. Each rule is a
+// package under contrib/ that registers itself with sdk.Register when
+// imported; `gitops-validator sdk new-rule <name>` creates one and adds its
+// import here.
+package contrib
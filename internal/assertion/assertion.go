@@ -0,0 +1,414 @@
+// Package assertion evaluates the JSONPath-style expressions of custom
+// assertion rules against parsed resources.
+//
+// An expression is one or more comparisons joined by && and ||, where &&
+// binds tighter. A comparison is a path, optionally followed by an operator
+// and a literal:
+//
+//	$.spec.replicas >= 2
+//	$.metadata.labels['app.kubernetes.io/name']
+//	!$.spec.template.spec.hostNetwork
+//	$.spec.template.spec.containers[*].image =~ '^registry.example.com/'
+//
+// Paths start at $ and select fields with .name or ['name'], list items with
+// [n] and every list item with [*]. A comparison holds when it holds for every
+// value a [*] path selects. A path on its own holds when the value exists and
+// is not empty, false or null; a leading ! negates a comparison.
+//
+// Operators are ==, !=, <, <=, >, >= and =~ (regular expression match).
+// Values compare as numbers when both sides are numeric and as strings
+// otherwise. Literals are numbers, quoted strings, true, false and null; a
+// missing value equals only null.
+package assertion
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Expression is a compiled assertion
+type Expression struct {
+	source string
+	// anyOf holds the ||-separated terms, each a list of &&-ed comparisons
+	anyOf [][]comparison
+}
+
+// comparison is a path compared with a literal, or a path existence test
+type comparison struct {
+	negate   bool
+	path     []pathStep
+	operator string // "" for an existence test
+	literal  string
+	isNull   bool
+	pattern  *regexp.Regexp
+}
+
+// pathStep selects a field, a list index or (wildcard) every list item
+type pathStep struct {
+	field    string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+// value is one value a path selects; found is false when it does not exist
+type value struct {
+	v     interface{}
+	found bool
+}
+
+// operators are matched longest first
+var operators = []string{"==", "!=", "<=", ">=", "=~", "<", ">"}
+
+// Compile parses an assertion expression
+func Compile(source string) (*Expression, error) {
+	expression := &Expression{source: source}
+	for _, term := range splitOutsideQuotes(source, "||") {
+		var comparisons []comparison
+		for _, part := range splitOutsideQuotes(term, "&&") {
+			c, err := parseComparison(strings.TrimSpace(part))
+			if err != nil {
+				return nil, fmt.Errorf("invalid assertion %q: %w", source, err)
+			}
+			comparisons = append(comparisons, c)
+		}
+		expression.anyOf = append(expression.anyOf, comparisons)
+	}
+	return expression, nil
+}
+
+// String returns the expression source
+func (e *Expression) String() string {
+	return e.source
+}
+
+// Evaluate reports whether the assertion holds for a document
+func (e *Expression) Evaluate(document map[string]interface{}) bool {
+	for _, comparisons := range e.anyOf {
+		holds := true
+		for _, c := range comparisons {
+			if !c.evaluate(document) {
+				holds = false
+				break
+			}
+		}
+		if holds {
+			return true
+		}
+	}
+	return false
+}
+
+// parseComparison parses "[!]path [operator literal]"
+func parseComparison(text string) (comparison, error) {
+	var c comparison
+	if strings.HasPrefix(text, "!") {
+		c.negate = true
+		text = strings.TrimSpace(text[1:])
+	}
+	if text == "" {
+		return c, fmt.Errorf("empty comparison")
+	}
+
+	pathText, rest := text, ""
+	if i, op := findOperator(text); i >= 0 {
+		pathText, rest = strings.TrimSpace(text[:i]), strings.TrimSpace(text[i+len(op):])
+		c.operator = op
+	}
+
+	path, err := parsePath(pathText)
+	if err != nil {
+		return c, err
+	}
+	c.path = path
+
+	if c.operator == "" {
+		return c, nil
+	}
+	if rest == "" {
+		return c, fmt.Errorf("missing value after %s", c.operator)
+	}
+	literal, isNull, err := parseLiteral(rest)
+	if err != nil {
+		return c, err
+	}
+	c.literal, c.isNull = literal, isNull
+	if c.operator == "=~" {
+		if c.pattern, err = regexp.Compile(literal); err != nil {
+			return c, fmt.Errorf("invalid regular expression %q: %w", literal, err)
+		}
+	}
+	return c, nil
+}
+
+// findOperator returns the position of the first operator outside brackets
+// and quotes, or -1
+func findOperator(text string) (int, string) {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(text); i++ {
+		ch := text[i]
+		switch {
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			}
+			continue
+		case ch == '\'' || ch == '"':
+			quote = ch
+			continue
+		case ch == '[':
+			depth++
+			continue
+		case ch == ']':
+			depth--
+			continue
+		}
+		if depth > 0 {
+			continue
+		}
+		for _, op := range operators {
+			if strings.HasPrefix(text[i:], op) {
+				return i, op
+			}
+		}
+	}
+	return -1, ""
+}
+
+// parsePath parses $.a.b['c.d'][0][*]
+func parsePath(text string) ([]pathStep, error) {
+	if !strings.HasPrefix(text, "$") {
+		return nil, fmt.Errorf("path %q must start with $", text)
+	}
+
+	var steps []pathStep
+	rest := text[1:]
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			end := 1
+			for end < len(rest) && rest[end] != '.' && rest[end] != '[' {
+				end++
+			}
+			field := rest[1:end]
+			if field == "" || strings.ContainsAny(field, " \t'\"=!<>~&|()") {
+				return nil, fmt.Errorf("invalid field name %q in path %q (quote names with ['...'])", field, text)
+			}
+			steps = append(steps, pathStep{field: field})
+			rest = rest[end:]
+		case '[':
+			end := strings.Index(rest, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed [ in path %q", text)
+			}
+			inner := strings.TrimSpace(rest[1:end])
+			switch {
+			case inner == "*":
+				steps = append(steps, pathStep{wildcard: true})
+			case len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0]:
+				steps = append(steps, pathStep{field: inner[1 : len(inner)-1]})
+			default:
+				index, err := strconv.Atoi(inner)
+				if err != nil {
+					return nil, fmt.Errorf("invalid index [%s] in path %q", inner, text)
+				}
+				steps = append(steps, pathStep{index: index, isIndex: true})
+			}
+			rest = rest[end+1:]
+		default:
+			return nil, fmt.Errorf("unexpected %q in path %q", rest[0], text)
+		}
+	}
+	return steps, nil
+}
+
+// parseLiteral parses a quoted string, number, true, false or null
+func parseLiteral(text string) (string, bool, error) {
+	if len(text) >= 2 && (text[0] == '\'' || text[0] == '"') {
+		if text[len(text)-1] != text[0] {
+			return "", false, fmt.Errorf("unterminated string %s", text)
+		}
+		return text[1 : len(text)-1], false, nil
+	}
+	switch text {
+	case "null", "~":
+		return "", true, nil
+	case "true", "false":
+		return text, false, nil
+	}
+	if _, err := strconv.ParseFloat(text, 64); err != nil {
+		return "", false, fmt.Errorf("invalid value %s (quote strings)", text)
+	}
+	return text, false, nil
+}
+
+// evaluate reports whether the comparison holds for every selected value
+func (c comparison) evaluate(document map[string]interface{}) bool {
+	values := selectValues(document, c.path)
+	holds := true
+	for _, v := range values {
+		if !c.holds(v) {
+			holds = false
+			break
+		}
+	}
+	return holds != c.negate
+}
+
+// holds compares a single value
+func (c comparison) holds(v value) bool {
+	if c.operator == "" {
+		return isTruthy(v)
+	}
+
+	if !v.found || isNullScalar(v.v) {
+		switch c.operator {
+		case "==":
+			return c.isNull
+		case "!=":
+			return !c.isNull
+		default:
+			return false
+		}
+	}
+	if c.isNull {
+		return c.operator == "!="
+	}
+
+	actual, isScalar := v.v.(string)
+	if !isScalar {
+		return c.operator == "!="
+	}
+
+	if c.operator == "=~" {
+		return c.pattern.MatchString(actual)
+	}
+
+	a, aErr := strconv.ParseFloat(actual, 64)
+	b, bErr := strconv.ParseFloat(c.literal, 64)
+	if aErr == nil && bErr == nil {
+		switch c.operator {
+		case "==":
+			return a == b
+		case "!=":
+			return a != b
+		case "<":
+			return a < b
+		case "<=":
+			return a <= b
+		case ">":
+			return a > b
+		case ">=":
+			return a >= b
+		}
+	}
+
+	switch c.operator {
+	case "==":
+		return actual == c.literal
+	case "!=":
+		return actual != c.literal
+	}
+	return false
+}
+
+// selectValues returns the values a path selects. A [*] step yields one value
+// per list item; a missing intermediate value yields a single missing value.
+func selectValues(document map[string]interface{}, path []pathStep) []value {
+	current := []value{{v: document, found: true}}
+	for _, step := range path {
+		var next []value
+		for _, v := range current {
+			if !v.found {
+				next = append(next, v)
+				continue
+			}
+			switch {
+			case step.wildcard:
+				switch items := v.v.(type) {
+				case []interface{}:
+					for _, item := range items {
+						next = append(next, value{v: item, found: true})
+					}
+				case map[string]interface{}:
+					for _, item := range items {
+						next = append(next, value{v: item, found: true})
+					}
+				default:
+					next = append(next, value{})
+				}
+			case step.isIndex:
+				items, ok := v.v.([]interface{})
+				index := step.index
+				if ok && index < 0 {
+					index += len(items)
+				}
+				if !ok || index < 0 || index >= len(items) {
+					next = append(next, value{})
+				} else {
+					next = append(next, value{v: items[index], found: true})
+				}
+			default:
+				fields, ok := v.v.(map[string]interface{})
+				field, exists := fields[step.field]
+				next = append(next, value{v: field, found: ok && exists})
+			}
+		}
+		current = next
+	}
+	return current
+}
+
+// isTruthy reports whether a value exists and is not empty, false or null
+func isTruthy(v value) bool {
+	if !v.found || v.v == nil {
+		return false
+	}
+	switch typed := v.v.(type) {
+	case string:
+		return typed != "" && typed != "false" && !isNullScalar(typed)
+	case []interface{}:
+		return len(typed) > 0
+	case map[string]interface{}:
+		return len(typed) > 0
+	}
+	return true
+}
+
+// isNullScalar reports whether a parsed scalar is YAML null
+func isNullScalar(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	s, ok := v.(string)
+	return ok && (s == "null" || s == "~")
+}
+
+// splitOutsideQuotes splits text on sep, ignoring separators inside quotes
+func splitOutsideQuotes(text, sep string) []string {
+	var parts []string
+	var quote byte
+	start := 0
+	for i := 0; i < len(text); i++ {
+		ch := text[i]
+		if quote != 0 {
+			if ch == quote {
+				quote = 0
+			}
+			continue
+		}
+		if ch == '\'' || ch == '"' {
+			quote = ch
+			continue
+		}
+		if strings.HasPrefix(text[i:], sep) {
+			parts = append(parts, text[start:i])
+			start = i + len(sep)
+			i += len(sep) - 1
+		}
+	}
+	return append(parts, text[start:])
+}
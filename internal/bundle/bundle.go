@@ -0,0 +1,156 @@
+// Package bundle extracts tar archives, such as rendered bundles stored as CI
+// artifacts, so they can be validated like a checked-out repository.
+package bundle
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// IsArchive reports whether path is a tar archive rather than a directory
+func IsArchive(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		return false
+	}
+	lower := strings.ToLower(path)
+	for _, suffix := range []string{".tar", ".tar.gz", ".tgz"} {
+		if strings.HasSuffix(lower, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Extract extracts a tar stream, gzip-compressed or not, into a new temporary
+// directory the caller removes. Entries escaping the directory, through their
+// name, a symlink target or a symlink extracted earlier, are rejected; the
+// directory is removed then.
+func Extract(r io.Reader) (string, error) {
+	dir, err := os.MkdirTemp("", "gitops-validator-bundle-")
+	if err != nil {
+		return "", err
+	}
+	if err := extract(r, dir); err != nil {
+		os.RemoveAll(dir)
+		return "", err
+	}
+	return dir, nil
+}
+
+// extract extracts a tar stream into dir
+func extract(r io.Reader, dir string) error {
+	buffered := bufio.NewReader(r)
+	var stream io.Reader = buffered
+	if magic, err := buffered.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(buffered)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		stream = gz
+	}
+
+	var symlinks []string
+	archive := tar.NewReader(stream)
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		target := filepath.Join(dir, filepath.FromSlash(header.Name))
+		if !inside(dir, target) {
+			return fmt.Errorf("entry '%s' is outside the bundle", header.Name)
+		}
+		// The checks above are lexical: a directory extracted as a symlink
+		// would let later entries be written wherever it points
+		if throughSymlink(dir, target) {
+			return fmt.Errorf("entry '%s' is written through a symlink", header.Name)
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0o755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return err
+			}
+			file, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(file, archive)
+			file.Close()
+			if err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			// Symlinks are kept, so the symlink rule can check them
+			if filepath.IsAbs(header.Linkname) || !inside(dir, filepath.Join(filepath.Dir(target), header.Linkname)) {
+				return fmt.Errorf("symlink '%s' points outside the bundle", header.Name)
+			}
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return err
+			}
+			if err := os.Symlink(header.Linkname, target); err != nil {
+				return err
+			}
+			symlinks = append(symlinks, header.Name)
+		}
+	}
+
+	// Symlink targets may pass through other symlinks, which the lexical
+	// check does not follow, so each is resolved once all exist. Broken
+	// symlinks are kept for the symlink rule.
+	root, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return err
+	}
+	for _, name := range symlinks {
+		resolved, err := filepath.EvalSymlinks(filepath.Join(dir, filepath.FromSlash(name)))
+		if err == nil && !inside(root, resolved) {
+			return fmt.Errorf("symlink '%s' points outside the bundle", name)
+		}
+	}
+	return nil
+}
+
+// inside reports whether path is dir or below it, lexically
+func inside(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// throughSymlink reports whether target, or a directory between dir and
+// target, is a symlink
+func throughSymlink(dir, target string) bool {
+	rel, err := filepath.Rel(dir, target)
+	if err != nil || rel == "." {
+		return false
+	}
+	current := dir
+	for _, component := range strings.Split(rel, string(filepath.Separator)) {
+		current = filepath.Join(current, component)
+		info, err := os.Lstat(current)
+		if err != nil {
+			// Nothing below a missing path exists yet
+			return false
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			return true
+		}
+	}
+	return false
+}
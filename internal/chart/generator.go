@@ -9,13 +9,16 @@ import (
 
 // ChartGenerator generates dependency charts from resource graphs
 type ChartGenerator struct {
-	graph *parser.ResourceGraph
+	graph    *parser.ResourceGraph
+	repoPath string
 }
 
-// NewChartGenerator creates a new ChartGenerator
-func NewChartGenerator(graph *parser.ResourceGraph) *ChartGenerator {
+// NewChartGenerator creates a new ChartGenerator. repoPath is the repository
+// root that non-relative references (Flux spec.path) are resolved against.
+func NewChartGenerator(graph *parser.ResourceGraph, repoPath string) *ChartGenerator {
 	return &ChartGenerator{
-		graph: graph,
+		graph:    graph,
+		repoPath: repoPath,
 	}
 }
 
@@ -96,33 +99,43 @@ func (g *ChartGenerator) generateMermaidChartInternal(entryPoints []*parser.Pars
 	return strings.Join(lines, "\n")
 }
 
-// generateNodeAndEdges recursively generates nodes and edges for a resource and its dependencies
+// generateNodeAndEdges generates nodes and edges for a resource and everything
+// it references, breadth-first with an explicit queue so deep reference chains
+// cannot exhaust the stack
 func (g *ChartGenerator) generateNodeAndEdges(resource *parser.ParsedResource, lines *[]string, visited map[string]bool, nodeCounter *int, nodeMap map[string]string) {
-	resourceKey := resource.GetResourceKey()
-	if visited[resourceKey] {
+	if visited[resource.GetResourceKey()] {
 		return
 	}
-
-	visited[resourceKey] = true
-
-	// Create node for this resource
-	nodeID := g.getOrCreateNodeID(resource, nodeCounter, nodeMap)
-	icon := g.getResourceIcon(resource)
-	label := fmt.Sprintf("%s<br/>%s", resource.Name, icon)
-	*lines = append(*lines, fmt.Sprintf("    %s[\"%s\"]", nodeID, label))
-
-	// Generate edges to dependencies
-	for _, dep := range resource.Dependencies {
-		if dep.ReferenceType == string(parser.ReferenceTypePath) || dep.ReferenceType == string(parser.ReferenceTypeResource) {
-			// Find the target resource
-			targetResource := g.graph.FindTargetResource(dep, resource, "")
-			if targetResource != nil {
-				targetNodeID := g.getOrCreateNodeID(targetResource, nodeCounter, nodeMap)
-				edgeLabel := g.getEdgeLabel(dep)
-				*lines = append(*lines, fmt.Sprintf("    %s -->|%s| %s", nodeID, edgeLabel, targetNodeID))
-
-				// Recursively process the target resource
-				g.generateNodeAndEdges(targetResource, lines, visited, nodeCounter, nodeMap)
+	visited[resource.GetResourceKey()] = true
+
+	queue := []*parser.ParsedResource{resource}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		// Create node for this resource
+		nodeID := g.getOrCreateNodeID(current, nodeCounter, nodeMap)
+		icon := g.getResourceIcon(current)
+		label := fmt.Sprintf("%s<br/>%s", current.Name, icon)
+		*lines = append(*lines, fmt.Sprintf("    %s[\"%s\"]", nodeID, label))
+
+		// Generate edges to dependencies
+		for _, dep := range current.Dependencies {
+			if dep.ReferenceType == string(parser.ReferenceTypePath) || dep.ReferenceType == string(parser.ReferenceTypeResource) ||
+				dep.ReferenceType == string(parser.ReferenceTypeSourceRef) {
+				// Find the target resource
+				targetResource := g.graph.FindTargetResource(dep, current, g.repoPath)
+				if targetResource != nil {
+					targetNodeID := g.getOrCreateNodeID(targetResource, nodeCounter, nodeMap)
+					edgeLabel := g.getEdgeLabel(dep)
+					*lines = append(*lines, fmt.Sprintf("    %s -->|%s| %s", nodeID, edgeLabel, targetNodeID))
+
+					// Queue the target resource
+					if !visited[targetResource.GetResourceKey()] {
+						visited[targetResource.GetResourceKey()] = true
+						queue = append(queue, targetResource)
+					}
+				}
 			}
 		}
 	}
@@ -152,7 +165,7 @@ func (g *ChartGenerator) getResourceIcon(resource *parser.ParsedResource) string
 	case parser.ResourceTypeHelmRelease:
 		return "🚀 helm-release"
 	case parser.ResourceTypeFluxSource:
-		return "📦 flux-source"
+		return "📦 " + sourceKindLabels[resource.Kind]
 	case parser.ResourceTypeFluxImage:
 		return "🖼️ flux-image"
 	case parser.ResourceTypeFluxNotification:
@@ -162,12 +175,23 @@ func (g *ChartGenerator) getResourceIcon(resource *parser.ParsedResource) string
 	}
 }
 
+// sourceKindLabels are the chart labels of the Flux source kinds
+var sourceKindLabels = map[string]string{
+	"GitRepository":  "git-repository",
+	"OCIRepository":  "oci-repository",
+	"HelmRepository": "helm-repository",
+	"Bucket":         "bucket",
+}
+
 // getEdgeLabel returns a label for the edge based on the reference type
 func (g *ChartGenerator) getEdgeLabel(ref parser.ResourceReference) string {
 	switch ref.ReferenceType {
 	case string(parser.ReferenceTypePath):
 		return "path"
 	case string(parser.ReferenceTypeSourceRef):
+		if ref.Type == "helm-chart-ref" {
+			return "chartRef"
+		}
 		return "sourceRef"
 	case string(parser.ReferenceTypeChart):
 		return "chart"
@@ -202,40 +226,56 @@ func (g *ChartGenerator) GenerateTreeChart(entryPoints []*parser.ParsedResource,
 	return strings.Join(lines, "\n")
 }
 
-// generateTreeNode recursively generates tree nodes
+// treeNode is a resource waiting to be written to a tree chart
+type treeNode struct {
+	resource *parser.ParsedResource
+	prefix   string
+	isLast   bool
+}
+
+// generateTreeNode generates tree nodes depth-first with an explicit stack,
+// so deep reference chains cannot exhaust the stack
 func (g *ChartGenerator) generateTreeNode(resource *parser.ParsedResource, prefix string, lines *[]string, visited map[string]bool, isLast bool) {
-	resourceKey := resource.GetResourceKey()
-	if visited[resourceKey] {
-		return
-	}
+	stack := []treeNode{{resource: resource, prefix: prefix, isLast: isLast}}
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		resourceKey := node.resource.GetResourceKey()
+		if visited[resourceKey] {
+			continue
+		}
+		visited[resourceKey] = true
 
-	visited[resourceKey] = true
+		icon := g.getResourceIcon(node.resource)
+		nodePrefix := "└── "
+		if !node.isLast {
+			nodePrefix = "├── "
+		}
 
-	icon := g.getResourceIcon(resource)
-	nodePrefix := "└── "
-	if !isLast {
-		nodePrefix = "├── "
-	}
+		*lines = append(*lines, fmt.Sprintf("%s%s %s", node.prefix, nodePrefix, icon))
 
-	*lines = append(*lines, fmt.Sprintf("%s%s %s", prefix, nodePrefix, icon))
-
-	// Add dependencies
-	deps := resource.Dependencies
-	for i, dep := range deps {
-		if dep.ReferenceType == string(parser.ReferenceTypePath) || dep.ReferenceType == string(parser.ReferenceTypeResource) {
-			targetResource := g.graph.FindTargetResource(dep, resource, "")
-			if targetResource != nil {
-				childPrefix := prefix
-				if isLast {
-					childPrefix += "    "
-				} else {
-					childPrefix += "│   "
-				}
+		childPrefix := node.prefix
+		if node.isLast {
+			childPrefix += "    "
+		} else {
+			childPrefix += "│   "
+		}
 
-				isLastDep := i == len(deps)-1
-				g.generateTreeNode(targetResource, childPrefix, lines, visited, isLastDep)
+		// Add dependencies, pushed in reverse so the first is written first
+		deps := node.resource.Dependencies
+		var children []treeNode
+		for i, dep := range deps {
+			if dep.ReferenceType == string(parser.ReferenceTypePath) || dep.ReferenceType == string(parser.ReferenceTypeResource) {
+				targetResource := g.graph.FindTargetResource(dep, node.resource, g.repoPath)
+				if targetResource != nil {
+					children = append(children, treeNode{resource: targetResource, prefix: childPrefix, isLast: i == len(deps)-1})
+				}
 			}
 		}
+		for i := len(children) - 1; i >= 0; i-- {
+			stack = append(stack, children[i])
+		}
 	}
 }
 
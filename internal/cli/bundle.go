@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/moon-hex/gitops-validator/internal/bundle"
+)
+
+// cleanups run before the process exits; runValidation ends with os.Exit,
+// which skips deferred calls
+var cleanups []func()
+
+// exit runs the cleanups and exits with code
+func exit(code int) {
+	for _, cleanup := range cleanups {
+		cleanup()
+	}
+	os.Exit(code)
+}
+
+// resolveBundles replaces tar archives among paths by the directories they
+// are extracted to, and reads one from standard input with --stdin. The
+// directories are removed by exit.
+func resolveBundles(paths []string, stdin bool) ([]string, error) {
+	if stdin {
+		dir, err := extractBundle(os.Stdin)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read bundle from standard input: %w", err)
+		}
+		return []string{dir}, nil
+	}
+
+	resolved := make([]string, len(paths))
+	for i, path := range paths {
+		if !bundle.IsArchive(path) {
+			resolved[i] = path
+			continue
+		}
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		dir, err := extractBundle(file)
+		file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to extract %s: %w", path, err)
+		}
+		resolved[i] = dir
+	}
+	return resolved, nil
+}
+
+// extractBundle extracts a tar stream into a temporary directory removed by
+// exit
+func extractBundle(r io.Reader) (string, error) {
+	dir, err := bundle.Extract(r)
+	if err != nil {
+		return "", err
+	}
+	cleanups = append(cleanups, func() { os.RemoveAll(dir) })
+	return dir, nil
+}
@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/moon-hex/gitops-validator/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+var examplesCmd = &cobra.Command{
+	Use:   "examples",
+	Short: "Work with example repositories",
+}
+
+var examplesGenerateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Generate a synthetic GitOps repository",
+	Long: `Generate a synthetic GitOps repository for benchmarking, demos and
+reproducing scaling issues in bug reports. Every cluster is bootstrapped
+from clusters/<name>/flux-system and deploys all app bases through --depth
+levels of kustomize overlays. Intentional errors are placed at positions
+chosen by --seed, so the same flags always produce the same repository;
+its README.md lists them with the rule each triggers. The repository comes
+with a gitops-validator.yaml to validate it with.
+
+Sizes:
+  small   1 cluster,   5 apps, depth 1,  3 errors
+  medium  3 clusters,  50 apps, depth 2, 10 errors
+  large   10 clusters, 500 apps, depth 3, 25 errors
+
+Examples:
+  gitops-validator examples generate --size large --output /tmp/large-repo
+  gitops-validator examples generate --size medium --errors 0 --output bench
+  gitops-validator examples generate --clusters 2 --apps 20 --depth 4 --output demo`,
+	Run: func(cmd *cobra.Command, args []string) {
+		size, _ := cmd.Flags().GetString("size")
+		options, ok := generator.Sizes[size]
+		if !ok {
+			sizes := make([]string, 0, len(generator.Sizes))
+			for name := range generator.Sizes {
+				sizes = append(sizes, name)
+			}
+			sort.Strings(sizes)
+			fmt.Fprintf(os.Stderr, "Error: unknown size '%s' (use %s)\n", size, strings.Join(sizes, ", "))
+			os.Exit(1)
+		}
+
+		// Explicit flags override the size preset
+		flags := cmd.Flags()
+		if flags.Changed("clusters") {
+			options.Clusters, _ = flags.GetInt("clusters")
+		}
+		if flags.Changed("apps") {
+			options.Apps, _ = flags.GetInt("apps")
+		}
+		if flags.Changed("depth") {
+			options.Depth, _ = flags.GetInt("depth")
+		}
+		if flags.Changed("errors") {
+			options.Errors, _ = flags.GetInt("errors")
+		}
+		options.Seed, _ = flags.GetInt64("seed")
+		output, _ := flags.GetString("output")
+
+		summary, err := generator.Generate(output, options)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("Generated %d files in %s: %d clusters, %d apps, depth %d, %d intentional errors\n",
+			summary.Files, output, options.Clusters, options.Apps, options.Depth, len(summary.Errors))
+		for _, placed := range summary.Errors {
+			fmt.Printf("  %s %-28s %s\n", placed.Rule, placed.Kind, placed.File)
+		}
+		fmt.Printf("\nValidate it with: gitops-validator --config %s --path %s\n", filepath.Join(output, generator.ConfigFile), output)
+	},
+}
+
+func init() {
+	examplesGenerateCmd.Flags().String("size", "small", "preset: small, medium or large")
+	examplesGenerateCmd.Flags().Int("clusters", 0, "number of clusters (overrides --size)")
+	examplesGenerateCmd.Flags().Int("apps", 0, "number of apps deployed to every cluster (overrides --size)")
+	examplesGenerateCmd.Flags().Int("depth", 0, "kustomize overlay levels between a cluster and an app base (overrides --size)")
+	examplesGenerateCmd.Flags().Int("errors", 0, "number of intentional errors (overrides --size)")
+	examplesGenerateCmd.Flags().Int64("seed", 1, "seed for placing the intentional errors")
+	examplesGenerateCmd.Flags().String("output", "generated-repo", "directory to write the repository to (must not exist or be empty)")
+
+	examplesCmd.AddCommand(examplesGenerateCmd)
+	rootCmd.AddCommand(examplesCmd)
+}
@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/moon-hex/gitops-validator/internal/validator"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var graphCmd = &cobra.Command{
+	Use:   "graph",
+	Short: "Query the resource dependency graph",
+}
+
+var graphPathCmd = &cobra.Command{
+	Use:   "path <from> <to>",
+	Short: "Print the reference chain connecting two resources",
+	Long: `Print the shortest reference chain from one resource to another, or state
+that none exists. Useful for debugging why a manifest is or isn't deployed
+by a given Kustomization.
+
+Resources can be identified by "namespace/name", "Kind/name", metadata.name,
+or file path relative to the repository root.
+
+Examples:
+  gitops-validator graph path flux-system HelmRelease/backend --path .
+  gitops-validator graph path flux-system/flux-system apps/backend/helm-release.yaml`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := "."
+		if paths := viper.GetStringSlice("path"); len(paths) > 1 {
+			return fmt.Errorf("graph path supports a single --path")
+		} else if len(paths) == 1 {
+			path = paths[0]
+		}
+
+		v := validator.NewValidatorWithConfigPath(configFile, path, viper.GetBool("verbose"), viper.GetString("yaml-path"))
+		if fluxRoot := viper.GetString("flux-root"); fluxRoot != "" {
+			v.SetFluxRoot(fluxRoot)
+		}
+		return v.PrintReferencePath(args[0], args[1])
+	},
+}
+
+func init() {
+	graphCmd.AddCommand(graphPathCmd)
+	rootCmd.AddCommand(graphCmd)
+}
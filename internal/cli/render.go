@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/moon-hex/gitops-validator/internal/validator"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var renderCmd = &cobra.Command{
+	Use:   "render",
+	Short: "Show resources as kustomize and Flux render them",
+}
+
+var renderNamesCmd = &cobra.Command{
+	Use:   "names [flux-kustomization]",
+	Short: "Print the final names and namespaces of the resources each Flux Kustomization applies",
+	Long: `Print, per Flux Kustomization, the name and namespace of every resource it
+applies once kustomize namePrefix, nameSuffix and namespace and Flux
+spec.targetNamespace are applied, next to the file it comes from. Resources
+that are renamed or moved on the way show their name in the file, which helps
+debugging references and cluster objects that do not match the manifests.
+
+The Flux Kustomization can be identified by "namespace/name", "Kustomization/name",
+metadata.name, or file path relative to the repository root; without one, all
+are printed.
+
+Examples:
+  gitops-validator render names --path .
+  gitops-validator render names flux-system/apps --path .`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := "."
+		if paths := viper.GetStringSlice("path"); len(paths) > 1 {
+			return fmt.Errorf("render names supports a single --path")
+		} else if len(paths) == 1 {
+			path = paths[0]
+		}
+
+		identifier := ""
+		if len(args) == 1 {
+			identifier = args[0]
+		}
+
+		v := validator.NewValidatorWithConfigPath(configFile, path, viper.GetBool("verbose"), viper.GetString("yaml-path"))
+		if fluxRoot := viper.GetString("flux-root"); fluxRoot != "" {
+			v.SetFluxRoot(fluxRoot)
+		}
+		return v.PrintRenderedNames(identifier)
+	},
+}
+
+func init() {
+	renderCmd.AddCommand(renderNamesCmd)
+	rootCmd.AddCommand(renderCmd)
+}
@@ -3,7 +3,9 @@ package cli
 import (
 	"fmt"
 	"os"
+	"strings"
 
+	"github.com/moon-hex/gitops-validator/internal/config"
 	"github.com/moon-hex/gitops-validator/internal/validator"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -11,7 +13,7 @@ import (
 
 var (
 	configFile      string
-	repoPath        string
+	repoPaths       []string
 	verbose         bool
 	yamlPath        string
 	chartFormat     string
@@ -55,15 +57,34 @@ Examples:
   gitops-validator --path . --verbose                    # Default: fail on errors only
   gitops-validator --path . --no-fail-on-errors          # Don't fail on errors
   gitops-validator --path . --fail-on-warnings           # Also fail on warnings
+  gitops-validator --path . --severity deprecated-apis=warning  # Override a rule's severity for this run
   gitops-validator --path . --chart mermaid              # Generate dependency chart
   gitops-validator --path . --chart mermaid --chart-output deps.md  # Save chart to file
+  gitops-validator --path . --chart mermaid --chart-output deps.md --with-chart  # Validate and save chart in one run
   gitops-validator --path . --output-format markdown     # GitHub-friendly table output
   gitops-validator --path . --output-format json         # JSON for machine consumption
+  gitops-validator --path . --output-format ndjson       # Stream one JSON result per line
+  gitops-validator --path . --output-format rdf-min      # Compact JSON for LLM-based review bots
+  gitops-validator --path . --output console,json=report.json,sarif=report.sarif  # Console log plus report files
+  gitops-validator --path . --output console,badge=badge.json  # shields.io endpoint badge for the README
+  gitops-validator --path . --run-manifest run.json --run-manifest-key cosign.key  # Signed provenance of the run
+  gitops-validator --path . --flux-root deploy/gitops    # Flux paths relative to a subdirectory
+  gitops-validator --path repo-a --path repo-b           # Validate several repositories concurrently
+  gitops-validator --path bundle.tar.gz                  # Validate a tar archive, e.g. a rendered bundle
+  tar -czf - . | gitops-validator --stdin                # Validate a tar stream from standard input
+  gitops-validator --path . --k8s-version 1.29           # Judge deprecated APIs against the clusters' Kubernetes version
+  gitops-validator --path . --render                     # Also build kustomizations with kustomize and report build errors
+  gitops-validator --path . --online                     # Also check chart versions and remote kustomize resources
+  gitops-validator --path . --offline                    # Skip checks that need the network
+  gitops-validator --path . --no-color                   # Plain output without ANSI colors
+  gitops-validator --path . --no-pager                   # Don't page long output through $PAGER
   gitops-validator --path . --parallel                   # Run validators in parallel (Phase III)
   gitops-validator --path . --pipeline fast              # Use fast pipeline for CI/CD
   gitops-validator --path . --pipeline comprehensive     # Use comprehensive pipeline
   gitops-validator --path . --aggregation errors-only    # Show only errors with stats
   gitops-validator --path . --aggregation summary        # Show summary with top 50 issues
+  gitops-validator --path . --aggregation directories    # Roll up counts per top-level directory
+  gitops-validator --path . --aggregation entry-points   # Group results by the Flux Kustomization applying them
 
 Version: ` + version + `
 Commit: ` + commit + `
@@ -75,15 +96,18 @@ func init() {
 	cobra.OnInitialize(initConfig)
 
 	rootCmd.PersistentFlags().StringVar(&configFile, "config", "", "config file (default is data/gitops-validator.yaml)")
-	rootCmd.PersistentFlags().StringVarP(&repoPath, "path", "p", "", "path to GitOps repository (default: current directory)")
+	rootCmd.PersistentFlags().StringSliceVarP(&repoPaths, "path", "p", nil, "path to GitOps repository or tar archive (default: current directory); repeat or comma-separate to validate several repositories")
+	rootCmd.PersistentFlags().Bool("stdin", false, "read the repository as a tar stream, optionally gzip-compressed, from standard input")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "verbose output")
+	rootCmd.PersistentFlags().String("flux-root", "", "subdirectory of --path that Flux spec.path values are relative to (default: repository root)")
 	rootCmd.PersistentFlags().StringVar(&yamlPath, "yaml-path", "", "path to deprecated APIs YAML file (default is data/deprecated-apis.yaml)")
 	rootCmd.PersistentFlags().StringVar(&chartFormat, "chart", "", "generate dependency chart (mermaid, tree, json)")
 	rootCmd.PersistentFlags().StringVar(&chartOutput, "chart-output", "", "output file for dependency chart (default: stdout)")
-	rootCmd.PersistentFlags().StringVar(&chartEntryPoint, "chart-entrypoint", "", "generate chart for specific entry point only")
+	rootCmd.PersistentFlags().StringVar(&chartEntryPoint, "chart-entrypoint", "", "generate chart for specific entry point, or cluster directory, only")
+	rootCmd.PersistentFlags().Bool("with-chart", false, "validate as well as generate the --chart, parsing the repository once")
 	rootCmd.PersistentFlags().BoolVar(&parallel, "parallel", false, "run validators in parallel for better performance")
 	rootCmd.PersistentFlags().StringVar(&pipeline, "pipeline", "", "validation pipeline: default, fast, comprehensive")
-	rootCmd.PersistentFlags().StringVar(&aggregation, "aggregation", "", "result aggregation: errors-only, warnings-only, summary, grouped")
+	rootCmd.PersistentFlags().StringVar(&aggregation, "aggregation", "", "result aggregation: errors-only, warnings-only, summary, grouped, directories, entry-points")
 
 	// Exit code configuration flags
 	rootCmd.PersistentFlags().Bool("fail-on-errors", true, "exit with code 1 on errors (default: true)")
@@ -92,9 +116,21 @@ func init() {
 	rootCmd.PersistentFlags().Bool("no-fail-on-warnings", false, "don't exit with code 2 on warnings")
 	rootCmd.PersistentFlags().Bool("fail-on-info", false, "exit with code 3 on info messages (default: false)")
 	rootCmd.PersistentFlags().Bool("no-fail-on-info", false, "don't exit with code 3 on info messages")
+	rootCmd.PersistentFlags().StringSlice("severity", nil, "override a rule's severity for this run, e.g. flux-postbuild-variables=warning (repeatable)")
 
 	// Output formatting for CI (markdown/json)
-	rootCmd.PersistentFlags().String("output-format", "", "output format for results: markdown, json, or default")
+	rootCmd.PersistentFlags().String("output-format", "", "output format for results: markdown, json, json-report, ndjson, sarif, badge, rdf-min, or default")
+	rootCmd.PersistentFlags().String("output", "", "comma-separated outputs, each format[=file], e.g. console,json=report.json,sarif=report.sarif")
+	rootCmd.PersistentFlags().String("badge-metric", "health", "what the badge output reports: health (score and grade) or errors (error count)")
+	rootCmd.PersistentFlags().Bool("github-comment", false, "post the markdown results as a pull request comment, updated on every run (needs GITHUB_TOKEN)")
+	rootCmd.PersistentFlags().String("run-manifest", "", "write a JSON run manifest (tool version, config digest, repository commit, result counts, timings) to this file")
+	rootCmd.PersistentFlags().String("run-manifest-key", "", "sign the run manifest with this cosign key, writing <manifest>.sig (needs cosign)")
+	rootCmd.PersistentFlags().String("k8s-version", "", "Kubernetes version the clusters run, e.g. 1.29: deprecated APIs it serves are warnings, APIs it does not serve errors")
+	rootCmd.PersistentFlags().Bool("render", false, "build the kustomizations Flux applies with kustomize and report build errors")
+	rootCmd.PersistentFlags().Bool("online", false, "enable opt-in checks that query remote endpoints (chart versions in Helm repository indexes, remote kustomize resources)")
+	rootCmd.PersistentFlags().Bool("offline", false, "skip checks that need the network (Helm indexes, remote bases, schemas)")
+	rootCmd.PersistentFlags().Bool("no-pager", false, "don't pipe long console output through $PAGER")
+	rootCmd.PersistentFlags().Bool("no-color", false, "disable colored output (also disabled by NO_COLOR or when stdout is not a terminal)")
 
 	// Add version command
 	rootCmd.AddCommand(&cobra.Command{
@@ -108,18 +144,33 @@ func init() {
 	})
 
 	viper.BindPFlag("path", rootCmd.PersistentFlags().Lookup("path"))
+	viper.BindPFlag("stdin", rootCmd.PersistentFlags().Lookup("stdin"))
 	viper.BindPFlag("verbose", rootCmd.PersistentFlags().Lookup("verbose"))
 	viper.BindPFlag("yaml-path", rootCmd.PersistentFlags().Lookup("yaml-path"))
+	viper.BindPFlag("flux-root", rootCmd.PersistentFlags().Lookup("flux-root"))
 	viper.BindPFlag("chart", rootCmd.PersistentFlags().Lookup("chart"))
 	viper.BindPFlag("chart-output", rootCmd.PersistentFlags().Lookup("chart-output"))
 	viper.BindPFlag("chart-entrypoint", rootCmd.PersistentFlags().Lookup("chart-entrypoint"))
+	viper.BindPFlag("with-chart", rootCmd.PersistentFlags().Lookup("with-chart"))
 	viper.BindPFlag("fail-on-errors", rootCmd.PersistentFlags().Lookup("fail-on-errors"))
 	viper.BindPFlag("no-fail-on-errors", rootCmd.PersistentFlags().Lookup("no-fail-on-errors"))
 	viper.BindPFlag("fail-on-warnings", rootCmd.PersistentFlags().Lookup("fail-on-warnings"))
 	viper.BindPFlag("no-fail-on-warnings", rootCmd.PersistentFlags().Lookup("no-fail-on-warnings"))
 	viper.BindPFlag("fail-on-info", rootCmd.PersistentFlags().Lookup("fail-on-info"))
 	viper.BindPFlag("no-fail-on-info", rootCmd.PersistentFlags().Lookup("no-fail-on-info"))
+	viper.BindPFlag("severity", rootCmd.PersistentFlags().Lookup("severity"))
 	viper.BindPFlag("output-format", rootCmd.PersistentFlags().Lookup("output-format"))
+	viper.BindPFlag("output", rootCmd.PersistentFlags().Lookup("output"))
+	viper.BindPFlag("badge-metric", rootCmd.PersistentFlags().Lookup("badge-metric"))
+	viper.BindPFlag("github-comment", rootCmd.PersistentFlags().Lookup("github-comment"))
+	viper.BindPFlag("run-manifest", rootCmd.PersistentFlags().Lookup("run-manifest"))
+	viper.BindPFlag("run-manifest-key", rootCmd.PersistentFlags().Lookup("run-manifest-key"))
+	viper.BindPFlag("k8s-version", rootCmd.PersistentFlags().Lookup("k8s-version"))
+	viper.BindPFlag("render", rootCmd.PersistentFlags().Lookup("render"))
+	viper.BindPFlag("online", rootCmd.PersistentFlags().Lookup("online"))
+	viper.BindPFlag("offline", rootCmd.PersistentFlags().Lookup("offline"))
+	viper.BindPFlag("no-color", rootCmd.PersistentFlags().Lookup("no-color"))
+	viper.BindPFlag("no-pager", rootCmd.PersistentFlags().Lookup("no-pager"))
 	viper.BindPFlag("parallel", rootCmd.PersistentFlags().Lookup("parallel"))
 	viper.BindPFlag("pipeline", rootCmd.PersistentFlags().Lookup("pipeline"))
 	viper.BindPFlag("aggregation", rootCmd.PersistentFlags().Lookup("aggregation"))
@@ -152,8 +203,20 @@ func runValidation(cmd *cobra.Command, args []string) error {
 	chartEntryPoint := viper.GetString("chart-entrypoint")
 	outputFormat := viper.GetString("output-format")
 
+	// Repositories come from --path (repeatable), standard input or the
+	// config's paths list
+	paths := viper.GetStringSlice("path")
+	stdin := viper.GetBool("stdin")
+	if stdin && len(paths) > 0 {
+		fmt.Fprintf(os.Stderr, "Error: --stdin cannot be combined with --path\n")
+		exit(1)
+	}
+	if len(paths) == 0 && !stdin {
+		paths = config.DiscoverConfig(configFile).GitOpsValidator.Paths
+	}
+
 	// Check if path was explicitly set by user (not just default)
-	pathExplicitlySet := cmd.Flags().Changed("path")
+	pathExplicitlySet := cmd.Flags().Changed("path") || len(paths) > 0 || stdin
 
 	// If no validation or chart generation is requested, show help
 	if chartFormat == "" && !verbose && yamlPath == "" && chartOutput == "" && chartEntryPoint == "" && !pathExplicitlySet {
@@ -161,13 +224,16 @@ func runValidation(cmd *cobra.Command, args []string) error {
 	}
 
 	// Only proceed with validation if we have a valid request
-	path := viper.GetString("path")
-	if path == "" {
-		path = "."
+	if len(paths) == 0 && !stdin {
+		paths = []string{"."}
 	}
 
 	if verbose {
-		fmt.Printf("Validating GitOps repository at: %s\n", path)
+		if stdin {
+			fmt.Printf("Validating GitOps repository from standard input\n")
+		} else {
+			fmt.Printf("Validating GitOps repository at: %s\n", strings.Join(paths, ", "))
+		}
 		if yamlPath != "" {
 			fmt.Printf("Using deprecated APIs YAML: %s\n", yamlPath)
 		}
@@ -180,31 +246,137 @@ func runValidation(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Tar archives, such as rendered bundles stored as CI artifacts, are
+	// validated from a temporary directory
+	paths, err := resolveBundles(paths, stdin)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		exit(1)
+	}
+
 	// Get exit code configuration from flags
 	failOnErrors := viper.GetBool("fail-on-errors") && !viper.GetBool("no-fail-on-errors")
 	failOnWarnings := viper.GetBool("fail-on-warnings") && !viper.GetBool("no-fail-on-warnings")
 	failOnInfo := viper.GetBool("fail-on-info") && !viper.GetBool("no-fail-on-info")
 	parallel := viper.GetBool("parallel")
 
-	// Create validator with parallel execution support
-	v := validator.NewValidatorWithExitCodesAndConfig(configFile, path, verbose, yamlPath, failOnErrors, failOnWarnings, failOnInfo)
-	v.SetParallel(parallel)
+	// newValidator creates a validator for one repository with all flags applied
+	newValidator := func(path string) *validator.Validator {
+		// Create validator with parallel execution support
+		v := validator.NewValidatorWithExitCodesAndConfig(configFile, path, verbose, yamlPath, failOnErrors, failOnWarnings, failOnInfo)
+		v.SetParallel(parallel)
+		if fluxRoot := viper.GetString("flux-root"); fluxRoot != "" {
+			v.SetFluxRoot(fluxRoot)
+		}
+		if viper.GetBool("render") {
+			v.SetRender(true)
+		}
+		if k8sVersion := viper.GetString("k8s-version"); k8sVersion != "" {
+			if err := v.SetKubernetesVersion(k8sVersion); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: invalid --k8s-version: %v\n", err)
+				exit(1)
+			}
+		}
+		if viper.GetBool("online") {
+			v.SetOnline(true)
+		}
+		if viper.GetBool("offline") {
+			v.SetOffline(true)
+		}
+		v.SetNoColor(viper.GetBool("no-color"))
+		v.SetNoPager(viper.GetBool("no-pager"))
+
+		// Set pipeline if requested
+		pipelineName := viper.GetString("pipeline")
+		if pipelineName != "" {
+			if err := v.SetPipelineByName(pipelineName); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: Failed to set pipeline: %v\n", err)
+			}
+		}
 
-	// Set pipeline if requested
-	pipelineName := viper.GetString("pipeline")
-	if pipelineName != "" {
-		if err := v.SetPipelineByName(pipelineName); err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: Failed to set pipeline: %v\n", err)
+		// Set aggregation if requested
+		aggregationPreset := viper.GetString("aggregation")
+		if aggregationPreset != "" {
+			v.SetAggregationPreset(aggregationPreset)
+		}
+		if outputFormat != "" {
+			v.SetOutputFormat(outputFormat)
+		}
+		if outputs := viper.GetString("output"); outputs != "" {
+			if err := v.SetOutputs(outputs); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: invalid --output: %v\n", err)
+				exit(1)
+			}
+		}
+		if err := v.SetSeverityOverrides(viper.GetStringSlice("severity")); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid --severity: %v\n", err)
+			exit(1)
+		}
+		if err := v.SetBadgeMetric(viper.GetString("badge-metric")); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid --badge-metric: %v\n", err)
+			exit(1)
+		}
+		v.SetGitHubComment(viper.GetBool("github-comment"))
+		if manifest := viper.GetString("run-manifest"); manifest != "" {
+			v.SetRunManifest(manifest, viper.GetString("run-manifest-key"), version)
+		} else if viper.GetString("run-manifest-key") != "" {
+			fmt.Fprintf(os.Stderr, "Error: --run-manifest-key needs --run-manifest\n")
+			exit(1)
 		}
+		return v
 	}
 
-	// Set aggregation if requested
-	aggregationPreset := viper.GetString("aggregation")
-	if aggregationPreset != "" {
-		v.SetAggregationPreset(aggregationPreset)
+	// Several repositories are validated concurrently, one report section each
+	if len(paths) > 1 {
+		if chartFormat != "" {
+			fmt.Fprintf(os.Stderr, "Error: --chart supports a single --path\n")
+			exit(1)
+		}
+
+		labels := validator.OutputLabels(paths)
+		validators := make([]*validator.Validator, len(paths))
+		for i, path := range paths {
+			validators[i] = newValidator(path)
+			validators[i].SetOutputLabel(labels[i])
+			// One pager session per section would be confusing
+			validators[i].SetNoPager(true)
+		}
+
+		exitCode, err := validator.ValidateAll(validators)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exit(1)
+		}
+		exit(exitCode)
+		return nil // This line is unreachable but required by Go compiler
 	}
-	if outputFormat != "" {
-		v.SetOutputFormat(outputFormat)
+
+	v := newValidator(paths[0])
+
+	// With --with-chart, the chart is generated from the graph validation
+	// parsed, after the findings are reported
+	if viper.GetBool("with-chart") {
+		if chartFormat == "" {
+			fmt.Fprintf(os.Stderr, "Error: --with-chart needs --chart\n")
+			exit(1)
+		}
+		if err := v.Run(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exit(1)
+		}
+		exitCode := v.Report()
+		var err error
+		if chartEntryPoint != "" {
+			err = v.GenerateChartForEntryPoint(chartFormat, chartOutput, chartEntryPoint)
+		} else {
+			err = v.GenerateChart(chartFormat, chartOutput)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exit(1)
+		}
+		exit(exitCode)
+		return nil // This line is unreachable but required by Go compiler
 	}
 
 	// If chart generation is requested, handle it separately
@@ -217,9 +389,9 @@ func runValidation(cmd *cobra.Command, args []string) error {
 		}
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+			exit(1)
 		}
-		os.Exit(0)
+		exit(0)
 		return nil // This line is unreachable but required by Go compiler
 	}
 
@@ -228,11 +400,11 @@ func runValidation(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		// For parsing errors, show the error and exit
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+		exit(1)
 	}
 	// Always exit with the validation result code (0 for success, 1/2/3 for different failure types)
 	// This prevents Cobra from showing help text since we never return an error from RunE
-	os.Exit(exitCode)
+	exit(exitCode)
 	return nil // This line is unreachable but required by Go compiler
 }
 
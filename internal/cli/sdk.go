@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/moon-hex/gitops-validator/internal/scaffold"
+	"github.com/moon-hex/gitops-validator/internal/types"
+	"github.com/spf13/cobra"
+)
+
+var sdkCmd = &cobra.Command{
+	Use:   "sdk",
+	Short: "Develop contributed rules",
+}
+
+var sdkNewRuleCmd = &cobra.Command{
+	Use:   "new-rule <name>",
+	Short: "Scaffold a contributed rule",
+	Long: `Scaffold a contributed rule, written against the sdk package, in a new
+package under the contrib directory:
+
+  <name>.go              the rule and its registration, with a skeleton check
+  README.md              the documentation the rule's results link to
+  gitops-validator.yaml  a test case enabling the rule, validating repo/
+  repo/
+
+The package's import is added to the contrib rules.go, which main.go links
+in, and the rule gets the next free GVC ID. Contributed rules are opt-in:
+they run when their entry under gitops-validator.rules enables them.
+
+Run it from the repository root, or pass --dir.
+
+Examples:
+  gitops-validator sdk new-rule configmap-app-label --description "ConfigMaps carry an app.kubernetes.io/name label"
+  gitops-validator sdk new-rule ingress-tls --description "Ingresses terminate TLS" --severity error`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		flags := cmd.Flags()
+		options := scaffold.Options{Name: args[0]}
+		options.Dir, _ = flags.GetString("dir")
+		options.Description, _ = flags.GetString("description")
+		options.Fix, _ = flags.GetString("fix")
+		severity, _ := flags.GetString("severity")
+		options.Severity = types.Severity(severity)
+
+		if options.Description == "" {
+			fmt.Fprintf(os.Stderr, "Error: --description is required\n")
+			os.Exit(1)
+		}
+
+		rule, err := scaffold.NewRule(options)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("Scaffolded rule %s (%s):\n", options.Name, rule.ID)
+		for _, file := range rule.Files {
+			fmt.Printf("  %s\n", file)
+		}
+		fmt.Printf("\nNext steps:\n")
+		fmt.Printf("  1. Implement the check and document it in %s/README.md\n", rule.Dir)
+		fmt.Printf("  2. Rebuild: go build -o gitops-validator .\n")
+		fmt.Printf("  3. Record the test case's results: ./gitops-validator test %s --update\n", rule.Dir)
+	},
+}
+
+func init() {
+	sdkNewRuleCmd.Flags().String("dir", "contrib", "contrib directory holding rules.go")
+	sdkNewRuleCmd.Flags().String("description", "", "one-line summary of what the rule detects (required)")
+	sdkNewRuleCmd.Flags().String("fix", "", "one-line remediation hint for compact outputs")
+	sdkNewRuleCmd.Flags().String("severity", string(types.SeverityWarning), "severity of the rule's results: error, warning or info")
+
+	sdkCmd.AddCommand(sdkNewRuleCmd)
+	rootCmd.AddCommand(sdkCmd)
+}
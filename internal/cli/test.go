@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/moon-hex/gitops-validator/internal/validator"
+	"github.com/spf13/cobra"
+)
+
+var testCmd = &cobra.Command{
+	Use:   "test [fixture-dir...]",
+	Short: "Compare validation results of fixture repositories with committed snapshots",
+	Long: `Validate fixture repositories and compare the results with the
+expected-results.json committed next to each, as regression tests for rules
+and policy configuration. Exits with code 1 when any fixture differs.
+
+A directory holding expected-results.json, a repo/ subdirectory, a
+repo.tar[.gz] archive or a gitops-validator.yaml is a fixture; any other
+directory is a collection whose subdirectories are fixtures. A fixture
+validates <dir>/repo or the extracted archive if it exists, else <dir>
+itself, with <dir>/gitops-validator.yaml or, without one, the --config file
+(default: the discovered config). A snapshot may record the error a run
+must fail with instead of results.
+
+Without arguments, the fixtures in examples/test-cases are run.
+
+Examples:
+  gitops-validator test
+  gitops-validator test policy-fixtures --config .gitops-validator.yaml
+  gitops-validator test examples/test-cases --update   # accept the current results`,
+	Run: func(cmd *cobra.Command, args []string) {
+		dirs := args
+		if len(dirs) == 0 {
+			dirs = []string{"examples/test-cases"}
+		}
+
+		fixtures, err := validator.FindSnapshotFixtures(dirs)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		update, _ := cmd.Flags().GetBool("update")
+		if failed := validator.RunSnapshotTests(os.Stdout, fixtures, configFile, update); failed > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	testCmd.Flags().Bool("update", false, "write the current results to expected-results.json instead of comparing")
+	rootCmd.AddCommand(testCmd)
+}
@@ -4,8 +4,14 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/moon-hex/gitops-validator/internal/assertion"
+	"github.com/moon-hex/gitops-validator/internal/pathutil"
+	"github.com/moon-hex/gitops-validator/internal/types"
 	"gopkg.in/yaml.v3"
 )
 
@@ -20,6 +26,19 @@ type GitOpsValidatorConfig struct {
 	Path    string `yaml:"path"`
 	Verbose bool   `yaml:"verbose"`
 
+	// Repositories validated together when --path is not given (one report section each)
+	Paths []string `yaml:"paths"`
+
+	// Subdirectory of the checkout that Flux paths are relative to (default: repository root)
+	FluxRoot string `yaml:"flux-root"`
+
+	// Build the kustomizations Flux applies with kustomize and report build errors (also --render)
+	Render bool `yaml:"render"`
+
+	// Kubernetes version the clusters run, e.g. "1.29" (also --k8s-version); deprecated
+	// APIs it still serves are warnings, and APIs it does not serve are errors
+	KubernetesVersion string `yaml:"kubernetes-version"`
+
 	// Entry points configuration
 	EntryPoints EntryPointsConfig `yaml:"entry-points"`
 
@@ -37,6 +56,95 @@ type GitOpsValidatorConfig struct {
 
 	// Exit code configuration
 	ExitCodes ExitCodeConfig `yaml:"exit-codes"`
+
+	// Local checkouts for Flux sources that point at other repositories
+	Sources []SourceMappingConfig `yaml:"sources"`
+
+	// Health score weights
+	HealthScore HealthScoreConfig `yaml:"health-score"`
+
+	// Known exceptions; matching findings are suppressed until the suppression expires
+	Suppressions []SuppressionConfig `yaml:"suppressions"`
+
+	// Tenants and the Flux Kustomizations at the root of their trees
+	Tenants []TenantConfig `yaml:"tenants"`
+
+	// Custom assertions evaluated against every resource of a kind
+	Assertions []AssertionConfig `yaml:"assertions"`
+
+	// Network access of checks that query remote endpoints
+	Network NetworkConfig `yaml:"network"`
+
+	// Annotation and escalation of findings in files shared by several entry points
+	SeverityPropagation SeverityPropagationConfig `yaml:"severity-propagation"`
+
+	// Minimum severities of findings by path, e.g. errors for production clusters
+	SeverityFloors []SeverityFloorConfig `yaml:"severity-floors"`
+}
+
+// SeverityFloorConfig raises the findings of some rules at a path to a
+// minimum severity. A finding is at the path when its file matches, or when
+// it is deployed by a Flux Kustomization defined in a matching file, so
+// "clusters/production/**" also covers the shared bases production applies.
+type SeverityFloorConfig struct {
+	// Path is a glob relative to the repository root ("clusters/production/**")
+	Path string `yaml:"path"`
+	// Rules are rule IDs, config rule names or result types; empty means every rule
+	Rules []string `yaml:"rules"`
+	// Severity is the minimum severity: info, warning or error
+	Severity types.Severity `yaml:"severity"`
+	// Reason documents why the floor exists
+	Reason string `yaml:"reason"`
+}
+
+// MatchesRule reports whether the floor applies to a finding with any of the
+// given identifiers (rule ID, config rule name or result type)
+func (f SeverityFloorConfig) MatchesRule(identifiers ...string) bool {
+	if len(f.Rules) == 0 {
+		return true
+	}
+	for _, rule := range f.Rules {
+		for _, id := range identifiers {
+			if id != "" && strings.EqualFold(rule, id) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// SeverityPropagationConfig annotates findings in files that several Flux
+// Kustomizations apply, such as shared bases, with the affected entry points
+type SeverityPropagationConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// EscalateAbove raises the severity of a finding one level (info to warning,
+	// warning to error) when more entry points than this are affected; 0 never escalates
+	EscalateAbove int `yaml:"escalate-above"`
+}
+
+// NetworkConfig controls checks that reach the network. Failed requests are
+// retried with exponential backoff; durations are strings such as "1s".
+type NetworkConfig struct {
+	// Online enables checks that are opt-in because they query remote
+	// endpoints on every run, such as Helm repository indexes and remote
+	// kustomize resources (also --online)
+	Online bool `yaml:"online"`
+	// Offline disables every network-dependent check (also --offline) and wins over Online
+	Offline bool `yaml:"offline"`
+	// Attempts is how often a request is tried in total; 1 disables retries (default 4)
+	Attempts int `yaml:"attempts"`
+	// Backoff is the delay before the first retry, doubled for each further one (default 1s)
+	Backoff string `yaml:"backoff"`
+	// MaxBackoff caps the delay between retries (default 30s)
+	MaxBackoff string `yaml:"max-backoff"`
+	// Timeout limits a single request (default 30s)
+	Timeout string `yaml:"timeout"`
+}
+
+// HealthScoreConfig defines how findings are weighted in the health score
+type HealthScoreConfig struct {
+	SeverityWeights map[types.Severity]float64 `yaml:"severity-weights"` // error, warning, info
+	RuleWeights     map[string]float64         `yaml:"rule-weights"`     // multiplier per rule name (default 1)
 }
 
 // EntryPointsConfig defines how to identify entry point resources
@@ -45,25 +153,80 @@ type EntryPointsConfig struct {
 	Namespaces []string `yaml:"namespaces"` // Namespaces to consider
 	Types      []string `yaml:"types"`      // Resource types
 	Patterns   []string `yaml:"patterns"`   // Glob patterns
+	// AutoDetect is the strategy used when none of the above matches anything:
+	// none (default), flux-only or directories
+	AutoDetect string `yaml:"auto-detect"`
 }
 
+// Entry point auto-detection strategies
+const (
+	AutoDetectNone        = "none"
+	AutoDetectFluxOnly    = "flux-only"
+	AutoDetectDirectories = "directories"
+)
+
 // RulesConfig defines which validation rules to run
 type RulesConfig struct {
-	FluxKustomization               RuleConfig                  `yaml:"flux-kustomization"`
-	FluxPostBuildVariables          RuleConfig                  `yaml:"flux-postbuild-variables"`
-	KubernetesKustomization         RuleConfig                  `yaml:"kubernetes-kustomization"`
-	KustomizationVersionConsistency RuleConfig                  `yaml:"kustomization-version-consistency"`
-	OrphanedResources               OrphanedResourcesRuleConfig `yaml:"orphaned-resources"`
-	DeprecatedAPIs                  RuleConfig                  `yaml:"deprecated-apis"`
-	DoubleReferences                RuleConfig                  `yaml:"double-references"`
-	CircularDependencies            RuleConfig                  `yaml:"circular-dependencies"`
-	HTTPRoutePolicy                 RuleConfig                  `yaml:"http-route-policy"`
+	FluxKustomization               RuleConfig                    `yaml:"flux-kustomization"`
+	FluxPostBuildVariables          RuleConfig                    `yaml:"flux-postbuild-variables"`
+	KubernetesKustomization         RuleConfig                    `yaml:"kubernetes-kustomization"`
+	KustomizationVersionConsistency RuleConfig                    `yaml:"kustomization-version-consistency"`
+	OrphanedResources               OrphanedResourcesRuleConfig   `yaml:"orphaned-resources"`
+	DeprecatedAPIs                  RuleConfig                    `yaml:"deprecated-apis"`
+	DoubleReferences                RuleConfig                    `yaml:"double-references"`
+	CircularDependencies            RuleConfig                    `yaml:"circular-dependencies"`
+	HTTPRoutePolicy                 RuleConfig                    `yaml:"http-route-policy"`
+	Symlinks                        RuleConfig                    `yaml:"symlinks"`
+	HelmReleaseCollisions           RuleConfig                    `yaml:"helm-release-collisions"`
+	NamespaceCollisions             RuleConfig                    `yaml:"namespace-collisions"`
+	TargetNamespaces                TargetNamespacesRuleConfig    `yaml:"target-namespaces"`
+	FluxIntervals                   FluxIntervalsRuleConfig       `yaml:"flux-intervals"`
+	FluxPruneWait                   FluxPruneWaitRuleConfig       `yaml:"flux-prune-wait"`
+	KustomizeNamespaces             KustomizeNamespacesRuleConfig `yaml:"kustomize-namespaces"`
+	HelmReleaseValues               RuleConfig                    `yaml:"helm-release-values"`
+	SOPS                            RuleConfig                    `yaml:"sops"`
+	HelmChartVersions               RuleConfig                    `yaml:"helm-chart-versions"`
+	EnvFieldRefs                    RuleConfig                    `yaml:"env-field-refs"`
+	MultipleInclusions              RuleConfig                    `yaml:"multiple-inclusions"`
+	ImageAutomationRefs             RuleConfig                    `yaml:"image-automation-refs"`
+	ImagePolicyMarkers              RuleConfig                    `yaml:"image-policy-markers"`
+	NotificationRefs                RuleConfig                    `yaml:"notification-refs"`
+	CrossNamespaceRefs              RuleConfig                    `yaml:"cross-namespace-refs"`
+	ReferenceGraph                  RuleConfig                    `yaml:"reference-graph"`
+	SOPSDecryption                  RuleConfig                    `yaml:"sops-decryption"`
+	FluxAPIVersions                 RuleConfig                    `yaml:"flux-api-versions"`
+	FluxServiceAccounts             RuleConfig                    `yaml:"flux-service-accounts"`
+	ImageAutomationWriteBack        RuleConfig                    `yaml:"image-automation-write-back"`
+	RenderedNameCollisions          RuleConfig                    `yaml:"rendered-name-collisions"`
+	KustomizeImages                 RuleConfig                    `yaml:"kustomize-images"`
+	KustomizeReplacements           RuleConfig                    `yaml:"kustomize-replacements"`
+	KustomizeRemoteResources        RuleConfig                    `yaml:"kustomize-remote-resources"`
+	KustomizeBuild                  RuleConfig                    `yaml:"kustomize-build"`
+	MissingNamespaces               MissingNamespacesRuleConfig   `yaml:"missing-namespaces"`
+	SecretRefs                      SecretRefsRuleConfig          `yaml:"secret-refs"`
+	ConfigMapRefs                   ConfigMapRefsRuleConfig       `yaml:"configmap-refs"`
+	WorkloadServiceAccounts         RuleConfig                    `yaml:"workload-service-accounts"`
+	StorageClasses                  RuleConfig                    `yaml:"storage-classes"`
+	RouteBackends                   RuleConfig                    `yaml:"route-backends"`
+	ServiceSelectors                RuleConfig                    `yaml:"service-selectors"`
+	NetworkPolicies                 NetworkPoliciesRuleConfig     `yaml:"network-policies"`
+	CustomResources                 CustomResourcesRuleConfig     `yaml:"custom-resources"`
+	Kubeconform                     KubeconformRuleConfig         `yaml:"kubeconform"`
+	ContainerResources              ContainerResourcesRuleConfig  `yaml:"container-resources"`
+	ImageTags                       ImageTagsRuleConfig           `yaml:"image-tags"`
+	LabelPolicies                   LabelPoliciesRuleConfig       `yaml:"label-policies"`
+	// Contrib holds the other keys, the rules registered through the sdk
+	// package, by rule name
+	Contrib map[string]RuleConfig `yaml:",inline"`
 }
 
 // RuleConfig defines a single validation rule
 type RuleConfig struct {
-	Enabled  bool   `yaml:"enabled"`
-	Severity string `yaml:"severity"`
+	Enabled  bool           `yaml:"enabled"`
+	Severity types.Severity `yaml:"severity"`
+	// Params holds the other keys of the rule, checked against the
+	// parameters the rule registered with RegisterRuleParams
+	Params RuleParams `yaml:",inline"`
 }
 
 // OrphanedResourceCategoryConfig defines a named category for orphaned resource grouping
@@ -79,9 +242,277 @@ type OrphanedResourceCategoryConfig struct {
 
 // OrphanedResourcesRuleConfig extends RuleConfig with optional path-based categories
 type OrphanedResourcesRuleConfig struct {
-	Enabled    bool                              `yaml:"enabled"`
-	Severity   string                            `yaml:"severity"`
-	Categories []OrphanedResourceCategoryConfig  `yaml:"categories"`
+	Enabled    bool                             `yaml:"enabled"`
+	Severity   types.Severity                   `yaml:"severity"`
+	Categories []OrphanedResourceCategoryConfig `yaml:"categories"`
+}
+
+// TargetNamespacesRuleConfig extends RuleConfig with namespaces that exist
+// without a Namespace manifest in the repository
+type TargetNamespacesRuleConfig struct {
+	Enabled  bool           `yaml:"enabled"`
+	Severity types.Severity `yaml:"severity"`
+	// Allowed lists namespace names or glob patterns ("tenant-*") created outside the repository
+	Allowed []string `yaml:"allowed"`
+}
+
+// MissingNamespacesRuleConfig extends RuleConfig with namespaces that exist
+// without a Namespace manifest in the repository
+type MissingNamespacesRuleConfig struct {
+	Enabled  bool           `yaml:"enabled"`
+	Severity types.Severity `yaml:"severity"`
+	// Allowed lists namespace names or glob patterns ("tenant-*") created outside the repository
+	Allowed []string `yaml:"allowed"`
+}
+
+// SecretRefsRuleConfig extends RuleConfig with Secrets that exist without
+// being created by the repository
+type SecretRefsRuleConfig struct {
+	Enabled  bool           `yaml:"enabled"`
+	Severity types.Severity `yaml:"severity"`
+	// External lists Secret names, or namespace/name, created outside the repository; glob patterns are allowed
+	External []string `yaml:"external"`
+}
+
+// ConfigMapRefsRuleConfig extends RuleConfig with ConfigMaps that exist
+// without being created by the repository
+type ConfigMapRefsRuleConfig struct {
+	Enabled  bool           `yaml:"enabled"`
+	Severity types.Severity `yaml:"severity"`
+	// External lists ConfigMap names, or namespace/name, created outside the repository; glob patterns are allowed
+	External []string `yaml:"external"`
+}
+
+// NetworkPoliciesRuleConfig extends RuleConfig with the report of namespaces
+// whose workloads no NetworkPolicy covers
+type NetworkPoliciesRuleConfig struct {
+	Enabled  bool           `yaml:"enabled"`
+	Severity types.Severity `yaml:"severity"`
+	// RequirePolicies also reports namespaces with workloads but no NetworkPolicy
+	RequirePolicies bool `yaml:"require-policies"`
+	// ExcludeNamespaces lists namespace names or glob patterns that need no NetworkPolicy
+	ExcludeNamespaces []string `yaml:"exclude-namespaces"`
+}
+
+// CustomResourcesRuleConfig extends RuleConfig with the CRDs installed
+// without a manifest in the repository
+type CustomResourcesRuleConfig struct {
+	Enabled  bool           `yaml:"enabled"`
+	Severity types.Severity `yaml:"severity"`
+	// KnownCRDs lists API groups ("cert-manager.io"), kinds ("Certificate.cert-manager.io")
+	// or CRD names ("certificates.cert-manager.io") installed outside the repository; glob patterns are allowed
+	KnownCRDs []string `yaml:"known-crds"`
+}
+
+// KubeconformRuleConfig configures schema validation with kubeconform, which
+// is opt-in as it runs an external binary and downloads schemas
+type KubeconformRuleConfig struct {
+	Enabled  bool           `yaml:"enabled"`
+	Severity types.Severity `yaml:"severity"`
+	// Binary is the kubeconform executable, looked up in PATH (default "kubeconform"); paths are relative to the repository
+	Binary string `yaml:"binary"`
+	// KubernetesVersion selects the schemas of a Kubernetes release, e.g. "1.29.0" (default:
+	// the top-level kubernetes-version, else the latest)
+	KubernetesVersion string `yaml:"kubernetes-version"`
+	// SchemaLocations are kubeconform -schema-location values; empty means the Kubernetes schemas and the CRDs catalog
+	SchemaLocations []string `yaml:"schema-locations"`
+	// Cache is the directory downloaded schemas are kept in, relative to the repository; offline runs read schemas from it
+	Cache string `yaml:"cache"`
+	// Strict rejects fields the schemas do not define
+	Strict bool `yaml:"strict"`
+	// ReportMissingSchemas reports resources no schema location has a schema for
+	ReportMissingSchemas bool `yaml:"report-missing-schemas"`
+	// SkipKinds are kinds, or group/version/Kind, left out of the validation
+	SkipKinds []string `yaml:"skip-kinds"`
+}
+
+// ContainerResourcesRuleConfig configures the opt-in policy requiring
+// containers to set resource requests and limits
+type ContainerResourcesRuleConfig struct {
+	Enabled  bool           `yaml:"enabled"`
+	Severity types.Severity `yaml:"severity"`
+	// Require lists requests.cpu, requests.memory, limits.cpu and limits.memory, or
+	// requests and limits for both (default: requests.cpu, requests.memory, limits.memory)
+	Require []string `yaml:"require"`
+	// ExemptNamespaces lists namespace names or glob patterns the policy does not apply to
+	ExemptNamespaces []string `yaml:"exempt-namespaces"`
+	// Exceptions exempt workloads by file; checks are "requests" and "limits"
+	Exceptions []PathExceptionConfig `yaml:"exceptions"`
+}
+
+// ImageTagsRuleConfig extends RuleConfig with the policy container images
+// must follow
+type ImageTagsRuleConfig struct {
+	Enabled  bool           `yaml:"enabled"`
+	Severity types.Severity `yaml:"severity"`
+	// DisallowLatest flags images tagged latest or without a tag
+	DisallowLatest bool `yaml:"disallow-latest"`
+	// RequireDigest flags images not pinned by digest
+	RequireDigest bool `yaml:"require-digest"`
+	// AllowedRegistries lists registries ("ghcr.io", "*.dkr.ecr.*.amazonaws.com") or
+	// repository prefixes ("ghcr.io/example") images may come from; empty allows all
+	AllowedRegistries []string `yaml:"allowed-registries"`
+}
+
+// LabelPoliciesRuleConfig extends RuleConfig with the labels and annotations
+// resources must carry
+type LabelPoliciesRuleConfig struct {
+	Enabled  bool           `yaml:"enabled"`
+	Severity types.Severity `yaml:"severity"`
+	// Policies each require labels and annotations on the resources they select
+	Policies []LabelPolicyConfig `yaml:"policies"`
+}
+
+// LabelPolicyConfig requires labels and annotations, optionally with values
+// matching a regular expression, on resources of some kinds or files
+type LabelPolicyConfig struct {
+	// Name identifies the policy in findings (default: its position, "policies[0]")
+	Name string `yaml:"name"`
+	// Kinds selects resources by kind; empty selects all kinds
+	Kinds []string `yaml:"kinds"`
+	// Paths selects resources by file, as globs relative to the repository root; empty selects all files
+	Paths []string `yaml:"paths"`
+	// Labels and Annotations map the required keys to a regular expression the
+	// whole value must match, or to "" when any value will do
+	Labels      map[string]string `yaml:"labels"`
+	Annotations map[string]string `yaml:"annotations"`
+	// Message explains the policy in findings
+	Message string `yaml:"message"`
+}
+
+// FluxIntervalsRuleConfig extends RuleConfig with the shortest interval Flux
+// sources may be polled at
+type FluxIntervalsRuleConfig struct {
+	Enabled  bool           `yaml:"enabled"`
+	Severity types.Severity `yaml:"severity"`
+	// MinimumInterval is a duration such as "1m" (the default) or "5m"
+	MinimumInterval string `yaml:"minimum-interval"`
+}
+
+// FluxPruneWaitRuleConfig extends RuleConfig with the largest tree wait: true
+// is accepted on and Kustomizations exempt from the checks
+type FluxPruneWaitRuleConfig struct {
+	Enabled  bool           `yaml:"enabled"`
+	Severity types.Severity `yaml:"severity"`
+	// MaxWaitResources is the largest number of resources a Kustomization with wait: true may apply (default 50)
+	MaxWaitResources int `yaml:"max-wait-resources"`
+	// Exceptions exempt Kustomizations by file or spec.path
+	Exceptions []PathExceptionConfig `yaml:"exceptions"`
+}
+
+// KustomizeNamespacesRuleConfig extends RuleConfig with cluster-scoped
+// custom resource kinds whose CRDs are not in the repository
+type KustomizeNamespacesRuleConfig struct {
+	Enabled  bool           `yaml:"enabled"`
+	Severity types.Severity `yaml:"severity"`
+	// ClusterScopedKinds lists additional cluster-scoped kinds, e.g. "ClusterTriggerBinding"
+	ClusterScopedKinds []string `yaml:"cluster-scoped-kinds"`
+}
+
+// PathExceptionConfig exempts the resources at a path from some checks of a rule
+type PathExceptionConfig struct {
+	// Path is a glob relative to the repository root ("infrastructure/crds/**")
+	Path string `yaml:"path"`
+	// Checks names the checks skipped; empty skips all of them
+	Checks []string `yaml:"checks"`
+	// Reason documents why the exception exists
+	Reason string `yaml:"reason"`
+}
+
+// SourceMappingConfig maps an external Flux source to a local checkout so that
+// spec.path of Kustomizations using that source can still be validated
+type SourceMappingConfig struct {
+	// Name is the GitRepository/OCIRepository name, optionally as "namespace/name"
+	Name string `yaml:"name"`
+	// Path is the local checkout directory, absolute or relative to the validated repository
+	Path string `yaml:"path"`
+}
+
+// SuppressionConfig declares a known exception for a rule. Findings it matches
+// are suppressed until Expires; after that they are reported as errors.
+type SuppressionConfig struct {
+	// Rule is the rule ID (GV0009), config rule name or result type to suppress
+	Rule string `yaml:"rule"`
+	// File is a glob relative to the repository root ("apps/**" matches everything under apps/)
+	File string `yaml:"file"`
+	// Resource is the name of the resource the finding is reported against
+	Resource string `yaml:"resource"`
+	// Reason documents why the exception exists
+	Reason string `yaml:"reason"`
+	// Expires is the last day (YYYY-MM-DD) the suppression applies; empty never expires
+	Expires string `yaml:"expires"`
+}
+
+// suppressionDateLayout is the date format of SuppressionConfig.Expires
+const suppressionDateLayout = "2006-01-02"
+
+// MatchesRule reports whether the suppression's rule is any of the given
+// identifiers (rule ID, config rule name or result type)
+func (s SuppressionConfig) MatchesRule(identifiers ...string) bool {
+	for _, id := range identifiers {
+		if id != "" && strings.EqualFold(s.Rule, id) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchesLocation reports whether the suppression's file glob and resource
+// filters match a finding in relPath reported against any of resourceNames
+func (s SuppressionConfig) MatchesLocation(relPath string, resourceNames ...string) bool {
+	if s.File != "" && !pathutil.MatchPattern(relPath, s.File) {
+		return false
+	}
+	if s.Resource == "" {
+		return true
+	}
+	for _, name := range resourceNames {
+		if name != "" && s.Resource == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Expired reports whether the suppression's expiry date has passed. The
+// suppression still applies on the expiry date itself; a date that cannot be
+// parsed counts as expired so a typo never suppresses a finding forever.
+func (s SuppressionConfig) Expired(now time.Time) bool {
+	if s.Expires == "" {
+		return false
+	}
+	expires, err := time.ParseInLocation(suppressionDateLayout, s.Expires, now.Location())
+	if err != nil {
+		return true
+	}
+	return !now.Before(expires.AddDate(0, 0, 1))
+}
+
+// TenantConfig names a tenant and the Flux Kustomizations it owns. Everything
+// those Kustomizations deploy, including nested Kustomizations, belongs to it.
+type TenantConfig struct {
+	// Name is the tenant shown in findings
+	Name string `yaml:"name"`
+	// Roots are globs relative to the repository root matching the files that
+	// define the tenant's Flux Kustomizations ("clusters/*/team-a.yaml")
+	Roots []string `yaml:"roots"`
+}
+
+// AssertionConfig is an organization policy checked against every resource of
+// a kind; resources for which Assert does not hold are reported
+type AssertionConfig struct {
+	// Name identifies the assertion in findings (default: the expression)
+	Name string `yaml:"name"`
+	// Kind is the resource kind the assertion applies to, or "*" for all kinds
+	Kind string `yaml:"kind"`
+	// APIVersion optionally restricts the assertion to one apiVersion
+	APIVersion string `yaml:"apiVersion"`
+	// Assert is the expression that must hold, e.g. "$.spec.replicas >= 2"
+	Assert string `yaml:"assert"`
+	// Severity of findings: error, warning (default) or info
+	Severity types.Severity `yaml:"severity"`
+	// Message explains the policy in findings
+	Message string `yaml:"message"`
 }
 
 // DeprecatedAPIsConfig defines deprecated API configuration
@@ -94,15 +525,44 @@ type DeprecatedAPIsConfig struct {
 
 // DeprecatedAPIInfo represents a custom deprecated API
 type DeprecatedAPIInfo struct {
-	APIVersion       string `yaml:"api_version"`
-	DeprecationInfo  string `yaml:"deprecation_info"`
-	Severity         string `yaml:"severity"`
-	OperatorCategory string `yaml:"operator_category"`
+	APIVersion       string         `yaml:"api_version"`
+	DeprecationInfo  string         `yaml:"deprecation_info"`
+	Severity         types.Severity `yaml:"severity"`
+	OperatorCategory string         `yaml:"operator_category"`
+	// Kinds limits the entry to these kinds of the API version; empty matches all
+	Kinds []string `yaml:"kinds"`
+	// IntroducedIn, DeprecatedIn and RemovedIn are the Kubernetes versions
+	// ("1.22") of the API's lifecycle; with any of them set, the severity
+	// follows from kubernetes-version instead of Severity
+	IntroducedIn string `yaml:"introduced_in"`
+	DeprecatedIn string `yaml:"deprecated_in"`
+	RemovedIn    string `yaml:"removed_in"`
+	// Replacement is the API version to migrate to
+	Replacement string `yaml:"replacement"`
+}
+
+// HasLifecycle reports whether the API sets any of its lifecycle versions
+func (a DeprecatedAPIInfo) HasLifecycle() bool {
+	return a.IntroducedIn != "" || a.DeprecatedIn != "" || a.RemovedIn != ""
+}
+
+// KubernetesMinorVersion returns the minor version of a Kubernetes version
+// such as "1.29", "v1.29" or "1.29.3"
+func KubernetesMinorVersion(version string) (int, error) {
+	parts := strings.Split(strings.TrimPrefix(version, "v"), ".")
+	if len(parts) < 2 || len(parts) > 3 || parts[0] != "1" {
+		return 0, fmt.Errorf("invalid Kubernetes version '%s', must look like 1.29", version)
+	}
+	minor, err := strconv.Atoi(parts[1])
+	if err != nil || minor < 0 {
+		return 0, fmt.Errorf("invalid Kubernetes version '%s', must look like 1.29", version)
+	}
+	return minor, nil
 }
 
 // OverrideInfo represents an override for an embedded deprecated API
 type OverrideInfo struct {
-	Severity string `yaml:"severity"`
+	Severity types.Severity `yaml:"severity"`
 }
 
 // ChartConfig defines chart generation settings
@@ -125,6 +585,9 @@ type ExitCodeConfig struct {
 	FailOnErrors   bool `yaml:"fail-on-errors"`   // Exit with code 1 on errors (default: true)
 	FailOnWarnings bool `yaml:"fail-on-warnings"` // Exit with code 2 on warnings (default: false)
 	FailOnInfo     bool `yaml:"fail-on-info"`     // Exit with code 3 on info messages (default: false)
+	// Rules maps a rule ID, config rule name, result type or category to the
+	// exit code used when one of its findings fails the run
+	Rules map[string]int `yaml:"rules"`
 }
 
 // DefaultConfig returns the default configuration
@@ -139,15 +602,54 @@ func DefaultConfig() *Config {
 				Patterns:   []string{"clusters/*", "apps/*", "infrastructure/*"},
 			},
 			Rules: RulesConfig{
-				FluxKustomization:               RuleConfig{Enabled: true, Severity: "error"},
-				FluxPostBuildVariables:          RuleConfig{Enabled: true, Severity: "error"},
-				KubernetesKustomization:         RuleConfig{Enabled: true, Severity: "error"},
-				KustomizationVersionConsistency: RuleConfig{Enabled: true, Severity: "error"},
-				OrphanedResources:               OrphanedResourcesRuleConfig{Enabled: true, Severity: "warning"},
-			HTTPRoutePolicy:                 RuleConfig{Enabled: true, Severity: "warning"},
-				DeprecatedAPIs:                  RuleConfig{Enabled: true, Severity: "warning"},
-				DoubleReferences:                RuleConfig{Enabled: true, Severity: "warning"},
-				CircularDependencies:            RuleConfig{Enabled: true, Severity: "error"},
+				FluxKustomization:               RuleConfig{Enabled: true, Severity: types.SeverityError},
+				FluxPostBuildVariables:          RuleConfig{Enabled: true, Severity: types.SeverityError},
+				KubernetesKustomization:         RuleConfig{Enabled: true, Severity: types.SeverityError},
+				KustomizationVersionConsistency: RuleConfig{Enabled: true, Severity: types.SeverityError},
+				OrphanedResources:               OrphanedResourcesRuleConfig{Enabled: true, Severity: types.SeverityWarning},
+				HTTPRoutePolicy:                 RuleConfig{Enabled: true, Severity: types.SeverityWarning},
+				DeprecatedAPIs:                  RuleConfig{Enabled: true, Severity: types.SeverityWarning},
+				DoubleReferences:                RuleConfig{Enabled: true, Severity: types.SeverityWarning},
+				CircularDependencies:            RuleConfig{Enabled: true, Severity: types.SeverityError},
+				Symlinks:                        RuleConfig{Enabled: true, Severity: types.SeverityError},
+				HelmReleaseCollisions:           RuleConfig{Enabled: true, Severity: types.SeverityError},
+				NamespaceCollisions:             RuleConfig{Enabled: true, Severity: types.SeverityError},
+				TargetNamespaces:                TargetNamespacesRuleConfig{Enabled: true, Severity: types.SeverityWarning},
+				FluxIntervals:                   FluxIntervalsRuleConfig{Enabled: true, Severity: types.SeverityError, MinimumInterval: "1m"},
+				FluxPruneWait:                   FluxPruneWaitRuleConfig{Enabled: true, Severity: types.SeverityWarning, MaxWaitResources: 50},
+				KustomizeNamespaces:             KustomizeNamespacesRuleConfig{Enabled: true, Severity: types.SeverityError},
+				HelmReleaseValues:               RuleConfig{Enabled: true, Severity: types.SeverityError},
+				SOPS:                            RuleConfig{Enabled: true, Severity: types.SeverityError},
+				HelmChartVersions:               RuleConfig{Enabled: true, Severity: types.SeverityError},
+				EnvFieldRefs:                    RuleConfig{Enabled: true, Severity: types.SeverityError},
+				MultipleInclusions:              RuleConfig{Enabled: true, Severity: types.SeverityError},
+				ImageAutomationRefs:             RuleConfig{Enabled: true, Severity: types.SeverityError},
+				ImagePolicyMarkers:              RuleConfig{Enabled: true, Severity: types.SeverityError},
+				NotificationRefs:                RuleConfig{Enabled: true, Severity: types.SeverityError},
+				CrossNamespaceRefs:              RuleConfig{Enabled: false, Severity: types.SeverityError},
+				ReferenceGraph:                  RuleConfig{Enabled: true, Severity: types.SeverityError},
+				SOPSDecryption:                  RuleConfig{Enabled: true, Severity: types.SeverityError},
+				FluxAPIVersions:                 RuleConfig{Enabled: false, Severity: types.SeverityWarning},
+				FluxServiceAccounts:             RuleConfig{Enabled: true, Severity: types.SeverityError},
+				ImageAutomationWriteBack:        RuleConfig{Enabled: true, Severity: types.SeverityWarning},
+				RenderedNameCollisions:          RuleConfig{Enabled: true, Severity: types.SeverityError},
+				KustomizeImages:                 RuleConfig{Enabled: true, Severity: types.SeverityWarning},
+				KustomizeReplacements:           RuleConfig{Enabled: true, Severity: types.SeverityError},
+				KustomizeRemoteResources:        RuleConfig{Enabled: true, Severity: types.SeverityWarning},
+				KustomizeBuild:                  RuleConfig{Enabled: true, Severity: types.SeverityError},
+				MissingNamespaces:               MissingNamespacesRuleConfig{Enabled: true, Severity: types.SeverityWarning},
+				SecretRefs:                      SecretRefsRuleConfig{Enabled: true, Severity: types.SeverityError},
+				ConfigMapRefs:                   ConfigMapRefsRuleConfig{Enabled: true, Severity: types.SeverityError},
+				WorkloadServiceAccounts:         RuleConfig{Enabled: true, Severity: types.SeverityError},
+				StorageClasses:                  RuleConfig{Enabled: true, Severity: types.SeverityWarning},
+				RouteBackends:                   RuleConfig{Enabled: true, Severity: types.SeverityError},
+				ServiceSelectors:                RuleConfig{Enabled: true, Severity: types.SeverityWarning},
+				NetworkPolicies:                 NetworkPoliciesRuleConfig{Enabled: true, Severity: types.SeverityInfo},
+				CustomResources:                 CustomResourcesRuleConfig{Enabled: true, Severity: types.SeverityWarning},
+				Kubeconform:                     KubeconformRuleConfig{Enabled: false, Severity: types.SeverityError},
+				ContainerResources:              ContainerResourcesRuleConfig{Enabled: false, Severity: types.SeverityWarning},
+				ImageTags:                       ImageTagsRuleConfig{Enabled: true, Severity: types.SeverityWarning, DisallowLatest: true},
+				LabelPolicies:                   LabelPoliciesRuleConfig{Enabled: true, Severity: types.SeverityWarning},
 			},
 			DeprecatedAPIs: DeprecatedAPIsConfig{
 				UseEmbedded: true,
@@ -191,6 +693,10 @@ func DefaultConfig() *Config {
 				FailOnWarnings: false, // Default: don't fail on warnings
 				FailOnInfo:     false, // Default: don't fail on info
 			},
+			HealthScore: HealthScoreConfig{
+				SeverityWeights: map[types.Severity]float64{types.SeverityError: 10, types.SeverityWarning: 3, types.SeverityInfo: 1},
+				RuleWeights:     map[string]float64{},
+			},
 		},
 	}
 }
@@ -218,9 +724,51 @@ func LoadConfig(configPath string) (*Config, error) {
 		config.GitOpsValidator.Ignore.Files = defaultConfig.GitOpsValidator.Ignore.Files
 	}
 
+	// Merge health score weights key by key, so setting one severity keeps
+	// the default weights of the others (rule weights default to 1)
+	healthScore := &config.GitOpsValidator.HealthScore
+	if healthScore.SeverityWeights == nil {
+		healthScore.SeverityWeights = make(map[types.Severity]float64)
+	}
+	for severity, weight := range defaultConfig.GitOpsValidator.HealthScore.SeverityWeights {
+		if _, set := healthScore.SeverityWeights[severity]; !set {
+			healthScore.SeverityWeights[severity] = weight
+		}
+	}
+	if healthScore.RuleWeights == nil {
+		healthScore.RuleWeights = make(map[string]float64)
+	}
+
 	return &config, nil
 }
 
+// DiscoverConfig loads configPath or, when it is empty, the first of
+// data/gitops-validator.yaml and .gitops-validator.yaml in the working
+// directory. The built-in defaults are used when no file can be loaded.
+func DiscoverConfig(configPath string) *Config {
+	configPath = DiscoverConfigPath(configPath)
+	if configPath != "" {
+		if loadedConfig, err := LoadConfig(configPath); err == nil {
+			return loadedConfig
+		}
+	}
+	return DefaultConfig()
+}
+
+// DiscoverConfigPath returns the config file DiscoverConfig loads: configPath
+// when set, else the first existing default location, else ""
+func DiscoverConfigPath(configPath string) string {
+	if configPath != "" {
+		return configPath
+	}
+	for _, candidate := range []string{"data/gitops-validator.yaml", ".gitops-validator.yaml"} {
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate
+		}
+	}
+	return ""
+}
+
 // ShouldIgnorePath checks if a path should be ignored based on ignore patterns
 func (c *Config) ShouldIgnorePath(path string) bool {
 	// Normalize path separators to forward slashes for consistent matching
@@ -262,6 +810,28 @@ func (c *Config) ShouldIgnorePath(path string) bool {
 	return false
 }
 
+// ValidateSeverities checks the severities results are given or weighted
+// with: those of custom deprecated APIs, assertions and health score weights.
+// A misspelled one would otherwise produce results no summary counts.
+func (c *Config) ValidateSeverities() error {
+	for _, api := range c.GitOpsValidator.DeprecatedAPIs.CustomAPIs {
+		if !api.Severity.Valid() && (api.Severity != "" || !api.HasLifecycle()) {
+			return fmt.Errorf("invalid severity '%s' for API '%s', must be error, warning, or info", api.Severity, api.APIVersion)
+		}
+	}
+	for _, custom := range c.GitOpsValidator.Assertions {
+		if custom.Severity != "" && !custom.Severity.Valid() {
+			return fmt.Errorf("invalid severity '%s' for assertion '%s', must be error, warning, or info", custom.Severity, custom.Assert)
+		}
+	}
+	for name := range c.GitOpsValidator.HealthScore.SeverityWeights {
+		if !name.Valid() {
+			return fmt.Errorf("invalid health score severity '%s', must be error, warning, or info", name)
+		}
+	}
+	return nil
+}
+
 // Validate validates the configuration
 func (c *Config) Validate() error {
 	// Validate entry point patterns
@@ -270,19 +840,47 @@ func (c *Config) Validate() error {
 			return fmt.Errorf("invalid entry point pattern: %s", pattern)
 		}
 	}
+	switch c.GitOpsValidator.EntryPoints.AutoDetect {
+	case "", AutoDetectNone, AutoDetectFluxOnly, AutoDetectDirectories:
+	default:
+		return fmt.Errorf("invalid entry point auto-detect strategy '%s', must be none, flux-only or directories", c.GitOpsValidator.EntryPoints.AutoDetect)
+	}
 
 	// Validate deprecated API versions
 	for _, api := range c.GitOpsValidator.DeprecatedAPIs.CustomAPIs {
 		if api.APIVersion == "" {
 			return fmt.Errorf("deprecated API version cannot be empty")
 		}
-		if api.Severity != "error" && api.Severity != "warning" && api.Severity != "info" {
+		if !api.Severity.Valid() && (api.Severity != "" || !api.HasLifecycle()) {
 			return fmt.Errorf("invalid severity '%s' for API '%s', must be error, warning, or info", api.Severity, api.APIVersion)
 		}
+		for _, version := range []string{api.IntroducedIn, api.DeprecatedIn, api.RemovedIn} {
+			if _, err := KubernetesMinorVersion(version); version != "" && err != nil {
+				return fmt.Errorf("API '%s': %w", api.APIVersion, err)
+			}
+		}
+	}
+	// Validate label policy patterns
+	for i, policy := range c.GitOpsValidator.Rules.LabelPolicies.Policies {
+		for _, patterns := range []map[string]string{policy.Labels, policy.Annotations} {
+			for key, pattern := range patterns {
+				if _, err := regexp.Compile(pattern); err != nil {
+					return fmt.Errorf("invalid pattern for '%s' in label policy %d: %w", key, i, err)
+				}
+			}
+		}
+	}
+	if version := c.GitOpsValidator.KubernetesVersion; version != "" {
+		if _, err := KubernetesMinorVersion(version); err != nil {
+			return fmt.Errorf("kubernetes-version: %w", err)
+		}
 	}
 
 	// Validate rule severities
-	ruleSeverities := []struct{ enabled bool; severity string }{
+	ruleSeverities := []struct {
+		enabled  bool
+		severity types.Severity
+	}{
 		{c.GitOpsValidator.Rules.FluxKustomization.Enabled, c.GitOpsValidator.Rules.FluxKustomization.Severity},
 		{c.GitOpsValidator.Rules.FluxPostBuildVariables.Enabled, c.GitOpsValidator.Rules.FluxPostBuildVariables.Severity},
 		{c.GitOpsValidator.Rules.KubernetesKustomization.Enabled, c.GitOpsValidator.Rules.KubernetesKustomization.Severity},
@@ -292,14 +890,196 @@ func (c *Config) Validate() error {
 		{c.GitOpsValidator.Rules.DoubleReferences.Enabled, c.GitOpsValidator.Rules.DoubleReferences.Severity},
 		{c.GitOpsValidator.Rules.CircularDependencies.Enabled, c.GitOpsValidator.Rules.CircularDependencies.Severity},
 		{c.GitOpsValidator.Rules.HTTPRoutePolicy.Enabled, c.GitOpsValidator.Rules.HTTPRoutePolicy.Severity},
+		{c.GitOpsValidator.Rules.Symlinks.Enabled, c.GitOpsValidator.Rules.Symlinks.Severity},
+		{c.GitOpsValidator.Rules.HelmReleaseCollisions.Enabled, c.GitOpsValidator.Rules.HelmReleaseCollisions.Severity},
+		{c.GitOpsValidator.Rules.NamespaceCollisions.Enabled, c.GitOpsValidator.Rules.NamespaceCollisions.Severity},
+		{c.GitOpsValidator.Rules.TargetNamespaces.Enabled, c.GitOpsValidator.Rules.TargetNamespaces.Severity},
+		{c.GitOpsValidator.Rules.FluxIntervals.Enabled, c.GitOpsValidator.Rules.FluxIntervals.Severity},
+		{c.GitOpsValidator.Rules.FluxPruneWait.Enabled, c.GitOpsValidator.Rules.FluxPruneWait.Severity},
+		{c.GitOpsValidator.Rules.KustomizeNamespaces.Enabled, c.GitOpsValidator.Rules.KustomizeNamespaces.Severity},
+		{c.GitOpsValidator.Rules.HelmReleaseValues.Enabled, c.GitOpsValidator.Rules.HelmReleaseValues.Severity},
+		{c.GitOpsValidator.Rules.SOPS.Enabled, c.GitOpsValidator.Rules.SOPS.Severity},
+		{c.GitOpsValidator.Rules.HelmChartVersions.Enabled, c.GitOpsValidator.Rules.HelmChartVersions.Severity},
+		{c.GitOpsValidator.Rules.EnvFieldRefs.Enabled, c.GitOpsValidator.Rules.EnvFieldRefs.Severity},
+		{c.GitOpsValidator.Rules.MultipleInclusions.Enabled, c.GitOpsValidator.Rules.MultipleInclusions.Severity},
+		{c.GitOpsValidator.Rules.ImageAutomationRefs.Enabled, c.GitOpsValidator.Rules.ImageAutomationRefs.Severity},
+		{c.GitOpsValidator.Rules.ImagePolicyMarkers.Enabled, c.GitOpsValidator.Rules.ImagePolicyMarkers.Severity},
+		{c.GitOpsValidator.Rules.NotificationRefs.Enabled, c.GitOpsValidator.Rules.NotificationRefs.Severity},
+		{c.GitOpsValidator.Rules.CrossNamespaceRefs.Enabled, c.GitOpsValidator.Rules.CrossNamespaceRefs.Severity},
+		{c.GitOpsValidator.Rules.ReferenceGraph.Enabled, c.GitOpsValidator.Rules.ReferenceGraph.Severity},
+		{c.GitOpsValidator.Rules.SOPSDecryption.Enabled, c.GitOpsValidator.Rules.SOPSDecryption.Severity},
+		{c.GitOpsValidator.Rules.FluxAPIVersions.Enabled, c.GitOpsValidator.Rules.FluxAPIVersions.Severity},
+		{c.GitOpsValidator.Rules.FluxServiceAccounts.Enabled, c.GitOpsValidator.Rules.FluxServiceAccounts.Severity},
+		{c.GitOpsValidator.Rules.ImageAutomationWriteBack.Enabled, c.GitOpsValidator.Rules.ImageAutomationWriteBack.Severity},
+		{c.GitOpsValidator.Rules.RenderedNameCollisions.Enabled, c.GitOpsValidator.Rules.RenderedNameCollisions.Severity},
+		{c.GitOpsValidator.Rules.KustomizeImages.Enabled, c.GitOpsValidator.Rules.KustomizeImages.Severity},
+		{c.GitOpsValidator.Rules.KustomizeReplacements.Enabled, c.GitOpsValidator.Rules.KustomizeReplacements.Severity},
+		{c.GitOpsValidator.Rules.KustomizeRemoteResources.Enabled, c.GitOpsValidator.Rules.KustomizeRemoteResources.Severity},
+		{c.GitOpsValidator.Rules.KustomizeBuild.Enabled, c.GitOpsValidator.Rules.KustomizeBuild.Severity},
+		{c.GitOpsValidator.Rules.MissingNamespaces.Enabled, c.GitOpsValidator.Rules.MissingNamespaces.Severity},
+		{c.GitOpsValidator.Rules.SecretRefs.Enabled, c.GitOpsValidator.Rules.SecretRefs.Severity},
+		{c.GitOpsValidator.Rules.ConfigMapRefs.Enabled, c.GitOpsValidator.Rules.ConfigMapRefs.Severity},
+		{c.GitOpsValidator.Rules.WorkloadServiceAccounts.Enabled, c.GitOpsValidator.Rules.WorkloadServiceAccounts.Severity},
+		{c.GitOpsValidator.Rules.StorageClasses.Enabled, c.GitOpsValidator.Rules.StorageClasses.Severity},
+		{c.GitOpsValidator.Rules.RouteBackends.Enabled, c.GitOpsValidator.Rules.RouteBackends.Severity},
+		{c.GitOpsValidator.Rules.ServiceSelectors.Enabled, c.GitOpsValidator.Rules.ServiceSelectors.Severity},
+		{c.GitOpsValidator.Rules.NetworkPolicies.Enabled, c.GitOpsValidator.Rules.NetworkPolicies.Severity},
+		{c.GitOpsValidator.Rules.CustomResources.Enabled, c.GitOpsValidator.Rules.CustomResources.Severity},
+		{c.GitOpsValidator.Rules.Kubeconform.Enabled, c.GitOpsValidator.Rules.Kubeconform.Severity},
+		{c.GitOpsValidator.Rules.ContainerResources.Enabled, c.GitOpsValidator.Rules.ContainerResources.Severity},
+		{c.GitOpsValidator.Rules.ImageTags.Enabled, c.GitOpsValidator.Rules.ImageTags.Severity},
+		{c.GitOpsValidator.Rules.LabelPolicies.Enabled, c.GitOpsValidator.Rules.LabelPolicies.Severity},
+	}
+
+	for _, rule := range c.GitOpsValidator.Rules.Contrib {
+		if rule.Severity == "" {
+			// Contributed rules default to warning
+			continue
+		}
+		ruleSeverities = append(ruleSeverities, struct {
+			enabled  bool
+			severity types.Severity
+		}{rule.Enabled, rule.Severity})
 	}
 
 	for _, rule := range ruleSeverities {
-		if rule.enabled && rule.severity != "error" && rule.severity != "warning" && rule.severity != "info" {
+		if rule.enabled && !rule.severity.Valid() {
 			return fmt.Errorf("invalid rule severity '%s', must be error, warning, or info", rule.severity)
 		}
 	}
 
+	// Validate rule parameters
+	if err := c.ValidateRuleParams(); err != nil {
+		return err
+	}
+
+	// Validate health score weights
+	for name, weight := range c.GitOpsValidator.HealthScore.SeverityWeights {
+		if !name.Valid() {
+			return fmt.Errorf("invalid health score severity '%s', must be error, warning, or info", name)
+		}
+		if weight < 0 {
+			return fmt.Errorf("health score weight for severity '%s' cannot be negative", name)
+		}
+	}
+	for name, weight := range c.GitOpsValidator.HealthScore.RuleWeights {
+		if weight < 0 {
+			return fmt.Errorf("health score weight for rule '%s' cannot be negative", name)
+		}
+	}
+
+	if c.GitOpsValidator.SeverityPropagation.EscalateAbove < 0 {
+		return fmt.Errorf("severity-propagation escalate-above cannot be negative")
+	}
+
+	// Validate the minimum Flux source interval
+	if minimum := c.GitOpsValidator.Rules.FluxIntervals.MinimumInterval; minimum != "" {
+		if _, err := time.ParseDuration(minimum); err != nil {
+			return fmt.Errorf("invalid flux-intervals minimum-interval '%s': %w", minimum, err)
+		}
+	}
+
+	// Validate prune/wait exceptions
+	if c.GitOpsValidator.Rules.FluxPruneWait.MaxWaitResources < 0 {
+		return fmt.Errorf("flux-prune-wait max-wait-resources cannot be negative")
+	}
+	for _, exception := range c.GitOpsValidator.Rules.FluxPruneWait.Exceptions {
+		if exception.Path == "" {
+			return fmt.Errorf("flux-prune-wait exception must have a path")
+		}
+		for _, check := range exception.Checks {
+			if check != "prune" && check != "wait" {
+				return fmt.Errorf("invalid flux-prune-wait exception check '%s', must be prune or wait", check)
+			}
+		}
+	}
+
+	// Validate network settings
+	if c.GitOpsValidator.Network.Attempts < 0 {
+		return fmt.Errorf("network attempts cannot be negative")
+	}
+	for name, value := range map[string]string{
+		"backoff":     c.GitOpsValidator.Network.Backoff,
+		"max-backoff": c.GitOpsValidator.Network.MaxBackoff,
+		"timeout":     c.GitOpsValidator.Network.Timeout,
+	} {
+		if value == "" {
+			continue
+		}
+		if d, err := time.ParseDuration(value); err != nil || d <= 0 {
+			return fmt.Errorf("invalid network %s '%s', must be a positive duration such as 1s", name, value)
+		}
+	}
+
+	// Validate exit code mappings
+	for rule, code := range c.GitOpsValidator.ExitCodes.Rules {
+		if code < 1 || code > 125 {
+			return fmt.Errorf("invalid exit code %d for rule '%s', must be between 1 and 125", code, rule)
+		}
+	}
+
+	// Validate suppressions
+	for _, suppression := range c.GitOpsValidator.Suppressions {
+		if suppression.Rule == "" {
+			return fmt.Errorf("suppression requires a rule (file '%s', resource '%s')", suppression.File, suppression.Resource)
+		}
+		if suppression.File != "" {
+			if _, err := filepath.Match(suppression.File, "test"); err != nil {
+				return fmt.Errorf("invalid suppression file pattern: %s", suppression.File)
+			}
+		}
+		if suppression.Expires != "" {
+			if _, err := time.Parse(suppressionDateLayout, suppression.Expires); err != nil {
+				return fmt.Errorf("invalid expiry date '%s' for suppression of rule '%s', expected YYYY-MM-DD", suppression.Expires, suppression.Rule)
+			}
+		}
+	}
+
+	// Validate severity floors
+	for _, floor := range c.GitOpsValidator.SeverityFloors {
+		if floor.Path == "" {
+			return fmt.Errorf("severity floor requires a path")
+		}
+		if _, err := filepath.Match(floor.Path, "test"); err != nil {
+			return fmt.Errorf("invalid severity floor path pattern: %s", floor.Path)
+		}
+		if !floor.Severity.Valid() {
+			return fmt.Errorf("invalid severity '%s' for severity floor '%s', must be error, warning, or info", floor.Severity, floor.Path)
+		}
+	}
+
+	// Validate tenants
+	for _, tenant := range c.GitOpsValidator.Tenants {
+		if tenant.Name == "" || len(tenant.Roots) == 0 {
+			return fmt.Errorf("tenant requires a name and at least one root (got name '%s')", tenant.Name)
+		}
+		for _, root := range tenant.Roots {
+			if _, err := filepath.Match(root, "test"); err != nil {
+				return fmt.Errorf("invalid root pattern '%s' for tenant '%s'", root, tenant.Name)
+			}
+		}
+	}
+
+	// Validate assertions
+	for _, custom := range c.GitOpsValidator.Assertions {
+		if custom.Kind == "" || custom.Assert == "" {
+			return fmt.Errorf("assertion requires a kind and an assert expression (got kind '%s', assert '%s')", custom.Kind, custom.Assert)
+		}
+		if custom.Severity != "" && !custom.Severity.Valid() {
+			return fmt.Errorf("invalid severity '%s' for assertion '%s', must be error, warning, or info", custom.Severity, custom.Assert)
+		}
+		if _, err := assertion.Compile(custom.Assert); err != nil {
+			return err
+		}
+	}
+
+	// Validate source mappings
+	for _, source := range c.GitOpsValidator.Sources {
+		if source.Name == "" || source.Path == "" {
+			return fmt.Errorf("source mapping requires both name and path (got name '%s', path '%s')", source.Name, source.Path)
+		}
+	}
+
 	return nil
 }
 
@@ -346,11 +1126,58 @@ func (c *Config) GetEntryPointPatterns() []string {
 	return c.GitOpsValidator.EntryPoints.Patterns
 }
 
+// GetEntryPointAutoDetect returns the entry point auto-detection strategy,
+// none when unset
+func (c *Config) GetEntryPointAutoDetect() string {
+	if c.GitOpsValidator.EntryPoints.AutoDetect == "" {
+		return AutoDetectNone
+	}
+	return c.GitOpsValidator.EntryPoints.AutoDetect
+}
+
 // GetEntryPointResources returns the specific resources that should be considered entry points
 func (c *Config) GetEntryPointResources() []string {
 	return c.GitOpsValidator.EntryPoints.Resources
 }
 
+// GetFluxRoot returns the subdirectory that Flux spec.path values are relative to
+func (c *Config) GetFluxRoot() string {
+	return c.GitOpsValidator.FluxRoot
+}
+
+// ResolveFluxRoot returns the directory that repository-root-relative references
+// (Flux spec.path) resolve against for the checkout at repoPath
+func (c *Config) ResolveFluxRoot(repoPath string) string {
+	if c.GitOpsValidator.FluxRoot == "" {
+		return repoPath
+	}
+	return filepath.Join(repoPath, c.GitOpsValidator.FluxRoot)
+}
+
+// GetSourceMapping returns the local checkout path configured for a Flux source.
+// Mappings may be keyed by "namespace/name" or by name alone.
+func (c *Config) GetSourceMapping(namespace, name string) (string, bool) {
+	for _, source := range c.GitOpsValidator.Sources {
+		if source.Name == name || (namespace != "" && source.Name == namespace+"/"+name) {
+			return source.Path, true
+		}
+	}
+	return "", false
+}
+
+// GetTenantForRoot returns the tenant owning the Flux Kustomization defined in
+// relPath (relative to the repository root), if any
+func (c *Config) GetTenantForRoot(relPath string) (string, bool) {
+	for _, tenant := range c.GitOpsValidator.Tenants {
+		for _, root := range tenant.Roots {
+			if pathutil.MatchPattern(relPath, root) {
+				return tenant.Name, true
+			}
+		}
+	}
+	return "", false
+}
+
 // IsRuleEnabled checks if a specific rule is enabled
 func (c *Config) IsRuleEnabled(ruleName string) bool {
 	switch ruleName {
@@ -372,13 +1199,91 @@ func (c *Config) IsRuleEnabled(ruleName string) bool {
 		return c.GitOpsValidator.Rules.CircularDependencies.Enabled
 	case "http-route-policy":
 		return c.GitOpsValidator.Rules.HTTPRoutePolicy.Enabled
+	case "symlinks":
+		return c.GitOpsValidator.Rules.Symlinks.Enabled
+	case "helm-release-collisions":
+		return c.GitOpsValidator.Rules.HelmReleaseCollisions.Enabled
+	case "namespace-collisions":
+		return c.GitOpsValidator.Rules.NamespaceCollisions.Enabled
+	case "target-namespaces":
+		return c.GitOpsValidator.Rules.TargetNamespaces.Enabled
+	case "flux-intervals":
+		return c.GitOpsValidator.Rules.FluxIntervals.Enabled
+	case "flux-prune-wait":
+		return c.GitOpsValidator.Rules.FluxPruneWait.Enabled
+	case "kustomize-namespaces":
+		return c.GitOpsValidator.Rules.KustomizeNamespaces.Enabled
+	case "helm-release-values":
+		return c.GitOpsValidator.Rules.HelmReleaseValues.Enabled
+	case "sops":
+		return c.GitOpsValidator.Rules.SOPS.Enabled
+	case "helm-chart-versions":
+		return c.GitOpsValidator.Rules.HelmChartVersions.Enabled
+	case "env-field-refs":
+		return c.GitOpsValidator.Rules.EnvFieldRefs.Enabled
+	case "multiple-inclusions":
+		return c.GitOpsValidator.Rules.MultipleInclusions.Enabled
+	case "image-automation-refs":
+		return c.GitOpsValidator.Rules.ImageAutomationRefs.Enabled
+	case "image-policy-markers":
+		return c.GitOpsValidator.Rules.ImagePolicyMarkers.Enabled
+	case "notification-refs":
+		return c.GitOpsValidator.Rules.NotificationRefs.Enabled
+	case "cross-namespace-refs":
+		return c.GitOpsValidator.Rules.CrossNamespaceRefs.Enabled
+	case "reference-graph":
+		return c.GitOpsValidator.Rules.ReferenceGraph.Enabled
+	case "sops-decryption":
+		return c.GitOpsValidator.Rules.SOPSDecryption.Enabled
+	case "flux-api-versions":
+		return c.GitOpsValidator.Rules.FluxAPIVersions.Enabled
+	case "flux-service-accounts":
+		return c.GitOpsValidator.Rules.FluxServiceAccounts.Enabled
+	case "image-automation-write-back":
+		return c.GitOpsValidator.Rules.ImageAutomationWriteBack.Enabled
+	case "rendered-name-collisions":
+		return c.GitOpsValidator.Rules.RenderedNameCollisions.Enabled
+	case "kustomize-images":
+		return c.GitOpsValidator.Rules.KustomizeImages.Enabled
+	case "kustomize-replacements":
+		return c.GitOpsValidator.Rules.KustomizeReplacements.Enabled
+	case "kustomize-remote-resources":
+		return c.GitOpsValidator.Rules.KustomizeRemoteResources.Enabled
+	case "kustomize-build":
+		return c.GitOpsValidator.Rules.KustomizeBuild.Enabled
+	case "missing-namespaces":
+		return c.GitOpsValidator.Rules.MissingNamespaces.Enabled
+	case "secret-refs":
+		return c.GitOpsValidator.Rules.SecretRefs.Enabled
+	case "configmap-refs":
+		return c.GitOpsValidator.Rules.ConfigMapRefs.Enabled
+	case "workload-service-accounts":
+		return c.GitOpsValidator.Rules.WorkloadServiceAccounts.Enabled
+	case "storage-classes":
+		return c.GitOpsValidator.Rules.StorageClasses.Enabled
+	case "route-backends":
+		return c.GitOpsValidator.Rules.RouteBackends.Enabled
+	case "service-selectors":
+		return c.GitOpsValidator.Rules.ServiceSelectors.Enabled
+	case "network-policies":
+		return c.GitOpsValidator.Rules.NetworkPolicies.Enabled
+	case "custom-resources":
+		return c.GitOpsValidator.Rules.CustomResources.Enabled
+	case "kubeconform":
+		return c.GitOpsValidator.Rules.Kubeconform.Enabled
+	case "container-resources":
+		return c.GitOpsValidator.Rules.ContainerResources.Enabled
+	case "image-tags":
+		return c.GitOpsValidator.Rules.ImageTags.Enabled
+	case "label-policies":
+		return c.GitOpsValidator.Rules.LabelPolicies.Enabled
 	default:
-		return false
+		return c.GitOpsValidator.Rules.Contrib[ruleName].Enabled
 	}
 }
 
 // GetRuleSeverity returns the severity for a specific rule
-func (c *Config) GetRuleSeverity(ruleName string) string {
+func (c *Config) GetRuleSeverity(ruleName string) types.Severity {
 	switch ruleName {
 	case "flux-kustomization":
 		return c.GitOpsValidator.Rules.FluxKustomization.Severity
@@ -398,7 +1303,88 @@ func (c *Config) GetRuleSeverity(ruleName string) string {
 		return c.GitOpsValidator.Rules.CircularDependencies.Severity
 	case "http-route-policy":
 		return c.GitOpsValidator.Rules.HTTPRoutePolicy.Severity
+	case "symlinks":
+		return c.GitOpsValidator.Rules.Symlinks.Severity
+	case "helm-release-collisions":
+		return c.GitOpsValidator.Rules.HelmReleaseCollisions.Severity
+	case "namespace-collisions":
+		return c.GitOpsValidator.Rules.NamespaceCollisions.Severity
+	case "target-namespaces":
+		return c.GitOpsValidator.Rules.TargetNamespaces.Severity
+	case "flux-intervals":
+		return c.GitOpsValidator.Rules.FluxIntervals.Severity
+	case "flux-prune-wait":
+		return c.GitOpsValidator.Rules.FluxPruneWait.Severity
+	case "kustomize-namespaces":
+		return c.GitOpsValidator.Rules.KustomizeNamespaces.Severity
+	case "helm-release-values":
+		return c.GitOpsValidator.Rules.HelmReleaseValues.Severity
+	case "sops":
+		return c.GitOpsValidator.Rules.SOPS.Severity
+	case "helm-chart-versions":
+		return c.GitOpsValidator.Rules.HelmChartVersions.Severity
+	case "env-field-refs":
+		return c.GitOpsValidator.Rules.EnvFieldRefs.Severity
+	case "multiple-inclusions":
+		return c.GitOpsValidator.Rules.MultipleInclusions.Severity
+	case "image-automation-refs":
+		return c.GitOpsValidator.Rules.ImageAutomationRefs.Severity
+	case "image-policy-markers":
+		return c.GitOpsValidator.Rules.ImagePolicyMarkers.Severity
+	case "notification-refs":
+		return c.GitOpsValidator.Rules.NotificationRefs.Severity
+	case "cross-namespace-refs":
+		return c.GitOpsValidator.Rules.CrossNamespaceRefs.Severity
+	case "reference-graph":
+		return c.GitOpsValidator.Rules.ReferenceGraph.Severity
+	case "sops-decryption":
+		return c.GitOpsValidator.Rules.SOPSDecryption.Severity
+	case "flux-api-versions":
+		return c.GitOpsValidator.Rules.FluxAPIVersions.Severity
+	case "flux-service-accounts":
+		return c.GitOpsValidator.Rules.FluxServiceAccounts.Severity
+	case "image-automation-write-back":
+		return c.GitOpsValidator.Rules.ImageAutomationWriteBack.Severity
+	case "rendered-name-collisions":
+		return c.GitOpsValidator.Rules.RenderedNameCollisions.Severity
+	case "kustomize-images":
+		return c.GitOpsValidator.Rules.KustomizeImages.Severity
+	case "kustomize-replacements":
+		return c.GitOpsValidator.Rules.KustomizeReplacements.Severity
+	case "kustomize-remote-resources":
+		return c.GitOpsValidator.Rules.KustomizeRemoteResources.Severity
+	case "kustomize-build":
+		return c.GitOpsValidator.Rules.KustomizeBuild.Severity
+	case "missing-namespaces":
+		return c.GitOpsValidator.Rules.MissingNamespaces.Severity
+	case "secret-refs":
+		return c.GitOpsValidator.Rules.SecretRefs.Severity
+	case "configmap-refs":
+		return c.GitOpsValidator.Rules.ConfigMapRefs.Severity
+	case "workload-service-accounts":
+		return c.GitOpsValidator.Rules.WorkloadServiceAccounts.Severity
+	case "storage-classes":
+		return c.GitOpsValidator.Rules.StorageClasses.Severity
+	case "route-backends":
+		return c.GitOpsValidator.Rules.RouteBackends.Severity
+	case "service-selectors":
+		return c.GitOpsValidator.Rules.ServiceSelectors.Severity
+	case "network-policies":
+		return c.GitOpsValidator.Rules.NetworkPolicies.Severity
+	case "custom-resources":
+		return c.GitOpsValidator.Rules.CustomResources.Severity
+	case "kubeconform":
+		return c.GitOpsValidator.Rules.Kubeconform.Severity
+	case "container-resources":
+		return c.GitOpsValidator.Rules.ContainerResources.Severity
+	case "image-tags":
+		return c.GitOpsValidator.Rules.ImageTags.Severity
+	case "label-policies":
+		return c.GitOpsValidator.Rules.LabelPolicies.Severity
 	default:
-		return "warning"
+		if rule, ok := c.GitOpsValidator.Rules.Contrib[ruleName]; ok && rule.Severity != "" {
+			return rule.Severity
+		}
+		return types.SeverityWarning
 	}
 }
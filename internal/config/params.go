@@ -0,0 +1,264 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/moon-hex/gitops-validator/internal/semver"
+)
+
+// ParamType is the type of a rule parameter
+type ParamType string
+
+const (
+	ParamString     ParamType = "string"
+	ParamStringList ParamType = "string list"
+	ParamInt        ParamType = "integer"
+	ParamBool       ParamType = "boolean"
+	ParamDuration   ParamType = "duration"
+	ParamRegexp     ParamType = "regular expression"
+	ParamVersion    ParamType = "version"
+)
+
+// ParamSpec declares a parameter a rule accepts next to enabled and severity
+type ParamSpec struct {
+	Name        string
+	Type        ParamType
+	Description string
+}
+
+// RuleParams are the parameters configured for a rule, as decoded from YAML.
+// They are validated against the rule's ParamSpecs when the configuration is
+// validated, so the typed getters only fall back to their default for
+// parameters that are not set.
+type RuleParams map[string]interface{}
+
+var (
+	ruleParamsMu   sync.RWMutex
+	ruleParamSpecs = make(map[string][]ParamSpec)
+)
+
+// RegisterRuleParams declares the parameters a rule, named as in the rules
+// section of the config, accepts. Checks call it from an init function, so
+// parameterizing a rule needs no config struct of its own:
+//
+//	rules:
+//	  image-policy-markers:
+//	    enabled: true
+//	    severity: error
+//	    report-unused: false
+func RegisterRuleParams(rule string, specs ...ParamSpec) {
+	ruleParamsMu.Lock()
+	defer ruleParamsMu.Unlock()
+	ruleParamSpecs[rule] = append(ruleParamSpecs[rule], specs...)
+}
+
+// RuleParamSpecs returns the parameters registered for a rule
+func RuleParamSpecs(rule string) []ParamSpec {
+	ruleParamsMu.RLock()
+	defer ruleParamsMu.RUnlock()
+	return append([]ParamSpec(nil), ruleParamSpecs[rule]...)
+}
+
+// RuleParams returns the parameters configured for a rule, or nil for rules
+// that are not configured or have a config struct of their own
+func (c *Config) RuleParams(rule string) RuleParams {
+	if config, ok := c.ruleConfigs()[rule]; ok {
+		return config.Params
+	}
+	return nil
+}
+
+// ruleConfigs maps the rule names of the rules section to the rules using
+// the generic RuleConfig, contributed rules included
+func (c *Config) ruleConfigs() map[string]RuleConfig {
+	configs := make(map[string]RuleConfig)
+	rules := reflect.ValueOf(c.GitOpsValidator.Rules)
+	for i := 0; i < rules.NumField(); i++ {
+		config, ok := rules.Field(i).Interface().(RuleConfig)
+		if !ok {
+			continue
+		}
+		name, _, _ := strings.Cut(rules.Type().Field(i).Tag.Get("yaml"), ",")
+		configs[name] = config
+	}
+	for name, config := range c.GitOpsValidator.Rules.Contrib {
+		configs[name] = config
+	}
+	return configs
+}
+
+// ValidateRuleParams checks the configured parameters of every rule against
+// the registered ones. It is also part of Validate; the validator runs it on
+// its own before validating, as other config problems such as a malformed
+// assertion are reported as findings instead.
+func (c *Config) ValidateRuleParams() error {
+	configs := c.ruleConfigs()
+	names := make([]string, 0, len(configs))
+	for name := range configs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, rule := range names {
+		params := configs[rule].Params
+		keys := make([]string, 0, len(params))
+		for key := range params {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		specs := RuleParamSpecs(rule)
+		for _, key := range keys {
+			var spec *ParamSpec
+			for i := range specs {
+				if specs[i].Name == key {
+					spec = &specs[i]
+				}
+			}
+			if spec == nil {
+				supported := "none"
+				if len(specs) > 0 {
+					var described []string
+					for _, s := range specs {
+						described = append(described, fmt.Sprintf("%s (%s)", s.Name, s.Type))
+					}
+					supported = strings.Join(described, ", ")
+				}
+				return fmt.Errorf("unknown parameter '%s' for rule '%s' (supported: %s)", key, rule, supported)
+			}
+			if err := checkParam(*spec, params[key]); err != nil {
+				return fmt.Errorf("invalid parameter '%s' for rule '%s': %w", key, rule, err)
+			}
+		}
+	}
+	return nil
+}
+
+// checkParam reports whether value has the type of spec
+func checkParam(spec ParamSpec, value interface{}) error {
+	switch spec.Type {
+	case ParamString:
+		if _, ok := value.(string); !ok {
+			return fmt.Errorf("must be a string")
+		}
+	case ParamStringList:
+		list, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("must be a list of strings")
+		}
+		for _, item := range list {
+			if _, ok := item.(string); !ok {
+				return fmt.Errorf("must be a list of strings")
+			}
+		}
+	case ParamInt:
+		if _, ok := value.(int); !ok {
+			return fmt.Errorf("must be an integer")
+		}
+	case ParamBool:
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("must be true or false")
+		}
+	case ParamDuration:
+		text, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("must be a duration such as 10m")
+		}
+		if _, err := time.ParseDuration(text); err != nil {
+			return fmt.Errorf("must be a duration such as 10m: %w", err)
+		}
+	case ParamRegexp:
+		text, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("must be a regular expression")
+		}
+		if _, err := regexp.Compile(text); err != nil {
+			return fmt.Errorf("must be a regular expression: %w", err)
+		}
+	case ParamVersion:
+		text, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("must be a version such as 2.3.0")
+		}
+		if _, err := semver.Parse(text); err != nil {
+			return fmt.Errorf("must be a version such as 2.3.0: %w", err)
+		}
+	}
+	return nil
+}
+
+// String returns a string parameter, or fallback when it is not set
+func (p RuleParams) String(name, fallback string) string {
+	if value, ok := p[name].(string); ok {
+		return value
+	}
+	return fallback
+}
+
+// Strings returns a string list parameter, or fallback when it is not set
+func (p RuleParams) Strings(name string, fallback []string) []string {
+	list, ok := p[name].([]interface{})
+	if !ok {
+		return fallback
+	}
+	values := make([]string, 0, len(list))
+	for _, item := range list {
+		if value, ok := item.(string); ok {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// Int returns an integer parameter, or fallback when it is not set
+func (p RuleParams) Int(name string, fallback int) int {
+	if value, ok := p[name].(int); ok {
+		return value
+	}
+	return fallback
+}
+
+// Bool returns a boolean parameter, or fallback when it is not set
+func (p RuleParams) Bool(name string, fallback bool) bool {
+	if value, ok := p[name].(bool); ok {
+		return value
+	}
+	return fallback
+}
+
+// Duration returns a duration parameter, or fallback when it is not set
+func (p RuleParams) Duration(name string, fallback time.Duration) time.Duration {
+	if text, ok := p[name].(string); ok {
+		if value, err := time.ParseDuration(text); err == nil {
+			return value
+		}
+	}
+	return fallback
+}
+
+// Regexp returns a compiled regular expression parameter, or nil when it is
+// not set
+func (p RuleParams) Regexp(name string) *regexp.Regexp {
+	if text, ok := p[name].(string); ok {
+		if value, err := regexp.Compile(text); err == nil {
+			return value
+		}
+	}
+	return nil
+}
+
+// Version returns a version parameter and whether it is set
+func (p RuleParams) Version(name string) (semver.Version, bool) {
+	if text, ok := p[name].(string); ok {
+		if value, err := semver.Parse(text); err == nil {
+			return value, true
+		}
+	}
+	return semver.Version{}, false
+}
@@ -5,7 +5,9 @@ import (
 
 	"github.com/moon-hex/gitops-validator/internal/chart"
 	"github.com/moon-hex/gitops-validator/internal/config"
+	"github.com/moon-hex/gitops-validator/internal/network"
 	"github.com/moon-hex/gitops-validator/internal/parser"
+	"github.com/moon-hex/gitops-validator/internal/render"
 )
 
 // ValidationContext provides context for validators
@@ -13,7 +15,16 @@ type ValidationContext struct {
 	Graph    *parser.ResourceGraph
 	Config   *config.Config
 	RepoPath string
+	// FluxRoot is the directory Flux spec.path values resolve against
+	// (RepoPath unless a flux-root subdirectory is configured)
+	FluxRoot string
 	Verbose  bool
+	// Network is the client for checks that reach the network; check
+	// Network.Offline before building requests
+	Network *network.Client
+	// Renderer builds kustomizations with kustomize, each once per run;
+	// only used in render mode (Config.GitOpsValidator.Render)
+	Renderer *render.Renderer
 }
 
 // NewValidationContext creates a new ValidationContext
@@ -22,57 +33,90 @@ func NewValidationContext(graph *parser.ResourceGraph, cfg *config.Config, repoP
 		Graph:    graph,
 		Config:   cfg,
 		RepoPath: repoPath,
+		FluxRoot: cfg.ResolveFluxRoot(repoPath),
 		Verbose:  verbose,
+		Network:  network.NewClient(cfg.GitOpsValidator.Network),
+		Renderer: render.NewRenderer(),
 	}
 }
 
+// EntryPoint is a resource validation starts from and what made it one:
+// "resource <name>", "pattern <glob>", "type <type>", "namespace <namespace>"
+// or "auto-detect <strategy>"
+type EntryPoint struct {
+	Resource *parser.ParsedResource
+	Source   string
+}
+
 // FindEntryPoints finds all entry point resources based on configuration
 func (ctx *ValidationContext) FindEntryPoints() []*parser.ParsedResource {
 	var entryPoints []*parser.ParsedResource
+	for _, entryPoint := range ctx.FindEntryPointSources() {
+		entryPoints = append(entryPoints, entryPoint.Resource)
+	}
+	return entryPoints
+}
+
+// FindEntryPointSources finds all entry points with the configuration that
+// made them one. A resource matched several ways is listed once per match.
+func (ctx *ValidationContext) FindEntryPointSources() []EntryPoint {
+	var entryPoints []EntryPoint
+	add := func(source string, resources ...*parser.ParsedResource) {
+		for _, resource := range resources {
+			entryPoints = append(entryPoints, EntryPoint{Resource: resource, Source: source})
+		}
+	}
 
 	// Add explicitly configured resources
 	for _, resourceName := range ctx.Config.GetEntryPointResources() {
 		if resource := ctx.Graph.GetResource(resourceName); resource != nil {
-			entryPoints = append(entryPoints, resource)
+			add("resource "+resourceName, resource)
 		}
 	}
 
 	// Add resources matching patterns
 	for _, pattern := range ctx.Config.GetEntryPointPatterns() {
-		matches := ctx.Graph.GetResourcesMatchingPattern(pattern)
-		entryPoints = append(entryPoints, matches...)
+		add("pattern "+pattern, ctx.Graph.GetResourcesMatchingPattern(pattern)...)
 	}
 
 	// Add resources of specified types
 	for _, resourceType := range ctx.Config.GetEntryPointTypes() {
+		source := "type " + resourceType
 		switch resourceType {
 		case "flux-kustomization":
-			entryPoints = append(entryPoints, ctx.Graph.GetFluxKustomizations()...)
+			add(source, ctx.Graph.GetFluxKustomizations()...)
 		case "helm-release":
-			entryPoints = append(entryPoints, ctx.Graph.GetHelmReleases()...)
+			add(source, ctx.Graph.GetHelmReleases()...)
 		case "git-repository":
-			entryPoints = append(entryPoints, ctx.Graph.GetFluxSources()...)
+			add(source, ctx.Graph.GetFluxSources()...)
 		case "kubernetes-kustomization":
-			entryPoints = append(entryPoints, ctx.Graph.GetKubernetesKustomizations()...)
+			add(source, ctx.Graph.GetKubernetesKustomizations()...)
 		}
 	}
 
 	// Add resources in specified namespaces
 	for _, namespace := range ctx.Config.GetEntryPointNamespaces() {
-		entryPoints = append(entryPoints, ctx.Graph.GetResourcesByNamespace(namespace)...)
+		add("namespace "+namespace, ctx.Graph.GetResourcesByNamespace(namespace)...)
 	}
 
-	// Auto-detect common Flux entry points if no explicit entry points found
+	// Auto-detect entry points if none are configured and a strategy is enabled
 	if len(entryPoints) == 0 {
-		entryPoints = ctx.detectEntryPoints()
+		strategy := ctx.Config.GetEntryPointAutoDetect()
+		add("auto-detect "+strategy, ctx.detectEntryPoints(strategy)...)
 	}
 
 	return entryPoints
 }
 
-// detectEntryPoints automatically detects common Flux entry points
-func (ctx *ValidationContext) detectEntryPoints() []*parser.ParsedResource {
+// detectEntryPoints detects common Flux entry points. The flux-only strategy
+// takes Flux Kustomizations, HelmReleases and the flux-system namespace;
+// directories adds everything in apps/, infrastructure/ and clusters/, which
+// can hide orphaned files there. none detects nothing.
+func (ctx *ValidationContext) detectEntryPoints(strategy string) []*parser.ParsedResource {
 	var entryPoints []*parser.ParsedResource
+	if strategy != config.AutoDetectFluxOnly && strategy != config.AutoDetectDirectories {
+		return entryPoints
+	}
 
 	// Flux Kustomizations are always entry points
 	entryPoints = append(entryPoints, ctx.Graph.GetFluxKustomizations()...)
@@ -83,6 +127,10 @@ func (ctx *ValidationContext) detectEntryPoints() []*parser.ParsedResource {
 	// Resources in flux-system namespace
 	entryPoints = append(entryPoints, ctx.Graph.GetResourcesByNamespace("flux-system")...)
 
+	if strategy != config.AutoDetectDirectories {
+		return entryPoints
+	}
+
 	// Resources in common GitOps directories
 	commonDirs := []string{"apps", "infrastructure", "clusters"}
 	for _, dir := range commonDirs {
@@ -94,11 +142,12 @@ func (ctx *ValidationContext) detectEntryPoints() []*parser.ParsedResource {
 
 // FindOrphanedResources finds resources that are not referenced by any entry point
 func (ctx *ValidationContext) FindOrphanedResources(entryPoints []*parser.ParsedResource) []*parser.ParsedResource {
-	visited := make(map[string]bool)
+	traversal := ctx.TraverseReferences(entryPoints)
 
-	// Start traversal from all entry points
-	for _, entryPoint := range entryPoints {
-		ctx.traverseFromResource(entryPoint, visited)
+	// A resource is reached when any resource with its key is
+	visited := make(map[string]bool)
+	for resource := range traversal.Depth {
+		visited[resource.GetResourceKey()] = true
 	}
 
 	// Find unvisited resources
@@ -112,26 +161,6 @@ func (ctx *ValidationContext) FindOrphanedResources(entryPoints []*parser.Parsed
 	return orphaned
 }
 
-// traverseFromResource performs a depth-first traversal from a resource
-func (ctx *ValidationContext) traverseFromResource(resource *parser.ParsedResource, visited map[string]bool) {
-	key := resource.GetResourceKey()
-	if visited[key] {
-		return // Already visited
-	}
-
-	visited[key] = true
-
-	// Traverse dependencies — use FindAllTargetResources so that every document
-	// in a multi-doc YAML file is visited, not just the first one.
-	for _, dep := range resource.Dependencies {
-		if dep.ReferenceType == string(parser.ReferenceTypePath) || dep.ReferenceType == string(parser.ReferenceTypeResource) {
-			for _, target := range ctx.Graph.FindAllTargetResources(dep, resource, ctx.RepoPath) {
-				ctx.traverseFromResource(target, visited)
-			}
-		}
-	}
-}
-
 // FindDoubleReferencedResources finds resources that are referenced by multiple sources
 func (ctx *ValidationContext) FindDoubleReferencedResources() []DoubleReference {
 	var doubleRefs []DoubleReference
@@ -159,7 +188,7 @@ func (ctx *ValidationContext) GenerateDependencyChart(format string) (string, er
 	entryPoints := ctx.FindEntryPoints()
 	orphaned := ctx.FindOrphanedResources(entryPoints)
 
-	generator := chart.NewChartGenerator(ctx.Graph)
+	generator := chart.NewChartGenerator(ctx.Graph, ctx.FluxRoot)
 
 	switch format {
 	case "mermaid":
@@ -177,7 +206,7 @@ func (ctx *ValidationContext) GenerateDependencyChart(format string) (string, er
 func (ctx *ValidationContext) GenerateDependencyChartForEntryPoint(entryPoint *parser.ParsedResource, format string) (string, error) {
 	orphaned := ctx.FindOrphanedResources([]*parser.ParsedResource{entryPoint})
 
-	generator := chart.NewChartGenerator(ctx.Graph)
+	generator := chart.NewChartGenerator(ctx.Graph, ctx.FluxRoot)
 
 	switch format {
 	case "mermaid":
@@ -0,0 +1,109 @@
+package context
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/moon-hex/gitops-validator/internal/parser"
+)
+
+// Scoped views let a validator check a subset of the repository, such as a
+// single cluster, with the same context methods it uses on the whole of it.
+// A view shares the configuration and the resources of the context it was
+// made from; only its Graph is smaller.
+
+// ScopeToEntryPoint returns a view of the resources an entry point reaches:
+// everything its path and resource references lead to, the manifests Flux
+// applies from path directories without a kustomization file, and the Flux
+// sources these reference
+func (ctx *ValidationContext) ScopeToEntryPoint(entryPoint *parser.ParsedResource) *ValidationContext {
+	keep := make(map[*parser.ParsedResource]bool)
+	ctx.addReached(keep, entryPoint)
+	return ctx.scoped(keep)
+}
+
+// ScopeToCluster returns a view of a cluster directory, relative to the
+// repository root ("clusters/production"): the resources defined below it
+// and everything the Flux Kustomizations and kustomizations among them
+// reach, such as the shared bases the cluster deploys
+func (ctx *ValidationContext) ScopeToCluster(dir string) *ValidationContext {
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(ctx.RepoPath, dir)
+	}
+	dir = filepath.Clean(dir) + string(filepath.Separator)
+
+	keep := make(map[*parser.ParsedResource]bool)
+	for file, resources := range ctx.Graph.Files {
+		if !strings.HasPrefix(file, dir) {
+			continue
+		}
+		for _, resource := range resources {
+			switch parser.ClassifyResource(resource) {
+			case parser.ResourceTypeFluxKustomization, parser.ResourceTypeKubernetesKustomization:
+				ctx.addReached(keep, resource)
+			default:
+				keep[resource] = true
+			}
+		}
+	}
+	return ctx.scoped(keep)
+}
+
+// ScopeToNamespace returns a view of the resources deployed to a namespace.
+// Resources some Flux Kustomization deploys are placed by the namespace they
+// are applied to, after kustomize namespace and spec.targetNamespace
+// overrides; others by their own metadata.namespace.
+func (ctx *ValidationContext) ScopeToNamespace(namespace string) *ValidationContext {
+	keep := make(map[*parser.ParsedResource]bool)
+	deployed := make(map[*parser.ParsedResource]bool)
+	for _, root := range ctx.RootKustomizations() {
+		for _, d := range ctx.DeploymentTree(root) {
+			deployed[d.Resource] = true
+			if d.Namespace == namespace {
+				keep[d.Resource] = true
+			}
+		}
+	}
+	for _, resources := range ctx.Graph.Files {
+		for _, resource := range resources {
+			if !deployed[resource] && resource.Namespace == namespace {
+				keep[resource] = true
+			}
+		}
+	}
+	return ctx.scoped(keep)
+}
+
+// addReached adds a resource, everything it reaches and the Flux sources
+// those reference to keep
+func (ctx *ValidationContext) addReached(keep map[*parser.ParsedResource]bool, entryPoint *parser.ParsedResource) {
+	var reached []*parser.ParsedResource
+	for resource := range ctx.TraverseReferences([]*parser.ParsedResource{entryPoint}).Depth {
+		reached = append(reached, resource)
+	}
+	for _, d := range ctx.DeploymentTree(entryPoint) {
+		reached = append(reached, d.Resource)
+	}
+
+	for _, resource := range reached {
+		keep[resource] = true
+		for _, dep := range resource.Dependencies {
+			if dep.ReferenceType != string(parser.ReferenceTypeSourceRef) {
+				continue
+			}
+			if source := ctx.Graph.FindTargetResource(dep, resource, ctx.FluxRoot); source != nil {
+				keep[source] = true
+			}
+		}
+	}
+}
+
+// scoped returns a copy of the context whose graph only holds the kept
+// resources
+func (ctx *ValidationContext) scoped(keep map[*parser.ParsedResource]bool) *ValidationContext {
+	view := *ctx
+	view.Graph = ctx.Graph.Subgraph(func(resource *parser.ParsedResource) bool {
+		return keep[resource]
+	})
+	return &view
+}
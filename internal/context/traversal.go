@@ -0,0 +1,180 @@
+package context
+
+import (
+	"sort"
+
+	"github.com/moon-hex/gitops-validator/internal/parser"
+)
+
+// DefaultMaxReferenceDepth is the depth of reference chains beyond which the
+// reference-graph rule reports them, unless its max-depth parameter is set
+const DefaultMaxReferenceDepth = 50
+
+// ReferenceTraversal is the result of following path and resource references
+// breadth-first from a set of entry points
+type ReferenceTraversal struct {
+	// Depth is the length of the shortest reference chain from an entry point
+	// to each reached resource; entry points have depth 0
+	Depth map[*parser.ParsedResource]int
+	// parents are the BFS links back towards the entry points
+	parents map[*parser.ParsedResource]*parser.ParsedResource
+}
+
+// Reached reports whether the traversal reached a resource
+func (t *ReferenceTraversal) Reached(resource *parser.ParsedResource) bool {
+	_, reached := t.Depth[resource]
+	return reached
+}
+
+// Chain returns the shortest reference chain from an entry point to a
+// reached resource, starting with the entry point
+func (t *ReferenceTraversal) Chain(resource *parser.ParsedResource) []*parser.ParsedResource {
+	var chain []*parser.ParsedResource
+	for current := resource; current != nil; current = t.parents[current] {
+		chain = append(chain, current)
+	}
+	for i, j := 0, len(chain)-1; i < j; i, j = i+1, j-1 {
+		chain[i], chain[j] = chain[j], chain[i]
+	}
+	return chain
+}
+
+// TraverseReferences follows the path and resource references of the entry
+// points breadth-first with an explicit queue, so neither deep chains nor
+// cycles can exhaust the stack. Each resource is visited once; resources are
+// tracked by pointer since multi-cluster repositories have one flux-system
+// Kustomization per cluster under the same key.
+func (ctx *ValidationContext) TraverseReferences(entryPoints []*parser.ParsedResource) *ReferenceTraversal {
+	traversal := &ReferenceTraversal{
+		Depth:   make(map[*parser.ParsedResource]int),
+		parents: make(map[*parser.ParsedResource]*parser.ParsedResource),
+	}
+
+	var queue []*parser.ParsedResource
+	for _, entryPoint := range entryPoints {
+		if !traversal.Reached(entryPoint) {
+			traversal.Depth[entryPoint] = 0
+			queue = append(queue, entryPoint)
+		}
+	}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for _, next := range ctx.referenceTargets(current) {
+			if traversal.Reached(next) {
+				continue
+			}
+			traversal.Depth[next] = traversal.Depth[current] + 1
+			traversal.parents[next] = current
+			queue = append(queue, next)
+		}
+	}
+
+	return traversal
+}
+
+// referenceTargets returns the resources a resource's path and resource
+// references point to. FindAllTargetResources returns every document of a
+// multi-document YAML file, not just the first one. A Flux Kustomization path
+// to a directory without a kustomization file reaches every manifest Flux
+// generates a kustomization for.
+func (ctx *ValidationContext) referenceTargets(resource *parser.ParsedResource) []*parser.ParsedResource {
+	var targets []*parser.ParsedResource
+	for _, dep := range resource.Dependencies {
+		if dep.ReferenceType != string(parser.ReferenceTypePath) && dep.ReferenceType != string(parser.ReferenceTypeResource) {
+			continue
+		}
+		found := ctx.Graph.FindAllTargetResources(dep, resource, ctx.FluxRoot)
+		if len(found) == 0 && dep.Type == "flux-kustomization-path" {
+			found = ctx.generatedKustomizationResources(dep, resource)
+		}
+		targets = append(targets, found...)
+	}
+	return targets
+}
+
+// KustomizationCycles returns the cycles among kustomization.yaml files
+// including each other, each starting at the one with the lowest file path.
+// kustomize build rejects them. Cycles through a Flux Kustomization, such as
+// the flux-system Kustomization applying the directory it is defined in, are
+// how Flux manages itself and are not included. The search is an iterative
+// depth-first search with an explicit stack.
+func (ctx *ValidationContext) KustomizationCycles() [][]*parser.ParsedResource {
+	kustomizations := ctx.Graph.GetKubernetesKustomizations()
+	sort.Slice(kustomizations, func(i, j int) bool { return kustomizations[i].File < kustomizations[j].File })
+
+	edges := make(map[*parser.ParsedResource][]*parser.ParsedResource)
+	for _, kustomization := range kustomizations {
+		for _, target := range ctx.referenceTargets(kustomization) {
+			if parser.ClassifyResource(target) == parser.ResourceTypeKubernetesKustomization {
+				edges[kustomization] = append(edges[kustomization], target)
+			}
+		}
+	}
+
+	const (
+		unvisited = iota
+		onStack
+		done
+	)
+	state := make(map[*parser.ParsedResource]int)
+	seen := make(map[string]bool)
+	var cycles [][]*parser.ParsedResource
+
+	type frame struct {
+		resource *parser.ParsedResource
+		next     int
+	}
+	for _, start := range kustomizations {
+		if state[start] != unvisited {
+			continue
+		}
+		stack := []frame{{resource: start}}
+		state[start] = onStack
+
+		for len(stack) > 0 {
+			top := &stack[len(stack)-1]
+			if top.next == len(edges[top.resource]) {
+				state[top.resource] = done
+				stack = stack[:len(stack)-1]
+				continue
+			}
+			target := edges[top.resource][top.next]
+			top.next++
+
+			switch state[target] {
+			case unvisited:
+				state[target] = onStack
+				stack = append(stack, frame{resource: target})
+			case onStack:
+				// The stack from target upwards is the cycle
+				var cycle []*parser.ParsedResource
+				for i := len(stack) - 1; i >= 0; i-- {
+					cycle = append([]*parser.ParsedResource{stack[i].resource}, cycle...)
+					if stack[i].resource == target {
+						break
+					}
+				}
+				lowest := 0
+				for i, resource := range cycle {
+					if resource.File < cycle[lowest].File {
+						lowest = i
+					}
+				}
+				cycle = append(cycle[lowest:], cycle[:lowest]...)
+				key := ""
+				for _, resource := range cycle {
+					key += resource.File + "\x00"
+				}
+				if !seen[key] {
+					seen[key] = true
+					cycles = append(cycles, cycle)
+				}
+			}
+		}
+	}
+
+	return cycles
+}
@@ -0,0 +1,289 @@
+package context
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/moon-hex/gitops-validator/internal/parser"
+	"github.com/moon-hex/gitops-validator/internal/pathutil"
+)
+
+// DeployedResource is a resource as an entry point applies it to the cluster
+type DeployedResource struct {
+	Resource *parser.ParsedResource
+	// Namespace is the namespace the resource is applied to once the
+	// kustomization namespace and Flux spec.targetNamespace overrides of the
+	// tree are applied; it is the resource's own namespace if none apply
+	Namespace string
+	// Name is metadata.name once the namePrefix and nameSuffix of the
+	// including kustomizations are applied
+	Name string
+}
+
+// nameTransformSkippedKinds are the kinds kustomize leaves out of namePrefix
+// and nameSuffix
+var nameTransformSkippedKinds = map[string]bool{
+	"CustomResourceDefinition": true,
+	"APIService":               true,
+	"Namespace":                true,
+}
+
+// DeploymentTree returns the resources an entry point deploys: everything
+// included through Flux spec.path and kustomization resources, following
+// nested Flux Kustomizations. Patches are not followed, since they modify
+// resources rather than add them. A Flux path directory without a
+// kustomization file deploys every manifest below it, as Flux generates one.
+func (ctx *ValidationContext) DeploymentTree(entryPoint *parser.ParsedResource) []DeployedResource {
+	walk := &treeWalk{ctx: ctx, followNested: true, visited: make(map[*parser.ParsedResource]map[treeTransform]bool)}
+	walk.collect(entryPoint, treeTransform{}, true)
+	return walk.deployed
+}
+
+// AppliedResources returns the resources a Flux Kustomization applies itself:
+// its DeploymentTree without descending into nested Flux Kustomizations,
+// which are included as objects but apply their own trees
+func (ctx *ValidationContext) AppliedResources(kustomization *parser.ParsedResource) []DeployedResource {
+	walk := &treeWalk{ctx: ctx, visited: make(map[*parser.ParsedResource]map[treeTransform]bool)}
+	walk.collect(kustomization, treeTransform{}, true)
+	return walk.deployed[1:]
+}
+
+// RootKustomizations returns the Flux Kustomizations no other Flux
+// Kustomization deploys. With the bootstrap Kustomization committed, each
+// cluster has a single root whose tree holds everything it reconciles.
+func (ctx *ValidationContext) RootKustomizations() []*parser.ParsedResource {
+	deployedByOther := make(map[*parser.ParsedResource]bool)
+	for _, kustomization := range ctx.Graph.GetFluxKustomizations() {
+		for _, d := range ctx.DeploymentTree(kustomization) {
+			if d.Resource != kustomization && parser.ClassifyResource(d.Resource) == parser.ResourceTypeFluxKustomization {
+				deployedByOther[d.Resource] = true
+			}
+		}
+	}
+
+	var roots []*parser.ParsedResource
+	for _, kustomization := range ctx.Graph.GetFluxKustomizations() {
+		if !deployedByOther[kustomization] {
+			roots = append(roots, kustomization)
+		}
+	}
+	return roots
+}
+
+// MultipleInclusion is a file a Flux Kustomization applies through several
+// kustomizations, each of which adds its resources to the same build
+type MultipleInclusion struct {
+	File string
+	// Parents are the kustomizations including the file, in walk order
+	Parents []*parser.ParsedResource
+}
+
+// MultipleInclusions returns the files the tree a Flux Kustomization applies
+// itself includes from more than one kustomization with the same namespace
+// and name transformations, so kustomize would add their resources twice. A
+// base included through overlays with different namePrefix or namespace
+// settings renders distinct objects and is not reported.
+func (ctx *ValidationContext) MultipleInclusions(kustomization *parser.ParsedResource) []MultipleInclusion {
+	walk := &treeWalk{ctx: ctx, visited: make(map[*parser.ParsedResource]map[treeTransform]bool), includers: make(map[inclusion][]*parser.ParsedResource)}
+	walk.collect(kustomization, treeTransform{}, true)
+
+	var inclusions []MultipleInclusion
+	for included, parents := range walk.includers {
+		if len(parents) > 1 {
+			inclusions = append(inclusions, MultipleInclusion{File: included.file, Parents: parents})
+		}
+	}
+	sort.Slice(inclusions, func(i, j int) bool { return inclusions[i].File < inclusions[j].File })
+	return inclusions
+}
+
+// ApplyingKustomizations maps every file to the Flux Kustomizations that apply
+// it themselves rather than through a nested Flux Kustomization. Patch files
+// belong to the Kustomizations applying the resources they patch. A root Flux
+// Kustomization applies its own file, as the bootstrap Kustomization does.
+func (ctx *ValidationContext) ApplyingKustomizations() map[string][]*parser.ParsedResource {
+	applying := make(map[string][]*parser.ParsedResource)
+	add := func(file string, kustomization *parser.ParsedResource) {
+		for _, existing := range applying[file] {
+			if existing == kustomization {
+				return
+			}
+		}
+		applying[file] = append(applying[file], kustomization)
+	}
+
+	for _, kustomization := range ctx.Graph.GetFluxKustomizations() {
+		walk := &treeWalk{ctx: ctx, withPatches: true, visited: make(map[*parser.ParsedResource]map[treeTransform]bool)}
+		walk.collect(kustomization, treeTransform{}, true)
+		for _, d := range walk.deployed {
+			if d.Resource != kustomization {
+				add(d.Resource.File, kustomization)
+			}
+		}
+	}
+
+	for _, kustomization := range ctx.Graph.GetFluxKustomizations() {
+		if len(applying[kustomization.File]) == 0 {
+			add(kustomization.File, kustomization)
+		}
+	}
+	return applying
+}
+
+// treeWalk collects the resources below an entry point
+type treeWalk struct {
+	ctx          *ValidationContext
+	followNested bool // descend into Flux Kustomizations below the entry point
+	withPatches  bool // include the patch files of kustomizations
+	visited      map[*parser.ParsedResource]map[treeTransform]bool
+	deployed     []DeployedResource
+	// includers, when set, records the kustomizations including each file
+	includers map[inclusion][]*parser.ParsedResource
+}
+
+// inclusion is a file included with a transformation
+type inclusion struct {
+	file      string
+	transform treeTransform
+}
+
+// treeTransform is what the including kustomizations do to a resource: the
+// namespace override, where the outermost wins, and the accumulated
+// namePrefix and nameSuffix, where the outermost ends up outside
+type treeTransform struct {
+	namespace string
+	prefix    string
+	suffix    string
+}
+
+// collect adds a resource and what it deploys, transformed by the including
+// kustomizations
+func (w *treeWalk) collect(resource *parser.ParsedResource, transform treeTransform, entryPoint bool) {
+	if w.visited[resource] == nil {
+		w.visited[resource] = make(map[treeTransform]bool)
+	}
+	if w.visited[resource][transform] {
+		return
+	}
+	w.visited[resource][transform] = true
+
+	effective := resource.Namespace
+	if transform.namespace != "" {
+		effective = transform.namespace
+	}
+	name := resource.Name
+	if parser.ClassifyResource(resource) != parser.ResourceTypeKubernetesKustomization && !nameTransformSkippedKinds[resource.Kind] {
+		name = transform.prefix + name + transform.suffix
+	}
+	w.deployed = append(w.deployed, DeployedResource{Resource: resource, Namespace: effective, Name: name})
+
+	// A Flux Kustomization is applied on its own; only its targetNamespace
+	// applies to what it deploys
+	child := transform
+	switch parser.ClassifyResource(resource) {
+	case parser.ResourceTypeFluxKustomization:
+		if !entryPoint && !w.followNested {
+			return
+		}
+		child = treeTransform{}
+		if spec, ok := resource.Content["spec"].(map[string]interface{}); ok {
+			child.namespace, _ = spec["targetNamespace"].(string)
+		}
+	case parser.ResourceTypeKubernetesKustomization:
+		if child.namespace == "" {
+			child.namespace, _ = resource.Content["namespace"].(string)
+		}
+		prefix, _ := resource.Content["namePrefix"].(string)
+		suffix, _ := resource.Content["nameSuffix"].(string)
+		child.prefix += prefix
+		child.suffix = suffix + child.suffix
+	}
+
+	for _, dep := range resource.Dependencies {
+		switch dep.Type {
+		case "flux-kustomization-path", "kustomization-resource":
+		case "kustomization-patch", "kustomization-patch-strategic", "kustomization-patch-json6902":
+			if !w.withPatches {
+				continue
+			}
+		default:
+			continue
+		}
+
+		targets := w.ctx.Graph.FindAllTargetResources(dep, resource, w.ctx.FluxRoot)
+		if len(targets) == 0 && dep.Type == "flux-kustomization-path" {
+			targets = w.ctx.generatedKustomizationResources(dep, resource)
+		}
+		for _, target := range targets {
+			w.include(target.File, child, resource)
+			w.collect(target, child, false)
+		}
+	}
+}
+
+// include records that parent includes file, once per parent
+func (w *treeWalk) include(file string, transform treeTransform, parent *parser.ParsedResource) {
+	if w.includers == nil {
+		return
+	}
+	key := inclusion{file: file, transform: transform}
+	for _, existing := range w.includers[key] {
+		if existing == parent {
+			return
+		}
+	}
+	w.includers[key] = append(w.includers[key], parent)
+}
+
+// generatedKustomizationResources returns what Flux deploys for a spec.path
+// directory without a kustomization file: every manifest below it, except
+// that subdirectories with a kustomization file contribute only that file
+func (ctx *ValidationContext) generatedKustomizationResources(dep parser.ResourceReference, source *parser.ParsedResource) []*parser.ParsedResource {
+	dir, ok := pathutil.ResolveReference(dep.Path, dep.IsRelative, source.File, ctx.FluxRoot)
+	if !ok {
+		return nil
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return nil
+	}
+	dir = filepath.Clean(dir)
+
+	prefix := dir + string(filepath.Separator)
+	if dir == "." {
+		prefix = ""
+	}
+
+	var files []string
+	for file := range ctx.Graph.Files {
+		if strings.HasPrefix(file, prefix) {
+			files = append(files, file)
+		}
+	}
+	sort.Strings(files)
+
+	var resources []*parser.ParsedResource
+	for _, file := range files {
+		if owner := ctx.owningKustomization(filepath.Dir(file), dir); owner == "" || owner == file {
+			resources = append(resources, ctx.Graph.Files[file]...)
+		}
+	}
+	return resources
+}
+
+// owningKustomization returns the kustomization file in the directory closest
+// to dir, up to but excluding root, or "" if there is none
+func (ctx *ValidationContext) owningKustomization(dir, root string) string {
+	for ; dir != root; dir = filepath.Dir(dir) {
+		for _, name := range []string{"kustomization.yaml", "kustomization.yml"} {
+			if _, exists := ctx.Graph.Files[filepath.Join(dir, name)]; exists {
+				return filepath.Join(dir, name)
+			}
+		}
+		if dir == "." || dir == string(filepath.Separator) {
+			break
+		}
+	}
+	return ""
+}
@@ -0,0 +1,360 @@
+// Package generator writes synthetic GitOps repositories for benchmarking,
+// demos and reproducing scaling issues: Flux clusters deploying shared app
+// bases through layers of kustomize overlays, with a chosen number of
+// intentional errors.
+package generator
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Options controls the shape of a generated repository
+type Options struct {
+	Clusters int   // clusters, each bootstrapped from clusters/<name>/flux-system
+	Apps     int   // app bases deployed to every cluster
+	Depth    int   // kustomize overlay levels between a cluster and an app base
+	Errors   int   // intentional errors, cycling through ErrorKinds
+	Seed     int64 // seed choosing where errors are placed
+}
+
+// Sizes are the presets of --size
+var Sizes = map[string]Options{
+	"small":  {Clusters: 1, Apps: 5, Depth: 1, Errors: 3},
+	"medium": {Clusters: 3, Apps: 50, Depth: 2, Errors: 10},
+	"large":  {Clusters: 10, Apps: 500, Depth: 3, Errors: 25},
+}
+
+// ErrorKinds are the intentional errors, in the order they are placed, with
+// the rule each one triggers
+var ErrorKinds = []struct {
+	Name string
+	Rule string
+}{
+	{"missing-resource", "GV0004"},
+	{"broken-flux-path", "GV0001"},
+	{"invalid-postbuild-variable", "GV0003"},
+	{"deprecated-api", "GV0010"},
+	{"orphaned-file", "GV0009"},
+	{"kustomize-version-mismatch", "GV0008"},
+}
+
+// ConfigFile is the validator config written next to the generated
+// repository, so it validates the same way wherever it is generated
+const ConfigFile = "gitops-validator.yaml"
+
+// validatorConfig maps the flux-system GitRepository, which points at a
+// made-up URL, to the generated repository itself and starts orphan detection
+// at the Flux Kustomizations only
+const validatorConfig = `gitops-validator:
+  sources:
+    - name: flux-system
+      path: .
+  entry-points:
+    namespaces:
+      - flux-system
+    types:
+      - flux-kustomization
+  deprecated-apis:
+    custom-apis:
+      - api_version: "extensions/v1beta1"
+        deprecation_info: "Deprecated in v1.16, removed in v1.22"
+        severity: "error"
+`
+
+// PlacedError is an intentional error and where it was placed
+type PlacedError struct {
+	Kind string
+	Rule string
+	File string
+}
+
+// Summary describes a generated repository
+type Summary struct {
+	Files  int
+	Errors []PlacedError
+}
+
+// Generate writes a repository to dir, which must not exist or be empty
+func Generate(dir string, options Options) (*Summary, error) {
+	if options.Clusters < 1 || options.Apps < 1 || options.Depth < 1 || options.Errors < 0 {
+		return nil, fmt.Errorf("clusters, apps and depth must be at least 1 and errors not negative")
+	}
+	if entries, err := os.ReadDir(dir); err == nil && len(entries) > 0 {
+		return nil, fmt.Errorf("output directory %s is not empty", dir)
+	}
+
+	g := &repository{
+		dir:     dir,
+		options: options,
+		random:  rand.New(rand.NewSource(options.Seed)),
+		files:   make(map[string]string),
+		summary: &Summary{},
+	}
+	g.generate()
+	if err := g.write(); err != nil {
+		return nil, err
+	}
+	return g.summary, nil
+}
+
+// repository builds the files of a generated repository in memory
+type repository struct {
+	dir     string
+	options Options
+	random  *rand.Rand
+	files   map[string]string // path relative to dir -> content
+	summary *Summary
+	// kustomization resources lists and apiVersions, rendered once errors are placed
+	resources   map[string][]string
+	apiVersions map[string]string
+}
+
+// generate lays out clusters, overlays and bases, then places the errors
+func (g *repository) generate() {
+	g.resources = make(map[string][]string)
+	g.apiVersions = make(map[string]string)
+
+	for app := 1; app <= g.options.Apps; app++ {
+		g.generateBase(appName(app))
+		for level := 1; level < g.options.Depth; level++ {
+			dir := fmt.Sprintf("apps/layers/level-%d/%s", level, appName(app))
+			g.resources[dir+"/kustomization.yaml"] = []string{g.lowerLevel(level, app)}
+		}
+	}
+
+	for cluster := 1; cluster <= g.options.Clusters; cluster++ {
+		g.generateCluster(clusterName(cluster))
+	}
+
+	for i := 0; i < g.options.Errors; i++ {
+		g.placeError(i)
+	}
+
+	for file, resources := range g.resources {
+		apiVersion := g.apiVersions[file]
+		if apiVersion == "" {
+			apiVersion = "kustomize.config.k8s.io/v1beta1"
+		}
+		g.files[file] = kustomizationFile(apiVersion, resources)
+	}
+	g.files["README.md"] = g.readme()
+	g.files[ConfigFile] = validatorConfig
+}
+
+// lowerLevel is the reference from an overlay at level to the level below
+func (g *repository) lowerLevel(level, app int) string {
+	if level == 1 {
+		return "../../../base/" + appName(app)
+	}
+	return fmt.Sprintf("../../level-%d/%s", level-1, appName(app))
+}
+
+// generateBase writes an app base: Deployment, Service and kustomization
+func (g *repository) generateBase(app string) {
+	dir := "apps/base/" + app
+	g.files[dir+"/deployment.yaml"] = fmt.Sprintf(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: %[1]s
+  labels:
+    app.kubernetes.io/name: %[1]s
+spec:
+  replicas: 2
+  selector:
+    matchLabels:
+      app.kubernetes.io/name: %[1]s
+  template:
+    metadata:
+      labels:
+        app.kubernetes.io/name: %[1]s
+    spec:
+      containers:
+        - name: %[1]s
+          image: ghcr.io/example/%[1]s:1.0.0
+          ports:
+            - containerPort: 8080
+`, app)
+	g.files[dir+"/service.yaml"] = fmt.Sprintf(`apiVersion: v1
+kind: Service
+metadata:
+  name: %[1]s
+spec:
+  selector:
+    app.kubernetes.io/name: %[1]s
+  ports:
+    - port: 80
+      targetPort: 8080
+`, app)
+	g.resources[dir+"/kustomization.yaml"] = []string{"deployment.yaml", "service.yaml"}
+}
+
+// generateCluster writes the bootstrap, the apps Flux Kustomization and the
+// cluster overlay deploying every app
+func (g *repository) generateCluster(cluster string) {
+	dir := "clusters/" + cluster
+	g.files[dir+"/flux-system/gotk-sync.yaml"] = fmt.Sprintf(`apiVersion: source.toolkit.fluxcd.io/v1
+kind: GitRepository
+metadata:
+  name: flux-system
+  namespace: flux-system
+spec:
+  interval: 1m
+  url: https://github.com/example/fleet
+  ref:
+    branch: main
+---
+apiVersion: kustomize.toolkit.fluxcd.io/v1
+kind: Kustomization
+metadata:
+  name: flux-system
+  namespace: flux-system
+spec:
+  interval: 10m
+  path: ./%s
+  prune: true
+  sourceRef:
+    kind: GitRepository
+    name: flux-system
+`, dir)
+	g.resources[dir+"/flux-system/kustomization.yaml"] = []string{"gotk-sync.yaml"}
+	g.files[dir+"/apps.yaml"] = fluxKustomization("apps", "./apps/"+cluster, "cluster_name", cluster)
+	g.resources[dir+"/kustomization.yaml"] = []string{"flux-system", "apps.yaml"}
+
+	var apps []string
+	for app := 1; app <= g.options.Apps; app++ {
+		if g.options.Depth == 1 {
+			apps = append(apps, "../base/"+appName(app))
+		} else {
+			apps = append(apps, fmt.Sprintf("../layers/level-%d/%s", g.options.Depth-1, appName(app)))
+		}
+	}
+	g.resources["apps/"+cluster+"/kustomization.yaml"] = apps
+}
+
+// placeError adds the i-th intentional error to a randomly chosen app or cluster
+func (g *repository) placeError(i int) {
+	kind := ErrorKinds[i%len(ErrorKinds)]
+	app := appName(g.random.Intn(g.options.Apps) + 1)
+	cluster := clusterName(g.random.Intn(g.options.Clusters) + 1)
+	base := "apps/base/" + app
+
+	var file string
+	switch kind.Name {
+	case "missing-resource":
+		file = base + "/kustomization.yaml"
+		g.resources[file] = append(g.resources[file], fmt.Sprintf("missing-%d.yaml", i))
+	case "broken-flux-path":
+		file = fmt.Sprintf("clusters/%s/broken-%d.yaml", cluster, i)
+		g.files[file] = fluxKustomization(fmt.Sprintf("broken-%d", i), fmt.Sprintf("./apps/does-not-exist-%d", i), "", "")
+		g.resources["clusters/"+cluster+"/kustomization.yaml"] = append(g.resources["clusters/"+cluster+"/kustomization.yaml"], filepath.Base(file))
+	case "invalid-postbuild-variable":
+		file = fmt.Sprintf("clusters/%s/postbuild-%d.yaml", cluster, i)
+		g.files[file] = fluxKustomization(fmt.Sprintf("postbuild-%d", i), "./apps/"+cluster, "cluster-name", cluster)
+		g.resources["clusters/"+cluster+"/kustomization.yaml"] = append(g.resources["clusters/"+cluster+"/kustomization.yaml"], filepath.Base(file))
+	case "deprecated-api":
+		file = fmt.Sprintf("%s/ingress-%d.yaml", base, i)
+		g.files[file] = fmt.Sprintf(`apiVersion: extensions/v1beta1
+kind: Ingress
+metadata:
+  name: %s-%d
+spec:
+  backend:
+    serviceName: %s
+    servicePort: 80
+`, app, i, app)
+		g.resources[base+"/kustomization.yaml"] = append(g.resources[base+"/kustomization.yaml"], filepath.Base(file))
+	case "orphaned-file":
+		// Outside the app directories, which overlays reference as a whole
+		file = fmt.Sprintf("apps/unused/%s-%d.yaml", app, i)
+		g.files[file] = fmt.Sprintf(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: %s-unused-%d
+data:
+  key: value
+`, app, i)
+	case "kustomize-version-mismatch":
+		file = base + "/kustomization.yaml"
+		g.apiVersions[file] = "kustomize.config.k8s.io/v1"
+	}
+
+	g.summary.Errors = append(g.summary.Errors, PlacedError{Kind: kind.Name, Rule: kind.Rule, File: file})
+}
+
+// readme documents how the repository was generated and its errors
+func (g *repository) readme() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Generated GitOps Repository\n\n")
+	fmt.Fprintf(&b, "Generated by `gitops-validator examples generate` with %d clusters, %d apps, depth %d, %d errors and seed %d.\n\n",
+		g.options.Clusters, g.options.Apps, g.options.Depth, g.options.Errors, g.options.Seed)
+	fmt.Fprintf(&b, "Validate it from this directory with:\n\n```bash\ngitops-validator --config %s --path .\n```\n\n", ConfigFile)
+	if len(g.summary.Errors) == 0 {
+		b.WriteString("The repository contains no intentional errors.\n")
+		return b.String()
+	}
+	b.WriteString("## Intentional Errors\n\n| Kind | Rule | File |\n|---|---|---|\n")
+	for _, placed := range g.summary.Errors {
+		fmt.Fprintf(&b, "| %s | %s | `%s` |\n", placed.Kind, placed.Rule, placed.File)
+	}
+	return b.String()
+}
+
+// write stores the files below the output directory
+func (g *repository) write() error {
+	for file, content := range g.files {
+		path := filepath.Join(g.dir, filepath.FromSlash(file))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			return err
+		}
+	}
+	g.summary.Files = len(g.files)
+	return nil
+}
+
+// kustomizationFile renders a kustomization.yaml
+func kustomizationFile(apiVersion string, resources []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "apiVersion: %s\nkind: Kustomization\n", apiVersion)
+	b.WriteString("resources:\n")
+	for _, resource := range resources {
+		fmt.Fprintf(&b, "  - %s\n", resource)
+	}
+	return b.String()
+}
+
+// fluxKustomization renders a Flux Kustomization, with one postBuild
+// substitution when variable is set
+func fluxKustomization(name, path, variable, value string) string {
+	content := fmt.Sprintf(`apiVersion: kustomize.toolkit.fluxcd.io/v1
+kind: Kustomization
+metadata:
+  name: %s
+  namespace: flux-system
+spec:
+  interval: 10m
+  path: %s
+  prune: true
+  sourceRef:
+    kind: GitRepository
+    name: flux-system
+`, name, path)
+	if variable != "" {
+		content += fmt.Sprintf("  postBuild:\n    substitute:\n      %s: %s\n", variable, value)
+	}
+	return content
+}
+
+func appName(i int) string {
+	return fmt.Sprintf("app-%03d", i)
+}
+
+func clusterName(i int) string {
+	return fmt.Sprintf("cluster-%02d", i)
+}
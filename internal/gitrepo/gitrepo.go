@@ -0,0 +1,165 @@
+// Package gitrepo reads the git checkout containing a validated path
+// directly from its .git directory, so report metadata and source checks
+// agree on the repository without needing a git binary.
+package gitrepo
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Repository locates a git checkout
+type Repository struct {
+	// GitDir holds HEAD; it is the worktree's own directory for a linked
+	// worktree or a submodule
+	GitDir string
+	// CommonDir holds the shared refs and config; it is GitDir itself for
+	// an ordinary checkout
+	CommonDir string
+}
+
+// Remote is a remote from the repository config
+type Remote struct {
+	Name string
+	URL  string
+}
+
+// Find walks up from dir to the git checkout containing it, following the
+// gitdir: file of worktrees and submodules. It returns nil outside one.
+func Find(dir string) *Repository {
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+
+	for {
+		candidate := filepath.Join(dir, ".git")
+		if info, err := os.Stat(candidate); err == nil {
+			if info.IsDir() {
+				return &Repository{GitDir: candidate, CommonDir: candidate}
+			}
+			data, err := os.ReadFile(candidate)
+			if err != nil || !strings.HasPrefix(string(data), "gitdir: ") {
+				return nil
+			}
+			gitDir := strings.TrimSpace(strings.TrimPrefix(string(data), "gitdir: "))
+			if !filepath.IsAbs(gitDir) {
+				gitDir = filepath.Join(dir, gitDir)
+			}
+			commonDir := gitDir
+			if common, err := os.ReadFile(filepath.Join(gitDir, "commondir")); err == nil {
+				commonDir = strings.TrimSpace(string(common))
+				if !filepath.IsAbs(commonDir) {
+					commonDir = filepath.Join(gitDir, commonDir)
+				}
+			}
+			return &Repository{GitDir: gitDir, CommonDir: commonDir}
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil
+		}
+		dir = parent
+	}
+}
+
+// Head returns the checked out commit and branch. Both are empty when HEAD
+// cannot be read; branch is empty for a detached HEAD.
+func (r *Repository) Head() (commit, branch string) {
+	head, err := os.ReadFile(filepath.Join(r.GitDir, "HEAD"))
+	if err != nil {
+		return "", ""
+	}
+	ref := strings.TrimSpace(string(head))
+	if !strings.HasPrefix(ref, "ref: ") {
+		return ref, ""
+	}
+	ref = strings.TrimPrefix(ref, "ref: ")
+	branch = strings.TrimPrefix(ref, "refs/heads/")
+
+	for _, root := range []string{r.GitDir, r.CommonDir} {
+		if data, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(ref))); err == nil {
+			return strings.TrimSpace(string(data)), branch
+		}
+	}
+	return r.packedRef(ref), branch
+}
+
+// packedRef looks ref up in packed-refs
+func (r *Repository) packedRef(ref string) string {
+	file, err := os.Open(filepath.Join(r.CommonDir, "packed-refs"))
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[1] == ref {
+			return fields[0]
+		}
+	}
+	return ""
+}
+
+// Remotes returns the remotes of the repository config in the order they
+// are declared, and the remote each branch tracks by branch name
+func (r *Repository) Remotes() (remotes []Remote, tracked map[string]string) {
+	tracked = make(map[string]string)
+	file, err := os.Open(filepath.Join(r.CommonDir, "config"))
+	if err != nil {
+		return nil, tracked
+	}
+	defer file.Close()
+
+	var section string
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			section = strings.Trim(line, "[]")
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		key, value = strings.TrimSpace(key), strings.Trim(strings.TrimSpace(value), `"`)
+		switch {
+		case strings.HasPrefix(section, "remote ") && key == "url":
+			name := strings.Trim(strings.TrimPrefix(section, "remote "), `"`)
+			if !seen[name] {
+				seen[name] = true
+				remotes = append(remotes, Remote{Name: name, URL: value})
+			}
+		case strings.HasPrefix(section, "branch ") && key == "remote":
+			var name string
+			if _, err := fmt.Sscanf(strings.TrimPrefix(section, "branch "), "%q", &name); err == nil {
+				tracked[name] = value
+			}
+		}
+	}
+	return remotes, tracked
+}
+
+// RemoteURL returns the URL of the remote branch tracks, else of origin,
+// else of the first remote in the repository config
+func (r *Repository) RemoteURL(branch string) string {
+	remotes, tracked := r.Remotes()
+	for _, name := range []string{tracked[branch], "origin"} {
+		for _, remote := range remotes {
+			if name != "" && remote.Name == name {
+				return remote.URL
+			}
+		}
+	}
+	if len(remotes) > 0 {
+		return remotes[0].URL
+	}
+	return ""
+}
@@ -0,0 +1,199 @@
+// Package network is the HTTP client shared by checks that reach the
+// network, such as Helm index queries, remote bases and schema downloads.
+//
+// Requests failing with a transport error or a 429, 500, 502, 503 or 504
+// response are retried with exponential backoff, honoring Retry-After. In
+// offline mode no request is sent and every call returns ErrOffline, so
+// checks can skip themselves.
+package network
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/moon-hex/gitops-validator/internal/config"
+)
+
+// Defaults for settings missing from the network config
+const (
+	DefaultAttempts   = 4
+	DefaultBackoff    = time.Second
+	DefaultMaxBackoff = 30 * time.Second
+	DefaultTimeout    = 30 * time.Second
+)
+
+// ErrOffline is returned for every request while offline mode is enabled
+var ErrOffline = errors.New("network access is disabled (--offline)")
+
+// Client sends requests with retries
+type Client struct {
+	HTTPClient *http.Client
+	Attempts   int
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+	Offline    bool
+}
+
+// Error is a request that failed on every attempt
+type Error struct {
+	Method   string
+	URL      string
+	Attempts int
+	Err      error
+}
+
+func (e *Error) Error() string {
+	attempts := "1 attempt"
+	if e.Attempts != 1 {
+		attempts = fmt.Sprintf("%d attempts", e.Attempts)
+	}
+	return fmt.Sprintf("%s %s failed after %s: %v", e.Method, e.URL, attempts, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// NewClient creates a client from the network config, falling back to the
+// defaults for missing or invalid settings
+func NewClient(cfg config.NetworkConfig) *Client {
+	attempts := cfg.Attempts
+	if attempts <= 0 {
+		attempts = DefaultAttempts
+	}
+	return &Client{
+		HTTPClient: &http.Client{Timeout: parseDuration(cfg.Timeout, DefaultTimeout)},
+		Attempts:   attempts,
+		Backoff:    parseDuration(cfg.Backoff, DefaultBackoff),
+		MaxBackoff: parseDuration(cfg.MaxBackoff, DefaultMaxBackoff),
+		Offline:    cfg.Offline,
+	}
+}
+
+// Get sends a GET request
+func (c *Client) Get(url string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.Do(req)
+}
+
+// Do sends a request, retrying transport errors and retryable responses.
+// After the last attempt a retryable response is returned like any other,
+// so callers handle all status codes in one place. POST requests are sent
+// once, since repeating them may repeat their effect, and a request with a
+// body is only retried when the body can be re-read (see http.Request.GetBody).
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	if c.Offline {
+		return nil, ErrOffline
+	}
+
+	attempts := c.Attempts
+	if attempts <= 0 {
+		attempts = 1
+	}
+	if req.Method == http.MethodPost || (req.Body != nil && req.GetBody == nil) {
+		attempts = 1
+	}
+
+	delay := c.Backoff
+	for attempt := 1; ; attempt++ {
+		if attempt > 1 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+
+		resp, err := c.HTTPClient.Do(req)
+		if err == nil && !retryableStatus(resp.StatusCode) {
+			return resp, nil
+		}
+		if attempt >= attempts {
+			if err != nil {
+				var urlErr *url.Error
+				if errors.As(err, &urlErr) {
+					err = urlErr.Err
+				}
+				return nil, &Error{Method: req.Method, URL: req.URL.Redacted(), Attempts: attempt, Err: err}
+			}
+			return resp, nil
+		}
+
+		wait := jitter(delay)
+		if resp != nil {
+			if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+				wait = retryAfter
+			}
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		if wait > c.MaxBackoff {
+			wait = c.MaxBackoff
+		}
+		time.Sleep(wait)
+
+		delay *= 2
+		if delay > c.MaxBackoff {
+			delay = c.MaxBackoff
+		}
+	}
+}
+
+// IsOffline reports whether err was caused by offline mode
+func IsOffline(err error) bool {
+	return errors.Is(err, ErrOffline)
+}
+
+// retryableStatus reports whether a response status is worth retrying
+func retryableStatus(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// jitter spreads retries of concurrent checks: a random delay between half
+// and all of d
+func jitter(d time.Duration) time.Duration {
+	if d <= 1 {
+		return d
+	}
+	half := d / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// parseRetryAfter reads a Retry-After header in seconds or as an HTTP date
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(at); wait > 0 {
+			return wait, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// parseDuration parses value, or returns fallback when it is empty or invalid
+func parseDuration(value string, fallback time.Duration) time.Duration {
+	if d, err := time.ParseDuration(value); err == nil && d > 0 {
+		return d
+	}
+	return fallback
+}
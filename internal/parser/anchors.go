@@ -0,0 +1,203 @@
+package parser
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ParseIssue is a problem found while decoding a file, reported as a
+// yaml-anchor finding
+type ParseIssue struct {
+	File    string
+	Line    int
+	Column  int
+	Message string
+}
+
+// unknownAnchorError matches the decoder error for an alias of an undefined anchor
+var unknownAnchorError = regexp.MustCompile(`unknown anchor '([^']*)' referenced`)
+
+// nodeConverter converts the nodes of one document into Content maps,
+// resolving aliases and merge keys the way kustomize does: explicit keys win
+// over merged ones, and of several merged mappings the first one wins.
+type nodeConverter struct {
+	file string
+	// anchored nodes of the document; the decoder keeps anchors across
+	// documents, kustomize decodes each document on its own
+	anchors map[*yaml.Node]bool
+	// anchored nodes being converted, to stop aliases used inside their own anchor
+	expanding map[*yaml.Node]bool
+	issues    []ParseIssue
+}
+
+// newNodeConverter creates a converter for the document rooted at root
+func newNodeConverter(file string, root *yaml.Node) *nodeConverter {
+	c := &nodeConverter{file: file, anchors: make(map[*yaml.Node]bool), expanding: make(map[*yaml.Node]bool)}
+	var walk func(node *yaml.Node)
+	walk = func(node *yaml.Node) {
+		if node.Anchor != "" {
+			c.anchors[node] = true
+		}
+		for _, child := range node.Content {
+			walk(child)
+		}
+	}
+	walk(root)
+	return c
+}
+
+// nodeToInterface converts a YAML node to a Go interface{}
+func (c *nodeConverter) nodeToInterface(node *yaml.Node) interface{} {
+	if node.Anchor != "" {
+		c.expanding[node] = true
+		defer delete(c.expanding, node)
+	}
+
+	switch node.Kind {
+	case yaml.ScalarNode:
+		return node.Value
+	case yaml.AliasNode:
+		if c.expanding[node.Alias] {
+			c.issue(node, fmt.Sprintf("alias *%s is used inside the node anchored as &%s; the document cannot be expanded", node.Value, node.Value))
+			return nil
+		}
+		if !c.anchors[node.Alias] {
+			c.issue(node, fmt.Sprintf("alias *%s refers to an anchor of an earlier document; kustomize decodes each document on its own and rejects the file", node.Value))
+		}
+		return c.nodeToInterface(node.Alias)
+	case yaml.SequenceNode:
+		var result []interface{}
+		for _, item := range node.Content {
+			result = append(result, c.nodeToInterface(item))
+		}
+		return result
+	case yaml.MappingNode:
+		result := make(map[string]interface{})
+		var merges []*yaml.Node
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := ResolveAlias(node.Content[i])
+			value := node.Content[i+1]
+			if key.Kind == yaml.ScalarNode && key.Tag == "!!merge" {
+				merges = append(merges, value)
+				continue
+			}
+			result[key.Value] = c.nodeToInterface(value)
+		}
+		for _, merge := range merges {
+			c.merge(result, merge)
+		}
+		return result
+	default:
+		return nil
+	}
+}
+
+// merge adds the keys of the mappings a << merge key names to result, leaving
+// keys that are already set alone. A list of mappings is merged in order, so
+// earlier mappings win over later ones.
+func (c *nodeConverter) merge(result map[string]interface{}, value *yaml.Node) {
+	sources := []*yaml.Node{value}
+	if ResolveAlias(value).Kind == yaml.SequenceNode {
+		sources = ResolveAlias(value).Content
+	}
+
+	for _, source := range sources {
+		if ResolveAlias(source).Kind != yaml.MappingNode {
+			c.issue(source, "merge key << must refer to a mapping or a list of mappings; kustomize rejects the document")
+			continue
+		}
+		merged, _ := c.nodeToInterface(source).(map[string]interface{})
+		for key, mergedValue := range merged {
+			if _, exists := result[key]; !exists {
+				result[key] = mergedValue
+			}
+		}
+	}
+}
+
+// issue records a problem at node
+func (c *nodeConverter) issue(node *yaml.Node, message string) {
+	c.issues = append(c.issues, ParseIssue{File: c.file, Line: node.Line, Column: node.Column, Message: message})
+}
+
+// ResolveAlias returns the node an alias refers to, or node itself
+func ResolveAlias(node *yaml.Node) *yaml.Node {
+	for node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+	return node
+}
+
+// MappingValue returns the value node of key in a mapping node, resolving
+// aliases and merge keys as the parsed content does: explicit keys win over
+// merged ones, and of several merged mappings the first one wins. It returns
+// nil when the key is not set or node is not a mapping.
+func MappingValue(node *yaml.Node, key string) *yaml.Node {
+	_, value := mappingEntry(node, key, make(map[*yaml.Node]bool))
+	if value == nil {
+		return nil
+	}
+	return ResolveAlias(value)
+}
+
+// mappingEntry looks key up in node and returns its key and value nodes, the
+// value unresolved so that an alias keeps its own position. Mappings already
+// searched are skipped, so aliases used inside their own anchor do not loop.
+func mappingEntry(node *yaml.Node, key string, searched map[*yaml.Node]bool) (*yaml.Node, *yaml.Node) {
+	node = ResolveAlias(node)
+	if node.Kind != yaml.MappingNode || searched[node] {
+		return nil, nil
+	}
+	searched[node] = true
+
+	var merges []*yaml.Node
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		name := ResolveAlias(node.Content[i])
+		if name.Kind == yaml.ScalarNode && name.Tag == "!!merge" {
+			merges = append(merges, node.Content[i+1])
+			continue
+		}
+		if name.Value == key {
+			return name, node.Content[i+1]
+		}
+	}
+	for _, merge := range merges {
+		sources := []*yaml.Node{merge}
+		if ResolveAlias(merge).Kind == yaml.SequenceNode {
+			sources = ResolveAlias(merge).Content
+		}
+		for _, source := range sources {
+			if name, value := mappingEntry(source, key, searched); value != nil {
+				return name, value
+			}
+		}
+	}
+	return nil, nil
+}
+
+// unknownAnchor returns the anchor named by a decoder error about an alias of
+// an undefined anchor
+func unknownAnchor(err error) (string, bool) {
+	match := unknownAnchorError.FindStringSubmatch(err.Error())
+	if match == nil {
+		return "", false
+	}
+	return match[1], true
+}
+
+// aliasPosition returns the first line using *anchor and the column of the
+// alias, since the decoder error does not tell where the alias is; 0 when it
+// cannot be found
+func aliasPosition(data []byte, anchor string) (int, int) {
+	alias := regexp.MustCompile(`(^|[\s\[{,:-])(\*` + regexp.QuoteMeta(anchor) + `)($|[\s\]},])`)
+	for i, line := range bytes.Split(data, []byte("\n")) {
+		if match := alias.FindSubmatchIndex(line); match != nil {
+			return i + 1, utf8.RuneCount(line[:match[4]]) + 1
+		}
+	}
+	return 0, 0
+}
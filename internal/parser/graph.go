@@ -3,7 +3,10 @@ package parser
 import (
 	"fmt"
 	"path/filepath"
+	"sort"
 	"strings"
+
+	"github.com/moon-hex/gitops-validator/internal/pathutil"
 )
 
 // ResourceGraph represents the dependency graph of all resources
@@ -15,6 +18,8 @@ type ResourceGraph struct {
 	ByType       map[ResourceType][]*ParsedResource // Key: resource type
 	// Phase III: Fast lookup index
 	Index *ResourceIndex
+	// Problems found while decoding files, such as aliases of undefined anchors
+	ParseIssues []ParseIssue
 }
 
 // NewResourceGraph creates a new ResourceGraph
@@ -50,24 +55,31 @@ func (g *ResourceGraph) AddResource(resource *ParsedResource) {
 
 // BuildDependencyGraph extracts references and builds the dependency graph
 func (g *ResourceGraph) BuildDependencyGraph(repoPath string) error {
-	for _, resource := range g.Resources {
-		// Extract references from the resource
-		references := ExtractReferences(resource, repoPath)
-		resource.Dependencies = references
-
-		// For each reference, find the target resource and add reverse reference
-		for _, ref := range references {
-			targetResource := g.FindTargetResource(ref, resource, repoPath)
-			if targetResource != nil {
-				targetResource.ReferencedBy = append(targetResource.ReferencedBy, ResourceReference{
-					Type:          ref.Type,
-					Name:          resource.Name,
-					File:          resource.File,
-					Line:          resource.Line,
-					ReferenceType: ref.ReferenceType,
-					Path:          ref.Path,
-					IsRelative:    ref.IsRelative,
-				})
+	// Iterate per file rather than over Resources: resources whose keys collide
+	// (e.g. a kustomization and a Namespace both named "production") are only
+	// stored once in Resources but must all have their references extracted.
+	for _, fileResources := range g.Files {
+		for _, resource := range fileResources {
+			// Extract references from the resource
+			references := ExtractReferences(resource, repoPath)
+			resource.Dependencies = references
+
+			// For each reference, find the target resource and add reverse reference
+			for _, ref := range references {
+				targetResource := g.FindTargetResource(ref, resource, repoPath)
+				if targetResource != nil {
+					targetResource.ReferencedBy = append(targetResource.ReferencedBy, ResourceReference{
+						Type:          ref.Type,
+						Name:          resource.Name,
+						File:          resource.File,
+						Line:          ref.Line,
+						ReferenceType: ref.ReferenceType,
+						Path:          ref.Path,
+						IsRelative:    ref.IsRelative,
+						Kind:          ref.Kind,
+						Namespace:     ref.Namespace,
+					})
+				}
 			}
 		}
 	}
@@ -84,6 +96,9 @@ func (g *ResourceGraph) FindTargetResource(ref ResourceReference, sourceResource
 		// kustomization resources: entries are relative to the kustomization file
 		return g.findResourceByPath(ref.Path, true, sourceResource.File, repoPath)
 	case string(ReferenceTypeSourceRef):
+		if ref.Kind != "" {
+			return g.findSource(ref.Kind, ref.Namespace, ref.Path)
+		}
 		return g.findResourceByName(ref.Path)
 	case string(ReferenceTypeChart):
 		return nil
@@ -94,14 +109,9 @@ func (g *ResourceGraph) FindTargetResource(ref ResourceReference, sourceResource
 
 // findResourceByPath finds a resource by its file path
 func (g *ResourceGraph) findResourceByPath(path string, isRelative bool, sourceFile string, repoPath string) *ParsedResource {
-	var fullPath string
-
-	if isRelative {
-		// Path is relative to the source file
-		fullPath = filepath.Join(filepath.Dir(sourceFile), path)
-	} else {
-		// Path is relative to repo root
-		fullPath = filepath.Join(repoPath, path)
+	fullPath, ok := pathutil.ResolveReference(path, isRelative, sourceFile, repoPath)
+	if !ok {
+		return nil // Remote references never resolve to local resources
 	}
 
 	// Look for resources at the exact path
@@ -128,11 +138,9 @@ func (g *ResourceGraph) findResourceByPath(path string, isRelative bool, sourceF
 // multi-document YAML files (multiple --- sections in one file). Falls back
 // to directory-kustomization probing just like findResourceByPath.
 func (g *ResourceGraph) findAllResourcesByPath(path string, isRelative bool, sourceFile string, repoPath string) []*ParsedResource {
-	var fullPath string
-	if isRelative {
-		fullPath = filepath.Join(filepath.Dir(sourceFile), path)
-	} else {
-		fullPath = filepath.Join(repoPath, path)
+	fullPath, ok := pathutil.ResolveReference(path, isRelative, sourceFile, repoPath)
+	if !ok {
+		return nil
 	}
 
 	if resources, exists := g.Files[fullPath]; exists && len(resources) > 0 {
@@ -182,6 +190,20 @@ func (g *ResourceGraph) findResourceByName(name string) *ParsedResource {
 	return nil
 }
 
+// findSource finds a Flux source (source.toolkit.fluxcd.io) by kind, namespace
+// and name. Resources without a namespace match any namespace.
+func (g *ResourceGraph) findSource(kind, namespace, name string) *ParsedResource {
+	for _, resource := range g.ByKind[kind] {
+		if resource.Name != name || !strings.HasPrefix(resource.APIVersion, "source.toolkit.fluxcd.io/") {
+			continue
+		}
+		if resource.Namespace == "" || namespace == "" || resource.Namespace == namespace {
+			return resource
+		}
+	}
+	return nil
+}
+
 // Query Functions
 
 // GetResource returns a resource by its key
@@ -280,16 +302,45 @@ func (g *ResourceGraph) GetFluxSources() []*ParsedResource {
 	return g.ByType[ResourceTypeFluxSource]
 }
 
+// Subgraph returns a graph holding the resources keep accepts, with the same
+// indexes as the full graph and the parse issues of the files it keeps.
+// Resources are shared with the full graph, not copied, so their references
+// still name resources outside the subgraph; lookups on the subgraph only
+// find the kept ones.
+func (g *ResourceGraph) Subgraph(keep func(*ParsedResource) bool) *ResourceGraph {
+	sub := NewResourceGraph()
+
+	files := make([]string, 0, len(g.Files))
+	for file := range g.Files {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	for _, file := range files {
+		for _, resource := range g.Files[file] {
+			if keep(resource) {
+				sub.AddResource(resource)
+			}
+		}
+	}
+	for _, issue := range g.ParseIssues {
+		if _, kept := sub.Files[issue.File]; kept {
+			sub.ParseIssues = append(sub.ParseIssues, issue)
+		}
+	}
+
+	// Indexing resources the full graph already indexed cannot fail
+	_ = sub.BuildIndex()
+	return sub
+}
+
 // Validation helper functions
 
 // ValidatePathReference checks if a path reference exists
 func (g *ResourceGraph) ValidatePathReference(path string, isRelative bool, sourceFile string, repoPath string) error {
-	var fullPath string
-
-	if isRelative {
-		fullPath = filepath.Join(filepath.Dir(sourceFile), path)
-	} else {
-		fullPath = filepath.Join(repoPath, path)
+	fullPath, ok := pathutil.ResolveReference(path, isRelative, sourceFile, repoPath)
+	if !ok {
+		return nil // Remote references cannot be checked locally
 	}
 
 	// Check if file exists
@@ -320,3 +371,111 @@ func (g *ResourceGraph) ValidateResourceReference(ref ResourceReference) error {
 
 	return nil
 }
+
+// FindResources resolves a user-supplied identifier to matching resources.
+// The identifier may be a resource key ("namespace/name"), a "Kind/name" pair,
+// a bare metadata.name, or a file path (absolute or relative to repoPath).
+func (g *ResourceGraph) FindResources(identifier string, repoPath string) []*ParsedResource {
+	if resource, exists := g.Resources[identifier]; exists {
+		return []*ParsedResource{resource}
+	}
+
+	var matches []*ParsedResource
+
+	// Kind/name form, e.g. "HelmRelease/backend"
+	if parts := strings.SplitN(identifier, "/", 2); len(parts) == 2 {
+		for _, resource := range g.ByKind[parts[0]] {
+			if resource.Name == parts[1] {
+				matches = append(matches, resource)
+			}
+		}
+		if len(matches) > 0 {
+			return matches
+		}
+	}
+
+	// File path, relative to the repository root or as given
+	for _, candidate := range []string{filepath.Join(repoPath, identifier), filepath.Clean(identifier)} {
+		if resources, exists := g.Files[candidate]; exists && len(resources) > 0 {
+			return resources
+		}
+	}
+
+	// Bare metadata.name
+	for _, resource := range g.Resources {
+		if resource.Name == identifier {
+			matches = append(matches, resource)
+		}
+	}
+
+	return matches
+}
+
+// ReferenceStep is a single hop in a reference chain between two resources
+type ReferenceStep struct {
+	Resource  *ParsedResource   // Resource reached by this step
+	Reference ResourceReference // Reference followed to reach Resource (zero value for the start)
+}
+
+// ShortestReferencePath returns the shortest chain of references leading from any
+// resource in from to any resource in to, following Dependencies breadth-first.
+// The first step is the starting resource. Returns nil when no chain exists.
+func (g *ResourceGraph) ShortestReferencePath(from, to []*ParsedResource, repoPath string) []ReferenceStep {
+	targets := make(map[*ParsedResource]bool, len(to))
+	for _, resource := range to {
+		targets[resource] = true
+	}
+
+	parents := make(map[*ParsedResource]ReferenceStep)
+	visited := make(map[*ParsedResource]bool)
+	var queue []*ParsedResource
+	for _, resource := range from {
+		if !visited[resource] {
+			visited[resource] = true
+			queue = append(queue, resource)
+		}
+	}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		if targets[current] {
+			return buildReferenceChain(current, parents)
+		}
+
+		for _, dep := range current.Dependencies {
+			for _, next := range g.FindAllTargetResources(dep, current, repoPath) {
+				if visited[next] {
+					continue
+				}
+				visited[next] = true
+				parents[next] = ReferenceStep{Resource: current, Reference: dep}
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	return nil
+}
+
+// buildReferenceChain walks the BFS parent links back from end to the start resource
+func buildReferenceChain(end *ParsedResource, parents map[*ParsedResource]ReferenceStep) []ReferenceStep {
+	var chain []ReferenceStep
+	current := end
+	for {
+		parent, hasParent := parents[current]
+		if !hasParent {
+			chain = append(chain, ReferenceStep{Resource: current})
+			break
+		}
+		chain = append(chain, ReferenceStep{Resource: current, Reference: parent.Reference})
+		current = parent.Resource
+	}
+
+	// Reverse so the chain reads from start to end
+	for i, j := 0, len(chain)-1; i < j; i, j = i+1, j-1 {
+		chain[i], chain[j] = chain[j], chain[i]
+	}
+	return chain
+}
@@ -27,6 +27,7 @@ type ResourceIndex struct {
 	fluxKustomizations       []*ParsedResource
 	kubernetesKustomizations []*ParsedResource
 	helmReleases             []*ParsedResource
+	fluxSources              []*ParsedResource
 	otherResources           []*ParsedResource
 
 	// Dependency graph for fast traversal
@@ -45,6 +46,7 @@ func NewResourceIndex() *ResourceIndex {
 		fluxKustomizations:       make([]*ParsedResource, 0),
 		kubernetesKustomizations: make([]*ParsedResource, 0),
 		helmReleases:             make([]*ParsedResource, 0),
+		fluxSources:              make([]*ParsedResource, 0),
 		otherResources:           make([]*ParsedResource, 0),
 		dependencyGraph:          make(map[string][]string),
 		reverseDependencies:      make(map[string][]string),
@@ -111,6 +113,8 @@ func (ri *ResourceIndex) categorizeResource(resource *ParsedResource) {
 		ri.kubernetesKustomizations = append(ri.kubernetesKustomizations, resource)
 	case ri.isHelmRelease(resource):
 		ri.helmReleases = append(ri.helmReleases, resource)
+	case ri.isFluxSource(resource):
+		ri.fluxSources = append(ri.fluxSources, resource)
 	default:
 		ri.otherResources = append(ri.otherResources, resource)
 	}
@@ -178,6 +182,12 @@ func (ri *ResourceIndex) GetHelmReleases() []*ParsedResource {
 	return ri.helmReleases
 }
 
+// GetFluxSources returns all Flux source resources (GitRepository,
+// OCIRepository, HelmRepository, Bucket)
+func (ri *ResourceIndex) GetFluxSources() []*ParsedResource {
+	return ri.fluxSources
+}
+
 // GetDependencies returns direct dependencies of a resource
 func (ri *ResourceIndex) GetDependencies(filePath string) []string {
 	return ri.dependencyGraph[filePath]
@@ -210,6 +220,7 @@ func (ri *ResourceIndex) GetIndexStats() map[string]interface{} {
 		"flux_kustomizations":       len(ri.fluxKustomizations),
 		"kubernetes_kustomizations": len(ri.kubernetesKustomizations),
 		"helm_releases":             len(ri.helmReleases),
+		"flux_sources":              len(ri.fluxSources),
 		"other_resources":           len(ri.otherResources),
 		"unique_api_versions":       len(ri.byAPIVersionKind),
 		"unique_kinds":              ri.countUniqueKinds(),
@@ -242,6 +253,7 @@ func (ri *ResourceIndex) clear() {
 	ri.fluxKustomizations = make([]*ParsedResource, 0)
 	ri.kubernetesKustomizations = make([]*ParsedResource, 0)
 	ri.helmReleases = make([]*ParsedResource, 0)
+	ri.fluxSources = make([]*ParsedResource, 0)
 	ri.otherResources = make([]*ParsedResource, 0)
 	ri.dependencyGraph = make(map[string][]string)
 	ri.reverseDependencies = make(map[string][]string)
@@ -263,3 +275,7 @@ func (ri *ResourceIndex) isHelmRelease(resource *ParsedResource) bool {
 	return resource.APIVersion == "helm.toolkit.fluxcd.io/v2beta1" &&
 		resource.Kind == "HelmRelease"
 }
+
+func (ri *ResourceIndex) isFluxSource(resource *ParsedResource) bool {
+	return ClassifyResource(resource) == ResourceTypeFluxSource
+}
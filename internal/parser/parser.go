@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"bytes"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -51,7 +52,7 @@ func (p *ResourceParser) ParseAllResources() (*ResourceGraph, error) {
 			return nil
 		}
 
-		resources, err := p.ParseFile(path)
+		resources, issues, err := p.parseFile(path)
 		if err != nil {
 			// Log error but continue parsing other files
 			fmt.Printf("Warning: Failed to parse file %s: %v\n", path, err)
@@ -61,6 +62,7 @@ func (p *ResourceParser) ParseAllResources() (*ResourceGraph, error) {
 		for _, resource := range resources {
 			graph.AddResource(resource)
 		}
+		graph.ParseIssues = append(graph.ParseIssues, issues...)
 
 		return nil
 	})
@@ -70,7 +72,7 @@ func (p *ResourceParser) ParseAllResources() (*ResourceGraph, error) {
 	}
 
 	// Extract references and build the dependency graph
-	if err := graph.BuildDependencyGraph(p.repoPath); err != nil {
+	if err := graph.BuildDependencyGraph(p.config.ResolveFluxRoot(p.repoPath)); err != nil {
 		return nil, fmt.Errorf("failed to build dependency graph: %w", err)
 	}
 
@@ -79,72 +81,78 @@ func (p *ResourceParser) ParseAllResources() (*ResourceGraph, error) {
 
 // ParseFile parses a single YAML file and extracts all resources (handles --- delimited resources)
 func (p *ResourceParser) ParseFile(filePath string) ([]*ParsedResource, error) {
-	file, err := os.Open(filePath)
+	resources, _, err := p.parseFile(filePath)
+	return resources, err
+}
+
+// parseFile parses a single YAML file into resources and the problems found
+// while decoding it. A document referencing an undefined anchor stops the
+// decoder, so it and the documents after it are reported and skipped.
+func (p *ResourceParser) parseFile(filePath string) ([]*ParsedResource, []ParseIssue, error) {
+	data, err := os.ReadFile(filePath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open file %s: %w", filePath, err)
+		return nil, nil, fmt.Errorf("failed to open file %s: %w", filePath, err)
 	}
-	defer file.Close()
 
 	var resources []*ParsedResource
-	decoder := yaml.NewDecoder(file)
+	var issues []ParseIssue
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
 
 	for {
 		var doc yaml.Node
 		err := decoder.Decode(&doc)
 		if err != nil {
-			break // End of file or error
+			// End of file or error
+			if anchor, ok := unknownAnchor(err); ok {
+				line, column := aliasPosition(data, anchor)
+				issues = append(issues, ParseIssue{
+					File:    filePath,
+					Line:    line,
+					Column:  column,
+					Message: fmt.Sprintf("alias *%s refers to an anchor that is not defined in its document; kustomize and Flux reject the file, and it is not validated from this document on", anchor),
+				})
+			}
+			break
 		}
 
 		if doc.Kind == yaml.DocumentNode && len(doc.Content) > 0 {
-			resource := p.parseResourceNode(doc.Content[0], filePath)
+			resource, documentIssues := p.parseResourceNode(doc.Content[0], filePath)
+			issues = append(issues, documentIssues...)
 			if resource != nil {
+				// A comment separated from the first key by a blank line belongs to the document
+				resource.Suppressions = append(resource.Suppressions, parseSuppressionComment(doc.HeadComment, 0)...)
 				resources = append(resources, resource)
 			}
 		}
 	}
 
-	return resources, nil
+	return resources, issues, nil
 }
 
-// parseResourceNode parses a single YAML document node into a ParsedResource
-func (p *ResourceParser) parseResourceNode(node *yaml.Node, filePath string) *ParsedResource {
+// parseResourceNode parses a single YAML document node into a ParsedResource,
+// resolving aliases and merge keys as kustomize does
+func (p *ResourceParser) parseResourceNode(node *yaml.Node, filePath string) (*ParsedResource, []ParseIssue) {
 	if node.Kind != yaml.MappingNode {
-		return nil
+		return nil, nil
 	}
 
-	var apiVersion, kind, name, namespace string
-	var line int
-	content := make(map[string]interface{})
-
-	// Extract basic fields and build content map
-	for i := 0; i < len(node.Content); i += 2 {
-		key := node.Content[i]
-		value := node.Content[i+1]
-
-		if key.Value == "apiVersion" {
-			apiVersion = value.Value
-			line = value.Line
-		} else if key.Value == "kind" {
-			kind = value.Value
-		} else if key.Value == "metadata" {
-			if value.Kind == yaml.MappingNode {
-				for j := 0; j < len(value.Content); j += 2 {
-					if value.Content[j].Value == "name" {
-						name = value.Content[j+1].Value
-					} else if value.Content[j].Value == "namespace" {
-						namespace = value.Content[j+1].Value
-					}
-				}
-			}
-		}
+	converter := newNodeConverter(filePath, node)
+	content, _ := converter.nodeToInterface(node).(map[string]interface{})
+
+	apiVersion, _ := content["apiVersion"].(string)
+	kind, _ := content["kind"].(string)
+	metadata, _ := content["metadata"].(map[string]interface{})
+	name, _ := metadata["name"].(string)
+	namespace, _ := metadata["namespace"].(string)
 
-		// Build content map for further processing
-		content[key.Value] = p.nodeToInterface(value)
+	line, column := node.Line, node.Column
+	if value := MappingValue(node, "apiVersion"); value != nil {
+		line, column = value.Line, value.Column
 	}
 
 	// Skip if not a valid Kubernetes resource
 	if apiVersion == "" || kind == "" {
-		return nil
+		return nil, converter.issues
 	}
 	// kustomize.config.k8s.io Kustomization files never carry metadata.name —
 	// the file path is their identity. Use a path-derived synthetic name so
@@ -154,38 +162,17 @@ func (p *ResourceParser) parseResourceNode(node *yaml.Node, filePath string) *Pa
 	}
 
 	resource := &ParsedResource{
-		File:       filePath,
-		Line:       line,
-		APIVersion: apiVersion,
-		Kind:       kind,
-		Name:       name,
-		Namespace:  namespace,
-		Content:    content,
+		File:         filePath,
+		Line:         line,
+		Column:       column,
+		APIVersion:   apiVersion,
+		Kind:         kind,
+		Name:         name,
+		Namespace:    namespace,
+		Content:      content,
+		Suppressions: extractSuppressions(node),
+		Node:         node,
 	}
 
-	return resource
-}
-
-// nodeToInterface converts a YAML node to a Go interface{}
-func (p *ResourceParser) nodeToInterface(node *yaml.Node) interface{} {
-	switch node.Kind {
-	case yaml.ScalarNode:
-		return node.Value
-	case yaml.SequenceNode:
-		var result []interface{}
-		for _, item := range node.Content {
-			result = append(result, p.nodeToInterface(item))
-		}
-		return result
-	case yaml.MappingNode:
-		result := make(map[string]interface{})
-		for i := 0; i < len(node.Content); i += 2 {
-			key := node.Content[i]
-			value := node.Content[i+1]
-			result[key.Value] = p.nodeToInterface(value)
-		}
-		return result
-	default:
-		return nil
-	}
+	return resource, converter.issues
 }
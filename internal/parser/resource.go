@@ -3,13 +3,17 @@ package parser
 import (
 	"fmt"
 	"path/filepath"
+	"strconv"
 	"strings"
+
+	"gopkg.in/yaml.v3"
 )
 
 // ParsedResource represents a parsed Kubernetes resource
 type ParsedResource struct {
 	File         string                 // Source file path
 	Line         int                    // Line number in file
+	Column       int                    // Column of the apiVersion value on Line
 	APIVersion   string                 // apiVersion
 	Kind         string                 // kind
 	Name         string                 // metadata.name
@@ -17,6 +21,8 @@ type ParsedResource struct {
 	Content      map[string]interface{} // Full resource content
 	Dependencies []ResourceReference    // What this resource references
 	ReferencedBy []ResourceReference    // What references this resource
+	Suppressions []Suppression          // Rules disabled by gitops-validator:disable comments
+	Node         *yaml.Node             // Document node, for the positions of nested fields
 }
 
 // ResourceReference represents a reference from one resource to another
@@ -28,6 +34,8 @@ type ResourceReference struct {
 	ReferenceType string // "path", "sourceRef", "chart", etc.
 	Path          string // The actual path/reference value
 	IsRelative    bool   // Whether the path is relative to the file or repo root
+	Kind          string // Kind of the referenced source, for sourceRef references
+	Namespace     string // Namespace of the referenced source, for sourceRef references
 }
 
 // ResourceType represents the type of a resource
@@ -65,6 +73,95 @@ func (r *ParsedResource) GetResourceKey() string {
 	return r.Name
 }
 
+// Position is a place in a file; lines and columns start at 1
+type Position struct {
+	Line   int
+	Column int
+}
+
+// FieldPosition returns the position of the value of a nested field, given as
+// mapping keys and sequence indexes, e.g. FieldPosition("resources", "2").
+// Mappings and lists, which start on the line after their key, are placed at
+// the key. Aliases and merge keys are resolved; when the field is not set, the
+// resource's position is returned.
+func (r *ParsedResource) FieldPosition(path ...string) Position {
+	key, value := r.fieldNodes(path)
+	if value == nil {
+		return Position{Line: r.Line, Column: r.Column}
+	}
+	if resolved := ResolveAlias(value); resolved.Kind == yaml.MappingNode || resolved.Kind == yaml.SequenceNode {
+		value = key
+	}
+	return Position{Line: value.Line, Column: value.Column}
+}
+
+// KeyPosition returns the position of the key of a nested field, such as a
+// variable name, or of the entry for a sequence index; as FieldPosition
+// otherwise
+func (r *ParsedResource) KeyPosition(path ...string) Position {
+	key, _ := r.fieldNodes(path)
+	if key == nil {
+		return Position{Line: r.Line, Column: r.Column}
+	}
+	return Position{Line: key.Line, Column: key.Column}
+}
+
+// FieldLine returns the line of a nested field, as FieldPosition does
+func (r *ParsedResource) FieldLine(path ...string) int {
+	return r.FieldPosition(path...).Line
+}
+
+// fieldNodes returns the key and value nodes of a nested field, or nils when
+// it is not set. For a sequence index, both are the entry. The value is not
+// resolved, so an alias is placed where it is used rather than at its anchor.
+func (r *ParsedResource) fieldNodes(path []string) (*yaml.Node, *yaml.Node) {
+	var key *yaml.Node
+	value := r.Node
+	for _, step := range path {
+		if value == nil {
+			return nil, nil
+		}
+		node := ResolveAlias(value)
+		if node.Kind == yaml.SequenceNode {
+			index, err := strconv.Atoi(step)
+			if err != nil || index < 0 || index >= len(node.Content) {
+				return nil, nil
+			}
+			key, value = node.Content[index], node.Content[index]
+			continue
+		}
+		key, value = mappingEntry(node, step, make(map[*yaml.Node]bool))
+	}
+	if len(path) == 0 || value == nil {
+		return nil, nil
+	}
+	return key, value
+}
+
+// ListEntries returns the string entries of a top-level list field, or with
+// a key the string values of that key in its mapping entries, along with the
+// position of each value. Other entries are skipped.
+func (r *ParsedResource) ListEntries(field, key string) ([]string, []Position) {
+	var values []string
+	var positions []Position
+
+	entries, _ := r.Content[field].([]interface{})
+	for i, entry := range entries {
+		path := []string{field, strconv.Itoa(i)}
+		if key != "" {
+			fields, _ := entry.(map[string]interface{})
+			entry = fields[key]
+			path = append(path, key)
+		}
+		if value, ok := entry.(string); ok {
+			values = append(values, value)
+			positions = append(positions, r.FieldPosition(path...))
+		}
+	}
+
+	return values, positions
+}
+
 // ClassifyResource determines the type of a resource
 func ClassifyResource(resource *ParsedResource) ResourceType {
 	switch {
@@ -78,6 +175,10 @@ func ClassifyResource(resource *ParsedResource) ResourceType {
 		return ResourceTypeFluxSource
 	case resource.Kind == "HelmRepository" && strings.HasPrefix(resource.APIVersion, "source.toolkit.fluxcd.io/"):
 		return ResourceTypeFluxSource
+	case resource.Kind == "OCIRepository" && strings.HasPrefix(resource.APIVersion, "source.toolkit.fluxcd.io/"):
+		return ResourceTypeFluxSource
+	case resource.Kind == "Bucket" && strings.HasPrefix(resource.APIVersion, "source.toolkit.fluxcd.io/"):
+		return ResourceTypeFluxSource
 	case resource.Kind == "ImageRepository" && strings.HasPrefix(resource.APIVersion, "image.toolkit.fluxcd.io/"):
 		return ResourceTypeFluxImage
 	case resource.Kind == "ImagePolicy" && strings.HasPrefix(resource.APIVersion, "image.toolkit.fluxcd.io/"):
@@ -139,26 +240,16 @@ func extractFluxKustomizationReferences(resource *ParsedResource, repoPath strin
 				Type:          "flux-kustomization-path",
 				Name:          resource.Name,
 				File:          resource.File,
-				Line:          resource.Line,
+				Line:          resource.FieldLine("spec", "path"),
 				ReferenceType: string(ReferenceTypePath),
 				Path:          path,
 				IsRelative:    false, // Flux paths are relative to repo root
 			})
 		}
 
-		// Extract sourceRef reference
-		if sourceRef, ok := spec["sourceRef"].(map[string]interface{}); ok {
-			if name, ok := sourceRef["name"].(string); ok {
-				references = append(references, ResourceReference{
-					Type:          "flux-source",
-					Name:          name,
-					File:          resource.File,
-					Line:          resource.Line,
-					ReferenceType: string(ReferenceTypeSourceRef),
-					Path:          name,
-					IsRelative:    false,
-				})
-			}
+		// Extract sourceRef reference (GitRepository, OCIRepository or Bucket)
+		if ref, ok := sourceReference("flux-source", spec["sourceRef"], resource, resource.FieldLine("spec", "sourceRef")); ok {
+			references = append(references, ref)
 		}
 	}
 
@@ -171,13 +262,13 @@ func extractKubernetesKustomizationReferences(resource *ParsedResource, repoPath
 
 	// Extract resources references (relative to kustomization file)
 	if resources, ok := resource.Content["resources"].([]interface{}); ok {
-		for _, res := range resources {
+		for i, res := range resources {
 			if resPath, ok := res.(string); ok {
 				references = append(references, ResourceReference{
 					Type:          "kustomization-resource",
 					Name:          resource.Name,
 					File:          resource.File,
-					Line:          resource.Line,
+					Line:          resource.FieldLine("resources", strconv.Itoa(i)),
 					ReferenceType: string(ReferenceTypeResource),
 					Path:          resPath,
 					IsRelative:    true, // K8s kustomization paths are relative to the file
@@ -188,14 +279,14 @@ func extractKubernetesKustomizationReferences(resource *ParsedResource, repoPath
 
 	// Extract patches references
 	if patches, ok := resource.Content["patches"].([]interface{}); ok {
-		for _, patch := range patches {
+		for i, patch := range patches {
 			if patchMap, ok := patch.(map[string]interface{}); ok {
 				if path, ok := patchMap["path"].(string); ok {
 					references = append(references, ResourceReference{
 						Type:          "kustomization-patch",
 						Name:          resource.Name,
 						File:          resource.File,
-						Line:          resource.Line,
+						Line:          resource.FieldLine("patches", strconv.Itoa(i), "path"),
 						ReferenceType: string(ReferenceTypePath),
 						Path:          path,
 						IsRelative:    true, // K8s kustomization paths are relative to the file
@@ -207,13 +298,13 @@ func extractKubernetesKustomizationReferences(resource *ParsedResource, repoPath
 
 	// Extract patchesStrategicMerge references
 	if patches, ok := resource.Content["patchesStrategicMerge"].([]interface{}); ok {
-		for _, patch := range patches {
+		for i, patch := range patches {
 			if patchPath, ok := patch.(string); ok {
 				references = append(references, ResourceReference{
 					Type:          "kustomization-patch-strategic",
 					Name:          resource.Name,
 					File:          resource.File,
-					Line:          resource.Line,
+					Line:          resource.FieldLine("patchesStrategicMerge", strconv.Itoa(i)),
 					ReferenceType: string(ReferenceTypePath),
 					Path:          patchPath,
 					IsRelative:    true, // K8s kustomization paths are relative to the file
@@ -222,9 +313,186 @@ func extractKubernetesKustomizationReferences(resource *ParsedResource, repoPath
 		}
 	}
 
+	// Extract patchesJson6902 references; entries with an inline patch have no path
+	if patches, ok := resource.Content["patchesJson6902"].([]interface{}); ok {
+		for i, patch := range patches {
+			if patchMap, ok := patch.(map[string]interface{}); ok {
+				if path, ok := patchMap["path"].(string); ok {
+					references = append(references, ResourceReference{
+						Type:          "kustomization-patch-json6902",
+						Name:          resource.Name,
+						File:          resource.File,
+						Line:          resource.FieldLine("patchesJson6902", strconv.Itoa(i), "path"),
+						ReferenceType: string(ReferenceTypePath),
+						Path:          path,
+						IsRelative:    true, // K8s kustomization paths are relative to the file
+					})
+				}
+			}
+		}
+	}
+
+	// Extract configMapGenerator and secretGenerator file references, so the
+	// files they read count as referenced
+	for _, file := range KustomizationGeneratorFiles(resource.Content) {
+		references = append(references, ResourceReference{
+			Type:          "kustomization-generator-file",
+			Name:          resource.Name,
+			File:          resource.File,
+			Line:          resource.Line,
+			ReferenceType: string(ReferenceTypePath),
+			Path:          file.Path,
+			IsRelative:    true, // K8s kustomization paths are relative to the file
+		})
+	}
+
+	// Extract replacements loaded from files
+	for _, path := range KustomizationReplacementFiles(resource.Content) {
+		references = append(references, ResourceReference{
+			Type:          "kustomization-replacement",
+			Name:          resource.Name,
+			File:          resource.File,
+			Line:          resource.Line,
+			ReferenceType: string(ReferenceTypePath),
+			Path:          path,
+			IsRelative:    true, // K8s kustomization paths are relative to the file
+		})
+	}
+
+	// Extract the other files kustomize reads: CRD schemas, transformer
+	// configurations, plugin configurations given as files and the openapi
+	// schema
+	for _, path := range kustomizationAuxiliaryFiles(resource.Content) {
+		references = append(references, ResourceReference{
+			Type:          "kustomization-file",
+			Name:          resource.Name,
+			File:          resource.File,
+			Line:          resource.Line,
+			ReferenceType: string(ReferenceTypePath),
+			Path:          path,
+			IsRelative:    true, // K8s kustomization paths are relative to the file
+		})
+	}
+
 	return references
 }
 
+// KustomizationFileEntries returns every path a kustomization reads files
+// from, relative to the kustomization file: resources and components, patch
+// files, generator and replacement sources, crds, configurations, the
+// transformers, generators and validators given as files, and the openapi
+// schema. Inline YAML entries of transformers, generators and validators are
+// skipped.
+func KustomizationFileEntries(content map[string]interface{}) []string {
+	var paths []string
+	for _, field := range []string{"resources", "components", "patchesStrategicMerge"} {
+		entries, _ := content[field].([]interface{})
+		for _, entry := range entries {
+			if path, ok := entry.(string); ok && path != "" {
+				paths = append(paths, path)
+			}
+		}
+	}
+	paths = append(paths, kustomizationAuxiliaryFiles(content)...)
+	for _, field := range []string{"patches", "patchesJson6902"} {
+		entries, _ := content[field].([]interface{})
+		for _, entry := range entries {
+			fields, _ := entry.(map[string]interface{})
+			if path, ok := fields["path"].(string); ok && path != "" {
+				paths = append(paths, path)
+			}
+		}
+	}
+	for _, file := range KustomizationGeneratorFiles(content) {
+		paths = append(paths, file.Path)
+	}
+	return append(paths, KustomizationReplacementFiles(content)...)
+}
+
+// kustomizationAuxiliaryFiles returns the files of the crds, configurations,
+// transformers, generators and validators lists and the openapi schema.
+// Inline YAML entries of the plugin lists are skipped.
+func kustomizationAuxiliaryFiles(content map[string]interface{}) []string {
+	var paths []string
+	for _, field := range []string{"crds", "configurations", "transformers", "generators", "validators"} {
+		entries, _ := content[field].([]interface{})
+		for _, entry := range entries {
+			if path, ok := entry.(string); ok && path != "" && !strings.Contains(path, "\n") {
+				paths = append(paths, path)
+			}
+		}
+	}
+	if openapi, ok := content["openapi"].(map[string]interface{}); ok {
+		if path, ok := openapi["path"].(string); ok && path != "" {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// KustomizationReplacementFiles returns the files replacements entries load
+// with path, relative to the kustomization file
+func KustomizationReplacementFiles(content map[string]interface{}) []string {
+	var files []string
+	entries, _ := content["replacements"].([]interface{})
+	for _, entry := range entries {
+		fields, _ := entry.(map[string]interface{})
+		if path, ok := fields["path"].(string); ok && path != "" {
+			files = append(files, path)
+		}
+	}
+	return files
+}
+
+// GeneratorFile is a file read by a configMapGenerator or secretGenerator entry
+type GeneratorFile struct {
+	Generator string // configMapGenerator or secretGenerator
+	Name      string // Name of the generated ConfigMap or Secret
+	Path      string // Path relative to the kustomization file
+}
+
+// KustomizationGeneratorFiles returns the files read by the generators of a
+// kustomization: files entries, with any key= prefix removed, envs entries
+// and the deprecated env field
+func KustomizationGeneratorFiles(content map[string]interface{}) []GeneratorFile {
+	var files []GeneratorFile
+	for _, generator := range []string{"configMapGenerator", "secretGenerator"} {
+		entries, _ := content[generator].([]interface{})
+		for _, entry := range entries {
+			fields, ok := entry.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			name, _ := fields["name"].(string)
+			add := func(path string) {
+				if path != "" {
+					files = append(files, GeneratorFile{Generator: generator, Name: name, Path: path})
+				}
+			}
+
+			sources, _ := fields["files"].([]interface{})
+			for _, source := range sources {
+				if text, ok := source.(string); ok {
+					if _, path, hasKey := strings.Cut(text, "="); hasKey {
+						text = path
+					}
+					add(text)
+				}
+			}
+			envs, _ := fields["envs"].([]interface{})
+			for _, env := range envs {
+				if text, ok := env.(string); ok {
+					add(text)
+				}
+			}
+			if env, ok := fields["env"].(string); ok {
+				add(env)
+			}
+		}
+	}
+	return files
+}
+
 // extractHelmReleaseReferences extracts references from HelmRelease resources
 func extractHelmReleaseReferences(resource *ParsedResource, repoPath string) []ResourceReference {
 	var references []ResourceReference
@@ -238,7 +506,7 @@ func extractHelmReleaseReferences(resource *ParsedResource, repoPath string) []R
 						Type:          "helm-chart",
 						Name:          resource.Name,
 						File:          resource.File,
-						Line:          resource.Line,
+						Line:          resource.FieldLine("spec", "chart", "spec", "chart"),
 						ReferenceType: string(ReferenceTypeChart),
 						Path:          chart,
 						IsRelative:    false,
@@ -246,22 +514,43 @@ func extractHelmReleaseReferences(resource *ParsedResource, repoPath string) []R
 				}
 
 				// Extract sourceRef reference
-				if sourceRef, ok := spec["sourceRef"].(map[string]interface{}); ok {
-					if name, ok := sourceRef["name"].(string); ok {
-						references = append(references, ResourceReference{
-							Type:          "helm-source",
-							Name:          name,
-							File:          resource.File,
-							Line:          resource.Line,
-							ReferenceType: string(ReferenceTypeSourceRef),
-							Path:          name,
-							IsRelative:    false,
-						})
-					}
+				if ref, ok := sourceReference("helm-source", spec["sourceRef"], resource, resource.FieldLine("spec", "chart", "spec", "sourceRef")); ok {
+					references = append(references, ref)
 				}
 			}
 		}
+
+		// Extract chartRef reference (an OCIRepository or HelmChart holding the chart)
+		if ref, ok := sourceReference("helm-chart-ref", spec["chartRef"], resource, resource.FieldLine("spec", "chartRef")); ok {
+			references = append(references, ref)
+		}
 	}
 
 	return references
 }
+
+// sourceReference builds a sourceRef reference from a {kind, name, namespace}
+// map at line; the namespace defaults to the referencing resource's namespace
+func sourceReference(refType string, value interface{}, resource *ParsedResource, line int) (ResourceReference, bool) {
+	sourceRef, _ := value.(map[string]interface{})
+	name, _ := sourceRef["name"].(string)
+	if name == "" {
+		return ResourceReference{}, false
+	}
+	kind, _ := sourceRef["kind"].(string)
+	namespace, _ := sourceRef["namespace"].(string)
+	if namespace == "" {
+		namespace = resource.Namespace
+	}
+	return ResourceReference{
+		Type:          refType,
+		Name:          name,
+		File:          resource.File,
+		Line:          line,
+		ReferenceType: string(ReferenceTypeSourceRef),
+		Path:          name,
+		IsRelative:    false,
+		Kind:          kind,
+		Namespace:     namespace,
+	}, true
+}
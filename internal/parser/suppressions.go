@@ -0,0 +1,82 @@
+package parser
+
+import (
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SuppressionDirective is the comment marker that disables rules for a
+// resource or a single line, e.g. "# gitops-validator:disable GV0009"
+const SuppressionDirective = "gitops-validator:disable"
+
+// Suppression is a rule suppression declared in a YAML comment
+type Suppression struct {
+	Rules []string // Rule IDs, rule names or result types; empty disables every rule
+	Line  int      // Line the suppression applies to; 0 for the whole resource
+}
+
+// AppliesTo reports whether the suppression covers the given rule identifiers
+// (any of rule ID, rule name or result type) at the given line
+func (s Suppression) AppliesTo(line int, identifiers ...string) bool {
+	if s.Line != 0 && s.Line != line {
+		return false
+	}
+	if len(s.Rules) == 0 {
+		return true
+	}
+	for _, rule := range s.Rules {
+		for _, id := range identifiers {
+			if id != "" && strings.EqualFold(rule, id) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// extractSuppressions collects suppression comments from a resource's root
+// mapping node. A comment above the first key applies to the whole resource;
+// a comment above a nested node or at the end of a line applies to that line.
+func extractSuppressions(node *yaml.Node) []Suppression {
+	var suppressions []Suppression
+	suppressions = append(suppressions, parseSuppressionComment(node.HeadComment, 0)...)
+
+	var walk func(n *yaml.Node, resourceLevel bool)
+	walk = func(n *yaml.Node, resourceLevel bool) {
+		if resourceLevel {
+			suppressions = append(suppressions, parseSuppressionComment(n.HeadComment, 0)...)
+		} else {
+			suppressions = append(suppressions, parseSuppressionComment(n.HeadComment, n.Line)...)
+		}
+		suppressions = append(suppressions, parseSuppressionComment(n.LineComment, n.Line)...)
+		for _, child := range n.Content {
+			walk(child, false)
+		}
+	}
+
+	for i, child := range node.Content {
+		walk(child, i == 0)
+	}
+
+	return suppressions
+}
+
+// parseSuppressionComment parses every directive in a (possibly multi-line)
+// comment. Rule lists may be separated by spaces or commas.
+func parseSuppressionComment(comment string, line int) []Suppression {
+	var suppressions []Suppression
+	for _, text := range strings.Split(comment, "\n") {
+		text = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(text), "#"))
+		rest, found := strings.CutPrefix(text, SuppressionDirective)
+		if !found || (rest != "" && rest[0] != ' ' && rest[0] != '\t') {
+			continue
+		}
+
+		rules := strings.FieldsFunc(rest, func(r rune) bool {
+			return r == ',' || r == ' ' || r == '\t'
+		})
+		suppressions = append(suppressions, Suppression{Rules: rules, Line: line})
+	}
+	return suppressions
+}
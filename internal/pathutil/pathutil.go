@@ -0,0 +1,107 @@
+// Package pathutil provides the single path resolution implementation shared
+// by the resource graph, the validators and chart generation, so that every
+// component agrees on how "./", absolute and remote references are handled.
+package pathutil
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// remotePrefixes are reference prefixes that point outside the local checkout
+var remotePrefixes = []string{
+	"http://",
+	"https://",
+	"ssh://",
+	"git://",
+	"git::",
+	"git@",
+	"oci://",
+	"github.com/",
+	"gitlab.com/",
+	"bitbucket.org/",
+}
+
+// IsRemote reports whether a reference points to a remote location (URL,
+// git remote, OCI artifact) rather than a path in the repository.
+func IsRemote(path string) bool {
+	for _, prefix := range remotePrefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Normalize normalizes a path reference:
+// - Skips remote references (second return value is false)
+// - Strips leading "./" segments
+// - Keeps absolute paths (starting with "/") as they are
+func Normalize(path string) (string, bool) {
+	if path == "" || IsRemote(path) {
+		return "", false
+	}
+
+	if strings.HasPrefix(path, "/") {
+		return path, true
+	}
+
+	for strings.HasPrefix(path, "./") {
+		path = strings.TrimPrefix(path, "./")
+	}
+	return path, true
+}
+
+// Resolve resolves a path reference against a base directory. Absolute paths
+// are anchored at baseDir, matching how Flux treats spec.path relative to the
+// source root. Remote references are not resolved (second return value is false).
+func Resolve(baseDir, path string) (string, bool) {
+	normalized, ok := Normalize(path)
+	if !ok {
+		return "", false
+	}
+
+	return filepath.Join(baseDir, normalized), true
+}
+
+// ResolveReference resolves a reference found in sourceFile. Relative
+// references (kustomization resources, patches) resolve against the directory
+// of sourceFile; all others (Flux spec.path) resolve against repoPath.
+func ResolveReference(path string, isRelative bool, sourceFile, repoPath string) (string, bool) {
+	if isRelative {
+		return Resolve(filepath.Dir(sourceFile), path)
+	}
+	return Resolve(repoPath, path)
+}
+
+// MatchPattern matches a repository-relative path against a glob pattern.
+// A "**" segment matches any number of directories, including none, so
+// "apps/**" matches the apps directory and everything below it and
+// "**/secrets/*.yaml" matches a secrets directory at any depth. Other
+// segments use path.Match and never match across a "/".
+func MatchPattern(path, pattern string) bool {
+	return matchSegments(strings.Split(filepath.ToSlash(path), "/"), strings.Split(filepath.ToSlash(pattern), "/"))
+}
+
+// matchSegments matches path segments against pattern segments
+func matchSegments(segments, pattern []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for skip := 0; skip <= len(segments); skip++ {
+				if matchSegments(segments[skip:], pattern[1:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(segments) == 0 {
+			return false
+		}
+		if matched, _ := path.Match(pattern[0], segments[0]); !matched {
+			return false
+		}
+		segments, pattern = segments[1:], pattern[1:]
+	}
+	return len(segments) == 0
+}
@@ -0,0 +1,159 @@
+package pathutil
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestIsRemote(t *testing.T) {
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"https://github.com/org/repo//deploy?ref=v1.2.0", true},
+		{"http://example.com/manifests.yaml", true},
+		{"ssh://git@github.com/org/repo.git?ref=main", true},
+		{"git::https://github.com/org/repo.git//base?ref=main", true},
+		{"git@github.com:org/repo.git//base?ref=v1", true},
+		{"oci://ghcr.io/org/manifests", true},
+		{"github.com/org/repo/config/default?ref=v0.1.0", true},
+		{"gitlab.com/org/repo//base", true},
+		{"bitbucket.org/org/repo", true},
+		{"./apps/web", false},
+		{"../base", false},
+		{"/clusters/production", false},
+		{"deployment.yaml", false},
+		{"github.company.internal/base", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := IsRemote(tt.path); got != tt.want {
+			t.Errorf("IsRemote(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		path   string
+		want   string
+		wantOK bool
+	}{
+		{"./apps/web", "apps/web", true},
+		{"././apps", "apps", true},
+		{"apps/web", "apps/web", true},
+		{"../base", "../base", true},
+		{"./../../outside", "../../outside", true},
+		{"/clusters/production", "/clusters/production", true},
+		{"./", "", true},
+		{"", "", false},
+		{"https://github.com/org/repo//deploy?ref=v1.2.0", "", false},
+		{"github.com/org/repo/base?ref=main", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := Normalize(tt.path)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("Normalize(%q) = (%q, %v), want (%q, %v)", tt.path, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	root := filepath.FromSlash("/repo")
+	tests := []struct {
+		name   string
+		base   string
+		path   string
+		want   string
+		wantOK bool
+	}{
+		{"relative", root, "./apps/web", "/repo/apps/web", true},
+		{"absolute anchored at base", root, "/clusters/production", "/repo/clusters/production", true},
+		{"parent within root", filepath.FromSlash("/repo/apps/web"), "../base", "/repo/apps/base", true},
+		// Resolve only joins and cleans: a reference escaping the root
+		// resolves outside it, and callers report it as missing
+		{"parent escaping root", root, "../../etc", "/etc", true},
+		{"absolute escaping root", root, "/../etc", "/etc", true},
+		{"base itself", root, "./", "/repo", true},
+		{"remote with ref", root, "https://github.com/org/repo//base?ref=v1", "", false},
+		{"empty", root, "", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := Resolve(tt.base, tt.path)
+		want := ""
+		if tt.want != "" {
+			want = filepath.FromSlash(tt.want)
+		}
+		if got != want || ok != tt.wantOK {
+			t.Errorf("%s: Resolve(%q, %q) = (%q, %v), want (%q, %v)", tt.name, tt.base, tt.path, got, ok, want, tt.wantOK)
+		}
+	}
+}
+
+func TestResolveReference(t *testing.T) {
+	repo := filepath.FromSlash("/repo")
+	source := filepath.FromSlash("/repo/apps/overlays/production/kustomization.yaml")
+	tests := []struct {
+		name       string
+		path       string
+		isRelative bool
+		want       string
+		wantOK     bool
+	}{
+		{"kustomize resource beside the file", "./deployment.yaml", true, "/repo/apps/overlays/production/deployment.yaml", true},
+		{"kustomize resource in a base", "../../base", true, "/repo/apps/base", true},
+		{"kustomize resource escaping the root", "../../../../shared", true, "/shared", true},
+		{"kustomize absolute resource", "/apps/base", true, "/repo/apps/overlays/production/apps/base", true},
+		{"flux path from the repository root", "./clusters/production", false, "/repo/clusters/production", true},
+		{"flux absolute path", "/clusters/production", false, "/repo/clusters/production", true},
+		{"remote resource with ref", "github.com/org/repo/config?ref=v0.1.0", true, "", false},
+		{"remote git resource with ref", "git@github.com:org/repo.git//base?ref=main", false, "", false},
+	}
+	for _, tt := range tests {
+		got, ok := ResolveReference(tt.path, tt.isRelative, source, repo)
+		want := ""
+		if tt.want != "" {
+			want = filepath.FromSlash(tt.want)
+		}
+		if got != want || ok != tt.wantOK {
+			t.Errorf("%s: ResolveReference(%q, %v) = (%q, %v), want (%q, %v)", tt.name, tt.path, tt.isRelative, got, ok, want, tt.wantOK)
+		}
+	}
+}
+
+func TestMatchPattern(t *testing.T) {
+	tests := []struct {
+		path    string
+		pattern string
+		want    bool
+	}{
+		// Trailing /** matches the directory and everything below it
+		{"apps/legacy", "apps/legacy/**", true},
+		{"apps/legacy/web.yaml", "apps/legacy/**", true},
+		{"apps/legacy/web/deployment.yaml", "apps/legacy/**", true},
+		{"apps/legacy-v2/web.yaml", "apps/legacy/**", false},
+		{"apps", "apps/legacy/**", false},
+		// ** in the middle or at the start spans any number of directories
+		{"clusters/production/apps/web.yaml", "clusters/**/web.yaml", true},
+		{"clusters/web.yaml", "clusters/**/web.yaml", true},
+		{"clusters/production/web.json", "clusters/**/web.yaml", false},
+		{"apps/web/secrets/db.yaml", "**/secrets/*.yaml", true},
+		{"secrets/db.yaml", "**/secrets/*.yaml", true},
+		{"apps/web/secrets/nested/db.yaml", "**/secrets/*.yaml", false},
+		{"apps/web/deployment.yaml", "apps/**/*.yaml", true},
+		{"anything/at/all", "**", true},
+		// Other segments never match across a /
+		{"apps/web.yaml", "apps/*.yaml", true},
+		{"apps/web/deployment.yaml", "apps/*.yaml", false},
+		{"apps/web/deployment.yaml", "apps/*/deployment.yaml", true},
+		{"apps/web/deployment.yaml", "*.yaml", false},
+		{"apps/web/deployment.yaml", "apps/web/deployment.yaml", true},
+		{"apps/web/deployment.yml", "apps/web/deployment.y?ml", false},
+		{"clusters/prod-eu", "clusters/prod-[a-z][a-z]", true},
+	}
+	for _, tt := range tests {
+		if got := MatchPattern(tt.path, tt.pattern); got != tt.want {
+			t.Errorf("MatchPattern(%q, %q) = %v, want %v", tt.path, tt.pattern, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+// Package render builds kustomizations with kustomize's krusty library, the
+// engine behind kustomize build and Flux's kustomize-controller, for checks
+// that need the resources as they are applied rather than as they are written.
+package render
+
+import (
+	"path/filepath"
+	"sync"
+
+	"sigs.k8s.io/kustomize/api/krusty"
+	kustypes "sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+)
+
+// Object is a resource of a build's output
+type Object struct {
+	APIVersion string
+	Kind       string
+	Namespace  string
+	Name       string
+	Content    map[string]interface{}
+}
+
+// Build is the output of building one kustomization directory
+type Build struct {
+	Dir     string
+	Objects []Object
+	// Err is the error kustomize failed the build with
+	Err error
+}
+
+// Renderer builds kustomization directories, each once
+type Renderer struct {
+	mu     sync.Mutex
+	builds map[string]*Build
+}
+
+// NewRenderer creates a new Renderer
+func NewRenderer() *Renderer {
+	return &Renderer{builds: make(map[string]*Build)}
+}
+
+// Build builds the kustomization in dir like Flux's kustomize-controller
+// does: files outside dir may be loaded, and plugins, including Helm
+// charts, are disabled. Remote resources are cloned or downloaded, so
+// callers decide whether the network may be used.
+func (r *Renderer) Build(dir string) *Build {
+	dir, _ = filepath.Abs(dir)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if build, ok := r.builds[dir]; ok {
+		return build
+	}
+
+	build := &Build{Dir: dir}
+	r.builds[dir] = build
+
+	options := krusty.MakeDefaultOptions()
+	options.LoadRestrictions = kustypes.LoadRestrictionsNone
+	resources, err := krusty.MakeKustomizer(options).Run(filesys.MakeFsOnDisk(), dir)
+	if err != nil {
+		build.Err = err
+		return build
+	}
+
+	for _, resource := range resources.Resources() {
+		content, err := resource.Map()
+		if err != nil {
+			build.Err = err
+			return build
+		}
+		build.Objects = append(build.Objects, Object{
+			APIVersion: resource.GetApiVersion(),
+			Kind:       resource.GetKind(),
+			Namespace:  resource.GetNamespace(),
+			Name:       resource.GetName(),
+			Content:    content,
+		})
+	}
+	return build
+}
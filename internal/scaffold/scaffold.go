@@ -0,0 +1,247 @@
+// Package scaffold writes the skeleton of a contributed rule: a package that
+// registers the rule through the sdk package, the documentation its results
+// link to and a test case, and links the package into the binary.
+package scaffold
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/moon-hex/gitops-validator/internal/types"
+)
+
+// Options describes the rule to scaffold
+type Options struct {
+	Dir         string         // contrib directory, holding rules.go
+	Name        string         // rule name under gitops-validator.rules, kebab-case
+	Description string         // one-line summary of what the rule detects
+	Fix         string         // one-line remediation hint, optional
+	Severity    types.Severity // severity of the skeleton check's results
+}
+
+// Rule describes a scaffolded rule
+type Rule struct {
+	ID    string
+	Dir   string   // package directory of the rule
+	Files []string // files written or changed
+}
+
+// RulesFile is the file of the contrib directory importing the rule packages
+const RulesFile = "rules.go"
+
+var (
+	namePattern = regexp.MustCompile(`^[a-z][a-z0-9]*(-[a-z0-9]+)*$`)
+	idPattern   = regexp.MustCompile(`ID:\s*"GVC(\d{4})"`)
+)
+
+// NewRule writes the skeleton of a rule into a new package under the contrib
+// directory and adds the package's import to the contrib rules file
+func NewRule(options Options) (*Rule, error) {
+	if !namePattern.MatchString(options.Name) {
+		return nil, fmt.Errorf("rule name '%s' must be kebab-case, like configmap-app-label", options.Name)
+	}
+	if options.Description == "" {
+		return nil, fmt.Errorf("rule '%s' needs a description", options.Name)
+	}
+	if !options.Severity.Valid() {
+		return nil, fmt.Errorf("invalid severity '%s', must be error, warning, or info", options.Severity)
+	}
+	for _, known := range types.Rules {
+		if known.Type == options.Name || known.Rule == options.Name {
+			return nil, fmt.Errorf("rule '%s' already exists (%s)", options.Name, known.ID)
+		}
+	}
+
+	rulesFile := filepath.Join(options.Dir, RulesFile)
+	if _, err := os.Stat(rulesFile); err != nil {
+		return nil, fmt.Errorf("%s not found; run from the repository root or pass the contrib directory", rulesFile)
+	}
+	dir := filepath.Join(options.Dir, options.Name)
+	if _, err := os.Stat(dir); err == nil {
+		return nil, fmt.Errorf("%s already exists", dir)
+	}
+
+	moduleRoot, modulePath, err := findModule(options.Dir)
+	if err != nil {
+		return nil, err
+	}
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	relDir, err := filepath.Rel(moduleRoot, absDir)
+	if err != nil {
+		return nil, err
+	}
+	id, err := nextID(options.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	data := templateData{
+		Options: options,
+		ID:      id,
+		Package: strings.ReplaceAll(options.Name, "-", ""),
+		Docs:    filepath.ToSlash(filepath.Join(relDir, "README.md")),
+		Path:    filepath.ToSlash(dir),
+		Result: map[types.Severity]string{
+			types.SeverityError:   "Error",
+			types.SeverityWarning: "Warning",
+			types.SeverityInfo:    "Info",
+		}[options.Severity],
+	}
+
+	rule := &Rule{ID: id, Dir: dir}
+	files := []struct {
+		name     string
+		template *template.Template
+		gofmt    bool
+	}{
+		{strings.ReplaceAll(options.Name, "-", "_") + ".go", ruleTemplate, true},
+		{"README.md", readmeTemplate, false},
+		{"gitops-validator.yaml", configTemplate, false},
+		{filepath.Join("repo", "example.yaml"), exampleTemplate, false},
+	}
+	for _, file := range files {
+		var buf bytes.Buffer
+		if err := file.template.Execute(&buf, data); err != nil {
+			return nil, err
+		}
+		content := buf.Bytes()
+		if file.gofmt {
+			if content, err = format.Source(content); err != nil {
+				return nil, err
+			}
+		}
+		path := filepath.Join(dir, file.name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(path, content, 0o644); err != nil {
+			return nil, err
+		}
+		rule.Files = append(rule.Files, path)
+	}
+
+	importPath := modulePath + "/" + filepath.ToSlash(relDir)
+	if err := addImport(rulesFile, importPath); err != nil {
+		return nil, err
+	}
+	rule.Files = append(rule.Files, rulesFile)
+
+	readme := filepath.Join(options.Dir, "README.md")
+	if _, err := os.Stat(readme); err == nil {
+		row := fmt.Sprintf("| %s | [%s](%s/README.md) | %s |\n", id, options.Name, options.Name, options.Description)
+		file, err := os.OpenFile(readme, os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			return nil, err
+		}
+		_, err = file.WriteString(row)
+		file.Close()
+		if err != nil {
+			return nil, err
+		}
+		rule.Files = append(rule.Files, readme)
+	}
+
+	return rule, nil
+}
+
+// findModule returns the directory of the go.mod above dir and its module path
+func findModule(dir string) (root, path string, err error) {
+	root, err = filepath.Abs(dir)
+	if err != nil {
+		return "", "", err
+	}
+	for {
+		file, err := os.Open(filepath.Join(root, "go.mod"))
+		if err == nil {
+			defer file.Close()
+			scanner := bufio.NewScanner(file)
+			for scanner.Scan() {
+				if module, ok := strings.CutPrefix(strings.TrimSpace(scanner.Text()), "module "); ok {
+					return root, strings.Trim(strings.TrimSpace(module), `"`), nil
+				}
+			}
+			return "", "", fmt.Errorf("%s declares no module", filepath.Join(root, "go.mod"))
+		}
+		parent := filepath.Dir(root)
+		if parent == root {
+			return "", "", fmt.Errorf("no go.mod found above %s", dir)
+		}
+		root = parent
+	}
+}
+
+// nextID returns the ID after the highest contributed rule ID, among the
+// registered rules and the rule packages under the contrib directory, which
+// may not be built into this binary yet
+func nextID(dir string) (string, error) {
+	highest := 0
+	record := func(digits string) {
+		if n, err := strconv.Atoi(digits); err == nil && n > highest {
+			highest = n
+		}
+	}
+	for _, rule := range types.Rules {
+		if digits, ok := strings.CutPrefix(rule.ID, "GVC"); ok {
+			record(digits)
+		}
+	}
+	err := filepath.WalkDir(dir, func(path string, entry os.DirEntry, err error) error {
+		if err != nil || entry.IsDir() || !strings.HasSuffix(path, ".go") {
+			return err
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		for _, match := range idPattern.FindAllSubmatch(content, -1) {
+			record(string(match[1]))
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("GVC%04d", highest+1), nil
+}
+
+// addImport adds a blank import to a Go file, keeping the imports sorted
+func addImport(file, importPath string) error {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	source := string(content)
+	line := fmt.Sprintf("\t_ %q\n", importPath)
+
+	if start := strings.Index(source, "\nimport (\n"); start >= 0 {
+		start += len("\nimport (\n")
+		end := strings.Index(source[start:], ")\n")
+		if end < 0 {
+			return fmt.Errorf("%s: unterminated import block", file)
+		}
+		lines := strings.SplitAfter(source[start:start+end], "\n")
+		lines = append(lines[:len(lines)-1], line)
+		sort.Strings(lines)
+		source = source[:start] + strings.Join(lines, "") + source[start+end:]
+	} else {
+		source = strings.TrimRight(source, "\n") + "\n\nimport (\n" + line + ")\n"
+	}
+
+	formatted, err := format.Source([]byte(source))
+	if err != nil {
+		return fmt.Errorf("%s: %w", file, err)
+	}
+	return os.WriteFile(file, formatted, 0o644)
+}
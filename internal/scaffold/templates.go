@@ -0,0 +1,113 @@
+package scaffold
+
+import "text/template"
+
+// templateData is what the templates of a rule are executed with
+type templateData struct {
+	Options
+	ID      string
+	Package string // Go package name: the rule name without dashes
+	Docs    string // README path relative to the repository root
+	Path    string // package directory, as the contrib directory was given
+	Result  string // sdk result builder of the severity
+}
+
+var ruleTemplate = template.Must(template.New("rule").Parse(`// Package {{.Package}} implements the contributed rule {{.Name}} ({{.ID}}).
+package {{.Package}}
+
+import "github.com/moon-hex/gitops-validator/sdk"
+
+// rule is the config rule name and the type of the results
+const rule = "{{.Name}}"
+
+func init() {
+	sdk.Register(sdk.Rule{
+		ID:          "{{.ID}}",
+		Name:        rule,
+		Description: {{printf "%q" .Description}},
+{{- if .Fix}}
+		Fix:         {{printf "%q" .Fix}},
+{{- end}}
+		Docs:        "{{.Docs}}",
+		Check:       check,
+	})
+}
+
+// check reports the rule's findings.
+//
+// TODO: replace this example, which flags ConfigMaps without an
+// app.kubernetes.io/name label, with the rule's logic.
+func check(ctx *sdk.Context) []sdk.Result {
+	var results []sdk.Result
+	for _, configMap := range sdk.Resources(ctx, "ConfigMap") {
+		if sdk.String(configMap.Content, "metadata", "labels", "app.kubernetes.io/name") == "" {
+			results = append(results, sdk.{{.Result}}(rule, configMap, "ConfigMap '%s' has no app.kubernetes.io/name label", configMap.Name))
+		}
+	}
+	return results
+}
+`))
+
+var readmeTemplate = template.Must(template.New("readme").Parse(`# {{.ID}}: {{.Name}}
+
+{{.Description}}
+
+Severity: {{.Severity}}. Like every contributed rule, it only runs when
+enabled:
+
+` + "```yaml" + `
+gitops-validator:
+  rules:
+    {{.Name}}:
+      enabled: true
+` + "```" + `
+
+## What it checks
+
+TODO: describe what the rule flags and why it matters.
+
+## How to fix
+
+TODO: describe how to resolve a finding.
+
+## Test case
+
+` + "`gitops-validator.yaml`" + ` and ` + "`repo/`" + ` are a test case enabling the rule;
+` + "`expected-results.json`" + ` records its results. After changing the rule, check
+them with:
+
+` + "```bash" + `
+gitops-validator test {{.Path}}
+` + "```" + `
+`))
+
+var configTemplate = template.Must(template.New("config").Parse(`gitops-validator:
+  rules:
+    {{.Name}}:
+      enabled: true
+  # The example manifests are not deployed from anywhere; as entry points
+  # they are not reported as orphaned
+  entry-points:
+    namespaces:
+      - default
+`))
+
+var exampleTemplate = template.Must(template.New("example").Parse(`# TODO: replace with manifests the rule flags and manifests it accepts
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: labeled
+  namespace: default
+  labels:
+    app.kubernetes.io/name: example
+data:
+  key: value
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: unlabeled
+  namespace: default
+data:
+  key: value
+`))
@@ -0,0 +1,322 @@
+// Package semver parses semantic versions and matches them against the
+// version constraints Flux accepts for Helm charts and OCI artifacts, such as
+// "6.x", "^1.2.0", "~1.4" or ">=1.0.0 <2.0.0 || 3.1.0".
+//
+// Constraints follow the semantics of github.com/Masterminds/semver, which
+// Flux uses: partial versions and x/X/* wildcards match every version they
+// leave open, and prereleases only match constraints that mention one.
+package semver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version is a parsed semantic version
+type Version struct {
+	Major, Minor, Patch uint64
+	Prerelease          string
+	Original            string
+}
+
+// Parse parses a version such as "1.2.3", "v1.2.3-rc.1+build" or "1.2"
+// (read as 1.2.0)
+func Parse(version string) (Version, error) {
+	parsed, wildcard, err := parsePartial(version)
+	if err != nil {
+		return Version{}, err
+	}
+	if wildcard < 3 {
+		return Version{}, fmt.Errorf("'%s' is not a version", version)
+	}
+	return parsed, nil
+}
+
+// Compare returns -1, 0 or 1 when a is lower than, equal to or higher than b
+func Compare(a, b Version) int {
+	for _, pair := range [][2]uint64{{a.Major, b.Major}, {a.Minor, b.Minor}, {a.Patch, b.Patch}} {
+		if pair[0] != pair[1] {
+			if pair[0] < pair[1] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return comparePrerelease(a.Prerelease, b.Prerelease)
+}
+
+// Constraint is a parsed version constraint: alternatives separated by ||,
+// each a list of terms that must all hold
+type Constraint struct {
+	alternatives [][]term
+	original     string
+}
+
+// term is a single comparison such as ">=1.2.0"
+type term struct {
+	operator string
+	version  Version
+	// wildcard is the index of the first component left open (0 major,
+	// 1 minor, 2 patch), 3 for a full version
+	wildcard int
+}
+
+// ParseConstraint parses a constraint. An empty constraint or "*" matches
+// every release version.
+func ParseConstraint(constraint string) (*Constraint, error) {
+	parsed := &Constraint{original: constraint}
+	for _, alternative := range strings.Split(constraint, "||") {
+		var terms []term
+		fields := strings.Fields(strings.ReplaceAll(alternative, ",", " "))
+		for i := 0; i < len(fields); i++ {
+			field := fields[i]
+			// An operator may be separated from its version by a space
+			if isOperator(field) && i+1 < len(fields) {
+				i++
+				field += fields[i]
+			}
+			// Hyphen ranges: "1.2 - 1.4" means >=1.2 <=1.4
+			if i+2 < len(fields) && fields[i+1] == "-" {
+				lower, err := parseTerm(">=" + field)
+				if err != nil {
+					return nil, err
+				}
+				upper, err := parseTerm("<=" + fields[i+2])
+				if err != nil {
+					return nil, err
+				}
+				terms = append(terms, lower, upper)
+				i += 2
+				continue
+			}
+			t, err := parseTerm(field)
+			if err != nil {
+				return nil, err
+			}
+			terms = append(terms, t)
+		}
+		if len(terms) == 0 {
+			terms = []term{{operator: "", wildcard: 0}}
+		}
+		parsed.alternatives = append(parsed.alternatives, terms)
+	}
+	return parsed, nil
+}
+
+// Check reports whether version satisfies the constraint
+func (c *Constraint) Check(version Version) bool {
+	for _, terms := range c.alternatives {
+		// Prereleases only match alternatives mentioning one
+		if version.Prerelease != "" {
+			allowed := false
+			for _, t := range terms {
+				if t.version.Prerelease != "" {
+					allowed = true
+				}
+			}
+			if !allowed {
+				continue
+			}
+		}
+
+		matched := true
+		for _, t := range terms {
+			if !t.check(version) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns the constraint as written
+func (c *Constraint) String() string {
+	return c.original
+}
+
+// parseTerm parses an operator and a possibly partial version
+func parseTerm(field string) (term, error) {
+	operator := ""
+	for _, candidate := range []string{">=", "<=", "!=", "~>", "=>", "=<", ">", "<", "=", "~", "^"} {
+		if strings.HasPrefix(field, candidate) {
+			operator = candidate
+			break
+		}
+	}
+	switch operator {
+	case "=>":
+		operator = ">="
+	case "=<":
+		operator = "<="
+	case "~>":
+		operator = "~"
+	}
+
+	version, wildcard, err := parsePartial(strings.TrimSpace(field[len(operator):]))
+	if err != nil {
+		return term{}, fmt.Errorf("invalid constraint '%s': %w", field, err)
+	}
+	return term{operator: operator, version: version, wildcard: wildcard}, nil
+}
+
+// check reports whether version satisfies the term
+func (t term) check(version Version) bool {
+	lower, upper := t.version, t.upperBound()
+	switch t.operator {
+	case "", "=":
+		if t.wildcard == 3 {
+			return Compare(version, t.version) == 0
+		}
+		return Compare(version, lower) >= 0 && Compare(version, upper) < 0
+	case "!=":
+		if t.wildcard == 3 {
+			return Compare(version, t.version) != 0
+		}
+		return Compare(version, lower) < 0 || Compare(version, upper) >= 0
+	case ">":
+		if t.wildcard == 3 {
+			return Compare(version, t.version) > 0
+		}
+		return Compare(version, upper) >= 0
+	case ">=":
+		return Compare(version, lower) >= 0
+	case "<":
+		return Compare(version, lower) < 0
+	case "<=":
+		if t.wildcard == 3 {
+			return Compare(version, t.version) <= 0
+		}
+		return Compare(version, upper) < 0
+	case "~":
+		// ~1.2.3 allows patch updates, ~1 minor ones
+		bound := Version{Major: lower.Major, Minor: lower.Minor + 1}
+		if t.wildcard < 2 {
+			bound = Version{Major: lower.Major + 1}
+		}
+		return Compare(version, lower) >= 0 && Compare(version, bound) < 0
+	case "^":
+		// ^ allows updates that do not change the leftmost non-zero component
+		var bound Version
+		switch {
+		case lower.Major > 0 || t.wildcard < 2:
+			bound = Version{Major: lower.Major + 1}
+		case lower.Minor > 0 || t.wildcard < 3:
+			bound = Version{Minor: lower.Minor + 1}
+		default:
+			bound = Version{Patch: lower.Patch + 1}
+		}
+		return Compare(version, lower) >= 0 && Compare(version, bound) < 0
+	}
+	return false
+}
+
+// upperBound returns the lowest version above everything a partial version
+// leaves open: 1.3.0 for 1.2.x, 2.0.0 for 1.x
+func (t term) upperBound() Version {
+	switch t.wildcard {
+	case 0:
+		return Version{Major: ^uint64(0)}
+	case 1:
+		return Version{Major: t.version.Major + 1}
+	case 2:
+		return Version{Major: t.version.Major, Minor: t.version.Minor + 1}
+	}
+	return t.version
+}
+
+// parsePartial parses a version whose trailing components may be missing or
+// wildcards, returning the index of the first open component (3 if none)
+func parsePartial(version string) (Version, int, error) {
+	original := version
+	version = strings.TrimPrefix(strings.TrimPrefix(version, "v"), "V")
+	if version == "" || version == "*" || version == "x" || version == "X" {
+		return Version{Original: original}, 0, nil
+	}
+
+	version, _, _ = strings.Cut(version, "+")
+	core, prerelease, _ := strings.Cut(version, "-")
+
+	parts := strings.Split(core, ".")
+	if len(parts) > 3 {
+		return Version{}, 0, fmt.Errorf("'%s' has more than three components", original)
+	}
+
+	parsed := Version{Prerelease: prerelease, Original: original}
+	components := []*uint64{&parsed.Major, &parsed.Minor, &parsed.Patch}
+	wildcard := len(parts)
+	for i, part := range parts {
+		if part == "x" || part == "X" || part == "*" {
+			wildcard = i
+			break
+		}
+		number, err := strconv.ParseUint(part, 10, 64)
+		if err != nil {
+			return Version{}, 0, fmt.Errorf("'%s' is not a version", original)
+		}
+		*components[i] = number
+	}
+	if wildcard < 3 && prerelease != "" {
+		return Version{}, 0, fmt.Errorf("'%s' has a prerelease but no patch version", original)
+	}
+	return parsed, wildcard, nil
+}
+
+// isOperator reports whether field is a comparison operator on its own
+func isOperator(field string) bool {
+	switch field {
+	case "=", "!=", ">", "<", ">=", "<=", "=>", "=<", "~", "~>", "^":
+		return true
+	}
+	return false
+}
+
+// comparePrerelease compares prerelease identifiers; a release is higher
+// than any of its prereleases
+func comparePrerelease(a, b string) int {
+	if a == b {
+		return 0
+	}
+	if a == "" {
+		return 1
+	}
+	if b == "" {
+		return -1
+	}
+
+	aParts, bParts := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(aParts) && i < len(bParts); i++ {
+		aNumber, aErr := strconv.ParseUint(aParts[i], 10, 64)
+		bNumber, bErr := strconv.ParseUint(bParts[i], 10, 64)
+		switch {
+		case aErr == nil && bErr == nil:
+			if aNumber != bNumber {
+				if aNumber < bNumber {
+					return -1
+				}
+				return 1
+			}
+		case aErr == nil:
+			// Numeric identifiers sort below alphanumeric ones
+			return -1
+		case bErr == nil:
+			return 1
+		case aParts[i] != bParts[i]:
+			if aParts[i] < bParts[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	switch {
+	case len(aParts) < len(bParts):
+		return -1
+	case len(aParts) > len(bParts):
+		return 1
+	}
+	return 0
+}
@@ -2,6 +2,7 @@ package types
 
 import (
 	"fmt"
+	"path/filepath"
 	"sort"
 	"strings"
 )
@@ -20,18 +21,21 @@ func NewResultAggregator(results []ValidationResult) *ResultAggregator {
 
 // AggregationOptions defines options for result aggregation
 type AggregationOptions struct {
-	FilterBySeverity []string // Filter by severity levels
-	FilterByType     []string // Filter by validation types
-	FilterByFile     []string // Filter by file patterns
-	FilterByResource []string // Filter by resource patterns
-	GroupBy          string   // Group by: severity, type, file, resource
-	SortBy           string   // Sort by: severity, type, file, resource, line
-	SortOrder        string   // Sort order: asc, desc
-	Limit            int      // Limit number of results
-	IncludeStats     bool     // Include statistics in output
-	ShowOnlyErrors   bool     // Show only error-level results
-	ShowOnlyWarnings bool     // Show only warning-level results
-	ShowOnlyInfo     bool     // Show only info-level results
+	FilterBySeverity []Severity          // Filter by severity levels
+	FilterByType     []string            // Filter by validation types
+	FilterByFile     []string            // Filter by file patterns
+	FilterByResource []string            // Filter by resource patterns
+	GroupBy          string              // Group by: severity, type, file, resource, directory, entry-point
+	RootPath         string              // Repository root stripped from file paths when grouping by directory
+	EntryPoints      map[string][]string // Entry points applying each file, for GroupBy "entry-point"
+	EvaluatedByRule  map[string]int      // Resources evaluated per rule, for per-rule statistics
+	SortBy           string              // Sort by: severity, type, file, resource, line
+	SortOrder        string              // Sort order: asc, desc
+	Limit            int                 // Limit number of results
+	IncludeStats     bool                // Include statistics in output
+	ShowOnlyErrors   bool                // Show only error-level results
+	ShowOnlyWarnings bool                // Show only warning-level results
+	ShowOnlyInfo     bool                // Show only info-level results
 }
 
 // AggregatedResults represents aggregated validation results
@@ -39,10 +43,31 @@ type AggregatedResults struct {
 	Results       []ValidationResult
 	Statistics    ResultStatistics
 	Groups        map[string][]ValidationResult
+	Directories   []DirectoryRollup  // Per top-level directory counts (GroupBy "directory")
+	EntryPoints   []EntryPointRollup // Per entry point counts (GroupBy "entry-point")
+	HealthScore   *HealthScore       // Repository health score, if computed
 	FilteredCount int
 	TotalCount    int
 }
 
+// DirectoryRollup summarizes results for one top-level directory of the repository
+type DirectoryRollup struct {
+	Directory string
+	Errors    int
+	Warnings  int
+	Info      int
+	Total     int
+}
+
+// EntryPointRollup summarizes results for the files one entry point applies
+type EntryPointRollup struct {
+	EntryPoint string
+	Errors     int
+	Warnings   int
+	Info       int
+	Total      int
+}
+
 // ResultStatistics provides statistics about validation results
 type ResultStatistics struct {
 	TotalResults      int
@@ -50,11 +75,28 @@ type ResultStatistics struct {
 	WarningCount      int
 	InfoCount         int
 	ByType            map[string]int
-	BySeverity        map[string]int
+	BySeverity        map[Severity]int
 	ByFile            map[string]int
 	MostCommonTypes   []TypeCount
 	MostCommonFiles   []FileCount
 	SeverityBreakdown SeverityBreakdown
+	ByRule            []RuleStatistics
+}
+
+// RuleStatistics describes how many resources a rule evaluated and how many it flagged
+type RuleStatistics struct {
+	Rule      string // Config rule name (result type for rules without one)
+	Evaluated int    // Resources the rule was evaluated against (0 if unknown)
+	Affected  int    // Distinct resources with at least one result
+	Results   int    // Total results reported by the rule
+}
+
+// AffectedPercent returns the share of evaluated resources that were affected
+func (rs RuleStatistics) AffectedPercent() float64 {
+	if rs.Evaluated == 0 {
+		return 0
+	}
+	return float64(rs.Affected) * 100 / float64(rs.Evaluated)
 }
 
 // TypeCount represents count of results by type
@@ -87,7 +129,15 @@ func (ra *ResultAggregator) Aggregate(options AggregationOptions) *AggregatedRes
 
 	// Group results if requested
 	groups := make(map[string][]ValidationResult)
-	if options.GroupBy != "" {
+	var directories []DirectoryRollup
+	var entryPoints []EntryPointRollup
+	if options.GroupBy == "directory" {
+		groups = ra.groupByDirectory(filteredResults, options.RootPath)
+		directories = rollupDirectories(groups)
+	} else if options.GroupBy == "entry-point" {
+		groups = ra.groupByEntryPoint(filteredResults, options.EntryPoints)
+		entryPoints = rollupEntryPoints(groups)
+	} else if options.GroupBy != "" {
 		groups = ra.groupResults(filteredResults, options.GroupBy)
 	}
 
@@ -103,11 +153,14 @@ func (ra *ResultAggregator) Aggregate(options AggregationOptions) *AggregatedRes
 
 	// Calculate statistics
 	statistics := ra.calculateStatistics(ra.results)
+	statistics.ByRule = ra.calculateRuleStatistics(ra.results, options.EvaluatedByRule)
 
 	return &AggregatedResults{
 		Results:       filteredResults,
 		Statistics:    statistics,
 		Groups:        groups,
+		Directories:   directories,
+		EntryPoints:   entryPoints,
 		FilteredCount: len(filteredResults),
 		TotalCount:    len(ra.results),
 	}
@@ -120,7 +173,11 @@ func (ra *ResultAggregator) applyFilters(results []ValidationResult, options Agg
 	for _, result := range results {
 		// Severity filter
 		if len(options.FilterBySeverity) > 0 {
-			if !ra.stringInSlice(result.Severity, options.FilterBySeverity) {
+			matched := false
+			for _, severity := range options.FilterBySeverity {
+				matched = matched || severity == result.Severity
+			}
+			if !matched {
 				continue
 			}
 		}
@@ -147,17 +204,17 @@ func (ra *ResultAggregator) applyFilters(results []ValidationResult, options Agg
 		}
 
 		// Show only errors
-		if options.ShowOnlyErrors && result.Severity != "error" {
+		if options.ShowOnlyErrors && result.Severity != SeverityError {
 			continue
 		}
 
 		// Show only warnings
-		if options.ShowOnlyWarnings && result.Severity != "warning" {
+		if options.ShowOnlyWarnings && result.Severity != SeverityWarning {
 			continue
 		}
 
 		// Show only info
-		if options.ShowOnlyInfo && result.Severity != "info" {
+		if options.ShowOnlyInfo && result.Severity != SeverityInfo {
 			continue
 		}
 
@@ -175,7 +232,7 @@ func (ra *ResultAggregator) groupResults(results []ValidationResult, groupBy str
 		var key string
 		switch groupBy {
 		case "severity":
-			key = result.Severity
+			key = string(result.Severity)
 		case "type":
 			key = result.Type
 		case "file":
@@ -192,6 +249,108 @@ func (ra *ResultAggregator) groupResults(results []ValidationResult, groupBy str
 	return groups
 }
 
+// groupByDirectory groups results by the top-level directory of their file
+func (ra *ResultAggregator) groupByDirectory(results []ValidationResult, rootPath string) map[string][]ValidationResult {
+	groups := make(map[string][]ValidationResult)
+	for _, result := range results {
+		key := TopLevelDirectory(result.File, rootPath)
+		groups[key] = append(groups[key], result)
+	}
+	return groups
+}
+
+// TopLevelDirectory returns the first path segment of file relative to rootPath
+// (e.g. "apps/" for "apps/backend/deployment.yaml"). Files at the root are
+// reported as "(root)" and results without a file as "(no file)".
+func TopLevelDirectory(file, rootPath string) string {
+	if file == "" {
+		return "(no file)"
+	}
+
+	rel := file
+	if rootPath != "" {
+		if r, err := filepath.Rel(rootPath, file); err == nil && !strings.HasPrefix(r, "..") {
+			rel = r
+		}
+	}
+
+	rel = strings.TrimPrefix(filepath.ToSlash(rel), "./")
+	if i := strings.Index(rel, "/"); i > 0 {
+		return rel[:i+1]
+	}
+	return "(root)"
+}
+
+// rollupDirectories counts results per severity for each directory group,
+// least healthy directories first
+func rollupDirectories(groups map[string][]ValidationResult) []DirectoryRollup {
+	var rollups []DirectoryRollup
+	for dir, results := range groups {
+		rollup := DirectoryRollup{Directory: dir, Total: len(results)}
+		for _, result := range results {
+			switch result.Severity {
+			case SeverityError:
+				rollup.Errors++
+			case SeverityWarning:
+				rollup.Warnings++
+			case SeverityInfo:
+				rollup.Info++
+			}
+		}
+		rollups = append(rollups, rollup)
+	}
+
+	sort.Slice(rollups, func(i, j int) bool {
+		if rollups[i].Errors != rollups[j].Errors {
+			return rollups[i].Errors > rollups[j].Errors
+		}
+		if rollups[i].Warnings != rollups[j].Warnings {
+			return rollups[i].Warnings > rollups[j].Warnings
+		}
+		if rollups[i].Total != rollups[j].Total {
+			return rollups[i].Total > rollups[j].Total
+		}
+		return rollups[i].Directory < rollups[j].Directory
+	})
+
+	return rollups
+}
+
+// groupByEntryPoint groups results by the entry points applying their file. A
+// result in a file shared by several entry points is in each of their groups;
+// one in a file no entry point applies is grouped as "(no entry point)".
+func (ra *ResultAggregator) groupByEntryPoint(results []ValidationResult, entryPoints map[string][]string) map[string][]ValidationResult {
+	groups := make(map[string][]ValidationResult)
+	for _, result := range results {
+		keys := entryPoints[result.File]
+		if result.File == "" {
+			keys = []string{"(no file)"}
+		} else if len(keys) == 0 {
+			keys = []string{"(no entry point)"}
+		}
+		for _, key := range keys {
+			groups[key] = append(groups[key], result)
+		}
+	}
+	return groups
+}
+
+// rollupEntryPoints counts results per severity for each entry point group,
+// least healthy entry points first
+func rollupEntryPoints(groups map[string][]ValidationResult) []EntryPointRollup {
+	var rollups []EntryPointRollup
+	for _, dir := range rollupDirectories(groups) {
+		rollups = append(rollups, EntryPointRollup{
+			EntryPoint: dir.Directory,
+			Errors:     dir.Errors,
+			Warnings:   dir.Warnings,
+			Info:       dir.Info,
+			Total:      dir.Total,
+		})
+	}
+	return rollups
+}
+
 // sortResults sorts results by the specified field
 func (ra *ResultAggregator) sortResults(results []ValidationResult, sortBy, sortOrder string) []ValidationResult {
 	sorted := make([]ValidationResult, len(results))
@@ -202,7 +361,7 @@ func (ra *ResultAggregator) sortResults(results []ValidationResult, sortBy, sort
 
 		switch sortBy {
 		case "severity":
-			valueI, valueJ = sorted[i].Severity, sorted[j].Severity
+			valueI, valueJ = string(sorted[i].Severity), string(sorted[j].Severity)
 		case "type":
 			valueI, valueJ = sorted[i].Type, sorted[j].Type
 		case "file":
@@ -233,7 +392,7 @@ func (ra *ResultAggregator) calculateStatistics(results []ValidationResult) Resu
 	stats := ResultStatistics{
 		TotalResults: len(results),
 		ByType:       make(map[string]int),
-		BySeverity:   make(map[string]int),
+		BySeverity:   make(map[Severity]int),
 		ByFile:       make(map[string]int),
 	}
 
@@ -241,11 +400,11 @@ func (ra *ResultAggregator) calculateStatistics(results []ValidationResult) Resu
 		// Count by severity
 		stats.BySeverity[result.Severity]++
 		switch result.Severity {
-		case "error":
+		case SeverityError:
 			stats.ErrorCount++
-		case "warning":
+		case SeverityWarning:
 			stats.WarningCount++
-		case "info":
+		case SeverityInfo:
 			stats.InfoCount++
 		default:
 			stats.SeverityBreakdown.Unknown++
@@ -272,6 +431,57 @@ func (ra *ResultAggregator) calculateStatistics(results []ValidationResult) Resu
 	return stats
 }
 
+// calculateRuleStatistics counts results and affected resources per rule and
+// combines them with the number of resources each rule evaluated
+func (ra *ResultAggregator) calculateRuleStatistics(results []ValidationResult, evaluated map[string]int) []RuleStatistics {
+	byRule := make(map[string]*RuleStatistics)
+	affected := make(map[string]map[string]bool)
+
+	get := func(rule string) *RuleStatistics {
+		if byRule[rule] == nil {
+			byRule[rule] = &RuleStatistics{Rule: rule, Evaluated: evaluated[rule]}
+			affected[rule] = make(map[string]bool)
+		}
+		return byRule[rule]
+	}
+
+	for rule, count := range evaluated {
+		if count > 0 {
+			get(rule)
+		}
+	}
+
+	for _, result := range results {
+		rule := result.Type
+		if info, ok := LookupRuleByType(result.Type); ok && info.Rule != "" {
+			rule = info.Rule
+		}
+
+		stats := get(rule)
+		stats.Results++
+
+		resourceKey := result.File + "|" + result.Resource
+		if !affected[rule][resourceKey] {
+			affected[rule][resourceKey] = true
+			stats.Affected++
+		}
+	}
+
+	var ruleStats []RuleStatistics
+	for _, stats := range byRule {
+		ruleStats = append(ruleStats, *stats)
+	}
+
+	sort.Slice(ruleStats, func(i, j int) bool {
+		if ruleStats[i].Affected != ruleStats[j].Affected {
+			return ruleStats[i].Affected > ruleStats[j].Affected
+		}
+		return ruleStats[i].Rule < ruleStats[j].Rule
+	})
+
+	return ruleStats
+}
+
 // calculateMostCommon calculates most common items from a count map
 func (ra *ResultAggregator) calculateMostCommon(countMap map[string]int, limit int) []TypeCount {
 	var items []TypeCount
@@ -336,6 +546,9 @@ func (ar *AggregatedResults) GetSummary() string {
 	summary.WriteString(fmt.Sprintf("  Errors: %d\n", ar.Statistics.ErrorCount))
 	summary.WriteString(fmt.Sprintf("  Warnings: %d\n", ar.Statistics.WarningCount))
 	summary.WriteString(fmt.Sprintf("  Info: %d\n", ar.Statistics.InfoCount))
+	if ar.HealthScore != nil {
+		summary.WriteString(fmt.Sprintf("  Health Score: %.1f/100 (%s)\n", ar.HealthScore.Score, ar.HealthScore.Grade))
+	}
 
 	if len(ar.Statistics.MostCommonTypes) > 0 {
 		summary.WriteString("\nMost Common Issues:\n")
@@ -347,5 +560,48 @@ func (ar *AggregatedResults) GetSummary() string {
 		}
 	}
 
+	if len(ar.Statistics.ByRule) > 0 {
+		summary.WriteString("\nRule Coverage:\n")
+		for _, rule := range ar.Statistics.ByRule {
+			if rule.Evaluated > 0 {
+				summary.WriteString(fmt.Sprintf("  %s: %s of %s resources affected (%.1f%%)\n",
+					rule.Rule, formatCount(rule.Affected), formatCount(rule.Evaluated), rule.AffectedPercent()))
+			} else {
+				summary.WriteString(fmt.Sprintf("  %s: %s resources affected\n", rule.Rule, formatCount(rule.Affected)))
+			}
+		}
+	}
+
+	if len(ar.Directories) > 0 {
+		summary.WriteString("\nResults by Directory:\n")
+		for _, dir := range ar.Directories {
+			summary.WriteString(fmt.Sprintf("  %s: %d errors, %d warnings, %d info\n", dir.Directory, dir.Errors, dir.Warnings, dir.Info))
+		}
+	}
+
+	if len(ar.EntryPoints) > 0 {
+		summary.WriteString("\nResults by Entry Point:\n")
+		for _, entryPoint := range ar.EntryPoints {
+			summary.WriteString(fmt.Sprintf("  %s: %d errors, %d warnings, %d info\n", entryPoint.EntryPoint, entryPoint.Errors, entryPoint.Warnings, entryPoint.Info))
+		}
+	}
+
 	return summary.String()
 }
+
+// formatCount formats a count with thousands separators (1203 → "1,203")
+func formatCount(n int) string {
+	digits := fmt.Sprintf("%d", n)
+	if n < 0 {
+		return "-" + formatCount(-n)
+	}
+
+	var out strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			out.WriteByte(',')
+		}
+		out.WriteRune(d)
+	}
+	return out.String()
+}
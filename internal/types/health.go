@@ -0,0 +1,77 @@
+package types
+
+import "math"
+
+// HealthWeights configures how much each finding lowers the health score
+type HealthWeights struct {
+	Severity map[Severity]float64 // Weight per severity (error, warning, info)
+	Rules    map[string]float64   // Multiplier per config rule name (default 1)
+}
+
+// DefaultHealthWeights returns the built-in severity weights
+func DefaultHealthWeights() HealthWeights {
+	return HealthWeights{
+		Severity: map[Severity]float64{SeverityError: 10, SeverityWarning: 3, SeverityInfo: 1},
+		Rules:    map[string]float64{},
+	}
+}
+
+// HealthScore summarizes repository health as a 0-100 score
+type HealthScore struct {
+	Score     float64 `json:"score"`     // 100 means no weighted findings
+	Grade     string  `json:"grade"`     // A (>=90) to F (<60)
+	Penalty   float64 `json:"penalty"`   // Sum of weighted findings
+	Resources int     `json:"resources"` // Resources the score is normalized against
+}
+
+// ComputeHealthScore weighs every result by severity and rule and normalizes the
+// total against the number of resources, so the score is comparable across repos
+// of different sizes: score = 100 * resources / (resources + penalty).
+func ComputeHealthScore(results []ValidationResult, resources int, weights HealthWeights) HealthScore {
+	penalty := 0.0
+	for _, result := range results {
+		severityWeight, ok := weights.Severity[result.Severity]
+		if !ok {
+			continue
+		}
+
+		ruleWeight := 1.0
+		rule := result.Type
+		if info, ok := LookupRuleByType(result.Type); ok && info.Rule != "" {
+			rule = info.Rule
+		}
+		if w, ok := weights.Rules[rule]; ok {
+			ruleWeight = w
+		}
+
+		penalty += severityWeight * ruleWeight
+	}
+
+	base := float64(resources)
+	if base < 1 {
+		base = 1
+	}
+	score := math.Round(1000*base/(base+penalty)) / 10
+
+	return HealthScore{
+		Score:     score,
+		Grade:     healthGrade(score),
+		Penalty:   penalty,
+		Resources: resources,
+	}
+}
+
+func healthGrade(score float64) string {
+	switch {
+	case score >= 90:
+		return "A"
+	case score >= 80:
+		return "B"
+	case score >= 70:
+		return "C"
+	case score >= 60:
+		return "D"
+	default:
+		return "F"
+	}
+}
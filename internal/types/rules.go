@@ -0,0 +1,131 @@
+package types
+
+import "strings"
+
+// RulesDocsURL is the base URL of the rule reference documentation.
+// Each rule is documented under an anchor matching its lower-cased ID.
+const RulesDocsURL = "https://github.com/moon-hex/gitops-validator/blob/main/docs/RULES.md"
+
+// RuleInfo describes a validation check and its stable identifier.
+// IDs are never renumbered or reused; new checks get the next free ID.
+type RuleInfo struct {
+	ID          string // Stable rule ID, e.g. GV0001
+	Type        string // ValidationResult.Type emitted by the check
+	Rule        string // Config rule name under gitops-validator.rules
+	Description string // One-line summary of what the check detects
+	Fix         string // One-line remediation hint for compact outputs
+	DocsURL     string // Documentation link of rules not documented in RULES.md
+}
+
+// Rules is the registry of all known checks, keyed by result type
+var Rules = []RuleInfo{
+	{ID: "GV0001", Type: "flux-kustomization-path", Rule: "flux-kustomization", Description: "Flux Kustomization spec.path does not exist", Fix: "Point spec.path at an existing directory relative to the Flux root"},
+	{ID: "GV0002", Type: "flux-kustomization-source", Rule: "flux-kustomization", Description: "Flux Kustomization spec.sourceRef is invalid", Fix: "Reference a GitRepository, OCIRepository or Bucket defined in the repository, or map it under sources"},
+	{ID: "GV0003", Type: "flux-postbuild-variables", Rule: "flux-postbuild-variables", Description: "Invalid Flux postBuild substitute variable name", Fix: "Rename the variable to letters, digits and underscores, not starting with a digit"},
+	{ID: "GV0004", Type: "kubernetes-kustomization", Rule: "kubernetes-kustomization", Description: "Broken or duplicate reference in kustomization.yaml", Fix: "Fix or remove the broken or duplicate reference"},
+	{ID: "GV0005", Type: "kustomization-resource", Rule: "kubernetes-kustomization", Description: "Broken or duplicate resources entry in kustomization.yaml", Fix: "Fix the path or remove the duplicate resources entry"},
+	{ID: "GV0006", Type: "kustomization-patch", Rule: "kubernetes-kustomization", Description: "Broken or duplicate patches entry in kustomization.yaml", Fix: "Fix the path or remove the duplicate patches entry"},
+	{ID: "GV0007", Type: "kustomization-strategic-merge", Rule: "kubernetes-kustomization", Description: "Broken patchesStrategicMerge entry in kustomization.yaml", Fix: "Fix the path of the patchesStrategicMerge entry"},
+	{ID: "GV0008", Type: "kustomization-version-consistency", Rule: "kustomization-version-consistency", Description: "Kustomization apiVersion mismatch across references", Fix: "Use the same kustomize.config.k8s.io apiVersion in referencing kustomizations"},
+	{ID: "GV0009", Type: "orphaned-resource", Rule: "orphaned-resources", Description: "YAML file not referenced by any kustomization or entry point", Fix: "Reference the file from a kustomization, or delete it"},
+	{ID: "GV0010", Type: "deprecated-api", Rule: "deprecated-apis", Description: "Resource uses an API version that is deprecated, or not served by the target Kubernetes version", Fix: "Migrate the resource to the supported apiVersion"},
+	{ID: "GV0011", Type: "http-route-policy", Rule: "http-route-policy", Description: "HTTPRoute or VirtualService without a SecurityPolicy in its namespace", Fix: "Add a SecurityPolicy to the route's namespace"},
+	{ID: "GV0012", Type: "resource-validation", Rule: "", Description: "Resource is missing apiVersion, kind or metadata.name", Fix: "Add the missing apiVersion, kind or metadata.name"},
+	{ID: "GV0013", Type: "kustomization-directory-target", Rule: "kubernetes-kustomization", Description: "Directory referenced from resources has no usable kustomization or mixes one with unlisted manifests", Fix: "Add a kustomization.yaml listing every manifest in the directory, or reference the files"},
+	{ID: "GV0014", Type: "symlink-target", Rule: "symlinks", Description: "Symlink is broken, points outside the repository, or makes a symlinked overlay resolve differently", Fix: "Point the symlink at an existing path inside the repository"},
+	{ID: "GV0015", Type: "helm-release-collision", Rule: "helm-release-collisions", Description: "HelmReleases in one cluster manage the same Helm release", Fix: "Give the HelmReleases distinct releaseName, targetNamespace or storageNamespace"},
+	{ID: "GV0016", Type: "namespace-collision", Rule: "namespace-collisions", Description: "Namespace is applied by more than one tenant or Flux Kustomization in a cluster", Fix: "Apply the Namespace from a single owner"},
+	{ID: "GV0017", Type: "flux-kustomization-common-metadata", Rule: "flux-kustomization", Description: "Flux Kustomization spec.commonMetadata is invalid or overwrites a selected label", Fix: "Use valid label and annotation keys and values that no selector in the tree matches"},
+	{ID: "GV0018", Type: "custom-assertion", Rule: "assertions", Description: "Resource does not satisfy a custom assertion from the config", Fix: "Change the resource to satisfy the assertion, or suppress it with a reason"},
+	{ID: "GV0019", Type: "flux-kustomization-depends-on", Rule: "flux-kustomization", Description: "Flux Kustomization spec.dependsOn is unresolved, self-referencing or cyclic", Fix: "Point dependsOn at an existing Flux Kustomization and break the cycle"},
+	{ID: "GV0020", Type: "target-namespace", Rule: "target-namespaces", Description: "Flux Kustomization or HelmRelease targetNamespace is not created by any Namespace manifest", Fix: "Add a Namespace manifest, or list the namespace under rules.target-namespaces.allowed"},
+	{ID: "GV0021", Type: "flux-interval", Rule: "flux-intervals", Description: "Flux interval, timeout or retryInterval is missing, malformed, overlapping or below the minimum", Fix: "Use durations such as 5m, a timeout below the interval and an interval at or above the minimum"},
+	{ID: "GV0022", Type: "flux-prune-wait", Rule: "flux-prune-wait", Description: "Flux Kustomization does not prune, or waits on a very large tree", Fix: "Set prune: true, and split large trees or use healthChecks instead of wait"},
+	{ID: "GV0023", Type: "kustomize-namespace", Rule: "kustomize-namespaces", Description: "kustomization.yaml namespace is written into cluster-scoped kinds or overridden by an including overlay", Fix: "Keep cluster-scoped custom resources out of kustomizations with a namespace, and set the namespace in one layer only"},
+	{ID: "GV0024", Type: "flux-kustomization-health-check", Rule: "flux-kustomization", Description: "Flux Kustomization spec.healthChecks entry does not match an object it applies", Fix: "Correct the apiVersion, kind, name or namespace of the healthChecks entry"},
+	{ID: "GV0025", Type: "flux-postbuild-substitute-from", Rule: "flux-postbuild-variables", Description: "Flux postBuild substituteFrom references a ConfigMap or Secret the repository does not create", Fix: "Add the ConfigMap or Secret to the repository, or mark the entry optional: true"},
+	{ID: "GV0026", Type: "helm-release-values-from", Rule: "helm-release-values", Description: "HelmRelease valuesFrom references a ConfigMap or Secret the repository does not create", Fix: "Add the ConfigMap or Secret to the HelmRelease's namespace, or mark the entry optional: true"},
+	{ID: "GV0027", Type: "yaml-anchor", Rule: "", Description: "YAML alias refers to an undefined anchor or itself, or a merge key does not refer to a mapping", Fix: "Define the anchor before its alias in the same document, and merge only mappings with <<"},
+	{ID: "GV0028", Type: "sops-config", Rule: "sops", Description: ".sops.yaml creation rule is malformed, or a sops-encrypted file matches no rule or is not encrypted for its keys", Fix: "Fix the key or path_regex in .sops.yaml, or run sops updatekeys on the file"},
+	{ID: "GV0029", Type: "helm-chart-version", Rule: "helm-chart-versions", Description: "HelmRelease chart or version is not served by its HelmRepository (--online only)", Fix: "Correct spec.chart.spec.chart or version to one listed in the repository's index.yaml"},
+	{ID: "GV0030", Type: "env-field-ref", Rule: "env-field-refs", Description: "Downward API fieldRef or resourceFieldRef that the API server rejects at pod creation", Fix: "Use a supported field path (e.g. metadata.name, metadata.labels['app']) or resource (e.g. limits.memory) and a container of the pod"},
+	{ID: "GV0031", Type: "multiple-inclusion", Rule: "multiple-inclusions", Description: "File included by several kustomizations of the same Flux Kustomization, which fails the kustomize build", Fix: "Include the file or base once, e.g. from the kustomization all its users share"},
+	{ID: "GV0032", Type: "image-automation-ref", Rule: "image-automation-refs", Description: "ImagePolicy or ImageUpdateAutomation references a missing ImageRepository or GitRepository", Fix: "Point spec.imageRepositoryRef or spec.sourceRef at an existing object, or grant access with spec.accessFrom"},
+	{ID: "GV0033", Type: "image-policy-marker", Rule: "image-policy-markers", Description: "$imagepolicy marker is malformed or names a missing ImagePolicy, or an ImagePolicy is used by no marker", Fix: "Write markers as {\"$imagepolicy\": \"<namespace>:<policy>[:tag|:name|:digest]\"} naming an existing ImagePolicy, and remove unused policies"},
+	{ID: "GV0034", Type: "notification-ref", Rule: "notification-refs", Description: "Alert or Receiver references a missing Provider, Secret or Flux object, or an unsupported kind or type", Fix: "Point providerRef at a Provider and secretRef at a Secret in the same namespace, and eventSources and resources at existing Flux objects or name '*'"},
+	{ID: "GV0035", Type: "cross-namespace-ref", Rule: "cross-namespace-refs", Description: "Flux object references an object in another namespace, which --no-cross-namespace-refs blocks", Fix: "Move the referenced object, or a copy of it, into the namespace of the referencing object and drop the namespace field"},
+	{ID: "GV0036", Type: "reference-graph", Rule: "reference-graph", Description: "kustomization.yaml files include each other in a cycle, or a reference chain is deeper than max-depth", Fix: "Remove one of the includes forming the cycle; flatten the chain or raise rules.reference-graph.max-depth"},
+	{ID: "GV0037", Type: "flux-kustomization-generated", Rule: "flux-kustomization", Description: "Flux Kustomization spec.path has no kustomization.yaml, so Flux applies every YAML file under it", Fix: "Add a kustomization.yaml to the directory listing the resources to deploy"},
+	{ID: "GV0038", Type: "sops-decryption", Rule: "sops-decryption", Description: "Flux Kustomization decryption secretRef names a missing Secret, or sops-encrypted files are applied without a decryption block", Fix: "Create the Secret or list it in rules.sops-decryption.cluster-managed-secrets; add spec.decryption with provider sops"},
+	{ID: "GV0039", Type: "flux-api-version", Rule: "flux-api-versions", Description: "Flux object uses an API version the target Flux version supersedes, or one it does not serve yet", Fix: "Change apiVersion to the one named in the message and replace the fields it removed"},
+	{ID: "GV0040", Type: "flux-service-account", Rule: "flux-service-accounts", Description: "Flux Kustomization or HelmRelease impersonates a ServiceAccount the repository does not create in its namespace", Fix: "Add the ServiceAccount manifest to the tenant's namespace, fix spec.serviceAccountName, or list it in cluster-managed-service-accounts"},
+	{ID: "GV0041", Type: "flux-postbuild-undefined-variable", Rule: "flux-postbuild-variables", Description: "Manifest applied by a Flux Kustomization uses a ${VAR} its postBuild does not define, which Flux replaces by an empty string", Fix: "Define the variable in postBuild.substitute or substituteFrom, give it a default with ${VAR:=value}, or escape it as $${VAR}"},
+	{ID: "GV0042", Type: "flux-postbuild-unused-variable", Rule: "flux-postbuild-variables", Description: "Flux Kustomization defines a postBuild.substitute variable none of the manifests it applies use (report-unused only)", Fix: "Remove the variable, or use it in the manifests"},
+	{ID: "GV0043", Type: "image-automation-write-back", Rule: "image-automation-write-back", Description: "ImageUpdateAutomation updates a missing path, has no branch to push to, or pushes to the branch Flux reconciles", Fix: "Fix spec.update.path, or set spec.git.push.branch to a branch other than the one the GitRepository reconciles"},
+	{ID: "GV0044", Type: "kustomization-generator", Rule: "kubernetes-kustomization", Description: "configMapGenerator or secretGenerator reads a file that does not exist", Fix: "Fix the files or envs entry, or add the file next to the kustomization.yaml"},
+	{ID: "GV0045", Type: "rendered-name-collision", Rule: "rendered-name-collisions", Description: "Objects of the same kind render to the same name in the same namespace after namePrefix, nameSuffix and namespace transformers", Fix: "Change a namePrefix, nameSuffix or namespace so the rendered names differ, or apply the object from one place"},
+	{ID: "GV0046", Type: "kustomize-image", Rule: "kustomize-images", Description: "kustomization.yaml images entry overrides an image none of its resources use, or has an invalid newTag or digest", Fix: "Match the entry name to an image the kustomization builds, or remove the entry; quote newTag and use a valid tag or digest"},
+	{ID: "GV0047", Type: "kustomize-replacement", Rule: "kustomize-replacements", Description: "kustomization.yaml replacement selects no or several source resources, or a source or target fieldPath that does not exist", Fix: "Point the selector at a resource the kustomization builds and the fieldPath at an existing field, or set options.create on the target"},
+	{ID: "GV0048", Type: "kustomization-json6902", Rule: "kubernetes-kustomization", Description: "patchesJson6902 entry in kustomization.yaml has a missing file or target, or targets no resource the kustomization builds", Fix: "Fix the path, and point the target at the group, version, kind and name of a resource the kustomization builds"},
+	{ID: "GV0049", Type: "kustomize-remote-resource", Rule: "kustomize-remote-resources", Description: "Remote kustomization resource is not a valid git, HTTP(S) or supported URL, is not pinned to a fixed ref, or does not exist (--online)", Fix: "Use a git URL such as github.com/org/repo//path?ref=v1.2.3 or an HTTPS file URL, pinned to a tag or commit"},
+	{ID: "GV0050", Type: "kustomize-build", Rule: "kustomize-build", Description: "kustomize build of a kustomization Flux applies fails (--render only)", Fix: "Fix the kustomization, patch or resource the kustomize error names, and check with kustomize build"},
+	{ID: "GV0051", Type: "missing-namespace", Rule: "missing-namespaces", Description: "Resources are applied to a namespace no Namespace manifest creates", Fix: "Add a Namespace manifest, or list the namespace under rules.missing-namespaces.allowed"},
+	{ID: "GV0052", Type: "secret-ref", Rule: "secret-refs", Description: "Workload references a Secret the repository does not create", Fix: "Create the Secret, mark the reference optional, or list it under rules.secret-refs.external"},
+	{ID: "GV0053", Type: "configmap-ref", Rule: "configmap-refs", Description: "Workload references a ConfigMap or ConfigMap key the repository does not create", Fix: "Create the ConfigMap or key, mark the reference optional, or list it under rules.configmap-refs.external"},
+	{ID: "GV0054", Type: "workload-service-account", Rule: "workload-service-accounts", Description: "Workload runs as a ServiceAccount the repository does not create, or as default next to its own", Fix: "Create the ServiceAccount, set serviceAccountName, or list it in cluster-managed-service-accounts"},
+	{ID: "GV0055", Type: "storage-class", Rule: "storage-classes", Description: "PersistentVolumeClaim uses a StorageClass that is not in the repository or the cluster list", Fix: "Fix storageClassName, add the StorageClass, or list it in cluster-storage-classes"},
+	{ID: "GV0056", Type: "route-backend", Rule: "route-backends", Description: "Ingress or HTTPRoute backend Service or port does not exist", Fix: "Fix the backend name or port, create the Service or ReferenceGrant, or list it in external-services"},
+	{ID: "GV0057", Type: "service-selector", Rule: "service-selectors", Description: "Service selector matches no workload, or several workloads", Fix: "Fix the selector or the pod template labels, or list the Service in external-workloads"},
+	{ID: "GV0058", Type: "network-policy", Rule: "network-policies", Description: "NetworkPolicy selects no workload, or a namespace has no NetworkPolicy", Fix: "Fix the podSelector, add a NetworkPolicy, or list the namespace in exclude-namespaces"},
+	{ID: "GV0059", Type: "custom-resource", Rule: "custom-resources", Description: "Custom resource has no CustomResourceDefinition in the repository or the known-crds list", Fix: "Add the CRD to the repository, or list its group or name in known-crds"},
+	{ID: "GV0060", Type: "schema", Rule: "kubeconform", Description: "Resource does not match its JSON schema (kubeconform)", Fix: "Fix the fields kubeconform reports, or the Kubernetes version and schema locations"},
+	{ID: "GV0061", Type: "container-resources", Rule: "container-resources", Description: "Container does not set the required resource requests or limits", Fix: "Set the missing requests and limits, or exempt the namespace or path"},
+	{ID: "GV0062", Type: "image-tag", Rule: "image-tags", Description: "Container image breaks the image policy (latest or missing tag, no digest, registry not allowed)", Fix: "Pin a version or digest, or pull the image from an allowed registry"},
+	{ID: "GV0063", Type: "label-policy", Rule: "label-policies", Description: "Resource lacks a label or annotation a policy requires, or its value does not match", Fix: "Set the label or annotation, for example with commonLabels, to a value the policy allows"},
+	{ID: "GV0900", Type: "validator-error", Rule: "", Description: "A validator failed to run", Fix: "Report the validator error as a bug"},
+	{ID: "GV0901", Type: "pipeline-error", Rule: "", Description: "The validation pipeline failed to run", Fix: "Fix the failing required pipeline stage"},
+	{ID: "GV0902", Type: "pipeline-stage-error", Rule: "", Description: "A validation pipeline stage failed to run", Fix: "Fix the failing pipeline stage"},
+	{ID: "GV0903", Type: "network-unavailable", Rule: "", Description: "A network-dependent check could not reach its endpoint", Fix: "Rerun when the endpoint is reachable, or skip network checks with --offline"},
+}
+
+// LookupRuleByType returns the rule registered for a result type
+func LookupRuleByType(resultType string) (RuleInfo, bool) {
+	for _, rule := range Rules {
+		if rule.Type == resultType {
+			return rule, true
+		}
+	}
+	return RuleInfo{}, false
+}
+
+// LookupRuleByID returns the rule registered under a stable ID (case-insensitive)
+func LookupRuleByID(id string) (RuleInfo, bool) {
+	for _, rule := range Rules {
+		if strings.EqualFold(rule.ID, id) {
+			return rule, true
+		}
+	}
+	return RuleInfo{}, false
+}
+
+// RuleDocsURL returns the documentation URL for a rule ID
+func RuleDocsURL(id string) string {
+	if rule, ok := LookupRuleByID(id); ok && rule.DocsURL != "" {
+		return rule.DocsURL
+	}
+	return RulesDocsURL + "#" + strings.ToLower(id)
+}
+
+// AnnotateRuleMetadata fills in RuleID and DocsURL for results whose type is registered.
+// Results that already carry a RuleID are left untouched.
+func AnnotateRuleMetadata(results []ValidationResult) {
+	for i := range results {
+		if results[i].RuleID != "" {
+			continue
+		}
+		if rule, ok := LookupRuleByType(results[i].Type); ok {
+			results[i].RuleID = rule.ID
+			results[i].DocsURL = RuleDocsURL(rule.ID)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Severity is the severity of a validation result
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+)
+
+// Severities lists the valid severities, most severe first
+var Severities = []Severity{SeverityError, SeverityWarning, SeverityInfo}
+
+// ParseSeverity parses a severity, ignoring case and surrounding spaces. A
+// misspelled severity is an error rather than a result no summary counts.
+func ParseSeverity(text string) (Severity, error) {
+	severity := Severity(strings.ToLower(strings.TrimSpace(text)))
+	if !severity.Valid() {
+		return "", fmt.Errorf("invalid severity '%s', must be error, warning, or info", text)
+	}
+	return severity, nil
+}
+
+// Valid reports whether the severity is error, warning or info
+func (s Severity) Valid() bool {
+	return s.Rank() > 0
+}
+
+// Rank orders severities: info 1, warning 2, error 3 and anything else 0
+func (s Severity) Rank() int {
+	switch s {
+	case SeverityInfo:
+		return 1
+	case SeverityWarning:
+		return 2
+	case SeverityError:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// Escalated returns the next higher severity; errors stay errors
+func (s Severity) Escalated() Severity {
+	switch s {
+	case SeverityInfo:
+		return SeverityWarning
+	case SeverityWarning:
+		return SeverityError
+	default:
+		return s
+	}
+}
+
+// String returns the severity as written in config and output
+func (s Severity) String() string {
+	return string(s)
+}
+
+// UnmarshalText reads a severity from config files and JSON, normalizing
+// case and spaces. Unknown values are kept as written, so config validation
+// can report them with the setting they belong to.
+func (s *Severity) UnmarshalText(text []byte) error {
+	if severity, err := ParseSeverity(string(text)); err == nil {
+		*s = severity
+	} else {
+		*s = Severity(text)
+	}
+	return nil
+}
@@ -2,13 +2,22 @@ package types
 
 // ValidationResult represents the result of a validation check
 type ValidationResult struct {
-	Type     string `json:"type"`
-	Severity string `json:"severity"` // error, warning, info
-	Message  string `json:"message"`
-	File     string `json:"file,omitempty"`
-	Line     int    `json:"line,omitempty"`
-	Resource string `json:"resource,omitempty"`
+	Type     string   `json:"type"`
+	Severity Severity `json:"severity"` // error, warning, info
+	Message  string   `json:"message"`
+	File     string   `json:"file,omitempty"`
+	Line     int      `json:"line,omitempty"`
+	Column   int      `json:"column,omitempty"` // starts at 1; 0 when only the line is known
+	Resource string   `json:"resource,omitempty"`
 	// Category is set by the orphaned-resource validator when path-based
 	// categories are configured. Used for grouped output.
 	Category string `json:"category,omitempty"`
+	// RuleID is the stable identifier of the check that produced this result
+	// (e.g. GV0001). Filled in from the rule registry; see rules.go.
+	RuleID string `json:"ruleId,omitempty"`
+	// DocsURL links to the remediation documentation for RuleID.
+	DocsURL string `json:"docsUrl,omitempty"`
+	// AffectedEntryPoints lists the Flux Kustomizations applying File when
+	// several do and severity propagation is enabled
+	AffectedEntryPoints []string `json:"affectedEntryPoints,omitempty"`
 }
@@ -0,0 +1,86 @@
+package validator
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/moon-hex/gitops-validator/internal/types"
+)
+
+// shieldsBadge is a shields.io endpoint badge descriptor
+// (https://shields.io/badges/endpoint-badge)
+type shieldsBadge struct {
+	SchemaVersion int    `json:"schemaVersion"`
+	Label         string `json:"label"`
+	Message       string `json:"message"`
+	Color         string `json:"color"`
+}
+
+// SetBadgeMetric selects what the badge output reports: "health" (the health
+// score, default) or "errors" (the number of errors)
+func (v *Validator) SetBadgeMetric(metric string) error {
+	switch m := strings.ToLower(strings.TrimSpace(metric)); m {
+	case "", "health":
+		v.badgeMetric = "health"
+	case "errors":
+		v.badgeMetric = m
+	default:
+		return fmt.Errorf("unknown badge metric '%s' (expected health or errors)", metric)
+	}
+	return nil
+}
+
+// renderBadge writes a shields.io endpoint badge for the run. Every result
+// counts, regardless of aggregation filters.
+func (v *Validator) renderBadge(out io.Writer, health types.HealthScore) error {
+	var badge shieldsBadge
+	if v.badgeMetric == "errors" {
+		badge = errorsBadge(v.results)
+	} else {
+		badge = healthBadge(health)
+	}
+
+	b, err := json.MarshalIndent(badge, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error formatting badge output: %w", err)
+	}
+	_, err = fmt.Fprintln(out, string(b))
+	return err
+}
+
+// healthBadge shows the health score and grade, colored by grade
+func healthBadge(health types.HealthScore) shieldsBadge {
+	colors := map[string]string{"A": "brightgreen", "B": "green", "C": "yellowgreen", "D": "yellow"}
+	color, ok := colors[health.Grade]
+	if !ok {
+		color = "red"
+	}
+
+	return shieldsBadge{
+		SchemaVersion: 1,
+		Label:         "gitops health",
+		Message:       fmt.Sprintf("%s/100 (%s)", strconv.FormatFloat(health.Score, 'f', -1, 64), health.Grade),
+		Color:         color,
+	}
+}
+
+// errorsBadge shows the number of errors: green when there are none
+func errorsBadge(results []types.ValidationResult) shieldsBadge {
+	errors := 0
+	for _, result := range results {
+		if result.Severity == types.SeverityError {
+			errors++
+		}
+	}
+
+	badge := shieldsBadge{SchemaVersion: 1, Label: "gitops validation", Message: "passing", Color: "brightgreen"}
+	if errors == 1 {
+		badge.Message, badge.Color = "1 error", "red"
+	} else if errors > 1 {
+		badge.Message, badge.Color = fmt.Sprintf("%d errors", errors), "red"
+	}
+	return badge
+}
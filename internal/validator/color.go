@@ -0,0 +1,62 @@
+package validator
+
+import (
+	"os"
+
+	"github.com/moon-hex/gitops-validator/internal/types"
+)
+
+// ANSI escape sequences used by the default human-readable output
+const (
+	ansiReset  = "\033[0m"
+	ansiRed    = "\033[31m"
+	ansiYellow = "\033[33m"
+	ansiCyan   = "\033[36m"
+	ansiGreen  = "\033[32m"
+)
+
+// colorSupported reports whether ANSI colors should be written to stdout.
+// Colors are disabled when NO_COLOR is set (https://no-color.org) or when
+// stdout is not a terminal (pipes, files, CI logs).
+func colorSupported() bool {
+	if _, set := os.LookupEnv("NO_COLOR"); set {
+		return false
+	}
+	return isTerminal(os.Stdout)
+}
+
+// isTerminal reports whether f is attached to a character device
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// SetNoColor disables ANSI colors regardless of terminal detection
+func (v *Validator) SetNoColor(noColor bool) {
+	v.color = !noColor && colorSupported()
+}
+
+// colorize wraps text in the given ANSI color when colors are enabled
+func (v *Validator) colorize(color, text string) string {
+	if !v.color || color == "" {
+		return text
+	}
+	return color + text + ansiReset
+}
+
+// severityColor returns the ANSI color for a result severity
+func severityColor(severity types.Severity) string {
+	switch severity {
+	case types.SeverityError:
+		return ansiRed
+	case types.SeverityWarning:
+		return ansiYellow
+	case types.SeverityInfo:
+		return ansiCyan
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,92 @@
+package validator
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/parser"
+	"github.com/moon-hex/gitops-validator/internal/types"
+)
+
+// entryPointAttribution labels the Flux Kustomizations applying each file, so
+// results can be grouped by the cluster and app they affect
+func (v *Validator) entryPointAttribution() map[string][]string {
+	if v.graph == nil {
+		return nil
+	}
+
+	ctx := context.NewValidationContext(v.graph, v.config, v.repoPath, false)
+	attribution := make(map[string][]string)
+	for file, kustomizations := range ctx.ApplyingKustomizations() {
+		for _, kustomization := range kustomizations {
+			attribution[file] = append(attribution[file], v.entryPointLabel(kustomization))
+		}
+	}
+	return attribution
+}
+
+// entryPointLabel names a Flux Kustomization and the file defining it, whose
+// path usually tells the cluster (flux-system/apps (clusters/production/apps.yaml))
+func (v *Validator) entryPointLabel(kustomization *parser.ParsedResource) string {
+	file := kustomization.File
+	if rel, err := filepath.Rel(v.repoPath, file); err == nil {
+		file = filepath.ToSlash(rel)
+	}
+	return fmt.Sprintf("%s (%s)", kustomization.GetResourceKey(), file)
+}
+
+// printEntryPointGroups prints results under the entry points applying them,
+// least healthy entry points first
+func (v *Validator) printEntryPointGroups(out io.Writer, aggregated *types.AggregatedResults) {
+	for i, entryPoint := range aggregated.EntryPoints {
+		if i > 0 {
+			fmt.Fprintln(out)
+		}
+		results := aggregated.Groups[entryPoint.EntryPoint]
+		fmt.Fprintln(out, v.colorize(ansiCyan, fmt.Sprintf("🚀 %s (%d):", entryPoint.EntryPoint, len(results))))
+		for _, result := range results {
+			v.printResultLine(out, result, "  ")
+		}
+	}
+}
+
+// printEntryPointSources lists the entry points with what made each one, so
+// entry points hiding orphaned files can be traced to their configuration
+func (v *Validator) printEntryPointSources(ctx *context.ValidationContext) {
+	entryPoints := ctx.FindEntryPointSources()
+
+	var order []*parser.ParsedResource
+	sources := make(map[*parser.ParsedResource][]string)
+	seen := make(map[context.EntryPoint]bool)
+	for _, entryPoint := range entryPoints {
+		if seen[entryPoint] {
+			continue
+		}
+		seen[entryPoint] = true
+		if _, listed := sources[entryPoint.Resource]; !listed {
+			order = append(order, entryPoint.Resource)
+		}
+		sources[entryPoint.Resource] = append(sources[entryPoint.Resource], entryPoint.Source)
+	}
+
+	if len(order) == 0 {
+		fmt.Printf("No entry points (auto-detect: %s)\n", v.config.GetEntryPointAutoDetect())
+		return
+	}
+	fmt.Printf("Entry points (%d):\n", len(order))
+	for _, resource := range order {
+		label := resource.Kind + " " + v.entryPointLabel(resource)
+		if parser.ClassifyResource(resource) == parser.ResourceTypeKubernetesKustomization {
+			// kustomization files have no name; their file says it all
+			file := resource.File
+			if rel, err := filepath.Rel(v.repoPath, file); err == nil {
+				file = filepath.ToSlash(rel)
+			}
+			label = resource.Kind + " " + file
+		}
+		fmt.Printf("  %s [%s]\n", label, strings.Join(sources[resource], ", "))
+	}
+}
@@ -0,0 +1,59 @@
+package validator
+
+import (
+	"strings"
+
+	"github.com/moon-hex/gitops-validator/internal/types"
+)
+
+// mappedExitCode returns the exit code configured under exit-codes.rules for
+// the findings that fail the run, or 0 if none is mapped. A finding fails the
+// run when fail-on-<severity> is set for its severity, and matches the mapping
+// for its category (an assertion name or orphaned-resource category), rule ID,
+// result type or config rule, in that order. When findings map to several
+// codes the highest wins.
+func (v *Validator) mappedExitCode() int {
+	mappings := v.config.GitOpsValidator.ExitCodes.Rules
+	if len(mappings) == 0 {
+		return 0
+	}
+
+	codes := make(map[string]int, len(mappings))
+	for rule, code := range mappings {
+		// Higher codes are reserved by shells for unrunnable commands and signals
+		if code >= 1 && code <= 125 {
+			codes[strings.ToLower(strings.TrimSpace(rule))] = code
+		}
+	}
+
+	exitCodes := v.config.GitOpsValidator.ExitCodes
+	failing := map[types.Severity]bool{
+		types.SeverityError:   exitCodes.FailOnErrors,
+		types.SeverityWarning: exitCodes.FailOnWarnings,
+		types.SeverityInfo:    exitCodes.FailOnInfo,
+	}
+
+	mapped := 0
+	for _, result := range v.results {
+		if !failing[result.Severity] {
+			continue
+		}
+
+		ruleName := ""
+		if info, ok := types.LookupRuleByType(result.Type); ok {
+			ruleName = info.Rule
+		}
+		for _, id := range []string{result.Category, result.RuleID, result.Type, ruleName} {
+			if id == "" {
+				continue
+			}
+			if code, ok := codes[strings.ToLower(id)]; ok {
+				if code > mapped {
+					mapped = code
+				}
+				break
+			}
+		}
+	}
+	return mapped
+}
@@ -0,0 +1,94 @@
+package validator
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/pathutil"
+	"github.com/moon-hex/gitops-validator/internal/types"
+)
+
+// validateSeverityFloors checks the configured severity floors, so a typo in
+// a rule name fails the run instead of leaving production unprotected
+func (v *Validator) validateSeverityFloors() error {
+	for _, floor := range v.config.GitOpsValidator.SeverityFloors {
+		if floor.Path == "" {
+			return fmt.Errorf("severity floor requires a path")
+		}
+		if !floor.Severity.Valid() {
+			return fmt.Errorf("invalid severity '%s' for severity floor '%s', must be error, warning, or info", floor.Severity, floor.Path)
+		}
+		for _, rule := range floor.Rules {
+			if !isKnownRule(strings.ToLower(rule)) {
+				return fmt.Errorf("unknown rule '%s' in severity floor '%s' (use a rule ID, config rule name or result type from docs/RULES.md)", rule, floor.Path)
+			}
+		}
+	}
+	return nil
+}
+
+// applySeverityFloors raises results to the minimum severity of the floors
+// matching them, so a rule can warn in staging and block in production with
+// one config. A floor matches a result in a file under its path, or in a file
+// deployed by a Flux Kustomization defined under it. Runs after --severity
+// overrides, which cannot lower a result below its floor.
+func (v *Validator) applySeverityFloors(results []types.ValidationResult) {
+	floors := v.config.GitOpsValidator.SeverityFloors
+	if len(floors) == 0 {
+		return
+	}
+
+	v.deployedByOnce.Do(func() {
+		v.deployedBy = make(map[string][]string)
+		if v.graph == nil {
+			return
+		}
+		ctx := context.NewValidationContext(v.graph, v.config, v.repoPath, false)
+		for _, kustomization := range v.graph.GetFluxKustomizations() {
+			seen := make(map[string]bool)
+			for _, deployed := range ctx.DeploymentTree(kustomization) {
+				file := deployed.Resource.File
+				if !seen[file] {
+					seen[file] = true
+					v.deployedBy[file] = append(v.deployedBy[file], v.relativePath(kustomization.File))
+				}
+			}
+		}
+	})
+
+	for i := range results {
+		ruleName := ""
+		if info, ok := types.LookupRuleByType(results[i].Type); ok {
+			ruleName = info.Rule
+		}
+		paths := append([]string{v.relativePath(results[i].File)}, v.deployedBy[results[i].File]...)
+
+		for _, floor := range floors {
+			if results[i].Severity.Rank() >= floor.Severity.Rank() ||
+				!floor.MatchesRule(results[i].RuleID, ruleName, results[i].Type) {
+				continue
+			}
+			for _, path := range paths {
+				if pathutil.MatchPattern(path, floor.Path) {
+					results[i].Severity = floor.Severity
+					results[i].Message = fmt.Sprintf("%s (raised to %s by the severity floor for %s", results[i].Message, floor.Severity, floor.Path)
+					if floor.Reason != "" {
+						results[i].Message += ": " + floor.Reason
+					}
+					results[i].Message += ")"
+					break
+				}
+			}
+		}
+	}
+}
+
+// relativePath returns file relative to the repository root with forward slashes
+func (v *Validator) relativePath(file string) string {
+	if rel, err := filepath.Rel(v.repoPath, file); err == nil {
+		return filepath.ToSlash(rel)
+	}
+	return file
+}
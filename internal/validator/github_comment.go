@@ -0,0 +1,196 @@
+package validator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/moon-hex/gitops-validator/internal/network"
+	"github.com/moon-hex/gitops-validator/internal/types"
+)
+
+// githubCommentMarker identifies the pull request comment this tool maintains
+const githubCommentMarker = "<!-- gitops-validator -->"
+
+// githubPullRequest is the pull request a report is posted to
+type githubPullRequest struct {
+	apiURL     string
+	repository string // owner/name
+	number     int
+	token      string
+}
+
+// githubComment is the subset of the issue comment API object used here
+type githubComment struct {
+	ID      int64  `json:"id"`
+	Body    string `json:"body"`
+	HTMLURL string `json:"html_url"`
+}
+
+// SetGitHubComment posts the Markdown report as a single pull request comment
+// that is updated on every run instead of adding a new one
+func (v *Validator) SetGitHubComment(enabled bool) {
+	v.githubComment = enabled
+}
+
+// githubPullRequestFromEnv reads the pull request to comment on from the
+// GitHub Actions environment: GITHUB_TOKEN, GITHUB_REPOSITORY and the number
+// from the pull_request event payload or a refs/pull/<n>/merge GITHUB_REF
+func githubPullRequestFromEnv() (githubPullRequest, error) {
+	pr := githubPullRequest{
+		apiURL:     strings.TrimSuffix(os.Getenv("GITHUB_API_URL"), "/"),
+		repository: os.Getenv("GITHUB_REPOSITORY"),
+		token:      os.Getenv("GITHUB_TOKEN"),
+	}
+	if pr.apiURL == "" {
+		pr.apiURL = "https://api.github.com"
+	}
+	if pr.token == "" {
+		return pr, fmt.Errorf("GITHUB_TOKEN is not set")
+	}
+	if pr.repository == "" {
+		return pr, fmt.Errorf("GITHUB_REPOSITORY is not set")
+	}
+
+	if eventPath := os.Getenv("GITHUB_EVENT_PATH"); eventPath != "" {
+		if data, err := os.ReadFile(eventPath); err == nil {
+			var event struct {
+				Number      int `json:"number"`
+				PullRequest struct {
+					Number int `json:"number"`
+				} `json:"pull_request"`
+			}
+			if json.Unmarshal(data, &event) == nil {
+				pr.number = event.PullRequest.Number
+				if pr.number == 0 {
+					pr.number = event.Number
+				}
+			}
+		}
+	}
+	if pr.number == 0 {
+		ref := os.Getenv("GITHUB_REF")
+		if strings.HasPrefix(ref, "refs/pull/") {
+			pr.number, _ = strconv.Atoi(strings.Split(strings.TrimPrefix(ref, "refs/pull/"), "/")[0])
+		}
+	}
+	if pr.number == 0 {
+		return pr, fmt.Errorf("no pull request number in GITHUB_EVENT_PATH or GITHUB_REF (not a pull_request workflow?)")
+	}
+
+	return pr, nil
+}
+
+// publishGitHubComment creates or updates the report comment on the pull
+// request. Repositories validated in one run each maintain their own comment.
+func (v *Validator) publishGitHubComment(results []types.ValidationResult, health types.HealthScore, git *GitMetadata) error {
+	pr, err := githubPullRequestFromEnv()
+	if err != nil {
+		return err
+	}
+
+	marker := githubCommentMarker
+	if v.outputLabel != "" {
+		marker = fmt.Sprintf("<!-- gitops-validator:%s -->", v.outputLabel)
+	}
+
+	var body bytes.Buffer
+	fmt.Fprintln(&body, marker)
+	if v.outputLabel != "" {
+		fmt.Fprintf(&body, "# 📂 %s\n\n", v.repoPath)
+	}
+	renderMarkdown(&body, results, health, git)
+
+	client := network.NewClient(v.config.GitOpsValidator.Network)
+	existing, err := findGitHubComment(client, pr, marker)
+	if err != nil {
+		return err
+	}
+
+	payload := map[string]string{"body": body.String()}
+	var comment githubComment
+	if existing != nil {
+		err = githubRequest(client, pr, http.MethodPatch, fmt.Sprintf("/repos/%s/issues/comments/%d", pr.repository, existing.ID), payload, &comment)
+	} else {
+		err = githubRequest(client, pr, http.MethodPost, fmt.Sprintf("/repos/%s/issues/%d/comments", pr.repository, pr.number), payload, &comment)
+	}
+	if err != nil {
+		return err
+	}
+
+	if v.verbose {
+		action := "created"
+		if existing != nil {
+			action = "updated"
+		}
+		fmt.Printf("GitHub comment %s: %s\n", action, comment.HTMLURL)
+	}
+	return nil
+}
+
+// findGitHubComment returns the pull request comment carrying marker, if any
+func findGitHubComment(client *network.Client, pr githubPullRequest, marker string) (*githubComment, error) {
+	for page := 1; ; page++ {
+		var comments []githubComment
+		path := fmt.Sprintf("/repos/%s/issues/%d/comments?per_page=100&page=%d", pr.repository, pr.number, page)
+		if err := githubRequest(client, pr, http.MethodGet, path, nil, &comments); err != nil {
+			return nil, err
+		}
+		for i := range comments {
+			if strings.HasPrefix(comments[i].Body, marker) {
+				return &comments[i], nil
+			}
+		}
+		if len(comments) < 100 {
+			return nil, nil
+		}
+	}
+}
+
+// githubRequest calls the GitHub REST API, decoding the response into out.
+// Failed reads and updates are retried as configured under network:.
+func githubRequest(client *network.Client, pr githubPullRequest, method, path string, payload, out interface{}) error {
+	var reqBody io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, pr.apiURL+path, reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+pr.token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		json.NewDecoder(resp.Body).Decode(&apiErr)
+		return fmt.Errorf("%s %s: %s %s", method, path, resp.Status, apiErr.Message)
+	}
+
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,28 @@
+package validator
+
+import (
+	"github.com/moon-hex/gitops-validator/internal/types"
+)
+
+// healthScore computes the repository health score for all results of the run
+// using the configured weights
+func (v *Validator) healthScore() types.HealthScore {
+	resources := 0
+	if v.graph != nil {
+		for _, fileResources := range v.graph.Files {
+			resources += len(fileResources)
+		}
+	}
+
+	// Configured weights override the defaults key by key
+	weights := types.DefaultHealthWeights()
+	cfg := v.config.GitOpsValidator.HealthScore
+	for severity, weight := range cfg.SeverityWeights {
+		weights.Severity[severity] = weight
+	}
+	for rule, weight := range cfg.RuleWeights {
+		weights.Rules[rule] = weight
+	}
+
+	return types.ComputeHealthScore(v.results, resources, weights)
+}
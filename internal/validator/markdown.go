@@ -0,0 +1,174 @@
+package validator
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/moon-hex/gitops-validator/internal/types"
+)
+
+// markdownMaxRows caps the rows listed per severity, keeping reports within
+// the size limits of pull request comments
+const markdownMaxRows = 50
+
+// markdownSection is the collapsible section of one severity
+type markdownSection struct {
+	severity types.Severity
+	title    string
+	open     bool // expanded by default
+}
+
+// markdownSeverities lists the severity sections in order; errors start expanded
+var markdownSeverities = []markdownSection{
+	{types.SeverityError, "❌ Errors", true},
+	{types.SeverityWarning, "⚠️ Warnings", false},
+	{types.SeverityInfo, "ℹ️ Info", false},
+}
+
+// renderMarkdown writes results for GitHub/GitLab pull request comments: a
+// summary line, then a collapsible section per severity holding a
+// collapsible table per file. Sections list at most markdownMaxRows results
+// and end with an "N more" footer linking to the CI run when one is known.
+// The validated revision is named under the title when known.
+func renderMarkdown(out io.Writer, results []types.ValidationResult, health types.HealthScore, git *GitMetadata) {
+	bySeverity := make(map[types.Severity][]types.ValidationResult)
+	for _, r := range results {
+		bySeverity[r.Severity] = append(bySeverity[r.Severity], r)
+	}
+
+	fmt.Fprintln(out, "## GitOps Validator Results")
+	fmt.Fprintln(out)
+	if git != nil {
+		fmt.Fprintf(out, "🔖 Revision: %s\n\n", git)
+	}
+	fmt.Fprintf(out, "**%d issues found** · ❌ %d errors · ⚠️ %d warnings · ℹ️ %d info · Health score %s/100 (%s)\n\n",
+		len(results), len(bySeverity[types.SeverityError]), len(bySeverity[types.SeverityWarning]), len(bySeverity[types.SeverityInfo]),
+		strconv.FormatFloat(health.Score, 'f', -1, 64), health.Grade)
+	if len(results) == 0 {
+		fmt.Fprintln(out, "✅ All validations passed!")
+		return
+	}
+
+	sections := append([]markdownSection(nil), markdownSeverities...)
+	for severity := range bySeverity {
+		if !severity.Valid() {
+			sections = append(sections, markdownSection{severity: severity, title: "📝 " + string(severity)})
+		}
+	}
+
+	for _, section := range sections {
+		sectionResults := bySeverity[section.severity]
+		if len(sectionResults) == 0 {
+			continue
+		}
+
+		open := ""
+		if section.open {
+			open = " open"
+		}
+		fmt.Fprintf(out, "<details%s>\n<summary><b>%s (%d)</b></summary>\n\n", open, section.title, len(sectionResults))
+
+		files, byFile := groupMarkdownFiles(sectionResults)
+		listed := 0
+		for _, file := range files {
+			if listed >= markdownMaxRows {
+				break
+			}
+			fileResults := byFile[file]
+			if remaining := markdownMaxRows - listed; len(fileResults) > remaining {
+				fileResults = fileResults[:remaining]
+			}
+			listed += len(fileResults)
+			renderMarkdownFile(out, file, fileResults, len(byFile[file]))
+		}
+
+		if more := len(sectionResults) - listed; more > 0 {
+			footer := fmt.Sprintf("… and %d more", more)
+			if url := ciRunURL(); url != "" {
+				footer = fmt.Sprintf("[%s](%s)", footer, url)
+			}
+			fmt.Fprintf(out, "%s\n\n", footer)
+		}
+		fmt.Fprintln(out, "</details>")
+		fmt.Fprintln(out)
+	}
+}
+
+// groupMarkdownFiles groups results by file, files in name order and each
+// file's results by line
+func groupMarkdownFiles(results []types.ValidationResult) ([]string, map[string][]types.ValidationResult) {
+	byFile := make(map[string][]types.ValidationResult)
+	for _, r := range results {
+		byFile[r.File] = append(byFile[r.File], r)
+	}
+
+	files := make([]string, 0, len(byFile))
+	for file, fileResults := range byFile {
+		files = append(files, file)
+		sort.SliceStable(fileResults, func(i, j int) bool { return fileResults[i].Line < fileResults[j].Line })
+	}
+	sort.Strings(files)
+	return files, byFile
+}
+
+// renderMarkdownFile writes the collapsible table of one file's results;
+// total is the file's result count before capping
+func renderMarkdownFile(out io.Writer, file string, results []types.ValidationResult, total int) {
+	name := "(no file)"
+	if file != "" {
+		name = "<code>" + file + "</code>"
+	}
+	fmt.Fprintf(out, "<details>\n<summary>%s (%d)</summary>\n\n", name, total)
+
+	withCategory := false
+	for _, r := range results {
+		if r.Category != "" {
+			withCategory = true
+		}
+	}
+
+	if withCategory {
+		fmt.Fprintln(out, "| Line | Rule | Type | Message | Resource | Category |")
+		fmt.Fprintln(out, "|---:|---|---|---|---|---|")
+	} else {
+		fmt.Fprintln(out, "| Line | Rule | Type | Message | Resource |")
+		fmt.Fprintln(out, "|---:|---|---|---|---|")
+	}
+	for _, r := range results {
+		rule := r.RuleID
+		if r.DocsURL != "" {
+			rule = fmt.Sprintf("[%s](%s)", r.RuleID, r.DocsURL)
+		}
+		line := ""
+		if r.Line > 0 {
+			line = fmt.Sprintf("%d", r.Line)
+		}
+		row := fmt.Sprintf("| %s | %s | %s | %s | %s |", line, rule, r.Type, markdownCell(r.Message), markdownCell(r.Resource))
+		if withCategory {
+			row += fmt.Sprintf(" %s |", markdownCell(r.Category))
+		}
+		fmt.Fprintln(out, row)
+	}
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "</details>")
+	fmt.Fprintln(out)
+}
+
+// markdownCell escapes text for a table cell
+func markdownCell(text string) string {
+	text = strings.ReplaceAll(text, "|", "\\|")
+	return strings.ReplaceAll(text, "\n", "<br>")
+}
+
+// ciRunURL returns the URL of the GitHub Actions run or GitLab CI job producing
+// the report, where the full log can be read, or "" outside CI
+func ciRunURL() string {
+	if server, repo, run := os.Getenv("GITHUB_SERVER_URL"), os.Getenv("GITHUB_REPOSITORY"), os.Getenv("GITHUB_RUN_ID"); server != "" && repo != "" && run != "" {
+		return fmt.Sprintf("%s/%s/actions/runs/%s", server, repo, run)
+	}
+	return os.Getenv("CI_JOB_URL")
+}
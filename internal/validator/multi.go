@@ -0,0 +1,127 @@
+package validator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// exitCodeRank orders the built-in exit codes from least to most severe when
+// several repositories are validated in one run
+var exitCodeRank = map[int]int{0: 0, 3: 1, 2: 2, 1: 3}
+
+// moreSevereExitCode reports whether code a outranks code b. Codes mapped in
+// exit-codes.rules outrank the built-in ones, and higher ones outrank lower.
+func moreSevereExitCode(a, b int) bool {
+	rank := func(code int) int {
+		if r, ok := exitCodeRank[code]; ok {
+			return r
+		}
+		return len(exitCodeRank) + code
+	}
+	return rank(a) > rank(b)
+}
+
+// ValidateAll validates several repositories concurrently, then prints one
+// report section per repository in the given order. It returns the most
+// severe exit code of all repositories; a repository that cannot be
+// validated counts as exit code 1.
+func ValidateAll(validators []*Validator) (int, error) {
+	// Concatenated JSON/SARIF/rdf-min documents on stdout would not be valid
+	// for any consumer, and interleaved NDJSON records would not say which
+	// repository they belong to
+	for _, v := range validators {
+		if !v.quietStdout && (v.outputFormat == "json" || v.outputFormat == "json-report" || v.outputFormat == "ndjson" || v.outputFormat == "sarif" || v.outputFormat == "badge" || v.outputFormat == "rdf-min") {
+			return 1, fmt.Errorf("%s output to stdout supports a single --path; write one file per repository with --output %s=<file>", v.outputFormat, v.outputFormat)
+		}
+	}
+
+	errs := make([]error, len(validators))
+
+	var wg sync.WaitGroup
+	for i, v := range validators {
+		wg.Add(1)
+		go func(i int, v *Validator) {
+			defer wg.Done()
+			errs[i] = v.Run()
+		}(i, v)
+	}
+	wg.Wait()
+
+	exitCode := 0
+	for i, v := range validators {
+		if v.outputFormat == "" || v.outputFormat == "markdown" {
+			if !v.quietStdout {
+				v.printSectionHeader()
+			}
+		}
+
+		code := 1
+		if errs[i] != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s: %v\n", v.repoPath, errs[i])
+		} else {
+			code = v.Report()
+		}
+
+		if moreSevereExitCode(code, exitCode) {
+			exitCode = code
+		}
+	}
+
+	return exitCode, nil
+}
+
+// printSectionHeader introduces a repository's results in a multi-path report
+func (v *Validator) printSectionHeader() {
+	title := fmt.Sprintf("📂 %s", v.repoPath)
+	if v.outputFormat == "markdown" {
+		fmt.Printf("\n# %s\n\n", title)
+		return
+	}
+	fmt.Printf("\n%s\n%s\n", v.colorize(ansiCyan, title), strings.Repeat("=", len(v.repoPath)+3))
+}
+
+// SetOutputLabel makes file outputs specific to this repository by inserting
+// the label before the file extension (report.json → report.<label>.json), so
+// that repositories validated in one run don't overwrite each other's reports
+func (v *Validator) SetOutputLabel(label string) {
+	v.outputLabel = label
+}
+
+// labeledOutputFile returns the file a target is written to for this repository
+func (v *Validator) labeledOutputFile(file string) string {
+	if v.outputLabel == "" {
+		return file
+	}
+	ext := filepath.Ext(file)
+	return strings.TrimSuffix(file, ext) + "." + v.outputLabel + ext
+}
+
+// OutputLabels derives a distinct, file-name friendly label for every path:
+// its base name, numbered when several paths share one
+func OutputLabels(paths []string) []string {
+	labels := make([]string, len(paths))
+	counts := make(map[string]int)
+	for i, path := range paths {
+		base := filepath.Base(filepath.Clean(path))
+		if base == "." || base == string(filepath.Separator) {
+			if abs, err := filepath.Abs(path); err == nil {
+				base = filepath.Base(abs)
+			}
+		}
+		labels[i] = base
+		counts[base]++
+	}
+
+	seen := make(map[string]int)
+	for i, label := range labels {
+		if counts[label] > 1 {
+			seen[label]++
+			labels[i] = label + "-" + strconv.Itoa(seen[label])
+		}
+	}
+	return labels
+}
@@ -0,0 +1,350 @@
+package validator
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/moon-hex/gitops-validator/internal/types"
+)
+
+// OutputTarget is one destination for validation results: a format written
+// either to stdout (File == "") or to a file
+type OutputTarget struct {
+	Format string
+	File   string
+}
+
+// outputFormats maps accepted format names to their canonical form
+var outputFormats = map[string]string{
+	"console":     "",
+	"default":     "",
+	"markdown":    "markdown",
+	"md":          "markdown",
+	"json":        "json",
+	"json-report": "json-report",
+	"ndjson":      "ndjson",
+	"sarif":       "sarif",
+	"badge":       "badge",
+	"rdf-min":     "rdf-min",
+}
+
+// ParseOutputTargets parses an output specification such as
+// "console,json=report.json,sarif=report.sarif". Entries without "=file" are
+// written to stdout; at most one format may go to stdout.
+func ParseOutputTargets(spec string) ([]OutputTarget, error) {
+	var targets []OutputTarget
+	stdoutFormat := ""
+	hasStdout := false
+
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		name, file, _ := strings.Cut(entry, "=")
+		name = strings.ToLower(strings.TrimSpace(name))
+		file = strings.TrimSpace(file)
+
+		format, ok := outputFormats[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown output format '%s' (expected console, markdown, json, json-report, ndjson, sarif, badge or rdf-min)", name)
+		}
+
+		if file == "" {
+			if hasStdout {
+				return nil, fmt.Errorf("only one output can go to stdout (got '%s' and '%s')", displayFormat(stdoutFormat), displayFormat(format))
+			}
+			hasStdout = true
+			stdoutFormat = format
+		}
+
+		targets = append(targets, OutputTarget{Format: format, File: file})
+	}
+
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("no outputs specified")
+	}
+
+	return targets, nil
+}
+
+func displayFormat(format string) string {
+	if format == "" {
+		return "console"
+	}
+	return format
+}
+
+// SetOutputs configures all result outputs from an --output specification.
+// The stdout entry (if any) becomes the output format; file entries are written
+// after validation finishes.
+func (v *Validator) SetOutputs(spec string) error {
+	targets, err := ParseOutputTargets(spec)
+	if err != nil {
+		return err
+	}
+
+	v.outputFormat = ""
+	v.fileOutputs = nil
+	v.quietStdout = true
+	for _, target := range targets {
+		if target.File == "" {
+			v.outputFormat = target.Format
+			v.quietStdout = false
+		} else {
+			v.fileOutputs = append(v.fileOutputs, target)
+		}
+	}
+
+	return nil
+}
+
+// SetStdout redirects what the validator prints to stdout, such as the
+// report in the output format, to w; call it before Run
+func (v *Validator) SetStdout(w io.Writer) {
+	v.stdout = w
+}
+
+func (v *Validator) writeOutputFile(target OutputTarget, summary Summary) error {
+	results, health, git := summary.Results, summary.HealthScore, summary.Git
+
+	file, err := os.Create(target.File)
+	if err != nil {
+		return err
+	}
+
+	var renderErr error
+	switch target.Format {
+	case "markdown":
+		renderMarkdown(file, results, health, git)
+	case "json":
+		renderErr = renderJSON(file, results)
+	case "json-report":
+		renderErr = renderJSONReport(file, results, health, git)
+	case "ndjson":
+		renderErr = renderNDJSON(file, results)
+	case "sarif":
+		renderErr = renderSARIF(file, results, git)
+	case "badge":
+		renderErr = v.renderBadge(file, health)
+	case "rdf-min":
+		renderErr = v.renderRDFMin(file, results, git)
+	default:
+		renderPlain(file, results, git)
+	}
+
+	if err := file.Close(); err != nil && renderErr == nil {
+		renderErr = err
+	}
+	return renderErr
+}
+
+// renderPlain writes the human-readable report without colors or grouping,
+// used for console output written to a file
+func renderPlain(out io.Writer, results []types.ValidationResult, git *GitMetadata) {
+	if git != nil {
+		fmt.Fprintf(out, "🔖 Revision: %s\n", git)
+	}
+	if len(results) == 0 {
+		fmt.Fprintln(out, "✅ All validations passed!")
+		return
+	}
+
+	fmt.Fprintf(out, "📋 Validation Results (%d issues found):\n\n", len(results))
+	plain := &Validator{}
+	for _, result := range results {
+		plain.printResultLine(out, result, "")
+	}
+}
+
+// renderJSON writes results as an indented JSON array
+func renderJSON(out io.Writer, results []types.ValidationResult) error {
+	if results == nil {
+		results = []types.ValidationResult{}
+	}
+	return writeIndentedJSON(out, results)
+}
+
+// jsonReport is the document written by the json-report output format
+type jsonReport struct {
+	Git         *GitMetadata             `json:"git,omitempty"`
+	HealthScore types.HealthScore        `json:"healthScore"`
+	Results     []types.ValidationResult `json:"results"`
+}
+
+// renderJSONReport writes the git revision, health score and results as an
+// indented JSON document
+func renderJSONReport(out io.Writer, results []types.ValidationResult, health types.HealthScore, git *GitMetadata) error {
+	if results == nil {
+		results = []types.ValidationResult{}
+	}
+	return writeIndentedJSON(out, jsonReport{Git: git, HealthScore: health, Results: results})
+}
+
+// writeIndentedJSON writes value as indented JSON followed by a newline
+func writeIndentedJSON(out io.Writer, value interface{}) error {
+	b, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error formatting JSON output: %w", err)
+	}
+	_, err = fmt.Fprintln(out, string(b))
+	return err
+}
+
+// renderNDJSON writes one JSON object per result per line
+func renderNDJSON(out io.Writer, results []types.ValidationResult) error {
+	encoder := json.NewEncoder(out)
+	for _, result := range results {
+		if err := encoder.Encode(result); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SARIF 2.1.0 document structure (subset used by code scanning tools)
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool                     sarifTool                 `json:"tool"`
+	VersionControlProvenance []sarifVersionControlInfo `json:"versionControlProvenance,omitempty"`
+	Results                  []sarifResult             `json:"results"`
+}
+
+// sarifVersionControlInfo identifies the revision the run analyzed
+type sarifVersionControlInfo struct {
+	RepositoryURI string          `json:"repositoryUri"`
+	RevisionID    string          `json:"revisionId,omitempty"`
+	Branch        string          `json:"branch,omitempty"`
+	Properties    map[string]bool `json:"properties,omitempty"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	Name             string       `json:"name"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+	HelpURI          string       `json:"helpUri,omitempty"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId,omitempty"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+// renderSARIF writes results as a SARIF 2.1.0 log for code scanning integrations
+func renderSARIF(out io.Writer, results []types.ValidationResult, git *GitMetadata) error {
+	driver := sarifDriver{
+		Name:           "gitops-validator",
+		InformationURI: "https://github.com/moon-hex/gitops-validator",
+		Rules:          []sarifRule{},
+	}
+
+	seenRules := make(map[string]bool)
+	sarifResults := []sarifResult{}
+	for _, r := range results {
+		if r.RuleID != "" && !seenRules[r.RuleID] {
+			seenRules[r.RuleID] = true
+			rule := sarifRule{ID: r.RuleID, Name: r.Type, HelpURI: r.DocsURL}
+			if info, ok := types.LookupRuleByID(r.RuleID); ok {
+				rule.ShortDescription = sarifMessage{Text: info.Description}
+			} else {
+				rule.ShortDescription = sarifMessage{Text: r.Type}
+			}
+			driver.Rules = append(driver.Rules, rule)
+		}
+
+		result := sarifResult{
+			RuleID:  r.RuleID,
+			Level:   sarifLevel(r.Severity),
+			Message: sarifMessage{Text: r.Message},
+		}
+		if r.File != "" {
+			location := sarifLocation{PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: strings.ReplaceAll(r.File, "\\", "/")},
+			}}
+			if r.Line > 0 {
+				location.PhysicalLocation.Region = &sarifRegion{StartLine: r.Line, StartColumn: r.Column}
+			}
+			result.Locations = []sarifLocation{location}
+		}
+		sarifResults = append(sarifResults, result)
+	}
+
+	run := sarifRun{Tool: sarifTool{Driver: driver}, Results: sarifResults}
+	// SARIF requires a repository URI, so only repositories with a remote
+	// record their revision
+	if git != nil && git.Remote != "" {
+		provenance := sarifVersionControlInfo{RepositoryURI: git.Remote, RevisionID: git.Commit, Branch: git.Branch}
+		if git.Dirty != nil {
+			provenance.Properties = map[string]bool{"dirty": *git.Dirty}
+		}
+		run.VersionControlProvenance = []sarifVersionControlInfo{provenance}
+	}
+
+	log := sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	}
+
+	b, err := json.MarshalIndent(log, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error formatting SARIF output: %w", err)
+	}
+	_, err = fmt.Fprintln(out, string(b))
+	return err
+}
+
+// sarifLevel maps result severities to SARIF levels
+func sarifLevel(severity types.Severity) string {
+	switch severity {
+	case types.SeverityError:
+		return "error"
+	case types.SeverityWarning:
+		return "warning"
+	default:
+		return "note"
+	}
+}
@@ -0,0 +1,62 @@
+package validator
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// pagerThreshold is the number of findings above which console output is paged
+const pagerThreshold = 50
+
+// SetNoPager disables piping long console output through $PAGER
+func (v *Validator) SetNoPager(noPager bool) {
+	v.noPager = noPager
+}
+
+// startPager returns the writer console output should go to. When stdout is a
+// terminal, paging is enabled and there are more than pagerThreshold findings,
+// output is piped through $PAGER (default "less"); the returned function waits
+// for the pager to exit. Otherwise output goes straight to stdout.
+func (v *Validator) startPager(findings int) (io.Writer, func()) {
+	noop := func() {}
+
+	// Only human-readable console formats are paged; JSON is meant for tools
+	if v.noPager || findings <= pagerThreshold || v.stdout != os.Stdout || !isTerminal(os.Stdout) {
+		return v.stdout, noop
+	}
+	if v.outputFormat != "" && v.outputFormat != "markdown" {
+		return v.stdout, noop
+	}
+
+	pager := strings.TrimSpace(os.Getenv("PAGER"))
+	if pager == "" {
+		pager = "less"
+	}
+	if pager == "cat" {
+		return os.Stdout, noop
+	}
+
+	args := strings.Fields(pager)
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	// Same defaults as git: quit if one screen, keep colors, don't clear the screen
+	if _, set := os.LookupEnv("LESS"); !set {
+		cmd.Env = append(os.Environ(), "LESS=FRX")
+	}
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return os.Stdout, noop
+	}
+	if err := cmd.Start(); err != nil {
+		return os.Stdout, noop
+	}
+
+	return stdin, func() {
+		stdin.Close()
+		cmd.Wait()
+	}
+}
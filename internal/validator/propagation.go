@@ -0,0 +1,37 @@
+package validator
+
+import (
+	"github.com/moon-hex/gitops-validator/internal/types"
+)
+
+// propagateSeverities annotates results in files applied by several Flux
+// Kustomizations, typically shared bases, with the entry points they affect.
+// When more entry points than severity-propagation.escalate-above are
+// affected, the severity is raised one level, since a single broken base then
+// breaks many clusters or apps at once. Runs after --severity overrides.
+func (v *Validator) propagateSeverities(results []types.ValidationResult) {
+	propagation := v.config.GitOpsValidator.SeverityPropagation
+	if !propagation.Enabled || v.graph == nil {
+		return
+	}
+
+	v.sharedFilesOnce.Do(func() {
+		v.sharedFiles = make(map[string][]string)
+		for file, entryPoints := range v.entryPointAttribution() {
+			if len(entryPoints) > 1 {
+				v.sharedFiles[file] = entryPoints
+			}
+		}
+	})
+
+	for i := range results {
+		entryPoints := v.sharedFiles[results[i].File]
+		if len(entryPoints) == 0 {
+			continue
+		}
+		results[i].AffectedEntryPoints = entryPoints
+		if propagation.EscalateAbove > 0 && len(entryPoints) > propagation.EscalateAbove {
+			results[i].Severity = results[i].Severity.Escalated()
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package validator
+
+import (
+	"encoding/json"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/moon-hex/gitops-validator/internal/types"
+)
+
+// rdfMinMessageLimit caps messages of the rdf-min format, in runes
+const rdfMinMessageLimit = 240
+
+// rdfMinReport is the document written by the rdf-min output format: a
+// compact report sized for LLM-based pull request review bots. Fix hints are
+// listed once per rule instead of once per finding.
+type rdfMinReport struct {
+	Git     *GitMetadata      `json:"git,omitempty"`
+	Results []rdfMinResult    `json:"results"`
+	Fixes   map[string]string `json:"fixes,omitempty"`
+}
+
+type rdfMinResult struct {
+	Rule     string         `json:"rule"`
+	Severity types.Severity `json:"sev"`
+	File     string         `json:"file,omitempty"`
+	Line     int            `json:"line,omitempty"`
+	Column   int            `json:"col,omitempty"`
+	Message  string         `json:"msg"`
+}
+
+// renderRDFMin writes results as a single line of JSON without decoration.
+// Paths are relative to the repository and results are sorted by file, line,
+// rule and message, so the same findings at the same revision always produce
+// the same bytes.
+func (v *Validator) renderRDFMin(out io.Writer, results []types.ValidationResult, git *GitMetadata) error {
+	report := rdfMinReport{Git: git, Results: []rdfMinResult{}, Fixes: map[string]string{}}
+	for _, result := range results {
+		rule := result.RuleID
+		if rule == "" {
+			rule = result.Type
+		}
+		if info, ok := types.LookupRuleByType(result.Type); ok && info.Fix != "" {
+			report.Fixes[rule] = info.Fix
+		}
+
+		file := result.File
+		if file != "" {
+			if rel, err := filepath.Rel(v.repoPath, file); err == nil && !strings.HasPrefix(rel, "..") {
+				file = rel
+			}
+			file = filepath.ToSlash(file)
+		}
+
+		report.Results = append(report.Results, rdfMinResult{
+			Rule:     rule,
+			Severity: result.Severity,
+			File:     file,
+			Line:     result.Line,
+			Column:   result.Column,
+			Message:  oneLine(result.Message, rdfMinMessageLimit),
+		})
+	}
+
+	sort.SliceStable(report.Results, func(i, j int) bool {
+		a, b := report.Results[i], report.Results[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		if a.Column != b.Column {
+			return a.Column < b.Column
+		}
+		if a.Rule != b.Rule {
+			return a.Rule < b.Rule
+		}
+		return a.Message < b.Message
+	})
+
+	// json.Encoder writes map keys sorted and ends the document with a newline
+	return json.NewEncoder(out).Encode(report)
+}
+
+// oneLine collapses whitespace runs (including newlines) in message to single
+// spaces and truncates it to limit runes
+func oneLine(message string, limit int) string {
+	message = strings.Join(strings.Fields(message), " ")
+	if runes := []rune(message); len(runes) > limit {
+		message = string(runes[:limit-1]) + "…"
+	}
+	return message
+}
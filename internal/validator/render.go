@@ -0,0 +1,107 @@
+package validator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/parser"
+	"github.com/moon-hex/gitops-validator/internal/validators/checks"
+)
+
+// PrintRenderedNames prints, per Flux Kustomization, the names and namespaces
+// of the resources it applies once kustomize namePrefix, nameSuffix and
+// namespace and Flux targetNamespace are applied, next to the file each comes
+// from. Resources renamed or moved on the way show their original name, which
+// explains references that do not match the objects in the cluster. With an
+// identifier only the matching Flux Kustomizations are printed.
+func (v *Validator) PrintRenderedNames(identifier string) error {
+	graph, err := v.parser.ParseAllResources()
+	if err != nil {
+		return fmt.Errorf("failed to parse resources: %w", err)
+	}
+	ctx := context.NewValidationContext(graph, v.config, v.repoPath, false)
+
+	entryPoints := graph.GetFluxKustomizations()
+	if identifier != "" {
+		entryPoints = nil
+		for _, resource := range graph.FindResources(identifier, v.repoPath) {
+			if parser.ClassifyResource(resource) == parser.ResourceTypeFluxKustomization {
+				entryPoints = append(entryPoints, resource)
+			}
+		}
+		if len(entryPoints) == 0 {
+			return fmt.Errorf("no Flux Kustomization matches '%s'", identifier)
+		}
+	}
+	if len(entryPoints) == 0 {
+		fmt.Println("No Flux Kustomizations found")
+		return nil
+	}
+	sort.SliceStable(entryPoints, func(i, j int) bool {
+		if entryPoints[i].File != entryPoints[j].File {
+			return entryPoints[i].File < entryPoints[j].File
+		}
+		return entryPoints[i].Line < entryPoints[j].Line
+	})
+
+	for i, entryPoint := range entryPoints {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("%s:\n", v.entryPointLabel(entryPoint))
+
+		var applied []context.DeployedResource
+		for _, deployed := range ctx.AppliedResources(entryPoint) {
+			if parser.ClassifyResource(deployed.Resource) != parser.ResourceTypeKubernetesKustomization {
+				applied = append(applied, deployed)
+			}
+		}
+		if len(applied) == 0 {
+			fmt.Println("  (nothing applied from the repository)")
+			continue
+		}
+
+		table := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(table, "  KIND\tNAMESPACE\tNAME\tSOURCE")
+		for _, deployed := range applied {
+			resource := deployed.Resource
+			namespace := deployed.Namespace
+			if checks.KustomizeClusterScoped(resource.Kind) {
+				namespace = resource.Namespace
+			}
+
+			source := resource.File
+			if rel, err := filepath.Rel(v.repoPath, source); err == nil {
+				source = filepath.ToSlash(rel)
+			}
+			source = fmt.Sprintf("%s:%d", source, resource.Line)
+			var changes []string
+			if deployed.Name != resource.Name {
+				changes = append(changes, fmt.Sprintf("name was %s", resource.Name))
+			}
+			if namespace != resource.Namespace {
+				original := resource.Namespace
+				if original == "" {
+					original = "unset"
+				}
+				changes = append(changes, fmt.Sprintf("namespace was %s", original))
+			}
+			if len(changes) > 0 {
+				source += fmt.Sprintf(" (%s)", strings.Join(changes, ", "))
+			}
+
+			if namespace == "" {
+				namespace = "-"
+			}
+			fmt.Fprintf(table, "  %s\t%s\t%s\t%s\n", resource.Kind, namespace, deployed.Name, source)
+		}
+		table.Flush()
+	}
+
+	return nil
+}
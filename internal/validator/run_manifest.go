@@ -0,0 +1,220 @@
+package validator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/moon-hex/gitops-validator/internal/types"
+	"gopkg.in/yaml.v3"
+)
+
+// The run manifest is an in-toto Statement, so it can be attached to release
+// provenance next to SLSA attestations and verified with the same tooling
+const (
+	runManifestStatementType = "https://in-toto.io/Statement/v1"
+	runManifestPredicateType = "https://github.com/moon-hex/gitops-validator/run-manifest/v1"
+)
+
+// runManifestOptions configures the run manifest (see SetRunManifest)
+type runManifestOptions struct {
+	file        string
+	cosignKey   string
+	toolVersion string
+}
+
+// runManifestStatement is the manifest document
+type runManifestStatement struct {
+	Type          string               `json:"_type"`
+	Subject       []runManifestSubject `json:"subject"`
+	PredicateType string               `json:"predicateType"`
+	Predicate     runManifestPredicate `json:"predicate"`
+}
+
+// runManifestSubject identifies the validated repository by its commit
+type runManifestSubject struct {
+	Name   string            `json:"name"`
+	Digest map[string]string `json:"digest"`
+}
+
+// runManifestPredicate describes how the repository was validated and what
+// was found
+type runManifestPredicate struct {
+	Tool       runManifestTool       `json:"tool"`
+	Config     runManifestConfig     `json:"config"`
+	Repository runManifestRepository `json:"repository"`
+	Results    runManifestResults    `json:"results"`
+	ExitCode   int                   `json:"exitCode"`
+	Timings    runManifestTimings    `json:"timings"`
+}
+
+type runManifestTool struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type runManifestConfig struct {
+	File   string `json:"file,omitempty"` // empty when the built-in defaults were used
+	SHA256 string `json:"sha256"`
+}
+
+type runManifestRepository struct {
+	Path   string `json:"path"`
+	Commit string `json:"commit,omitempty"`
+	Branch string `json:"branch,omitempty"`
+	Dirty  *bool  `json:"dirty,omitempty"`
+	Remote string `json:"remote,omitempty"`
+}
+
+type runManifestResults struct {
+	Total       int     `json:"total"`
+	Errors      int     `json:"errors"`
+	Warnings    int     `json:"warnings"`
+	Info        int     `json:"info"`
+	Suppressed  int     `json:"suppressed"`
+	HealthScore float64 `json:"healthScore"`
+	HealthGrade string  `json:"healthGrade"`
+}
+
+type runManifestTimings struct {
+	StartedAt  time.Time `json:"startedAt"`
+	FinishedAt time.Time `json:"finishedAt"`
+	ParseMs    int64     `json:"parseMs"`
+	ValidateMs int64     `json:"validateMs"`
+}
+
+// SetRunManifest writes a JSON run manifest to file after the report: tool
+// version, config digest, repository commit, result counts and timings. When
+// cosignKey is set the manifest is signed with cosign sign-blob, and the
+// signature is written next to it as <file>.sig.
+func (v *Validator) SetRunManifest(file, cosignKey, toolVersion string) {
+	v.runManifest = runManifestOptions{file: file, cosignKey: cosignKey, toolVersion: toolVersion}
+}
+
+// writeRunManifest writes and optionally signs the run manifest. Failures are
+// reported as warnings: the manifest is evidence about the run, not part of it.
+func (v *Validator) writeRunManifest(exitCode int) {
+	file := v.labeledOutputFile(v.runManifest.file)
+
+	statement, err := v.runManifestStatement(exitCode)
+	if err == nil {
+		var data []byte
+		if data, err = json.MarshalIndent(statement, "", "  "); err == nil {
+			err = os.WriteFile(file, append(data, '\n'), 0644)
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to write run manifest to %s: %v\n", file, err)
+		return
+	}
+	if v.verbose {
+		fmt.Printf("Run manifest written to: %s\n", file)
+	}
+
+	if v.runManifest.cosignKey == "" {
+		return
+	}
+	if err := signRunManifest(file, v.runManifest.cosignKey); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to sign run manifest %s: %v\n", file, err)
+		return
+	}
+	if v.verbose {
+		fmt.Printf("Run manifest signature written to: %s.sig\n", file)
+	}
+}
+
+// runManifestStatement collects the manifest of the finished run
+func (v *Validator) runManifestStatement(exitCode int) (runManifestStatement, error) {
+	configDigest, err := v.configDigest()
+	if err != nil {
+		return runManifestStatement{}, err
+	}
+
+	repoPath := v.repoPath
+	if abs, err := filepath.Abs(repoPath); err == nil {
+		repoPath = abs
+	}
+	repository := runManifestRepository{Path: v.repoPath}
+	if git := v.gitMetadata(); git != nil {
+		repository.Commit, repository.Branch, repository.Dirty, repository.Remote = git.Commit, git.Branch, git.Dirty, git.Remote
+	}
+
+	health := v.healthScore()
+	results := runManifestResults{
+		Total:       len(v.results),
+		Suppressed:  len(v.suppressed),
+		HealthScore: health.Score,
+		HealthGrade: health.Grade,
+	}
+	for _, result := range v.results {
+		switch result.Severity {
+		case types.SeverityError:
+			results.Errors++
+		case types.SeverityWarning:
+			results.Warnings++
+		case types.SeverityInfo:
+			results.Info++
+		}
+	}
+
+	subject := runManifestSubject{Name: filepath.Base(repoPath), Digest: map[string]string{}}
+	if repository.Commit != "" {
+		subject.Digest["gitCommit"] = repository.Commit
+	}
+
+	return runManifestStatement{
+		Type:          runManifestStatementType,
+		Subject:       []runManifestSubject{subject},
+		PredicateType: runManifestPredicateType,
+		Predicate: runManifestPredicate{
+			Tool:       runManifestTool{Name: "gitops-validator", Version: v.runManifest.toolVersion},
+			Config:     runManifestConfig{File: v.configPath, SHA256: configDigest},
+			Repository: repository,
+			Results:    results,
+			ExitCode:   exitCode,
+			Timings: runManifestTimings{
+				StartedAt:  v.startedAt.UTC(),
+				FinishedAt: time.Now().UTC(),
+				ParseMs:    v.parseDuration.Milliseconds(),
+				ValidateMs: v.validateDuration.Milliseconds(),
+			},
+		},
+	}, nil
+}
+
+// configDigest is the SHA-256 of the config file, or of the built-in defaults
+// as YAML when no file was used, so a run can be matched to its policy
+func (v *Validator) configDigest() (string, error) {
+	var data []byte
+	var err error
+	if v.configPath != "" {
+		data, err = os.ReadFile(v.configPath)
+	} else {
+		data, err = yaml.Marshal(v.config)
+	}
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// signRunManifest signs file with cosign sign-blob. cosign reads the key
+// password from COSIGN_PASSWORD; KMS and other key references work as well.
+func signRunManifest(file, key string) error {
+	cosign, err := exec.LookPath("cosign")
+	if err != nil {
+		return fmt.Errorf("cosign is not installed: %w", err)
+	}
+	cmd := exec.Command(cosign, "sign-blob", "--yes", "--key", key, "--output-signature", file+".sig", file)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/moon-hex/gitops-validator/internal/types"
+)
+
+// SetSeverityOverrides overrides the severity of a rule's results for this
+// run. Each entry has the form "rule=level", where rule is a config rule name
+// (flux-postbuild-variables), a rule ID (GV0003) or a result type, and level
+// is error, warning or info.
+func (v *Validator) SetSeverityOverrides(entries []string) error {
+	overrides := make(map[string]types.Severity)
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		rule, level, found := strings.Cut(entry, "=")
+		rule = strings.ToLower(strings.TrimSpace(rule))
+		level = strings.ToLower(strings.TrimSpace(level))
+		if !found || rule == "" {
+			return fmt.Errorf("'%s' must have the form rule=level", entry)
+		}
+		severity, err := types.ParseSeverity(level)
+		if err != nil {
+			return fmt.Errorf("invalid severity '%s' for rule '%s', must be error, warning, or info", level, rule)
+		}
+		if !isKnownRule(rule) {
+			return fmt.Errorf("unknown rule '%s' (use a rule ID, config rule name or result type from docs/RULES.md)", rule)
+		}
+
+		overrides[rule] = severity
+	}
+
+	v.severityOverrides = overrides
+	return nil
+}
+
+// isKnownRule reports whether a lower-cased identifier names a registered rule
+func isKnownRule(identifier string) bool {
+	for _, rule := range types.Rules {
+		if strings.EqualFold(rule.ID, identifier) || rule.Type == identifier || (rule.Rule != "" && rule.Rule == identifier) {
+			return true
+		}
+	}
+	return false
+}
+
+// overrideSeverities applies --severity overrides in place. An override for
+// the rule ID or result type takes precedence over one for the config rule,
+// which may cover several result types.
+func (v *Validator) overrideSeverities(results []types.ValidationResult) {
+	if len(v.severityOverrides) == 0 {
+		return
+	}
+
+	for i := range results {
+		ruleName := ""
+		if info, ok := types.LookupRuleByType(results[i].Type); ok {
+			ruleName = info.Rule
+		}
+
+		for _, id := range []string{strings.ToLower(results[i].RuleID), results[i].Type, ruleName} {
+			if level, ok := v.severityOverrides[id]; ok && id != "" {
+				results[i].Severity = level
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package validator
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/moon-hex/gitops-validator/internal/types"
+)
+
+// ResultSink is a destination for validation results. Write is called with
+// every result as soon as its validator produced it (after suppressions and
+// severity overrides), and Flush once from Report with the final summary.
+// Errors are reported as warnings and do not stop other sinks.
+//
+// The console, markdown, JSON, NDJSON, SARIF, badge and rdf-min outputs and
+// the GitHub pull request comment are all sinks; embedders add their own,
+// such as a webhook or a metrics exporter, with AddSink.
+type ResultSink interface {
+	Write(result types.ValidationResult) error
+	Flush(summary Summary) error
+}
+
+// Summary is what sinks receive once validation finished
+type Summary struct {
+	RepoPath string
+	// Results to report: all results, or the aggregated selection when
+	// aggregation is enabled
+	Results []types.ValidationResult
+	// Aggregated is set when aggregation is enabled
+	Aggregated *types.AggregatedResults
+	// Total is the number of results before aggregation
+	Total       int
+	HealthScore types.HealthScore
+	// Suppressed results, disabled by comments or config
+	Suppressed []types.ValidationResult
+	ExitCode   int
+	// Git identifies the validated revision; nil outside a git repository
+	Git *GitMetadata
+}
+
+// AddSink registers an additional sink; call it before Run
+func (v *Validator) AddSink(sink ResultSink) {
+	v.customSinks = append(v.customSinks, sink)
+}
+
+// resultSinks returns the sinks of the configured outputs followed by the
+// added ones, built once per run
+func (v *Validator) resultSinks() []ResultSink {
+	v.sinksOnce.Do(func() {
+		for _, target := range v.fileOutputs {
+			v.sinks = append(v.sinks, &fileSink{v: v, target: target})
+		}
+		if v.githubComment {
+			v.sinks = append(v.sinks, &githubCommentSink{v: v})
+		}
+		switch {
+		case v.quietStdout:
+		case v.outputFormat == "ndjson":
+			v.sinks = append(v.sinks, &ndjsonSink{v: v})
+		default:
+			v.sinks = append(v.sinks, &stdoutSink{v: v})
+		}
+		v.sinks = append(v.sinks, v.customSinks...)
+	})
+	return v.sinks
+}
+
+// writeSinks passes streamed results to every sink
+func (v *Validator) writeSinks(results []types.ValidationResult) {
+	for _, sink := range v.resultSinks() {
+		for _, result := range results {
+			if err := sink.Write(result); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+				break
+			}
+		}
+	}
+}
+
+// summary builds the summary handed to the sinks
+func (v *Validator) summary() Summary {
+	// The health score always reflects every result, not just the displayed ones
+	summary := Summary{
+		RepoPath:    v.repoPath,
+		Results:     v.results,
+		Total:       len(v.results),
+		HealthScore: v.healthScore(),
+		Suppressed:  v.suppressed,
+		ExitCode:    v.exitCode(),
+		Git:         v.gitMetadata(),
+	}
+
+	// Apply result aggregation if enabled
+	if v.useAggregation && v.aggregationOptions != nil {
+		options := *v.aggregationOptions
+		options.EvaluatedByRule = ruleEvaluationCounts(v.graph)
+		if options.GroupBy == "entry-point" {
+			options.EntryPoints = v.entryPointAttribution()
+		}
+		aggregator := types.NewResultAggregator(v.results)
+		summary.Aggregated = aggregator.Aggregate(options)
+		summary.Aggregated.HealthScore = &summary.HealthScore
+		summary.Results = summary.Aggregated.Results
+	}
+
+	return summary
+}
+
+// stdoutSink prints the report in the stdout output format
+type stdoutSink struct {
+	v *Validator
+}
+
+func (s *stdoutSink) Write(types.ValidationResult) error { return nil }
+
+func (s *stdoutSink) Flush(summary Summary) error {
+	s.v.printReport(summary)
+	return nil
+}
+
+// ndjsonSink writes each result to the validator's stdout as a single JSON
+// line as soon as it is produced. Lines are written whole, one at a time.
+type ndjsonSink struct {
+	v  *Validator
+	mu sync.Mutex
+}
+
+func (s *ndjsonSink) Write(result types.ValidationResult) error {
+	line, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("error formatting NDJSON output: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.v.stdout.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("error writing NDJSON output: %w", err)
+	}
+	return nil
+}
+
+func (s *ndjsonSink) Flush(Summary) error { return nil }
+
+// fileSink writes a report file requested with --output
+type fileSink struct {
+	v      *Validator
+	target OutputTarget
+}
+
+func (s *fileSink) Write(types.ValidationResult) error { return nil }
+
+func (s *fileSink) Flush(summary Summary) error {
+	target := s.target
+	target.File = s.v.labeledOutputFile(target.File)
+	if err := s.v.writeOutputFile(target, summary); err != nil {
+		return fmt.Errorf("failed to write %s output to %s: %w", displayFormat(target.Format), target.File, err)
+	}
+	if s.v.verbose {
+		fmt.Printf("%s results written to: %s\n", displayFormat(target.Format), target.File)
+	}
+	return nil
+}
+
+// githubCommentSink posts the markdown report as a pull request comment
+type githubCommentSink struct {
+	v *Validator
+}
+
+func (s *githubCommentSink) Write(types.ValidationResult) error { return nil }
+
+func (s *githubCommentSink) Flush(summary Summary) error {
+	if err := s.v.publishGitHubComment(summary.Results, summary.HealthScore, summary.Git); err != nil {
+		return fmt.Errorf("failed to post GitHub pull request comment: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,329 @@
+package validator
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/moon-hex/gitops-validator/internal/bundle"
+	"github.com/moon-hex/gitops-validator/internal/types"
+)
+
+// SnapshotFile is the name of a fixture's committed expected results
+const SnapshotFile = "expected-results.json"
+
+// snapshotConfigFile is the optional config of a fixture
+const snapshotConfigFile = "gitops-validator.yaml"
+
+// snapshotRepoArchives are the names of a fixture repository given as a tar
+// archive, extracted like a --path bundle
+var snapshotRepoArchives = []string{"repo.tar", "repo.tar.gz", "repo.tgz"}
+
+// SnapshotFixture is a repository validated by the test subcommand and
+// compared against its expected results
+type SnapshotFixture struct {
+	Dir        string // fixture directory holding the snapshot
+	RepoPath   string // <Dir>/repo or <Dir>/repo.tar[.gz] if it exists, else Dir
+	ConfigPath string // <Dir>/gitops-validator.yaml if it exists
+}
+
+// SnapshotResult is a result as stored in a snapshot: paths are relative to
+// the fixture repository, so snapshots do not depend on where tests run
+type SnapshotResult struct {
+	RuleID   string         `json:"ruleId,omitempty"`
+	Type     string         `json:"type"`
+	Severity types.Severity `json:"severity"`
+	File     string         `json:"file,omitempty"`
+	Line     int            `json:"line,omitempty"`
+	Column   int            `json:"column,omitempty"`
+	Resource string         `json:"resource,omitempty"`
+	Message  string         `json:"message"`
+}
+
+// snapshotDocument is the content of a snapshot file. Error is the error the
+// run fails with, for fixtures that must be rejected.
+type snapshotDocument struct {
+	Error   string           `json:"error,omitempty"`
+	Results []SnapshotResult `json:"results"`
+}
+
+// FindSnapshotFixtures resolves directories to fixtures. A directory holding
+// expected-results.json, a repo/ subdirectory or a gitops-validator.yaml is a
+// fixture; any other directory is a collection whose subdirectories are.
+func FindSnapshotFixtures(dirs []string) ([]SnapshotFixture, error) {
+	var fixtures []SnapshotFixture
+	for _, dir := range dirs {
+		info, err := os.Stat(dir)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			return nil, fmt.Errorf("%s is not a directory", dir)
+		}
+
+		if isSnapshotFixture(dir) {
+			fixtures = append(fixtures, newSnapshotFixture(dir))
+			continue
+		}
+
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if entry.IsDir() {
+				fixtures = append(fixtures, newSnapshotFixture(filepath.Join(dir, entry.Name())))
+			}
+		}
+	}
+	return fixtures, nil
+}
+
+// isSnapshotFixture reports whether dir is a fixture rather than a collection
+func isSnapshotFixture(dir string) bool {
+	for _, marker := range append([]string{SnapshotFile, "repo", snapshotConfigFile}, snapshotRepoArchives...) {
+		if _, err := os.Stat(filepath.Join(dir, marker)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// newSnapshotFixture locates the repository and config of a fixture
+func newSnapshotFixture(dir string) SnapshotFixture {
+	fixture := SnapshotFixture{Dir: dir, RepoPath: dir}
+	if info, err := os.Stat(filepath.Join(dir, "repo")); err == nil && info.IsDir() {
+		fixture.RepoPath = filepath.Join(dir, "repo")
+	}
+	for _, name := range snapshotRepoArchives {
+		if bundle.IsArchive(filepath.Join(dir, name)) {
+			fixture.RepoPath = filepath.Join(dir, name)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, snapshotConfigFile)); err == nil {
+		fixture.ConfigPath = filepath.Join(dir, snapshotConfigFile)
+	}
+	return fixture
+}
+
+// RunSnapshotTests validates every fixture and compares its results with the
+// snapshot, or rewrites the snapshots when update is set. Fixtures without a
+// config of their own use configPath (or the discovered config when empty),
+// so a repository's policy configuration is what its fixtures test. It
+// returns the number of fixtures whose results differ.
+func RunSnapshotTests(out io.Writer, fixtures []SnapshotFixture, configPath string, update bool) int {
+	failed := 0
+	for _, fixture := range fixtures {
+		fixtureConfig := fixture.ConfigPath
+		if fixtureConfig == "" {
+			fixtureConfig = configPath
+		}
+
+		actual, runErr := runSnapshotFixture(fixture, fixtureConfig)
+		snapshotPath := filepath.Join(fixture.Dir, SnapshotFile)
+
+		if update {
+			if err := writeSnapshot(snapshotPath, snapshotDocument{Error: runErr, Results: actual}); err != nil {
+				fmt.Fprintf(out, "❌ %s: %v\n", fixture.Dir, err)
+				failed++
+				continue
+			}
+			if runErr != "" {
+				fmt.Fprintf(out, "📝 %s: wrote error to %s\n", fixture.Dir, snapshotPath)
+				continue
+			}
+			fmt.Fprintf(out, "📝 %s: wrote %d results to %s\n", fixture.Dir, len(actual), snapshotPath)
+			continue
+		}
+
+		document, err := readSnapshot(snapshotPath)
+		if err != nil {
+			fmt.Fprintf(out, "❌ %s: %v (run with --update to create it)\n", fixture.Dir, err)
+			failed++
+			continue
+		}
+		if runErr != document.Error {
+			failed++
+			switch {
+			case document.Error == "":
+				fmt.Fprintf(out, "❌ %s: %s\n", fixture.Dir, runErr)
+			case runErr == "":
+				fmt.Fprintf(out, "❌ %s: expected the run to fail with: %s\n", fixture.Dir, document.Error)
+			default:
+				fmt.Fprintf(out, "❌ %s: %s (expected: %s)\n", fixture.Dir, runErr, document.Error)
+			}
+			continue
+		}
+		if runErr != "" {
+			fmt.Fprintf(out, "✅ %s (rejected: %s)\n", fixture.Dir, runErr)
+			continue
+		}
+
+		expected := document.Results
+		unexpected, missing := diffSnapshotResults(expected, actual)
+		if len(unexpected) == 0 && len(missing) == 0 {
+			fmt.Fprintf(out, "✅ %s (%d results)\n", fixture.Dir, len(actual))
+			continue
+		}
+
+		failed++
+		fmt.Fprintf(out, "❌ %s: %d unexpected, %d missing\n", fixture.Dir, len(unexpected), len(missing))
+		for _, result := range unexpected {
+			fmt.Fprintf(out, "    + %s\n", formatSnapshotResult(result))
+		}
+		for _, result := range missing {
+			fmt.Fprintf(out, "    - %s\n", formatSnapshotResult(result))
+		}
+	}
+
+	fmt.Fprintf(out, "\n%d fixtures, %d passed, %d failed\n", len(fixtures), len(fixtures)-failed, failed)
+	return failed
+}
+
+// runSnapshotFixture validates a fixture, extracting its repository first
+// when it is an archive, and returns its results in snapshot form, or the
+// error the run fails with
+func runSnapshotFixture(fixture SnapshotFixture, configPath string) ([]SnapshotResult, string) {
+	repoPath := fixture.RepoPath
+	if bundle.IsArchive(repoPath) {
+		file, err := os.Open(repoPath)
+		if err != nil {
+			return nil, err.Error()
+		}
+		dir, err := bundle.Extract(file)
+		file.Close()
+		if err != nil {
+			return nil, err.Error()
+		}
+		defer os.RemoveAll(dir)
+		repoPath = dir
+	}
+
+	v := NewValidatorWithConfigPath(configPath, repoPath, false, "")
+	if err := v.Run(); err != nil {
+		return nil, err.Error()
+	}
+	return snapshotResults(repoPath, v.Results()), ""
+}
+
+// snapshotResults converts results to their snapshot form, sorted
+func snapshotResults(repoPath string, results []types.ValidationResult) []SnapshotResult {
+	// Messages and resource names may embed paths as given on the command
+	// line or absolute ones
+	prefixes := []string{filepath.ToSlash(filepath.Clean(repoPath)) + "/"}
+	if abs, err := filepath.Abs(repoPath); err == nil {
+		prefixes = append([]string{filepath.ToSlash(abs) + "/"}, prefixes...)
+	}
+
+	snapshot := make([]SnapshotResult, 0, len(results))
+	for _, result := range results {
+		file := result.File
+		if file != "" {
+			if rel, err := filepath.Rel(repoPath, file); err == nil {
+				file = rel
+			}
+			file = filepath.ToSlash(file)
+		}
+
+		// Kustomization files are named after their path
+		message, resource := filepath.ToSlash(result.Message), filepath.ToSlash(result.Resource)
+		for _, prefix := range prefixes {
+			message = strings.ReplaceAll(message, prefix, "")
+			resource = strings.ReplaceAll(resource, prefix, "")
+		}
+
+		snapshot = append(snapshot, SnapshotResult{
+			RuleID:   result.RuleID,
+			Type:     result.Type,
+			Severity: result.Severity,
+			File:     file,
+			Line:     result.Line,
+			Column:   result.Column,
+			Resource: resource,
+			Message:  message,
+		})
+	}
+
+	sort.SliceStable(snapshot, func(i, j int) bool {
+		a, b := snapshot[i], snapshot[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		if a.Column != b.Column {
+			return a.Column < b.Column
+		}
+		if a.RuleID != b.RuleID {
+			return a.RuleID < b.RuleID
+		}
+		return a.Message < b.Message
+	})
+	return snapshot
+}
+
+// diffSnapshotResults returns the results only in actual and only in expected
+func diffSnapshotResults(expected, actual []SnapshotResult) (unexpected, missing []SnapshotResult) {
+	remaining := make(map[SnapshotResult]int)
+	for _, result := range expected {
+		remaining[result]++
+	}
+	for _, result := range actual {
+		if remaining[result] > 0 {
+			remaining[result]--
+			continue
+		}
+		unexpected = append(unexpected, result)
+	}
+	for _, result := range expected {
+		if remaining[result] > 0 {
+			remaining[result]--
+			missing = append(missing, result)
+		}
+	}
+	return unexpected, missing
+}
+
+// formatSnapshotResult renders a result on one line for diffs
+func formatSnapshotResult(result SnapshotResult) string {
+	location := result.File
+	if location == "" {
+		location = "(no file)"
+	} else if result.Line > 0 {
+		location = fmt.Sprintf("%s:%d", location, result.Line)
+		if result.Column > 0 {
+			location = fmt.Sprintf("%s:%d", location, result.Column)
+		}
+	}
+	return fmt.Sprintf("[%s] %s %s: %s", result.RuleID, result.Severity, location, result.Message)
+}
+
+// readSnapshot loads the expected results of a fixture
+func readSnapshot(path string) (snapshotDocument, error) {
+	var document snapshotDocument
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return document, err
+	}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return document, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return document, nil
+}
+
+// writeSnapshot stores the expected results of a fixture
+func writeSnapshot(path string, document snapshotDocument) error {
+	if document.Results == nil {
+		document.Results = []SnapshotResult{}
+	}
+	data, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
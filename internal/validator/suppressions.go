@@ -0,0 +1,138 @@
+package validator
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"time"
+
+	"github.com/moon-hex/gitops-validator/internal/config"
+	"github.com/moon-hex/gitops-validator/internal/parser"
+	"github.com/moon-hex/gitops-validator/internal/types"
+)
+
+// filterSuppressed removes results disabled by gitops-validator:disable
+// comments or config suppressions, keeping them aside so verbose mode can
+// report them. Results matched by an expired config suppression are kept and
+// raised to errors.
+func (v *Validator) filterSuppressed(results []types.ValidationResult) []types.ValidationResult {
+	kept := results[:0:0]
+	for _, result := range results {
+		result, suppressed := v.applySuppressions(result)
+		if suppressed {
+			v.suppressed = append(v.suppressed, result)
+			continue
+		}
+		kept = append(kept, result)
+	}
+	return kept
+}
+
+// unsuppressed applies suppressions without recording them, for results that
+// are streamed before the validator collects them
+func (v *Validator) unsuppressed(results []types.ValidationResult) []types.ValidationResult {
+	var kept []types.ValidationResult
+	for _, result := range results {
+		if result, suppressed := v.applySuppressions(result); !suppressed {
+			kept = append(kept, result)
+		}
+	}
+	return kept
+}
+
+// applySuppressions reports whether a result is suppressed. A result matched
+// only by expired config suppressions is returned as an error that names the
+// expired suppression.
+func (v *Validator) applySuppressions(result types.ValidationResult) (types.ValidationResult, bool) {
+	ruleName := ""
+	if info, ok := types.LookupRuleByType(result.Type); ok {
+		ruleName = info.Rule
+	}
+
+	// Config suppressions may name the result's resource or the manifest it was found in
+	resourceNames := []string{result.Resource}
+	if resource := v.resultResource(result); resource != nil {
+		for _, suppression := range resource.Suppressions {
+			if suppression.AppliesTo(result.Line, result.RuleID, ruleName, result.Type) {
+				return result, true
+			}
+		}
+		resourceNames = append(resourceNames, resource.Name, resource.Kind+"/"+resource.Name)
+	}
+
+	relPath := result.File
+	if rel, err := filepath.Rel(v.repoPath, result.File); err == nil {
+		relPath = rel
+	}
+
+	var expired *config.SuppressionConfig
+	now := time.Now()
+	for i, suppression := range v.config.GitOpsValidator.Suppressions {
+		if !suppression.MatchesRule(result.RuleID, ruleName, result.Type) || !suppression.MatchesLocation(relPath, resourceNames...) {
+			continue
+		}
+		if !suppression.Expired(now) {
+			return result, true
+		}
+		if expired == nil {
+			expired = &v.config.GitOpsValidator.Suppressions[i]
+		}
+	}
+
+	if expired != nil {
+		result.Severity = types.SeverityError
+		result.Message = fmt.Sprintf("%s (suppression expired on %s", result.Message, expired.Expires)
+		if expired.Reason != "" {
+			result.Message += ": " + expired.Reason
+		}
+		result.Message += ")"
+	}
+
+	return result, false
+}
+
+// resultResource finds the parsed document a result was reported against:
+// the only document in its file, the document with the result's resource
+// name, or the document whose start line precedes the result's line
+func (v *Validator) resultResource(result types.ValidationResult) *parser.ParsedResource {
+	if v.graph == nil || result.File == "" {
+		return nil
+	}
+
+	resources := v.graph.Files[result.File]
+	if len(resources) == 0 {
+		return nil
+	}
+	if len(resources) == 1 {
+		return resources[0]
+	}
+
+	for _, resource := range resources {
+		if result.Resource != "" && resource.Name == result.Resource {
+			return resource
+		}
+	}
+
+	var closest *parser.ParsedResource
+	for _, resource := range resources {
+		if result.Line > 0 && resource.Line <= result.Line && (closest == nil || resource.Line > closest.Line) {
+			closest = resource
+		}
+	}
+	if closest != nil {
+		return closest
+	}
+	return resources[0]
+}
+
+// printSuppressed lists suppressed results in verbose mode
+func (v *Validator) printSuppressed(out io.Writer) {
+	if !v.verbose || len(v.suppressed) == 0 {
+		return
+	}
+
+	fmt.Fprintf(out, "\n🔇 Suppressed Results (%d suppressed by comments or config):\n\n", len(v.suppressed))
+	for _, result := range v.suppressed {
+		v.printResultLine(out, result, "")
+	}
+}
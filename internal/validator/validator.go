@@ -1,12 +1,13 @@
 package validator
 
 import (
-	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/moon-hex/gitops-validator/internal/config"
 	"github.com/moon-hex/gitops-validator/internal/context"
@@ -23,8 +24,37 @@ type Validator struct {
 	parser   *parser.ResourceParser
 	graph    *parser.ResourceGraph
 	results  []types.ValidationResult
-	// new: optional output format ("", "markdown", "json")
+	// results disabled by suppression comments or config (shown in verbose mode)
+	suppressed []types.ValidationResult
+	// new: optional output format ("", "markdown", "json", "json-report", "ndjson", "sarif", "badge", "rdf-min")
 	outputFormat string
+	// what the badge output reports: "health" or "errors" (see SetBadgeMetric)
+	badgeMetric string
+	// additional outputs written to files (see SetOutputs)
+	fileOutputs []OutputTarget
+	// print nothing to stdout when --output only names files
+	quietStdout bool
+	// where the stdout output format is written (see SetStdout)
+	stdout io.Writer
+	// --severity overrides keyed by lower-cased rule ID, config rule name or result type
+	severityOverrides map[string]types.Severity
+	// entry points of files applied by several Flux Kustomizations (see propagateSeverities)
+	sharedFiles     map[string][]string
+	sharedFilesOnce sync.Once
+	// files of the Flux Kustomizations deploying each file (see applySeverityFloors)
+	deployedBy     map[string][]string
+	deployedByOnce sync.Once
+	// inserted into file output names when several paths are validated (see SetOutputLabel)
+	outputLabel string
+	// post the Markdown report as a sticky pull request comment (see SetGitHubComment)
+	githubComment bool
+	// sinks added with AddSink, and all sinks of the run (see resultSinks)
+	customSinks []ResultSink
+	sinks       []ResultSink
+	sinksOnce   sync.Once
+	// git metadata of the repository, read once (see gitMetadata)
+	gitOnce sync.Once
+	git     *GitMetadata
 	// Phase III: parallel validation
 	parallel bool
 	// Phase III: validation pipelines
@@ -33,6 +63,18 @@ type Validator struct {
 	// Phase III: result aggregation
 	aggregationOptions *types.AggregationOptions
 	useAggregation     bool
+	// ANSI colors in default human output (auto-detected, see SetNoColor)
+	color bool
+	// disable piping long console output through $PAGER
+	noPager bool
+	// config file the validator was created with, "" for the built-in defaults
+	configPath string
+	// provenance manifest written after the report (see SetRunManifest)
+	runManifest runManifestOptions
+	// when Run started and how long its phases took, for the run manifest
+	startedAt        time.Time
+	parseDuration    time.Duration
+	validateDuration time.Duration
 }
 
 func NewValidator(repoPath string, verbose bool, yamlPath string) *Validator {
@@ -43,25 +85,12 @@ func NewValidator(repoPath string, verbose bool, yamlPath string) *Validator {
 // configPath takes priority; if empty the usual discovery order is used:
 // data/gitops-validator.yaml → .gitops-validator.yaml in CWD → built-in defaults.
 func NewValidatorWithConfigPath(configPath string, repoPath string, verbose bool, yamlPath string) *Validator {
-	cfg := config.DefaultConfig()
-
-	switch {
-	case configPath != "":
-		if loadedConfig, err := config.LoadConfig(configPath); err == nil {
-			cfg = loadedConfig
-		}
-	case fileExists("data/gitops-validator.yaml"):
-		if loadedConfig, err := config.LoadConfig("data/gitops-validator.yaml"); err == nil {
-			cfg = loadedConfig
-		}
-	case fileExists(".gitops-validator.yaml"):
-		if loadedConfig, err := config.LoadConfig(".gitops-validator.yaml"); err == nil {
-			cfg = loadedConfig
-		}
-	}
+	configPath = config.DiscoverConfigPath(configPath)
+	cfg := config.DiscoverConfig(configPath)
 
 	return &Validator{
 		repoPath:           repoPath,
+		configPath:         configPath,
 		verbose:            verbose,
 		yamlPath:           yamlPath,
 		config:             cfg,
@@ -73,6 +102,8 @@ func NewValidatorWithConfigPath(configPath string, repoPath string, verbose bool
 		usePipeline:        false,
 		aggregationOptions: nil, // Aggregation disabled by default
 		useAggregation:     false,
+		color:              colorSupported(),
+		stdout:             os.Stdout,
 	}
 }
 
@@ -83,9 +114,35 @@ func NewValidatorWithParallel(repoPath string, verbose bool, yamlPath string, pa
 	return v
 }
 
-func fileExists(path string) bool {
-	_, err := os.Stat(path)
-	return err == nil
+// SetFluxRoot declares that Flux paths are relative to a subdirectory of the
+// repository (e.g. when the GitOps repo is vendored into a monorepo)
+func (v *Validator) SetFluxRoot(fluxRoot string) {
+	v.config.GitOpsValidator.FluxRoot = fluxRoot
+}
+
+// SetRender enables building the kustomizations Flux applies with kustomize
+func (v *Validator) SetRender(render bool) {
+	v.config.GitOpsValidator.Render = render
+}
+
+// SetKubernetesVersion sets the Kubernetes version the clusters run, which
+// deprecated APIs are judged against
+func (v *Validator) SetKubernetesVersion(version string) error {
+	if _, err := config.KubernetesMinorVersion(version); err != nil {
+		return err
+	}
+	v.config.GitOpsValidator.KubernetesVersion = version
+	return nil
+}
+
+// SetOnline enables the opt-in checks that query remote endpoints
+func (v *Validator) SetOnline(online bool) {
+	v.config.GitOpsValidator.Network.Online = online
+}
+
+// SetOffline disables every network-dependent check
+func (v *Validator) SetOffline(offline bool) {
+	v.config.GitOpsValidator.Network.Offline = offline
 }
 
 // SetParallel enables or disables parallel validation
@@ -151,6 +208,21 @@ func (v *Validator) SetAggregationPreset(preset string) {
 			SortBy:       "type",
 			SortOrder:    "asc",
 		})
+	case "directories":
+		v.SetAggregationOptions(&types.AggregationOptions{
+			GroupBy:      "directory",
+			RootPath:     v.repoPath,
+			IncludeStats: true,
+			SortBy:       "file",
+			SortOrder:    "asc",
+		})
+	case "entry-points":
+		v.SetAggregationOptions(&types.AggregationOptions{
+			GroupBy:      "entry-point",
+			IncludeStats: true,
+			SortBy:       "file",
+			SortOrder:    "asc",
+		})
 	default:
 		// No aggregation
 		v.useAggregation = false
@@ -174,47 +246,65 @@ func NewValidatorWithExitCodesAndConfig(configPath, repoPath string, verbose boo
 	return v
 }
 
+// Validate runs all checks, prints the results and returns the exit code
+// derived from the configured exit-code policy
 func (v *Validator) Validate() (int, error) {
-	if v.verbose {
-		fmt.Printf("Starting validation of repository: %s\n", v.repoPath)
+	if err := v.Run(); err != nil {
+		return 1, err
 	}
+	return v.Report(), nil
+}
 
-	// Check if repository path exists
-	if _, err := os.Stat(v.repoPath); os.IsNotExist(err) {
-		return 1, fmt.Errorf("repository path does not exist: %s", v.repoPath)
-	}
+// Run parses the repository and runs all checks, collecting results without
+// printing them
+func (v *Validator) Run() error {
+	v.startedAt = time.Now()
 
-	// Parse all resources into the graph
 	if v.verbose {
-		fmt.Printf("Parsing resources...\n")
+		fmt.Printf("Starting validation of repository: %s\n", v.repoPath)
 	}
 
-	graph, err := v.parser.ParseAllResources()
-	if err != nil {
-		return 1, fmt.Errorf("failed to parse resources: %w", err)
+	// Rule parameters are checked up front, so a typo fails the run rather
+	// than silently leaving a check at its defaults
+	if err := v.config.ValidateRuleParams(); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
 	}
-	v.graph = graph
-
-	if v.verbose {
-		fmt.Printf("Found %d resources in %d files\n", len(graph.Resources), len(graph.Files))
+	if err := v.config.ValidateSeverities(); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := v.validateSeverityFloors(); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
 	}
 
-	// Build fast lookup index for large repositories (Phase III)
-	if v.verbose {
-		fmt.Printf("Building resource index...\n")
+	// Check if repository path exists
+	if _, err := os.Stat(v.repoPath); os.IsNotExist(err) {
+		return fmt.Errorf("repository path does not exist: %s", v.repoPath)
 	}
-	if err := graph.BuildIndex(); err != nil {
-		return 1, fmt.Errorf("failed to build resource index: %w", err)
+
+	// Check that the Flux root (if configured) exists inside the repository
+	if fluxRoot := v.config.ResolveFluxRoot(v.repoPath); fluxRoot != v.repoPath {
+		if v.verbose {
+			fmt.Printf("Resolving Flux paths relative to: %s\n", fluxRoot)
+		}
+		if _, err := os.Stat(fluxRoot); os.IsNotExist(err) {
+			return fmt.Errorf("flux root does not exist: %s", fluxRoot)
+		}
 	}
 
-	if v.verbose {
-		stats := graph.Index.GetIndexStats()
-		fmt.Printf("Index built: %d resources, %d Flux Kustomizations, %d Kubernetes Kustomizations\n",
-			stats["total_resources"], stats["flux_kustomizations"], stats["kubernetes_kustomizations"])
+	parseStart := time.Now()
+	graph, err := v.loadGraph()
+	if err != nil {
+		return err
 	}
 
+	v.parseDuration = time.Since(parseStart)
+
 	// Create validation context
+	validateStart := time.Now()
 	validationContext := context.NewValidationContext(graph, v.config, v.repoPath, v.verbose)
+	if v.verbose {
+		v.printEntryPointSources(validationContext)
+	}
 
 	// Run validation using pipeline or traditional approach
 	if v.usePipeline {
@@ -229,6 +319,48 @@ func (v *Validator) Validate() (int, error) {
 			validators.NewDeprecatedAPIValidator(v.repoPath),
 			validators.NewFluxPostBuildVariablesValidator(v.repoPath),
 			validators.NewHTTPRoutePolicyValidator(v.repoPath),
+			validators.NewSymlinkValidator(v.repoPath),
+			validators.NewYAMLAnchorValidator(v.repoPath),
+			validators.NewHelmReleaseCollisionValidator(v.repoPath),
+			validators.NewNamespaceCollisionValidator(v.repoPath),
+			validators.NewTargetNamespaceValidator(v.repoPath),
+			validators.NewFluxIntervalValidator(v.repoPath),
+			validators.NewFluxPruneWaitValidator(v.repoPath),
+			validators.NewKustomizeNamespaceValidator(v.repoPath),
+			validators.NewHelmReleaseValuesFromValidator(v.repoPath),
+			validators.NewSOPSConfigValidator(v.repoPath),
+			validators.NewHelmChartVersionValidator(v.repoPath),
+			validators.NewEnvFieldRefValidator(v.repoPath),
+			validators.NewMultipleInclusionValidator(v.repoPath),
+			validators.NewImageAutomationRefValidator(v.repoPath),
+			validators.NewImagePolicyMarkerValidator(v.repoPath),
+			validators.NewNotificationRefValidator(v.repoPath),
+			validators.NewCrossNamespaceRefValidator(v.repoPath),
+			validators.NewReferenceGraphValidator(v.repoPath),
+			validators.NewSOPSDecryptionValidator(v.repoPath),
+			validators.NewFluxAPIVersionValidator(v.repoPath),
+			validators.NewFluxServiceAccountValidator(v.repoPath),
+			validators.NewImageAutomationWriteBackValidator(v.repoPath),
+			validators.NewRenderedNameCollisionValidator(v.repoPath),
+			validators.NewKustomizeImageValidator(v.repoPath),
+			validators.NewKustomizeReplacementValidator(v.repoPath),
+			validators.NewKustomizeRemoteResourceValidator(v.repoPath),
+			validators.NewKustomizeBuildValidator(v.repoPath),
+			validators.NewMissingNamespaceValidator(v.repoPath),
+			validators.NewSecretRefValidator(v.repoPath),
+			validators.NewConfigMapRefValidator(v.repoPath),
+			validators.NewWorkloadServiceAccountValidator(v.repoPath),
+			validators.NewStorageClassValidator(v.repoPath),
+			validators.NewRouteBackendValidator(v.repoPath),
+			validators.NewServiceSelectorValidator(v.repoPath),
+			validators.NewNetworkPolicyValidator(v.repoPath),
+			validators.NewCustomResourceValidator(v.repoPath),
+			validators.NewKubeconformValidator(v.repoPath),
+			validators.NewContainerResourcesValidator(v.repoPath),
+			validators.NewImageTagValidator(v.repoPath),
+			validators.NewLabelPolicyValidator(v.repoPath),
+			validators.NewContribValidator(v.repoPath),
+			validators.NewCustomAssertionValidator(v.repoPath),
 		}
 
 		// Run all validators with context (parallel or sequential)
@@ -239,9 +371,35 @@ func (v *Validator) Validate() (int, error) {
 		}
 	}
 
+	// Attach stable rule IDs and documentation links
+	types.AnnotateRuleMetadata(v.results)
+	v.validateDuration = time.Since(validateStart)
+
+	return nil
+}
+
+// Results returns the results collected by Run, after suppressions and
+// severity overrides
+func (v *Validator) Results() []types.ValidationResult {
+	return v.results
+}
+
+// Report prints the collected results and returns the exit code derived from
+// the configured exit-code policy
+func (v *Validator) Report() int {
 	// Print results
 	v.printResults()
 
+	exitCode := v.exitCode()
+	if v.runManifest.file != "" {
+		v.writeRunManifest(exitCode)
+	}
+	return exitCode
+}
+
+// exitCode derives the exit code of the collected results from the
+// configured exit-code policy
+func (v *Validator) exitCode() int {
 	// Check validation results based on configured exit codes
 	hasErrors := false
 	hasWarnings := false
@@ -249,27 +407,30 @@ func (v *Validator) Validate() (int, error) {
 
 	for _, result := range v.results {
 		switch result.Severity {
-		case "error":
+		case types.SeverityError:
 			hasErrors = true
-		case "warning":
+		case types.SeverityWarning:
 			hasWarnings = true
-		case "info":
+		case types.SeverityInfo:
 			hasInfo = true
 		}
 	}
 
 	// Return appropriate exit code based on configuration
+	if code := v.mappedExitCode(); code != 0 {
+		return code
+	}
 	if hasErrors && v.config.GitOpsValidator.ExitCodes.FailOnErrors {
-		return 1, nil // Exit code 1 for errors, no error returned
+		return 1 // Exit code 1 for errors
 	}
 	if hasWarnings && v.config.GitOpsValidator.ExitCodes.FailOnWarnings {
-		return 2, nil // Exit code 2 for warnings, no error returned
+		return 2 // Exit code 2 for warnings
 	}
 	if hasInfo && v.config.GitOpsValidator.ExitCodes.FailOnInfo {
-		return 3, nil // Exit code 3 for info, no error returned
+		return 3 // Exit code 3 for info
 	}
 
-	return 0, nil // Exit code 0 for success, no error returned
+	return 0 // Exit code 0 for success
 }
 
 // runValidatorsSequential runs validators sequentially (legacy behavior)
@@ -282,15 +443,15 @@ func (v *Validator) runValidatorsSequential(validatorList []validators.GraphVali
 		results, err := validator.Validate(validationContext)
 		if err != nil {
 			// Add error as validation result instead of failing completely
-			v.results = append(v.results, types.ValidationResult{
+			v.addResults(types.ValidationResult{
 				Type:     "validator-error",
-				Severity: "error",
+				Severity: types.SeverityError,
 				Message:  fmt.Sprintf("Validator %s failed: %s", validator.Name(), err.Error()),
 			})
 			continue
 		}
 
-		v.results = append(v.results, results...)
+		v.addResults(results...)
 	}
 }
 
@@ -343,16 +504,16 @@ func (v *Validator) runValidatorsParallel(validatorList []validators.GraphValida
 			if !ok {
 				resultChan = nil
 			} else {
-				v.results = append(v.results, results...)
+				v.addResults(results...)
 			}
 		case err, ok := <-errorChan:
 			if !ok {
 				errorChan = nil
 			} else {
 				// Add error as validation result instead of failing completely
-				v.results = append(v.results, types.ValidationResult{
+				v.addResults(types.ValidationResult{
 					Type:     "validator-error",
-					Severity: "error",
+					Severity: types.SeverityError,
 					Message:  err.Error(),
 				})
 			}
@@ -384,40 +545,148 @@ func (v *Validator) runValidationWithPipeline(validationContext *context.Validat
 		"deprecated-api":                    validators.NewDeprecatedAPIValidator(v.repoPath),
 		"flux-postbuild-variables":          validators.NewFluxPostBuildVariablesValidator(v.repoPath),
 		"http-route-policy":                 validators.NewHTTPRoutePolicyValidator(v.repoPath),
+		"symlink":                           validators.NewSymlinkValidator(v.repoPath),
+		"yaml-anchor":                       validators.NewYAMLAnchorValidator(v.repoPath),
+		"helm-release-collision":            validators.NewHelmReleaseCollisionValidator(v.repoPath),
+		"namespace-collision":               validators.NewNamespaceCollisionValidator(v.repoPath),
+		"target-namespace":                  validators.NewTargetNamespaceValidator(v.repoPath),
+		"flux-interval":                     validators.NewFluxIntervalValidator(v.repoPath),
+		"flux-prune-wait":                   validators.NewFluxPruneWaitValidator(v.repoPath),
+		"kustomize-namespace":               validators.NewKustomizeNamespaceValidator(v.repoPath),
+		"helm-release-values-from":          validators.NewHelmReleaseValuesFromValidator(v.repoPath),
+		"sops-config":                       validators.NewSOPSConfigValidator(v.repoPath),
+		"helm-chart-version":                validators.NewHelmChartVersionValidator(v.repoPath),
+		"env-field-ref":                     validators.NewEnvFieldRefValidator(v.repoPath),
+		"multiple-inclusion":                validators.NewMultipleInclusionValidator(v.repoPath),
+		"image-automation-ref":              validators.NewImageAutomationRefValidator(v.repoPath),
+		"image-policy-marker":               validators.NewImagePolicyMarkerValidator(v.repoPath),
+		"notification-ref":                  validators.NewNotificationRefValidator(v.repoPath),
+		"cross-namespace-ref":               validators.NewCrossNamespaceRefValidator(v.repoPath),
+		"reference-graph":                   validators.NewReferenceGraphValidator(v.repoPath),
+		"sops-decryption":                   validators.NewSOPSDecryptionValidator(v.repoPath),
+		"flux-api-version":                  validators.NewFluxAPIVersionValidator(v.repoPath),
+		"flux-service-account":              validators.NewFluxServiceAccountValidator(v.repoPath),
+		"image-automation-write-back":       validators.NewImageAutomationWriteBackValidator(v.repoPath),
+		"rendered-name-collision":           validators.NewRenderedNameCollisionValidator(v.repoPath),
+		"kustomize-image":                   validators.NewKustomizeImageValidator(v.repoPath),
+		"kustomize-replacement":             validators.NewKustomizeReplacementValidator(v.repoPath),
+		"kustomize-remote-resource":         validators.NewKustomizeRemoteResourceValidator(v.repoPath),
+		"kustomize-build":                   validators.NewKustomizeBuildValidator(v.repoPath),
+		"missing-namespace":                 validators.NewMissingNamespaceValidator(v.repoPath),
+		"secret-ref":                        validators.NewSecretRefValidator(v.repoPath),
+		"configmap-ref":                     validators.NewConfigMapRefValidator(v.repoPath),
+		"workload-service-account":          validators.NewWorkloadServiceAccountValidator(v.repoPath),
+		"storage-class":                     validators.NewStorageClassValidator(v.repoPath),
+		"route-backend":                     validators.NewRouteBackendValidator(v.repoPath),
+		"service-selector":                  validators.NewServiceSelectorValidator(v.repoPath),
+		"network-policy":                    validators.NewNetworkPolicyValidator(v.repoPath),
+		"custom-resource":                   validators.NewCustomResourceValidator(v.repoPath),
+		"kubeconform":                       validators.NewKubeconformValidator(v.repoPath),
+		"container-resources":               validators.NewContainerResourcesValidator(v.repoPath),
+		"image-tags":                        validators.NewImageTagValidator(v.repoPath),
+		"label-policies":                    validators.NewLabelPolicyValidator(v.repoPath),
+		"contrib":                           validators.NewContribValidator(v.repoPath),
+		"custom-assertion":                  validators.NewCustomAssertionValidator(v.repoPath),
 	}
 
 	// Create pipeline executor
 	executor := validators.NewPipelineExecutor(validatorRegistry, v.verbose)
+	// Stream each validator's results to the sinks as the pipeline produces
+	// them, on a copy since the batch is processed again below
+	executor.OnResults = func(results []types.ValidationResult) {
+		results = append([]types.ValidationResult(nil), results...)
+		types.AnnotateRuleMetadata(results)
+		v.overrideSeverities(results)
+		v.applySeverityFloors(results)
+		v.propagateSeverities(results)
+		v.writeSinks(v.unsuppressed(results))
+	}
+	// Stages marked SkipOnErrors judge earlier errors as they are reported
+	executor.HasErrors = func(results []types.ValidationResult) bool {
+		results = append([]types.ValidationResult(nil), results...)
+		types.AnnotateRuleMetadata(results)
+		v.overrideSeverities(results)
+		v.applySeverityFloors(results)
+		for _, result := range v.unsuppressed(results) {
+			if result.Severity == types.SeverityError {
+				return true
+			}
+		}
+		return false
+	}
 
 	// Execute pipeline
+	// A failed pipeline still returns the results of the stages that ran,
+	// including those marked RunAlways after the failure
 	results, err := executor.ExecutePipeline(v.pipeline, validationContext)
+
+	// Already streamed to the sinks through executor.OnResults
+	types.AnnotateRuleMetadata(results)
+	v.overrideSeverities(results)
+	v.applySeverityFloors(results)
+	v.propagateSeverities(results)
+	v.results = append(v.results, v.filterSuppressed(results)...)
+
 	if err != nil {
-		v.results = append(v.results, types.ValidationResult{
+		v.addResults(types.ValidationResult{
 			Type:     "pipeline-error",
-			Severity: "error",
+			Severity: types.SeverityError,
 			Message:  fmt.Sprintf("Pipeline execution failed: %s", err.Error()),
 		})
-	} else {
-		v.results = append(v.results, results...)
 	}
 }
 
-// GenerateChart generates a dependency chart in the specified format
-func (v *Validator) GenerateChart(format string, outputFile string) error {
-	if v.verbose {
-		fmt.Printf("Generating dependency chart...\n")
+// loadGraph parses the repository and builds its lookup index on first use,
+// so a run that validates and generates a chart parses the repository once
+func (v *Validator) loadGraph() (*parser.ResourceGraph, error) {
+	if v.graph != nil {
+		return v.graph, nil
 	}
 
 	// Parse all resources into the graph
+	if v.verbose {
+		fmt.Printf("Parsing resources...\n")
+	}
+
 	graph, err := v.parser.ParseAllResources()
 	if err != nil {
-		return fmt.Errorf("failed to parse resources: %w", err)
+		return nil, fmt.Errorf("failed to parse resources: %w", err)
 	}
 
 	if v.verbose {
 		fmt.Printf("Found %d resources in %d files\n", len(graph.Resources), len(graph.Files))
 	}
 
+	// Build fast lookup index for large repositories (Phase III)
+	if v.verbose {
+		fmt.Printf("Building resource index...\n")
+	}
+	if err := graph.BuildIndex(); err != nil {
+		return nil, fmt.Errorf("failed to build resource index: %w", err)
+	}
+
+	if v.verbose {
+		stats := graph.Index.GetIndexStats()
+		fmt.Printf("Index built: %d resources, %d Flux Kustomizations, %d Kubernetes Kustomizations\n",
+			stats["total_resources"], stats["flux_kustomizations"], stats["kubernetes_kustomizations"])
+	}
+
+	v.graph = graph
+	return graph, nil
+}
+
+// GenerateChart generates a dependency chart in the specified format. After
+// Run it reuses the graph validation parsed.
+func (v *Validator) GenerateChart(format string, outputFile string) error {
+	if v.verbose {
+		fmt.Printf("Generating dependency chart...\n")
+	}
+
+	graph, err := v.loadGraph()
+	if err != nil {
+		return err
+	}
+
 	// Create validation context
 	ctx := context.NewValidationContext(graph, v.config, v.repoPath, v.verbose)
 
@@ -427,36 +696,22 @@ func (v *Validator) GenerateChart(format string, outputFile string) error {
 		return fmt.Errorf("failed to generate chart: %w", err)
 	}
 
-	// Output the chart
-	if outputFile != "" {
-		err := os.WriteFile(outputFile, []byte(chart), 0644)
-		if err != nil {
-			return fmt.Errorf("failed to write chart to file %s: %w", outputFile, err)
-		}
-		if v.verbose {
-			fmt.Printf("Chart written to: %s\n", outputFile)
-		}
-	} else {
-		fmt.Println(chart)
-	}
-
-	return nil
+	return v.writeChart(chart, outputFile)
 }
 
-// GenerateChartForEntryPoint generates a dependency chart for a specific entry point
+// GenerateChartForEntryPoint generates a dependency chart for a specific entry
+// point, or else for a cluster directory relative to the repository root. The
+// chart only holds what the entry point or cluster reaches, so the rest of
+// the repository is not shown as orphaned. After Run it reuses the graph
+// validation parsed.
 func (v *Validator) GenerateChartForEntryPoint(format string, outputFile string, entryPointName string) error {
 	if v.verbose {
 		fmt.Printf("Generating dependency chart for entry point: %s\n", entryPointName)
 	}
 
-	// Parse all resources into the graph
-	graph, err := v.parser.ParseAllResources()
+	graph, err := v.loadGraph()
 	if err != nil {
-		return fmt.Errorf("failed to parse resources: %w", err)
-	}
-
-	if v.verbose {
-		fmt.Printf("Found %d resources in %d files\n", len(graph.Resources), len(graph.Files))
+		return err
 	}
 
 	// Create validation context
@@ -472,28 +727,78 @@ func (v *Validator) GenerateChartForEntryPoint(format string, outputFile string,
 		}
 	}
 
+	// Otherwise a cluster directory, such as clusters/production
+	if targetEntryPoint == nil {
+		if info, err := os.Stat(filepath.Join(v.repoPath, entryPointName)); err == nil && info.IsDir() {
+			chart, err := ctx.ScopeToCluster(entryPointName).GenerateDependencyChart(format)
+			if err != nil {
+				return fmt.Errorf("failed to generate chart: %w", err)
+			}
+			return v.writeChart(chart, outputFile)
+		}
+	}
+
 	if targetEntryPoint == nil {
 		return fmt.Errorf("entry point '%s' not found. Available entry points: %v",
 			entryPointName, getEntryPointNames(entryPoints))
 	}
 
 	// Generate the chart for this entry point
-	chart, err := ctx.GenerateDependencyChartForEntryPoint(targetEntryPoint, format)
+	chart, err := ctx.ScopeToEntryPoint(targetEntryPoint).GenerateDependencyChartForEntryPoint(targetEntryPoint, format)
 	if err != nil {
 		return fmt.Errorf("failed to generate chart: %w", err)
 	}
 
-	// Output the chart
-	if outputFile != "" {
-		err := os.WriteFile(outputFile, []byte(chart), 0644)
-		if err != nil {
-			return fmt.Errorf("failed to write chart to file %s: %w", outputFile, err)
-		}
-		if v.verbose {
-			fmt.Printf("Chart written to: %s\n", outputFile)
-		}
-	} else {
+	return v.writeChart(chart, outputFile)
+}
+
+// writeChart writes a chart to outputFile, or to stdout when it is empty
+func (v *Validator) writeChart(chart string, outputFile string) error {
+	if outputFile == "" {
 		fmt.Println(chart)
+		return nil
+	}
+	if err := os.WriteFile(outputFile, []byte(chart), 0644); err != nil {
+		return fmt.Errorf("failed to write chart to file %s: %w", outputFile, err)
+	}
+	if v.verbose {
+		fmt.Printf("Chart written to: %s\n", outputFile)
+	}
+	return nil
+}
+
+// PrintReferencePath prints the shortest reference chain from one resource to another,
+// or states that none exists. Resources may be identified by key, Kind/name, name or file path.
+// After Run it reuses the graph validation parsed.
+func (v *Validator) PrintReferencePath(from, to string) error {
+	graph, err := v.loadGraph()
+	if err != nil {
+		return err
+	}
+
+	fromResources := graph.FindResources(from, v.repoPath)
+	if len(fromResources) == 0 {
+		return fmt.Errorf("resource '%s' not found", from)
+	}
+	toResources := graph.FindResources(to, v.repoPath)
+	if len(toResources) == 0 {
+		return fmt.Errorf("resource '%s' not found", to)
+	}
+
+	chain := graph.ShortestReferencePath(fromResources, toResources, v.config.ResolveFluxRoot(v.repoPath))
+	if chain == nil {
+		fmt.Printf("No reference path from '%s' to '%s'\n", from, to)
+		return nil
+	}
+
+	fmt.Printf("Reference path from '%s' to '%s' (%d hops):\n", from, to, len(chain)-1)
+	for i, step := range chain {
+		description := fmt.Sprintf("%s '%s' (File: %s)", step.Resource.Kind, step.Resource.Name, step.Resource.File)
+		if i == 0 {
+			fmt.Printf("  %s\n", description)
+			continue
+		}
+		fmt.Printf("  %s└─%s→ %s\n", strings.Repeat("  ", i-1), step.Reference.ReferenceType, description)
 	}
 
 	return nil
@@ -508,31 +813,65 @@ func getEntryPointNames(entryPoints []*parser.ParsedResource) []string {
 	return names
 }
 
+// addResults records results and streams them to the sinks
+func (v *Validator) addResults(results ...types.ValidationResult) {
+	types.AnnotateRuleMetadata(results)
+	v.overrideSeverities(results)
+	v.applySeverityFloors(results)
+	v.propagateSeverities(results)
+	results = v.filterSuppressed(results)
+	v.results = append(v.results, results...)
+	v.writeSinks(results)
+}
+
+// printResults hands the summary to every sink: report files and the pull
+// request comment first, then stdout and the added sinks
 func (v *Validator) printResults() {
-	if len(v.results) == 0 {
-		fmt.Println("✅ All validations passed!")
-		return
+	summary := v.summary()
+	for _, sink := range v.resultSinks() {
+		if err := sink.Flush(summary); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
 	}
+}
 
-	// Apply result aggregation if enabled
-	var resultsToPrint []types.ValidationResult
-	if v.useAggregation && v.aggregationOptions != nil {
-		aggregator := types.NewResultAggregator(v.results)
-		aggregated := aggregator.Aggregate(*v.aggregationOptions)
-		resultsToPrint = aggregated.Results
+// printReport prints the report in the stdout output format
+func (v *Validator) printReport(summary Summary) {
+	resultsToPrint, aggregated, health := summary.Results, summary.Aggregated, summary.HealthScore
 
-		// Print summary if requested
-		if v.aggregationOptions.IncludeStats {
-			fmt.Println(aggregated.GetSummary())
-			fmt.Println()
+	// Machine-readable formats always produce a document, even without findings
+	humanOutput := v.outputFormat == "" || v.outputFormat == "markdown"
+	if len(v.results) == 0 && humanOutput {
+		if summary.Git != nil && v.outputFormat == "" {
+			fmt.Fprintf(v.stdout, "🔖 Revision: %s\n", summary.Git)
 		}
-	} else {
-		resultsToPrint = v.results
+		fmt.Fprintln(v.stdout, v.colorize(ansiGreen, "✅ All validations passed!"))
+		v.printSuppressed(v.stdout)
+		return
+	}
+
+	// Long console output goes through the pager, like git does
+	out, closePager := v.startPager(len(resultsToPrint))
+	defer closePager()
+
+	// Print summary if requested
+	if aggregated != nil && v.aggregationOptions.IncludeStats && humanOutput {
+		fmt.Fprintln(out, aggregated.GetSummary())
+		fmt.Fprintln(out)
 	}
 
 	// Default human-readable output
 	if v.outputFormat == "" {
-		fmt.Printf("\n📋 Validation Results (%d issues found):\n\n", len(resultsToPrint))
+		if summary.Git != nil {
+			fmt.Fprintf(out, "\n🔖 Revision: %s\n", summary.Git)
+		}
+		fmt.Fprintf(out, "\n📋 Validation Results (%d issues found):\n\n", len(resultsToPrint))
+
+		if aggregated != nil && v.aggregationOptions.GroupBy == "entry-point" {
+			v.printEntryPointGroups(out, aggregated)
+			v.printSuppressed(out)
+			return
+		}
 
 		// Separate orphaned-resource results (they may be grouped) from everything else
 		var other []types.ValidationResult
@@ -547,7 +886,7 @@ func (v *Validator) printResults() {
 
 		// Print non-orphaned results flat
 		for _, result := range other {
-			printResultLine(result, "")
+			v.printResultLine(out, result, "")
 		}
 
 		// Print orphaned results — grouped if any have a category, flat otherwise
@@ -585,12 +924,12 @@ func (v *Validator) printResults() {
 				seenCategories[cat.Name] = true
 				// blank line before every group (separates from previous content)
 				if !firstGroup || len(other) > 0 {
-					fmt.Println()
+					fmt.Fprintln(out)
 				}
 				firstGroup = false
-				fmt.Printf("⚠️  Orphaned Resources — %s (%d):\n", cat.Name, len(items))
+				fmt.Fprintln(out, v.colorize(ansiYellow, fmt.Sprintf("⚠️  Orphaned Resources — %s (%d):", cat.Name, len(items))))
 				for _, r := range items {
-					printResultLine(r, "  ")
+					v.printResultLine(out, r, "  ")
 				}
 			}
 
@@ -600,80 +939,82 @@ func (v *Validator) printResults() {
 					continue
 				}
 				firstGroup = false
-				fmt.Printf("\n⚠️  Orphaned Resources — %s (%d):\n", catName, len(items))
+				fmt.Fprintln(out)
+				fmt.Fprintln(out, v.colorize(ansiYellow, fmt.Sprintf("⚠️  Orphaned Resources — %s (%d):", catName, len(items))))
 				for _, r := range items {
-					printResultLine(r, "  ")
+					v.printResultLine(out, r, "  ")
 				}
 			}
 
 			// Uncategorised orphans last
 			if len(uncategorised) > 0 {
-				fmt.Println()
-				fmt.Printf("⚠️  Orphaned Resources — Uncategorized (%d):\n", len(uncategorised))
+				fmt.Fprintln(out)
+				fmt.Fprintln(out, v.colorize(ansiYellow, fmt.Sprintf("⚠️  Orphaned Resources — Uncategorized (%d):", len(uncategorised))))
 				for _, r := range uncategorised {
-					printResultLine(r, "  ")
+					v.printResultLine(out, r, "  ")
 				}
 			}
 		} else {
 			// No categories configured — print flat as before
 			for _, result := range orphaned {
-				printResultLine(result, "")
+				v.printResultLine(out, result, "")
 			}
 		}
+		v.printSuppressed(out)
 		return
 	}
 
-	// Markdown table output
-	if v.outputFormat == "markdown" || v.outputFormat == "md" {
-		fmt.Println("## GitOps Validator Results")
-		fmt.Println()
-		fmt.Printf("%d issues found\n\n", len(resultsToPrint))
-		fmt.Println("| Severity | Type | Message | File | Line | Resource | Category |")
-		fmt.Println("|---|---|---|---|---:|---|---|")
-		for _, r := range resultsToPrint {
-			msg := strings.ReplaceAll(r.Message, "|", "\\|")
-			fmt.Printf("| %s | %s | %s | %s | %d | %s | %s |\n",
-				strings.ToUpper(r.Severity), r.Type, msg, r.File, r.Line, r.Resource, r.Category)
-		}
-		return
+	var err error
+	switch v.outputFormat {
+	case "markdown":
+		renderMarkdown(out, resultsToPrint, health, summary.Git)
+	case "json":
+		err = renderJSON(out, resultsToPrint)
+	case "json-report":
+		err = renderJSONReport(out, resultsToPrint, health, summary.Git)
+	case "sarif":
+		err = renderSARIF(out, resultsToPrint, summary.Git)
+	case "badge":
+		err = v.renderBadge(out, health)
+	case "rdf-min":
+		err = v.renderRDFMin(out, resultsToPrint, summary.Git)
 	}
-
-	// JSON output
-	if v.outputFormat == "json" {
-		b, err := json.MarshalIndent(resultsToPrint, "", "  ")
-		if err != nil {
-			fmt.Printf("Error formatting JSON output: %v\n", err)
-			return
-		}
-		fmt.Println(string(b))
-		return
+	if err != nil {
+		fmt.Fprintf(out, "%v\n", err)
 	}
 }
 
 // printResultLine prints a single validation result with optional indentation prefix
-func printResultLine(result types.ValidationResult, indent string) {
+func (v *Validator) printResultLine(out io.Writer, result types.ValidationResult, indent string) {
 	icon := getSeverityIcon(result.Severity)
-	fmt.Printf("%s%s [%s] %s", indent, icon, strings.ToUpper(result.Severity), result.Message)
+	label := fmt.Sprintf("%s [%s] %s", icon, strings.ToUpper(string(result.Severity)), result.Message)
+	fmt.Fprintf(out, "%s%s", indent, v.colorize(severityColor(result.Severity), label))
 	if result.File != "" {
-		fmt.Printf(" (File: %s", result.File)
+		fmt.Fprintf(out, " (File: %s", result.File)
 		if result.Line > 0 {
-			fmt.Printf(":%d", result.Line)
+			fmt.Fprintf(out, ":%d", result.Line)
+			if result.Column > 0 {
+				fmt.Fprintf(out, ":%d", result.Column)
+			}
 		}
-		fmt.Printf(")")
+		fmt.Fprintf(out, ")")
 	}
 	if result.Resource != "" {
-		fmt.Printf(" (Resource: %s)", result.Resource)
+		fmt.Fprintf(out, " (Resource: %s)", result.Resource)
+	}
+	if len(result.AffectedEntryPoints) > 0 {
+		fmt.Fprintf(out, " (Entry points: %s)", strings.Join(result.AffectedEntryPoints, ", "))
 	}
-	fmt.Println()
+	fmt.Fprintln(out)
 }
 
-func getSeverityIcon(severity string) string {
+func getSeverityIcon(severity types.Severity) string {
 	switch severity {
-	case "error":
+	case types.SeverityError:
 		return "❌"
-	case "warning":
+	case types.SeverityWarning:
 		return "⚠️"
-	case "info":
+	case types.SeverityInfo:
 		return "ℹ️"
 	default:
 		return "📝"
@@ -704,11 +1045,13 @@ func (v *Validator) findYAMLFiles() ([]string, error) {
 	return yamlFiles, err
 }
 
-// SetOutputFormat configures how results are printed: "markdown", "json" or default human output
+// SetOutputFormat configures how results are printed: "markdown", "json", "json-report", "ndjson", "sarif", "badge", "rdf-min" or default human output
 func (v *Validator) SetOutputFormat(format string) {
 	f := strings.ToLower(strings.TrimSpace(format))
 	switch f {
-	case "markdown", "md", "json":
+	case "markdown", "md":
+		v.outputFormat = "markdown"
+	case "json", "json-report", "ndjson", "sarif", "badge", "rdf-min":
 		v.outputFormat = f
 	default:
 		v.outputFormat = ""
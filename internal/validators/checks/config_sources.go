@@ -0,0 +1,87 @@
+package checks
+
+import (
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/parser"
+)
+
+// ConfigSources indexes the ConfigMaps and Secrets the repository creates, by
+// kind, namespace and name, for checks of references to them such as
+// postBuild.substituteFrom and HelmRelease valuesFrom. Each is indexed under
+// the namespace in its manifest, the namespaces it is deployed to, and no
+// namespace. The value is empty for usable objects and names the
+// kustomization file for generated ones with a hash suffix.
+func ConfigSources(ctx *context.ValidationContext) map[string]string {
+	available := make(map[string]string)
+	add := func(kind, namespace, name, origin string) {
+		for _, ns := range []string{namespace, ""} {
+			key := configSourceKey(kind, ns, name)
+			if existing, exists := available[key]; !exists || (existing != "" && origin == "") {
+				available[key] = origin
+			}
+		}
+	}
+
+	deployedTo := deployedNamespaces(ctx)
+
+	for _, kind := range []string{"ConfigMap", "Secret"} {
+		for _, resource := range ctx.Graph.GetResourcesByKind(kind) {
+			add(kind, resource.Namespace, resource.Name, "")
+			for _, namespace := range deployedTo[resource] {
+				add(kind, namespace, resource.Name, "")
+			}
+		}
+	}
+
+	for _, kustomization := range ctx.Graph.GetKubernetesKustomizations() {
+		ownNamespace, _ := kustomization.Content["namespace"].(string)
+		options, _ := kustomization.Content["generatorOptions"].(map[string]interface{})
+		noHash := options["disableNameSuffixHash"] == "true"
+
+		for field, kind := range map[string]string{"configMapGenerator": "ConfigMap", "secretGenerator": "Secret"} {
+			generators, _ := kustomization.Content[field].([]interface{})
+			for _, generator := range generators {
+				entry, _ := generator.(map[string]interface{})
+				name, _ := entry["name"].(string)
+				if name == "" {
+					continue
+				}
+				origin := relativeFile(ctx, kustomization.File)
+				entryOptions, _ := entry["options"].(map[string]interface{})
+				if disabled, set := entryOptions["disableNameSuffixHash"]; (set && disabled == "true") || (!set && noHash) {
+					origin = ""
+				}
+
+				namespaces := deployedTo[kustomization]
+				if namespace, _ := entry["namespace"].(string); namespace != "" {
+					namespaces = append(namespaces, namespace)
+				}
+				add(kind, ownNamespace, name, origin)
+				for _, namespace := range namespaces {
+					add(kind, namespace, name, origin)
+				}
+			}
+		}
+	}
+
+	return available
+}
+
+// deployedNamespaces returns the namespaces each resource is deployed to by
+// the Flux Kustomizations of the repository, after namespace overrides
+func deployedNamespaces(ctx *context.ValidationContext) map[*parser.ParsedResource][]string {
+	deployedTo := make(map[*parser.ParsedResource][]string)
+	for _, root := range ctx.RootKustomizations() {
+		for _, deployed := range ctx.DeploymentTree(root) {
+			if !containsString(deployedTo[deployed.Resource], deployed.Namespace) {
+				deployedTo[deployed.Resource] = append(deployedTo[deployed.Resource], deployed.Namespace)
+			}
+		}
+	}
+	return deployedTo
+}
+
+// configSourceKey is the key of a ConfigMap or Secret in ConfigSources
+func configSourceKey(kind, namespace, name string) string {
+	return kind + "/" + namespace + "/" + name
+}
@@ -0,0 +1,184 @@
+package checks
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/types"
+)
+
+// rootCAConfigMap is the ConfigMap the API server publishes in every
+// namespace, with the cluster CA under ca.crt
+const rootCAConfigMap = "kube-root-ca.crt"
+
+// configMapKeySet is the keys of the ConfigMaps of one name in one namespace
+type configMapKeySet struct {
+	keys map[string]bool
+	// unknown is set when a definition's keys cannot be read, such as a
+	// generator reading a missing env file
+	unknown bool
+}
+
+// ConfigMapRefCheck validates the ConfigMaps the pod specs of workloads
+// reference through envFrom, env valueFrom.configMapKeyRef, configMap and
+// projected volumes. Each must exist in the namespace the workload is deployed
+// to, as a manifest or a configMapGenerator; otherwise pods do not start. For
+// those, the keys a configMapKeyRef or volume items read must be among the
+// keys of the manifests and generators of that name. References marked
+// optional, names with Flux variables and kube-root-ca.crt are skipped, and
+// ConfigMaps created outside the repository are declared with the rule's
+// external list.
+func ConfigMapRefCheck(ctx *context.ValidationContext) []types.ValidationResult {
+	var results []types.ValidationResult
+
+	targets := workloads(ctx)
+	if len(targets) == 0 {
+		return results
+	}
+
+	keySets := configMapKeySets(ctx)
+	deployedTo := deployedNamespaces(ctx)
+	external := ctx.Config.GitOpsValidator.Rules.ConfigMapRefs.External
+
+	for _, workload := range targets {
+		namespaces := deployedTo[workload]
+		if len(namespaces) == 0 {
+			namespaces = []string{workload.Namespace}
+		}
+
+		var order []string
+		uses := make(map[string][]string)
+		var keyRefs []podObjectRef
+		for _, ref := range podObjectRefs(podSpec(workload)) {
+			if ref.Kind != "ConfigMap" || ref.Name == "" || ref.Name == rootCAConfigMap || ref.Optional || strings.Contains(ref.Name, "${") {
+				continue
+			}
+			if !containsString(uses[ref.Name], ref.Where) {
+				if len(uses[ref.Name]) == 0 {
+					order = append(order, ref.Name)
+				}
+				uses[ref.Name] = append(uses[ref.Name], ref.Where)
+			}
+			if len(ref.Keys) > 0 {
+				keyRefs = append(keyRefs, ref)
+			}
+		}
+
+		for _, namespace := range namespaces {
+			owner := qualifiedName(namespace, workload.Name)
+			add := func(message string) {
+				results = append(results, types.ValidationResult{
+					Type:     "configmap-ref",
+					Severity: types.SeverityError,
+					Message:  message,
+					File:     workload.File,
+					Line:     workload.Line,
+					Column:   workload.Column,
+					Resource: workload.Name,
+				})
+			}
+
+			for _, name := range order {
+				if matchesExternal(external, namespace, name) {
+					continue
+				}
+				if _, exists := keySets[configSourceKey("ConfigMap", namespace, name)]; !exists {
+					add(fmt.Sprintf("%s '%s' references ConfigMap '%s' (%s), which the repository does not create; pods do not start until it exists (create it, mark the reference optional, or list it under rules.configmap-refs.external)",
+						workload.Kind, owner, qualifiedName(namespace, name), strings.Join(uses[name], ", ")))
+				}
+			}
+
+			for _, ref := range keyRefs {
+				set, exists := keySets[configSourceKey("ConfigMap", namespace, ref.Name)]
+				if !exists || set.unknown || matchesExternal(external, namespace, ref.Name) {
+					continue
+				}
+				for _, key := range ref.Keys {
+					if set.keys[key] {
+						continue
+					}
+					add(fmt.Sprintf("%s of %s '%s' reads key '%s' of ConfigMap '%s', which has %s; pods do not start (fix the key, or mark the reference optional)",
+						ref.Where, workload.Kind, owner, key, qualifiedName(namespace, ref.Name), describeKeys(set.keys)))
+				}
+			}
+		}
+	}
+
+	return results
+}
+
+// configMapKeySets indexes the keys of the ConfigMaps the repository creates,
+// from manifests and configMapGenerators, like ConfigSources by kind,
+// namespace and name
+func configMapKeySets(ctx *context.ValidationContext) map[string]*configMapKeySet {
+	sets := make(map[string]*configMapKeySet)
+	add := func(namespaces []string, name string, keys []string, known bool) {
+		for _, namespace := range append(namespaces, "") {
+			key := configSourceKey("ConfigMap", namespace, name)
+			set, ok := sets[key]
+			if !ok {
+				set = &configMapKeySet{keys: make(map[string]bool)}
+				sets[key] = set
+			}
+			set.unknown = set.unknown || !known
+			for _, k := range keys {
+				set.keys[k] = true
+			}
+		}
+	}
+
+	deployedTo := deployedNamespaces(ctx)
+	for _, resource := range ctx.Graph.GetResourcesByKind("ConfigMap") {
+		var keys []string
+		for _, field := range []string{"data", "binaryData"} {
+			values, _ := resource.Content[field].(map[string]interface{})
+			for key := range values {
+				keys = append(keys, key)
+			}
+		}
+		add(append([]string{resource.Namespace}, deployedTo[resource]...), resource.Name, keys, true)
+	}
+
+	for _, kustomization := range ctx.Graph.GetKubernetesKustomizations() {
+		ownNamespace, _ := kustomization.Content["namespace"].(string)
+		generators, _ := kustomization.Content["configMapGenerator"].([]interface{})
+		for _, generator := range generators {
+			entry, _ := generator.(map[string]interface{})
+			name, _ := entry["name"].(string)
+			if name == "" {
+				continue
+			}
+			namespaces := append([]string{ownNamespace}, deployedTo[kustomization]...)
+			if namespace, _ := entry["namespace"].(string); namespace != "" {
+				namespaces = append(namespaces, namespace)
+			}
+			keys, ok := generatorKeys(entry, filepath.Dir(kustomization.File))
+			add(namespaces, name, keys, ok)
+		}
+	}
+	return sets
+}
+
+// qualifiedName returns namespace/name, or name without a namespace
+func qualifiedName(namespace, name string) string {
+	if namespace == "" {
+		return name
+	}
+	return namespace + "/" + name
+}
+
+// describeKeys lists the keys of a ConfigMap for messages
+func describeKeys(keys map[string]bool) string {
+	if len(keys) == 0 {
+		return "no keys"
+	}
+	var quoted []string
+	for key := range keys {
+		quoted = append(quoted, fmt.Sprintf("'%s'", key))
+	}
+	sort.Strings(quoted)
+	return "only " + strings.Join(quoted, ", ")
+}
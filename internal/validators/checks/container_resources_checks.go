@@ -0,0 +1,124 @@
+package checks
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/types"
+)
+
+// defaultRequiredResources are the resource fields containers must set when
+// rules.container-resources.require is empty. CPU limits are left out, as
+// they throttle pods well below their node's capacity.
+var defaultRequiredResources = []string{"requests.cpu", "requests.memory", "limits.memory"}
+
+// ContainerResourcesCheck enforces the opt-in policy that the containers and
+// init containers of workloads set resource requests and limits: the fields
+// listed under require, where "requests" and "limits" stand for both their
+// cpu and memory. Defaults of a LimitRange deployed to the workload's
+// namespace count as set. Namespaces listed under exempt-namespaces are left
+// out, and exceptions exempt workloads by file from the requests or limits
+// checks, or both.
+func ContainerResourcesCheck(ctx *context.ValidationContext) []types.ValidationResult {
+	var results []types.ValidationResult
+
+	rule := ctx.Config.GitOpsValidator.Rules.ContainerResources
+	if !rule.Enabled {
+		return results
+	}
+	targets := workloads(ctx)
+	if len(targets) == 0 {
+		return results
+	}
+
+	var required []string
+	for _, field := range rule.Require {
+		switch field {
+		case "requests", "limits":
+			required = append(required, field+".cpu", field+".memory")
+		default:
+			required = append(required, field)
+		}
+	}
+	if len(required) == 0 {
+		required = defaultRequiredResources
+	}
+
+	deployedTo := deployedNamespaces(ctx)
+	// Fields a LimitRange fills in for containers, by namespace
+	defaults := make(map[string]map[string]bool)
+	for _, limitRange := range ctx.Graph.GetResourcesByKind("LimitRange") {
+		namespaces := deployedTo[limitRange]
+		if len(namespaces) == 0 {
+			namespaces = []string{limitRange.Namespace}
+		}
+		spec, _ := limitRange.Content["spec"].(map[string]interface{})
+		limits, _ := spec["limits"].([]interface{})
+		for _, item := range limits {
+			limit, _ := item.(map[string]interface{})
+			if limitType, _ := limit["type"].(string); limitType != "Container" {
+				continue
+			}
+			for field, prefix := range map[string]string{"defaultRequest": "requests.", "default": "limits."} {
+				values, _ := limit[field].(map[string]interface{})
+				for resource := range values {
+					for _, namespace := range namespaces {
+						if defaults[namespace] == nil {
+							defaults[namespace] = make(map[string]bool)
+						}
+						defaults[namespace][prefix+resource] = true
+					}
+				}
+			}
+		}
+	}
+
+	for _, workload := range targets {
+		file := relativeFile(ctx, workload.File)
+		namespaces := deployedTo[workload]
+		if len(namespaces) == 0 {
+			namespaces = []string{workload.Namespace}
+		}
+
+		for _, namespace := range namespaces {
+			if namespace != "" && namespaceAllowed(namespace, rule.ExemptNamespaces) {
+				continue
+			}
+			for _, container := range podContainers(podSpec(workload)) {
+				// Ephemeral containers cannot set resources
+				if container.Field == "ephemeralContainers" {
+					continue
+				}
+				resources, _ := container.Content["resources"].(map[string]interface{})
+				var missing []string
+				for _, field := range required {
+					section, name, _ := strings.Cut(field, ".")
+					if pathExcepted(rule.Exceptions, []string{file}, section) || defaults[namespace][field] {
+						continue
+					}
+					values, _ := resources[section].(map[string]interface{})
+					if _, set := values[name]; !set {
+						missing = append(missing, field)
+					}
+				}
+				if len(missing) == 0 {
+					continue
+				}
+
+				results = append(results, types.ValidationResult{
+					Type:     "container-resources",
+					Severity: types.SeverityWarning,
+					Message: fmt.Sprintf("%s of %s '%s' sets no %s; without requests the scheduler cannot place it reliably, and without limits it can starve its neighbours (set them, or add an exception under rules.container-resources)",
+						container, workload.Kind, qualifiedName(namespace, workload.Name), strings.Join(missing, ", ")),
+					File:     workload.File,
+					Line:     workload.Line,
+					Column:   workload.Column,
+					Resource: workload.Name,
+				})
+			}
+		}
+	}
+
+	return results
+}
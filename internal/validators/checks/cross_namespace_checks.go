@@ -0,0 +1,133 @@
+package checks
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/parser"
+	"github.com/moon-hex/gitops-validator/internal/types"
+)
+
+// crossNamespaceField is a field of a Flux kind holding a reference, or a
+// list of references, with an optional namespace
+type crossNamespaceField struct {
+	path []string
+	list bool
+	// kind is the kind referenced when the reference does not name one
+	kind string
+}
+
+// crossNamespaceFields are the reference fields of each Flux kind that
+// --no-cross-namespace-refs restricts, by kind and API group
+var crossNamespaceFields = map[[2]string][]crossNamespaceField{
+	{"Kustomization", "kustomize.toolkit.fluxcd.io"}: {
+		{path: []string{"spec", "sourceRef"}},
+		{path: []string{"spec", "dependsOn"}, list: true, kind: "Kustomization"},
+	},
+	{"HelmRelease", "helm.toolkit.fluxcd.io"}: {
+		{path: []string{"spec", "chart", "spec", "sourceRef"}},
+		{path: []string{"spec", "chartRef"}},
+		{path: []string{"spec", "dependsOn"}, list: true, kind: "HelmRelease"},
+	},
+	{"ImagePolicy", "image.toolkit.fluxcd.io"}: {
+		{path: []string{"spec", "imageRepositoryRef"}, kind: "ImageRepository"},
+	},
+	{"ImageUpdateAutomation", "image.toolkit.fluxcd.io"}: {
+		{path: []string{"spec", "sourceRef"}},
+	},
+	{"Alert", "notification.toolkit.fluxcd.io"}: {
+		{path: []string{"spec", "eventSources"}, list: true},
+	},
+	{"Receiver", "notification.toolkit.fluxcd.io"}: {
+		{path: []string{"spec", "resources"}, list: true},
+	},
+}
+
+// CrossNamespaceRefCheck reports Flux references naming a namespace other
+// than the one the referencing object is deployed to. Controllers started
+// with --no-cross-namespace-refs, as multi-tenant platforms run them, refuse
+// such references and the object never becomes ready. The rule is opt-in:
+// it reports nothing unless enabled in the config. Objects deployed to
+// several namespaces are checked against each, and namespaces with Flux
+// variables are skipped.
+func CrossNamespaceRefCheck(ctx *context.ValidationContext) []types.ValidationResult {
+	var results []types.ValidationResult
+
+	if !ctx.Config.IsRuleEnabled("cross-namespace-refs") {
+		return results
+	}
+
+	deployedTo := make(map[*parser.ParsedResource][]string)
+	for _, root := range ctx.RootKustomizations() {
+		for _, deployed := range ctx.DeploymentTree(root) {
+			if deployed.Namespace != "" && !containsString(deployedTo[deployed.Resource], deployed.Namespace) {
+				deployedTo[deployed.Resource] = append(deployedTo[deployed.Resource], deployed.Namespace)
+			}
+		}
+	}
+
+	for _, kind := range []string{"Kustomization", "HelmRelease", "ImagePolicy", "ImageUpdateAutomation", "Alert", "Receiver"} {
+		for _, resource := range ctx.Graph.GetResourcesByKind(kind) {
+			fields, ok := crossNamespaceFields[[2]string{kind, apiGroup(resource.APIVersion)}]
+			if !ok {
+				continue
+			}
+
+			namespaces := deployedTo[resource]
+			if len(namespaces) == 0 && resource.Namespace != "" {
+				namespaces = []string{resource.Namespace}
+			}
+			if len(namespaces) == 0 {
+				continue
+			}
+
+			for _, field := range fields {
+				var value interface{} = resource.Content
+				for _, key := range field.path {
+					parent, _ := value.(map[string]interface{})
+					value = parent[key]
+				}
+
+				references := []interface{}{value}
+				if field.list {
+					references, _ = value.([]interface{})
+				}
+				for i, entry := range references {
+					reference, _ := entry.(map[string]interface{})
+					namespace, _ := reference["namespace"].(string)
+					if namespace == "" || strings.Contains(namespace, "${") {
+						continue
+					}
+					refKind, _ := reference["kind"].(string)
+					if refKind == "" {
+						refKind = field.kind
+					}
+					name, _ := reference["name"].(string)
+					where := strings.Join(field.path, ".")
+					if field.list {
+						where = fmt.Sprintf("%s[%d]", where, i)
+					}
+
+					for _, own := range namespaces {
+						if own == namespace {
+							continue
+						}
+						results = append(results, types.ValidationResult{
+							Type:     "cross-namespace-ref",
+							Severity: types.SeverityError,
+							Message: fmt.Sprintf("%s '%s/%s' %s references %s '%s/%s' in another namespace; Flux refuses it when run with --no-cross-namespace-refs",
+								kind, own, resource.Name, where, refKind, namespace, name),
+							File:     resource.File,
+							Line:     resource.Line,
+							Column:   resource.Column,
+							Resource: resource.Name,
+						})
+					}
+				}
+			}
+		}
+	}
+
+	return results
+}
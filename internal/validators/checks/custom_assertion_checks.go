@@ -0,0 +1,88 @@
+package checks
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/moon-hex/gitops-validator/internal/assertion"
+	"github.com/moon-hex/gitops-validator/internal/config"
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/parser"
+	"github.com/moon-hex/gitops-validator/internal/types"
+)
+
+// CustomAssertionCheck evaluates the assertions configured under
+// assertions: against every resource of their kind. An assertion whose
+// expression does not compile is reported once as an error.
+func CustomAssertionCheck(ctx *context.ValidationContext) []types.ValidationResult {
+	var results []types.ValidationResult
+
+	for _, custom := range ctx.Config.GitOpsValidator.Assertions {
+		name := custom.Name
+		if name == "" {
+			name = custom.Assert
+		}
+
+		expression, err := assertion.Compile(custom.Assert)
+		if err != nil {
+			results = append(results, types.ValidationResult{
+				Type:     "custom-assertion",
+				Severity: types.SeverityError,
+				Message:  fmt.Sprintf("Assertion '%s' cannot be evaluated: %v", name, err),
+				Category: name,
+			})
+			continue
+		}
+
+		severity := custom.Severity
+		if severity == "" {
+			severity = types.SeverityWarning
+		}
+
+		for _, resource := range assertionTargets(ctx, custom) {
+			if expression.Evaluate(resource.Content) {
+				continue
+			}
+
+			message := custom.Message
+			if message == "" {
+				message = fmt.Sprintf("assertion '%s' does not hold", name)
+			}
+			results = append(results, types.ValidationResult{
+				Type:     "custom-assertion",
+				Severity: severity,
+				Message:  fmt.Sprintf("%s '%s': %s (%s)", resource.Kind, resource.GetResourceKey(), message, expression),
+				File:     resource.File,
+				Line:     resource.Line,
+				Column:   resource.Column,
+				Resource: resource.Name,
+				Category: name,
+			})
+		}
+	}
+
+	return results
+}
+
+// assertionTargets returns the resources an assertion applies to, in file order
+func assertionTargets(ctx *context.ValidationContext, custom config.AssertionConfig) []*parser.ParsedResource {
+	files := make([]string, 0, len(ctx.Graph.Files))
+	for file := range ctx.Graph.Files {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	var targets []*parser.ParsedResource
+	for _, file := range files {
+		for _, resource := range ctx.Graph.Files[file] {
+			if custom.Kind != "*" && resource.Kind != custom.Kind {
+				continue
+			}
+			if custom.APIVersion != "" && resource.APIVersion != custom.APIVersion {
+				continue
+			}
+			targets = append(targets, resource)
+		}
+	}
+	return targets
+}
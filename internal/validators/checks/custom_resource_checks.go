@@ -0,0 +1,125 @@
+package checks
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/parser"
+	"github.com/moon-hex/gitops-validator/internal/types"
+)
+
+// builtinAPIGroups are the API groups the Kubernetes API server serves
+// without a CRD, besides the core group; gateway.networking.k8s.io and
+// snapshot.storage.k8s.io are CRDs despite their names
+var builtinAPIGroups = []string{
+	"admissionregistration.k8s.io", "apiextensions.k8s.io", "apiregistration.k8s.io", "apps",
+	"authentication.k8s.io", "authorization.k8s.io", "autoscaling", "batch", "certificates.k8s.io",
+	"coordination.k8s.io", "discovery.k8s.io", "events.k8s.io", "extensions",
+	"flowcontrol.apiserver.k8s.io", "internal.apiserver.k8s.io", "metrics.k8s.io", "networking.k8s.io",
+	"node.k8s.io", "policy", "rbac.authorization.k8s.io", "resource.k8s.io", "scheduling.k8s.io",
+	"storage.k8s.io", "storagemigration.k8s.io",
+}
+
+// installedAPIGroups are the groups assumed installed without a CRD in the
+// repository: Flux's, which bootstrap installs, and the configuration files
+// of kustomize and of Kubernetes components (kube-scheduler, kubelet, audit
+// policies), which are read rather than applied to the cluster
+var installedAPIGroups = []string{"*.toolkit.fluxcd.io", "*.config.k8s.io", "audit.k8s.io", "config.kubernetes.io", "builtin"}
+
+// CustomResourceCheck reports custom resources whose CRD is neither defined by
+// a CustomResourceDefinition manifest in the repository nor listed in the
+// rule's known-crds; the cluster rejects them until the CRD is installed.
+// Each kind is reported once, at its first use. When the repository installs
+// HelmReleases, a chart may install the CRD, so the finding is informational;
+// otherwise it is a warning. The built-in API groups, Flux's and those of
+// configuration files are skipped, and so are API versions with Flux
+// variables.
+func CustomResourceCheck(ctx *context.ValidationContext) []types.ValidationResult {
+	var results []types.ValidationResult
+
+	defined := make(map[string]bool)
+	for _, crd := range ctx.Graph.GetResourcesByKind("CustomResourceDefinition") {
+		spec, _ := crd.Content["spec"].(map[string]interface{})
+		names, _ := spec["names"].(map[string]interface{})
+		group, _ := spec["group"].(string)
+		kind, _ := names["kind"].(string)
+		defined[kind+"."+group] = true
+	}
+	known := append(append([]string(nil), installedAPIGroups...), ctx.Config.GitOpsValidator.Rules.CustomResources.KnownCRDs...)
+
+	severity := types.SeverityWarning
+	consequence := "the cluster rejects it until the CRD is installed"
+	if len(ctx.Graph.GetHelmReleases()) > 0 {
+		severity = types.SeverityInfo
+		consequence = "the cluster rejects it unless a Helm chart or something outside the repository installs the CRD"
+	}
+
+	files := make([]string, 0, len(ctx.Graph.Files))
+	for file := range ctx.Graph.Files {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	var order []string
+	uses := make(map[string][]*parser.ParsedResource)
+	for _, file := range files {
+		for _, resource := range ctx.Graph.Files[file] {
+			group := apiGroup(resource.APIVersion)
+			if group == "" || resource.Kind == "" || containsString(builtinAPIGroups, group) || strings.Contains(resource.APIVersion+resource.Kind, "${") {
+				continue
+			}
+			key := resource.Kind + "." + group
+			if defined[key] || crdKnown(known, resource.Kind, group) {
+				continue
+			}
+			if len(uses[key]) == 0 {
+				order = append(order, key)
+			}
+			uses[key] = append(uses[key], resource)
+		}
+	}
+
+	for _, key := range order {
+		first := uses[key][0]
+		others := ""
+		if count := len(uses[key]) - 1; count == 1 {
+			others = " (and 1 other resource)"
+		} else if count > 1 {
+			others = fmt.Sprintf(" (and %d other resources)", count)
+		}
+		results = append(results, types.ValidationResult{
+			Type:     "custom-resource",
+			Severity: severity,
+			Message: fmt.Sprintf("%s '%s'%s uses %s, whose CustomResourceDefinition is not in the repository; %s (list the group or CRD under rules.custom-resources.known-crds if the cluster has it)",
+				first.Kind, first.GetResourceKey(), others, first.APIVersion, consequence),
+			File:     first.File,
+			Line:     first.Line,
+			Column:   first.Column,
+			Resource: first.Name,
+		})
+	}
+
+	return results
+}
+
+// crdKnown reports whether a kind of an API group matches one of the known
+// entries: the group, Kind.group, or the CRD name plural.group, each of them
+// possibly a glob pattern. The plural is guessed from the kind.
+func crdKnown(known []string, kind, group string) bool {
+	lower := strings.ToLower(kind)
+	candidates := []string{group, kind + "." + group, lower + "s." + group, lower + "es." + group}
+	if strings.HasSuffix(lower, "y") {
+		candidates = append(candidates, strings.TrimSuffix(lower, "y")+"ies."+group)
+	}
+	for _, pattern := range known {
+		for _, candidate := range candidates {
+			if matched, _ := path.Match(pattern, candidate); matched {
+				return true
+			}
+		}
+	}
+	return false
+}
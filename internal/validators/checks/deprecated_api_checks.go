@@ -9,12 +9,17 @@ import (
 	"github.com/moon-hex/gitops-validator/internal/types"
 )
 
-// DeprecatedAPICheck validates usage of deprecated Kubernetes API versions
+// DeprecatedAPICheck validates usage of deprecated Kubernetes API versions.
+// APIs with a known lifecycle are judged against kubernetes-version: removed
+// or not yet introduced ones are errors, and deprecated ones it still serves
+// are warnings. Without a target version, deprecated and removed APIs are
+// warnings. Custom APIs without lifecycle versions keep their configured
+// severity.
 func DeprecatedAPICheck(resource *parser.ParsedResource, config *config.Config) []types.ValidationResult {
 	var results []types.ValidationResult
 
 	// Check if the API version is deprecated
-	deprecatedInfo := checkDeprecatedAPI(resource.APIVersion, config)
+	deprecatedInfo := checkDeprecatedAPI(resource.APIVersion, resource.Kind, config)
 	if deprecatedInfo != nil {
 		results = append(results, types.ValidationResult{
 			Type:     "deprecated-api",
@@ -22,6 +27,7 @@ func DeprecatedAPICheck(resource *parser.ParsedResource, config *config.Config)
 			Message:  fmt.Sprintf("'%s' API for '%s' '%s' - %s", resource.APIVersion, resource.Kind, resource.Name, deprecatedInfo.DeprecationInfo),
 			File:     resource.File,
 			Line:     resource.Line,
+			Column:   resource.Column,
 			Resource: fmt.Sprintf("%s/%s", resource.APIVersion, resource.Kind),
 		})
 	}
@@ -30,24 +36,43 @@ func DeprecatedAPICheck(resource *parser.ParsedResource, config *config.Config)
 }
 
 // checkDeprecatedAPI checks if an API version is deprecated
-func checkDeprecatedAPI(apiVersion string, config *config.Config) *DeprecationInfo {
+func checkDeprecatedAPI(apiVersion, kind string, config *config.Config) *DeprecationInfo {
+	target := config.GitOpsValidator.KubernetesVersion
+
 	// Check custom deprecated APIs from config
 	for _, customAPI := range config.GitOpsValidator.DeprecatedAPIs.CustomAPIs {
-		if matchesAPIVersion(apiVersion, customAPI.APIVersion) {
+		if !matchesAPIVersion(apiVersion, customAPI.APIVersion) || (len(customAPI.Kinds) > 0 && !containsString(customAPI.Kinds, kind)) {
+			continue
+		}
+		if !customAPI.HasLifecycle() {
 			return &DeprecationInfo{
 				Severity:        customAPI.Severity,
 				DeprecationInfo: customAPI.DeprecationInfo,
 			}
 		}
+		lifecycle := apiLifecycle{
+			Introduced:  customAPI.IntroducedIn,
+			Deprecated:  customAPI.DeprecatedIn,
+			Removed:     customAPI.RemovedIn,
+			Replacement: customAPI.Replacement,
+		}
+		info := lifecycle.check(target)
+		if info != nil && target == "" && customAPI.Severity != "" {
+			info.Severity = customAPI.Severity
+		}
+		if info != nil && customAPI.DeprecationInfo != "" {
+			info.DeprecationInfo += " (" + customAPI.DeprecationInfo + ")"
+		}
+		return info
 	}
 
 	// Check built-in deprecated APIs
-	return checkBuiltinDeprecatedAPI(apiVersion)
+	return checkBuiltinDeprecatedAPI(apiVersion, kind, target)
 }
 
 // DeprecationInfo represents information about a deprecated API
 type DeprecationInfo struct {
-	Severity        string
+	Severity        types.Severity
 	DeprecationInfo string
 }
 
@@ -57,35 +82,124 @@ func matchesAPIVersion(apiVersion, pattern string) bool {
 	return matched
 }
 
-// checkBuiltinDeprecatedAPI checks against built-in deprecated API patterns
-func checkBuiltinDeprecatedAPI(apiVersion string) *DeprecationInfo {
-	// Built-in deprecated API patterns
-	deprecatedPatterns := map[string]DeprecationInfo{
-		`^v1beta1/.*`: {
-			Severity:        "warning",
-			DeprecationInfo: "v1beta1 APIs are deprecated and will be removed in future Kubernetes versions",
-		},
-		`^v1alpha1/.*`: {
-			Severity:        "warning",
-			DeprecationInfo: "v1alpha1 APIs are experimental and may be removed without notice",
-		},
-		`^extensions/v1beta1/.*`: {
-			Severity:        "error",
-			DeprecationInfo: "extensions/v1beta1 APIs are deprecated and removed in Kubernetes 1.22+",
-		},
-		`^apps/v1beta1/.*`: {
-			Severity:        "warning",
-			DeprecationInfo: "apps/v1beta1 APIs are deprecated, use apps/v1 instead",
-		},
-		`^apps/v1beta2/.*`: {
-			Severity:        "warning",
-			DeprecationInfo: "apps/v1beta2 APIs are deprecated, use apps/v1 instead",
-		},
+// apiLifecycle is when a Kubernetes API version was introduced, deprecated
+// and removed, as minor versions such as "1.22"
+type apiLifecycle struct {
+	APIVersion string
+	// Kinds limits the entry to these kinds; empty matches all
+	Kinds       []string
+	Introduced  string
+	Deprecated  string
+	Removed     string
+	Replacement string
+}
+
+// check judges the API against the target Kubernetes version, returning nil
+// when the target serves it without deprecation
+func (l apiLifecycle) check(target string) *DeprecationInfo {
+	migrate := ""
+	if l.Replacement != "" {
+		migrate = fmt.Sprintf("; migrate to %s", l.Replacement)
+	}
+
+	if target == "" {
+		switch {
+		case l.Removed != "" && l.Deprecated != "":
+			return &DeprecationInfo{Severity: types.SeverityWarning, DeprecationInfo: fmt.Sprintf("deprecated in Kubernetes %s and removed in %s%s (set kubernetes-version to check against your clusters)", l.Deprecated, l.Removed, migrate)}
+		case l.Removed != "":
+			return &DeprecationInfo{Severity: types.SeverityWarning, DeprecationInfo: fmt.Sprintf("removed in Kubernetes %s%s (set kubernetes-version to check against your clusters)", l.Removed, migrate)}
+		case l.Deprecated != "":
+			return &DeprecationInfo{Severity: types.SeverityWarning, DeprecationInfo: fmt.Sprintf("deprecated in Kubernetes %s%s", l.Deprecated, migrate)}
+		}
+		return nil
+	}
+
+	minor, err := config.KubernetesMinorVersion(target)
+	if err != nil {
+		return nil
+	}
+	reached := func(version string) bool {
+		since, err := config.KubernetesMinorVersion(version)
+		return version != "" && err == nil && since <= minor
+	}
+	switch {
+	case l.Introduced != "" && !reached(l.Introduced):
+		return &DeprecationInfo{Severity: types.SeverityError, DeprecationInfo: fmt.Sprintf("not served by Kubernetes %s, the target version; it was introduced in %s", target, l.Introduced)}
+	case reached(l.Removed):
+		return &DeprecationInfo{Severity: types.SeverityError, DeprecationInfo: fmt.Sprintf("removed in Kubernetes %s, so the target version %s no longer serves it%s", l.Removed, target, migrate)}
+	case reached(l.Deprecated) && l.Removed != "":
+		return &DeprecationInfo{Severity: types.SeverityWarning, DeprecationInfo: fmt.Sprintf("deprecated since Kubernetes %s and removed in %s, still served by the target version %s%s", l.Deprecated, l.Removed, target, migrate)}
+	case reached(l.Deprecated):
+		return &DeprecationInfo{Severity: types.SeverityWarning, DeprecationInfo: fmt.Sprintf("deprecated since Kubernetes %s, still served by the target version %s%s", l.Deprecated, target, migrate)}
 	}
+	return nil
+}
+
+// kubernetesAPILifecycles are the lifecycles of built-in Kubernetes API
+// versions, from the Kubernetes deprecated API migration guide. Entries with
+// only Introduced let a target version older than the API be reported.
+var kubernetesAPILifecycles = []apiLifecycle{
+	// Removed in 1.16
+	{APIVersion: "extensions/v1beta1", Kinds: []string{"Deployment", "DaemonSet", "ReplicaSet"}, Deprecated: "1.9", Removed: "1.16", Replacement: "apps/v1"},
+	{APIVersion: "extensions/v1beta1", Kinds: []string{"NetworkPolicy"}, Deprecated: "1.9", Removed: "1.16", Replacement: "networking.k8s.io/v1"},
+	{APIVersion: "extensions/v1beta1", Kinds: []string{"PodSecurityPolicy"}, Deprecated: "1.10", Removed: "1.16", Replacement: "policy/v1beta1"},
+	{APIVersion: "apps/v1beta1", Deprecated: "1.9", Removed: "1.16", Replacement: "apps/v1"},
+	{APIVersion: "apps/v1beta2", Deprecated: "1.9", Removed: "1.16", Replacement: "apps/v1"},
+
+	// Removed in 1.22
+	{APIVersion: "extensions/v1beta1", Kinds: []string{"Ingress"}, Deprecated: "1.14", Removed: "1.22", Replacement: "networking.k8s.io/v1"},
+	{APIVersion: "networking.k8s.io/v1beta1", Kinds: []string{"Ingress", "IngressClass"}, Deprecated: "1.19", Removed: "1.22", Replacement: "networking.k8s.io/v1"},
+	{APIVersion: "admissionregistration.k8s.io/v1beta1", Kinds: []string{"MutatingWebhookConfiguration", "ValidatingWebhookConfiguration"}, Deprecated: "1.16", Removed: "1.22", Replacement: "admissionregistration.k8s.io/v1"},
+	{APIVersion: "apiextensions.k8s.io/v1beta1", Deprecated: "1.16", Removed: "1.22", Replacement: "apiextensions.k8s.io/v1"},
+	{APIVersion: "apiregistration.k8s.io/v1beta1", Deprecated: "1.19", Removed: "1.22", Replacement: "apiregistration.k8s.io/v1"},
+	{APIVersion: "authentication.k8s.io/v1beta1", Deprecated: "1.19", Removed: "1.22", Replacement: "authentication.k8s.io/v1"},
+	{APIVersion: "authorization.k8s.io/v1beta1", Deprecated: "1.19", Removed: "1.22", Replacement: "authorization.k8s.io/v1"},
+	{APIVersion: "certificates.k8s.io/v1beta1", Deprecated: "1.19", Removed: "1.22", Replacement: "certificates.k8s.io/v1"},
+	{APIVersion: "coordination.k8s.io/v1beta1", Deprecated: "1.19", Removed: "1.22", Replacement: "coordination.k8s.io/v1"},
+	{APIVersion: "rbac.authorization.k8s.io/v1beta1", Deprecated: "1.17", Removed: "1.22", Replacement: "rbac.authorization.k8s.io/v1"},
+	{APIVersion: "scheduling.k8s.io/v1beta1", Deprecated: "1.14", Removed: "1.22", Replacement: "scheduling.k8s.io/v1"},
+	{APIVersion: "storage.k8s.io/v1beta1", Kinds: []string{"CSIDriver", "CSINode", "StorageClass", "VolumeAttachment"}, Deprecated: "1.19", Removed: "1.22", Replacement: "storage.k8s.io/v1"},
+
+	// Removed in 1.25
+	{APIVersion: "batch/v1beta1", Kinds: []string{"CronJob"}, Deprecated: "1.21", Removed: "1.25", Replacement: "batch/v1"},
+	{APIVersion: "discovery.k8s.io/v1beta1", Deprecated: "1.21", Removed: "1.25", Replacement: "discovery.k8s.io/v1"},
+	{APIVersion: "events.k8s.io/v1beta1", Deprecated: "1.19", Removed: "1.25", Replacement: "events.k8s.io/v1"},
+	{APIVersion: "autoscaling/v2beta1", Deprecated: "1.22", Removed: "1.25", Replacement: "autoscaling/v2"},
+	{APIVersion: "policy/v1beta1", Kinds: []string{"PodDisruptionBudget"}, Deprecated: "1.21", Removed: "1.25", Replacement: "policy/v1"},
+	{APIVersion: "policy/v1beta1", Kinds: []string{"PodSecurityPolicy"}, Deprecated: "1.21", Removed: "1.25", Replacement: "Pod Security Admission"},
+	{APIVersion: "node.k8s.io/v1beta1", Deprecated: "1.20", Removed: "1.25", Replacement: "node.k8s.io/v1"},
+
+	// Removed in 1.26, 1.27, 1.29 and 1.32
+	{APIVersion: "autoscaling/v2beta2", Deprecated: "1.23", Removed: "1.26", Replacement: "autoscaling/v2"},
+	{APIVersion: "flowcontrol.apiserver.k8s.io/v1beta1", Deprecated: "1.23", Removed: "1.26", Replacement: "flowcontrol.apiserver.k8s.io/v1"},
+	{APIVersion: "storage.k8s.io/v1beta1", Kinds: []string{"CSIStorageCapacity"}, Deprecated: "1.24", Removed: "1.27", Replacement: "storage.k8s.io/v1"},
+	{APIVersion: "flowcontrol.apiserver.k8s.io/v1beta2", Introduced: "1.23", Deprecated: "1.26", Removed: "1.29", Replacement: "flowcontrol.apiserver.k8s.io/v1"},
+	{APIVersion: "flowcontrol.apiserver.k8s.io/v1beta3", Introduced: "1.26", Deprecated: "1.29", Removed: "1.32", Replacement: "flowcontrol.apiserver.k8s.io/v1"},
+
+	// Replacements, reported when the target version predates them
+	{APIVersion: "apps/v1", Introduced: "1.9"},
+	{APIVersion: "networking.k8s.io/v1", Kinds: []string{"Ingress", "IngressClass"}, Introduced: "1.19"},
+	{APIVersion: "admissionregistration.k8s.io/v1", Kinds: []string{"MutatingWebhookConfiguration", "ValidatingWebhookConfiguration"}, Introduced: "1.16"},
+	{APIVersion: "admissionregistration.k8s.io/v1", Kinds: []string{"ValidatingAdmissionPolicy", "ValidatingAdmissionPolicyBinding"}, Introduced: "1.30"},
+	{APIVersion: "apiextensions.k8s.io/v1", Introduced: "1.16"},
+	{APIVersion: "certificates.k8s.io/v1", Introduced: "1.19"},
+	{APIVersion: "batch/v1", Kinds: []string{"CronJob"}, Introduced: "1.21"},
+	{APIVersion: "discovery.k8s.io/v1", Introduced: "1.21"},
+	{APIVersion: "events.k8s.io/v1", Introduced: "1.19"},
+	{APIVersion: "autoscaling/v2", Introduced: "1.23"},
+	{APIVersion: "policy/v1", Kinds: []string{"PodDisruptionBudget"}, Introduced: "1.21"},
+	{APIVersion: "node.k8s.io/v1", Introduced: "1.20"},
+	{APIVersion: "storage.k8s.io/v1", Kinds: []string{"CSIDriver"}, Introduced: "1.18"},
+	{APIVersion: "storage.k8s.io/v1", Kinds: []string{"CSIStorageCapacity"}, Introduced: "1.24"},
+	{APIVersion: "flowcontrol.apiserver.k8s.io/v1", Introduced: "1.29"},
+}
 
-	for pattern, info := range deprecatedPatterns {
-		if matchesAPIVersion(apiVersion, pattern) {
-			return &info
+// checkBuiltinDeprecatedAPI checks against the lifecycles of built-in
+// Kubernetes API versions
+func checkBuiltinDeprecatedAPI(apiVersion, kind, target string) *DeprecationInfo {
+	for _, lifecycle := range kubernetesAPILifecycles {
+		if lifecycle.APIVersion == apiVersion && (len(lifecycle.Kinds) == 0 || containsString(lifecycle.Kinds, kind)) {
+			return lifecycle.check(target)
 		}
 	}
 
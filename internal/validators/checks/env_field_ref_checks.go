@@ -0,0 +1,224 @@
+package checks
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/types"
+)
+
+// envFieldPaths are the pod fields environment variables can take from
+// valueFrom.fieldRef, besides metadata.labels['<key>'] and metadata.annotations['<key>']
+var envFieldPaths = []string{
+	"metadata.name", "metadata.namespace", "metadata.uid", "spec.nodeName", "spec.serviceAccountName",
+	"status.hostIP", "status.hostIPs", "status.podIP", "status.podIPs",
+}
+
+// volumeFieldPaths are the pod fields downwardAPI volume items can take
+var volumeFieldPaths = []string{
+	"metadata.name", "metadata.namespace", "metadata.uid", "metadata.labels", "metadata.annotations",
+}
+
+// labelFieldPath matches a single label or annotation, e.g. metadata.labels['app']
+var labelFieldPath = regexp.MustCompile(`^metadata\.(labels|annotations)\['[^']+'\]$`)
+
+// cpuDivisors and memoryDivisors are the divisors the API server accepts for
+// resourceFieldRef; memory's also apply to ephemeral-storage and hugepages
+var (
+	cpuDivisors    = []string{"1m", "1"}
+	memoryDivisors = []string{"1", "1k", "1M", "1G", "1T", "1P", "1E", "1Ki", "1Mi", "1Gi", "1Ti", "1Pi", "1Ei"}
+)
+
+// EnvFieldRefCheck validates the downward API references of pod specs:
+// valueFrom.fieldRef and valueFrom.resourceFieldRef of container env vars, and
+// the items of downwardAPI volumes and projected volume sources. The API
+// server rejects unknown field paths, resources, divisors and container names
+// only when a pod is created, so a Deployment with a typo such as
+// metadata.Name applies fine and then never gets a pod.
+func EnvFieldRefCheck(ctx *context.ValidationContext) []types.ValidationResult {
+	var results []types.ValidationResult
+
+	for _, workload := range workloads(ctx) {
+		spec := podSpec(workload)
+		containers := podContainers(spec)
+		var names []string
+		for _, container := range containers {
+			if container.Field != "ephemeralContainers" {
+				names = append(names, container.Name)
+			}
+		}
+
+		add := func(where, problem string) {
+			results = append(results, types.ValidationResult{
+				Type:     "env-field-ref",
+				Severity: types.SeverityError,
+				Message:  fmt.Sprintf("%s of %s '%s' %s; pods fail to be created", where, workload.Kind, workload.GetResourceKey(), problem),
+				File:     workload.File,
+				Line:     workload.Line,
+				Column:   workload.Column,
+				Resource: workload.Name,
+			})
+		}
+
+		for _, container := range containers {
+			env, _ := container.Content["env"].([]interface{})
+			for _, entry := range env {
+				variable, _ := entry.(map[string]interface{})
+				name, _ := variable["name"].(string)
+				valueFrom, _ := variable["valueFrom"].(map[string]interface{})
+				where := fmt.Sprintf("env var '%s' of %s", name, container)
+
+				if fieldRef, ok := valueFrom["fieldRef"].(map[string]interface{}); ok {
+					if problem := fieldRefProblem(fieldRef, false); problem != "" {
+						add(where, problem)
+					}
+				}
+				if resourceFieldRef, ok := valueFrom["resourceFieldRef"].(map[string]interface{}); ok {
+					if problem := resourceFieldRefProblem(resourceFieldRef, names, false); problem != "" {
+						add(where, problem)
+					}
+				}
+			}
+		}
+
+		volumes, _ := spec["volumes"].([]interface{})
+		for _, entry := range volumes {
+			volume, _ := entry.(map[string]interface{})
+			volumeName, _ := volume["name"].(string)
+
+			// downwardAPI volumes, and downwardAPI sources of projected volumes
+			var sources []map[string]interface{}
+			if downwardAPI, ok := volume["downwardAPI"].(map[string]interface{}); ok {
+				sources = append(sources, downwardAPI)
+			}
+			projected, _ := volume["projected"].(map[string]interface{})
+			projectedSources, _ := projected["sources"].([]interface{})
+			for _, source := range projectedSources {
+				source, _ := source.(map[string]interface{})
+				if downwardAPI, ok := source["downwardAPI"].(map[string]interface{}); ok {
+					sources = append(sources, downwardAPI)
+				}
+			}
+
+			for _, source := range sources {
+				items, _ := source["items"].([]interface{})
+				for _, item := range items {
+					item, _ := item.(map[string]interface{})
+					path, _ := item["path"].(string)
+					where := fmt.Sprintf("item '%s' of downwardAPI volume '%s'", path, volumeName)
+
+					if fieldRef, ok := item["fieldRef"].(map[string]interface{}); ok {
+						if problem := fieldRefProblem(fieldRef, true); problem != "" {
+							add(where, problem)
+						}
+					}
+					if resourceFieldRef, ok := item["resourceFieldRef"].(map[string]interface{}); ok {
+						if problem := resourceFieldRefProblem(resourceFieldRef, names, true); problem != "" {
+							add(where, problem)
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return results
+}
+
+// fieldRefProblem describes what is wrong with a fieldRef, or returns ""
+func fieldRefProblem(fieldRef map[string]interface{}, volume bool) string {
+	fieldPath, _ := fieldRef["fieldPath"].(string)
+	apiVersion, _ := fieldRef["apiVersion"].(string)
+	if strings.Contains(fieldPath+apiVersion, "${") {
+		return ""
+	}
+	if apiVersion != "" && apiVersion != "v1" {
+		return fmt.Sprintf("has fieldRef apiVersion '%s'; only v1 is supported", apiVersion)
+	}
+	if fieldPath == "" {
+		return "has a fieldRef without fieldPath"
+	}
+
+	valid := envFieldPaths
+	if volume {
+		valid = volumeFieldPaths
+	}
+	if containsString(valid, fieldPath) || (!volume && labelFieldPath.MatchString(fieldPath)) {
+		return ""
+	}
+
+	problem := fmt.Sprintf("has fieldRef.fieldPath '%s', which is not a downward API field", fieldPath)
+	switch {
+	case volume && labelFieldPath.MatchString(fieldPath):
+		return problem + "; volumes take all labels or annotations (metadata.labels, metadata.annotations), single keys only work for env vars"
+	case !volume && (fieldPath == "metadata.labels" || fieldPath == "metadata.annotations"):
+		return problem + fmt.Sprintf("; env vars take a single key (%s['<key>']), all of them only work in downwardAPI volumes", fieldPath)
+	}
+	if prefix, key, found := cutLabelKey(fieldPath); found && !volume {
+		return problem + fmt.Sprintf(" (did you mean %s['%s']?)", prefix, key)
+	}
+	for _, candidate := range valid {
+		if strings.EqualFold(candidate, fieldPath) {
+			return problem + fmt.Sprintf(" (did you mean %s?)", candidate)
+		}
+	}
+	return problem + fmt.Sprintf(" (supported: %s)", strings.Join(valid, ", "))
+}
+
+// cutLabelKey splits a path like metadata.labels.app into metadata.labels and app
+func cutLabelKey(fieldPath string) (string, string, bool) {
+	for _, prefix := range []string{"metadata.labels", "metadata.annotations"} {
+		for _, separator := range []string{".", "/", "['", "[\"", "["} {
+			if key, found := strings.CutPrefix(fieldPath, prefix+separator); found && key != "" {
+				return prefix, strings.Trim(key, `'"]`), true
+			}
+		}
+	}
+	return "", "", false
+}
+
+// resourceFieldRefProblem describes what is wrong with a resourceFieldRef,
+// or returns ""
+func resourceFieldRefProblem(resourceFieldRef map[string]interface{}, containers []string, volume bool) string {
+	resource, _ := resourceFieldRef["resource"].(string)
+	containerName, _ := resourceFieldRef["containerName"].(string)
+	divisor, _ := resourceFieldRef["divisor"].(string)
+	if strings.Contains(resource+containerName+divisor, "${") {
+		return ""
+	}
+
+	bound, name, _ := strings.Cut(resource, ".")
+	divisors := memoryDivisors
+	switch {
+	case resource == "":
+		return "has a resourceFieldRef without resource"
+	case (bound != "limits" && bound != "requests") ||
+		(name != "cpu" && name != "memory" && name != "ephemeral-storage" && !strings.HasPrefix(name, "hugepages-")):
+		message := fmt.Sprintf("has resourceFieldRef.resource '%s', which is not a container resource (supported: limits.cpu, limits.memory, limits.ephemeral-storage, limits.hugepages-<size> and the same for requests)", resource)
+		for _, candidate := range []string{"limits.cpu", "limits.memory", "limits.ephemeral-storage", "requests.cpu", "requests.memory", "requests.ephemeral-storage"} {
+			if strings.EqualFold(candidate, resource) || strings.EqualFold(candidate, strings.Replace(resource, "limit.", "limits.", 1)) ||
+				strings.EqualFold(candidate, strings.Replace(resource, "request.", "requests.", 1)) {
+				message = fmt.Sprintf("has resourceFieldRef.resource '%s', which is not a container resource (did you mean %s?)", resource, candidate)
+			}
+		}
+		return message
+	case name == "cpu":
+		divisors = cpuDivisors
+	}
+
+	if divisor != "" && !containsString(divisors, divisor) {
+		return fmt.Sprintf("has resourceFieldRef divisor '%s', which %s does not accept (supported: %s)", divisor, resource, strings.Join(divisors, ", "))
+	}
+	if containerName == "" {
+		if volume {
+			return "has a resourceFieldRef without containerName, which volumes require"
+		}
+		return ""
+	}
+	if !containsString(containers, containerName) {
+		return fmt.Sprintf("has resourceFieldRef.containerName '%s', which is not a container of the pod (containers: %s)", containerName, strings.Join(containers, ", "))
+	}
+	return ""
+}
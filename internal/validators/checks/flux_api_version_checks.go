@@ -0,0 +1,218 @@
+package checks
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/moon-hex/gitops-validator/internal/config"
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/parser"
+	"github.com/moon-hex/gitops-validator/internal/semver"
+	"github.com/moon-hex/gitops-validator/internal/types"
+)
+
+func init() {
+	config.RegisterRuleParams("flux-api-versions", config.ParamSpec{
+		Name:        "flux-version",
+		Type:        config.ParamVersion,
+		Description: fmt.Sprintf("Flux version to upgrade Flux APIs for, e.g. 2.3.0 (default %s)", latestFluxVersion),
+	})
+}
+
+// latestFluxVersion is the Flux version upgrades are advised for when the
+// flux-api-versions rule has no flux-version
+const latestFluxVersion = "2.7.0"
+
+// fluxAPIVersion is an API version of a Flux kind
+type fluxAPIVersion struct {
+	version string
+	// since is the first Flux release serving the version, empty for
+	// versions every Flux 2 release serves
+	since string
+	// removed are the spec fields the version dropped, as dotted paths
+	removed []string
+}
+
+// fluxAPIVersions are the API versions of each Flux kind, oldest first, by
+// kind and API group
+var fluxAPIVersions = map[[2]string][]fluxAPIVersion{
+	{"Kustomization", "kustomize.toolkit.fluxcd.io"}: {
+		{version: "v1beta1"},
+		{version: "v1beta2"},
+		{version: "v1", since: "2.0.0", removed: []string{"spec.validation", "spec.patchesStrategicMerge", "spec.patchesJson6902"}},
+	},
+	{"GitRepository", "source.toolkit.fluxcd.io"}: {
+		{version: "v1beta1"},
+		{version: "v1beta2"},
+		{version: "v1", since: "2.0.0", removed: []string{"spec.gitImplementation"}},
+	},
+	{"HelmRepository", "source.toolkit.fluxcd.io"}: {
+		{version: "v1beta1"},
+		{version: "v1beta2"},
+		{version: "v1", since: "2.3.0"},
+	},
+	{"HelmChart", "source.toolkit.fluxcd.io"}: {
+		{version: "v1beta1"},
+		{version: "v1beta2"},
+		{version: "v1", since: "2.3.0"},
+	},
+	{"Bucket", "source.toolkit.fluxcd.io"}: {
+		{version: "v1beta1"},
+		{version: "v1beta2"},
+		{version: "v1", since: "2.3.0"},
+	},
+	{"OCIRepository", "source.toolkit.fluxcd.io"}: {
+		{version: "v1beta2"},
+		{version: "v1", since: "2.6.0"},
+	},
+	{"HelmRelease", "helm.toolkit.fluxcd.io"}: {
+		{version: "v2beta1"},
+		{version: "v2beta2", since: "2.2.0"},
+		{version: "v2", since: "2.3.0", removed: []string{"spec.chart.spec.valuesFile"}},
+	},
+	{"Alert", "notification.toolkit.fluxcd.io"}: {
+		{version: "v1beta1"},
+		{version: "v1beta2"},
+		{version: "v1beta3", since: "2.3.0"},
+	},
+	{"Provider", "notification.toolkit.fluxcd.io"}: {
+		{version: "v1beta1"},
+		{version: "v1beta2"},
+		{version: "v1beta3", since: "2.3.0"},
+	},
+	{"Receiver", "notification.toolkit.fluxcd.io"}: {
+		{version: "v1beta1"},
+		{version: "v1beta2"},
+		{version: "v1", since: "2.1.0"},
+	},
+	{"ImageRepository", "image.toolkit.fluxcd.io"}: {
+		{version: "v1beta1"},
+		{version: "v1beta2", since: "2.0.0"},
+		{version: "v1", since: "2.7.0"},
+	},
+	{"ImagePolicy", "image.toolkit.fluxcd.io"}: {
+		{version: "v1beta1"},
+		{version: "v1beta2", since: "2.0.0"},
+		{version: "v1", since: "2.7.0"},
+	},
+	{"ImageUpdateAutomation", "image.toolkit.fluxcd.io"}: {
+		{version: "v1beta1"},
+		{version: "v1beta2", since: "2.3.0"},
+		{version: "v1", since: "2.7.0"},
+	},
+}
+
+// FluxAPIVersionCheck reports Flux objects whose apiVersion the target Flux
+// version (the flux-version parameter, or the latest release known) has
+// superseded, naming the version to upgrade to and the fields it dropped
+// that the object still sets. An apiVersion newer than the target serves is
+// an error: the controllers reject the object. Versions not listed, such as
+// long removed alphas, are left to the deprecated-apis rule. The rule is
+// opt-in: it reports nothing unless enabled in the config.
+func FluxAPIVersionCheck(ctx *context.ValidationContext) []types.ValidationResult {
+	var results []types.ValidationResult
+
+	if !ctx.Config.IsRuleEnabled("flux-api-versions") {
+		return results
+	}
+
+	target, pinned := ctx.Config.RuleParams("flux-api-versions").Version("flux-version")
+	if !pinned {
+		target, _ = semver.Parse(latestFluxVersion)
+	}
+	served := func(version fluxAPIVersion) bool {
+		if version.since == "" {
+			return true
+		}
+		since, _ := semver.Parse(version.since)
+		return semver.Compare(target, since) >= 0
+	}
+
+	kinds := make([]string, 0, len(fluxAPIVersions))
+	for key := range fluxAPIVersions {
+		kinds = append(kinds, key[0])
+	}
+	sort.Strings(kinds)
+
+	for _, kind := range kinds {
+		for _, resource := range ctx.Graph.GetResourcesByKind(kind) {
+			group, version, _ := strings.Cut(resource.APIVersion, "/")
+			versions, ok := fluxAPIVersions[[2]string{kind, group}]
+			if !ok {
+				continue
+			}
+
+			current := -1
+			for i, candidate := range versions {
+				if candidate.version == version {
+					current = i
+				}
+			}
+			if current < 0 {
+				continue
+			}
+
+			add := func(severity types.Severity, message string) {
+				results = append(results, types.ValidationResult{
+					Type:     "flux-api-version",
+					Severity: severity,
+					Message:  fmt.Sprintf("%s '%s' uses %s; %s", kind, resource.Name, resource.APIVersion, message),
+					File:     resource.File,
+					Line:     resource.Line,
+					Column:   resource.Column,
+					Resource: resource.Name,
+				})
+			}
+
+			if !served(versions[current]) {
+				add(types.SeverityError, fmt.Sprintf("Flux %s does not serve it (it needs Flux %s or later)", target.Original, versions[current].since))
+				continue
+			}
+
+			recommended := current
+			for i := current + 1; i < len(versions); i++ {
+				if served(versions[i]) {
+					recommended = i
+				}
+			}
+			if recommended == current {
+				continue
+			}
+
+			message := fmt.Sprintf("upgrade to %s/%s", group, versions[recommended].version)
+			if since := versions[recommended].since; since != "" {
+				message += fmt.Sprintf(", served since Flux %s", since)
+			}
+			var dropped []string
+			for _, upgrade := range versions[current+1 : recommended+1] {
+				for _, field := range upgrade.removed {
+					if hasField(resource, field) {
+						dropped = append(dropped, fmt.Sprintf("%s (removed in %s)", field, upgrade.version))
+					}
+				}
+			}
+			if len(dropped) > 0 {
+				message += fmt.Sprintf(", and replace %s", strings.Join(dropped, ", "))
+			}
+			add(types.SeverityWarning, message)
+		}
+	}
+
+	return results
+}
+
+// hasField reports whether a resource sets a field given as a dotted path
+func hasField(resource *parser.ParsedResource, path string) bool {
+	var value interface{} = resource.Content
+	for _, key := range strings.Split(path, ".") {
+		parent, ok := value.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		if value, ok = parent[key]; !ok {
+			return false
+		}
+	}
+	return true
+}
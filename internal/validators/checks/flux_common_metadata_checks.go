@@ -0,0 +1,192 @@
+package checks
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/parser"
+	"github.com/moon-hex/gitops-validator/internal/types"
+)
+
+var (
+	// qualifiedNamePattern is the name part of a label or annotation key
+	qualifiedNamePattern = regexp.MustCompile(`^([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9]$`)
+	// dnsSubdomainPattern is the optional prefix of a label or annotation key
+	dnsSubdomainPattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+	// labelValuePattern is a label value, which may be empty
+	labelValuePattern = regexp.MustCompile(`^(([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9])?$`)
+)
+
+// FluxKustomizationCommonMetadataCheck validates spec.commonMetadata of a Flux
+// Kustomization. Label and annotation keys and label values must be valid
+// Kubernetes syntax, or applying the tree fails. A label that a selector in the
+// applied tree matches on with another value is overwritten on every object
+// the Kustomization applies, so objects selected by it no longer match.
+func FluxKustomizationCommonMetadataCheck(kustomization *parser.ParsedResource, ctx *context.ValidationContext) []types.ValidationResult {
+	var results []types.ValidationResult
+
+	spec, _ := kustomization.Content["spec"].(map[string]interface{})
+	commonMetadata, ok := spec["commonMetadata"].(map[string]interface{})
+	if !ok {
+		return results
+	}
+
+	result := func(severity types.Severity, message string) types.ValidationResult {
+		return types.ValidationResult{
+			Type:     "flux-kustomization-common-metadata",
+			Severity: severity,
+			Message:  message,
+			File:     kustomization.File,
+			Line:     kustomization.Line,
+			Column:   kustomization.Column,
+			Resource: kustomization.Name,
+		}
+	}
+
+	labels, _ := commonMetadata["labels"].(map[string]interface{})
+	annotations, _ := commonMetadata["annotations"].(map[string]interface{})
+
+	for _, key := range sortedKeys(labels) {
+		if err := validateMetadataKey(key); err != nil {
+			results = append(results, result(types.SeverityError, fmt.Sprintf("Invalid commonMetadata label key '%s': %s", key, err)))
+		}
+		value, isString := labels[key].(string)
+		if !isString {
+			results = append(results, result(types.SeverityError, fmt.Sprintf("commonMetadata label '%s' must be a string value", key)))
+		} else if err := validateLabelValue(value); err != nil {
+			results = append(results, result(types.SeverityError, fmt.Sprintf("Invalid commonMetadata label value '%s=%s': %s", key, value, err)))
+		}
+	}
+	for _, key := range sortedKeys(annotations) {
+		if err := validateMetadataKey(key); err != nil {
+			results = append(results, result(types.SeverityError, fmt.Sprintf("Invalid commonMetadata annotation key '%s': %s", key, err)))
+		}
+		if _, isString := annotations[key].(string); !isString {
+			results = append(results, result(types.SeverityError, fmt.Sprintf("commonMetadata annotation '%s' must be a string value", key)))
+		}
+	}
+
+	if len(labels) == 0 {
+		return results
+	}
+
+	selected := selectedLabels(ctx, ctx.AppliedResources(kustomization))
+	for _, key := range sortedKeys(labels) {
+		value, isString := labels[key].(string)
+		if !isString {
+			continue
+		}
+
+		var selectors []string
+		for _, selection := range selected[key] {
+			if selection.value != value {
+				selectors = append(selectors, fmt.Sprintf("%s (%s=%s)", selection.selector, key, selection.value))
+			}
+		}
+		if len(selectors) == 0 {
+			continue
+		}
+
+		results = append(results, result(types.SeverityWarning, fmt.Sprintf("commonMetadata label '%s=%s' overwrites a label selected by %s; objects carrying that label will no longer match",
+			key, value, strings.Join(selectors, ", "))))
+	}
+
+	return results
+}
+
+// labelSelection is one selector matching on a label value
+type labelSelection struct {
+	selector string // the selecting resource
+	value    string
+}
+
+// selectedLabels returns, per label key, the selectors in a tree matching on
+// it: spec.selector of Services, spec.selector.matchLabels of workloads,
+// PodDisruptionBudgets and monitors, and spec.podSelector.matchLabels of
+// NetworkPolicies
+func selectedLabels(ctx *context.ValidationContext, tree []context.DeployedResource) map[string][]labelSelection {
+	selected := make(map[string][]labelSelection)
+	seen := make(map[*parser.ParsedResource]bool)
+
+	for _, deployed := range tree {
+		resource := deployed.Resource
+		if seen[resource] {
+			continue
+		}
+		seen[resource] = true
+
+		spec, ok := resource.Content["spec"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		var selectors []map[string]interface{}
+		if selector, ok := spec["selector"].(map[string]interface{}); ok {
+			if matchLabels, ok := selector["matchLabels"].(map[string]interface{}); ok {
+				selectors = append(selectors, matchLabels)
+			} else if resource.Kind == "Service" {
+				selectors = append(selectors, selector)
+			}
+		}
+		if podSelector, ok := spec["podSelector"].(map[string]interface{}); ok {
+			if matchLabels, ok := podSelector["matchLabels"].(map[string]interface{}); ok {
+				selectors = append(selectors, matchLabels)
+			}
+		}
+
+		name := resource.Name
+		if deployed.Namespace != "" {
+			name = deployed.Namespace + "/" + name
+		}
+		description := fmt.Sprintf("%s '%s' in %s", resource.Kind, name, relativeFile(ctx, resource.File))
+		for _, selector := range selectors {
+			for _, key := range sortedKeys(selector) {
+				selected[key] = append(selected[key], labelSelection{selector: description, value: fmt.Sprint(selector[key])})
+			}
+		}
+	}
+
+	return selected
+}
+
+// validateMetadataKey checks a label or annotation key: an optional DNS
+// subdomain prefix and a slash, then a name of at most 63 characters
+func validateMetadataKey(key string) error {
+	name := key
+	if i := strings.LastIndex(key, "/"); i >= 0 {
+		prefix := key[:i]
+		name = key[i+1:]
+		if strings.Contains(prefix, "/") {
+			return fmt.Errorf("only one '/' separating prefix and name is allowed")
+		}
+		if len(prefix) > 253 || !dnsSubdomainPattern.MatchString(prefix) {
+			return fmt.Errorf("prefix '%s' must be a lower-case DNS subdomain of at most 253 characters", prefix)
+		}
+	}
+	if len(name) > 63 || !qualifiedNamePattern.MatchString(name) {
+		return fmt.Errorf("name '%s' must be at most 63 alphanumeric characters, '-', '_' or '.', starting and ending with an alphanumeric character", name)
+	}
+	return nil
+}
+
+// validateLabelValue checks a label value: empty, or at most 63 alphanumeric
+// characters, '-', '_' or '.', starting and ending with an alphanumeric one
+func validateLabelValue(value string) error {
+	if len(value) > 63 || !labelValuePattern.MatchString(value) {
+		return fmt.Errorf("must be at most 63 alphanumeric characters, '-', '_' or '.', starting and ending with an alphanumeric character")
+	}
+	return nil
+}
+
+// sortedKeys returns the keys of a YAML mapping in sorted order
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,272 @@
+package checks
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/parser"
+	"github.com/moon-hex/gitops-validator/internal/types"
+)
+
+// dependsOnNode is a Flux Kustomization as deployed to a cluster
+type dependsOnNode struct {
+	resource  *parser.ParsedResource
+	namespace string
+	key       string // namespace/name
+}
+
+// FluxKustomizationDependsOnCheck validates spec.dependsOn of Flux
+// Kustomizations. An entry without namespace refers to the namespace of the
+// depending Kustomization, and resolves to the Kustomizations deployed in the
+// same cluster tree, or to any in the repository when that tree has none of
+// that name (as with trees whose bootstrap Kustomization is not committed).
+// A Kustomization depending on itself, directly or through a cycle, never
+// becomes ready, and neither does anything depending on it.
+func FluxKustomizationDependsOnCheck(ctx *context.ValidationContext) []types.ValidationResult {
+	var results []types.ValidationResult
+	reported := make(map[string]bool)
+
+	add := func(resource *parser.ParsedResource, message string) {
+		key := fmt.Sprintf("%s:%d:%s", resource.File, resource.Line, message)
+		if reported[key] {
+			return
+		}
+		reported[key] = true
+
+		results = append(results, types.ValidationResult{
+			Type:     "flux-kustomization-depends-on",
+			Severity: types.SeverityError,
+			Message:  message,
+			File:     resource.File,
+			Line:     resource.Line,
+			Column:   resource.Column,
+			Resource: resource.Name,
+		})
+	}
+
+	anywhere := make(map[string][]*parser.ParsedResource)
+	for _, kustomization := range ctx.Graph.GetFluxKustomizations() {
+		key := kustomization.Namespace + "/" + kustomization.Name
+		anywhere[key] = append(anywhere[key], kustomization)
+	}
+
+	keys := make(map[*parser.ParsedResource]string)
+	edges := make(map[*parser.ParsedResource][]*parser.ParsedResource)
+	for _, scope := range dependsOnScopes(ctx) {
+		inScope := make(map[string][]*parser.ParsedResource)
+		for _, node := range scope {
+			inScope[node.key] = append(inScope[node.key], node.resource)
+			if _, exists := keys[node.resource]; !exists {
+				keys[node.resource] = node.key
+			}
+		}
+
+		for _, node := range scope {
+			for _, dependency := range dependsOnEntries(node) {
+				if dependency == node.key {
+					add(node.resource, fmt.Sprintf("Kustomization '%s' depends on itself", node.key))
+					continue
+				}
+
+				targets := inScope[dependency]
+				if len(targets) == 0 {
+					targets = anywhere[dependency]
+				}
+				if len(targets) == 0 {
+					add(node.resource, fmt.Sprintf("dependsOn '%s' of Kustomization '%s' does not match any Flux Kustomization", dependency, node.key))
+					continue
+				}
+				for _, target := range targets {
+					if _, exists := keys[target]; !exists {
+						keys[target] = target.Namespace + "/" + target.Name
+					}
+					if !containsResource(edges[node.resource], target) {
+						edges[node.resource] = append(edges[node.resource], target)
+					}
+				}
+			}
+		}
+	}
+
+	for _, cycle := range dependsOnCycles(edges, keys) {
+		described := make([]string, 0, len(cycle)+1)
+		for _, resource := range cycle {
+			described = append(described, fmt.Sprintf("'%s' (%s)", keys[resource], relativeFile(ctx, resource.File)))
+		}
+		described = append(described, fmt.Sprintf("'%s'", keys[cycle[0]]))
+		add(cycle[0], fmt.Sprintf("dependsOn cycle: %s; none of these Kustomizations can become ready", strings.Join(described, " -> ")))
+	}
+
+	return results
+}
+
+// dependsOnScopes returns the Flux Kustomizations of every cluster tree, or
+// all of them as one scope when no cluster root is found
+func dependsOnScopes(ctx *context.ValidationContext) [][]dependsOnNode {
+	newNode := func(resource *parser.ParsedResource, namespace string) dependsOnNode {
+		return dependsOnNode{resource: resource, namespace: namespace, key: namespace + "/" + resource.Name}
+	}
+
+	roots := ctx.RootKustomizations()
+	if len(roots) == 0 {
+		var scope []dependsOnNode
+		for _, kustomization := range ctx.Graph.GetFluxKustomizations() {
+			scope = append(scope, newNode(kustomization, kustomization.Namespace))
+		}
+		return [][]dependsOnNode{scope}
+	}
+
+	var scopes [][]dependsOnNode
+	for _, root := range roots {
+		var scope []dependsOnNode
+		for _, deployed := range ctx.DeploymentTree(root) {
+			if parser.ClassifyResource(deployed.Resource) == parser.ResourceTypeFluxKustomization {
+				scope = append(scope, newNode(deployed.Resource, deployed.Namespace))
+			}
+		}
+		scopes = append(scopes, scope)
+	}
+	return scopes
+}
+
+// dependsOnEntries returns the namespace/name keys of spec.dependsOn
+func dependsOnEntries(node dependsOnNode) []string {
+	spec, _ := node.resource.Content["spec"].(map[string]interface{})
+	entries, _ := spec["dependsOn"].([]interface{})
+
+	var keys []string
+	for _, entry := range entries {
+		reference, ok := entry.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := reference["name"].(string)
+		if name == "" {
+			continue
+		}
+		namespace, _ := reference["namespace"].(string)
+		if namespace == "" {
+			namespace = node.namespace
+		}
+		keys = append(keys, namespace+"/"+name)
+	}
+	return keys
+}
+
+// dependsOnCycles returns one cycle per strongly connected component of more
+// than one Kustomization, starting at the one with the smallest key
+func dependsOnCycles(edges map[*parser.ParsedResource][]*parser.ParsedResource, keys map[*parser.ParsedResource]string) [][]*parser.ParsedResource {
+	less := func(a, b *parser.ParsedResource) bool {
+		if keys[a] != keys[b] {
+			return keys[a] < keys[b]
+		}
+		return a.File < b.File
+	}
+
+	nodes := make([]*parser.ParsedResource, 0, len(edges))
+	for node := range edges {
+		nodes = append(nodes, node)
+	}
+	sort.Slice(nodes, func(i, j int) bool { return less(nodes[i], nodes[j]) })
+
+	// Tarjan's algorithm
+	index := make(map[*parser.ParsedResource]int)
+	lowLink := make(map[*parser.ParsedResource]int)
+	onStack := make(map[*parser.ParsedResource]bool)
+	var stack []*parser.ParsedResource
+	var components [][]*parser.ParsedResource
+
+	var connect func(node *parser.ParsedResource)
+	connect = func(node *parser.ParsedResource) {
+		index[node] = len(index)
+		lowLink[node] = index[node]
+		stack = append(stack, node)
+		onStack[node] = true
+
+		for _, next := range edges[node] {
+			if _, seen := index[next]; !seen {
+				connect(next)
+				if lowLink[next] < lowLink[node] {
+					lowLink[node] = lowLink[next]
+				}
+			} else if onStack[next] && index[next] < lowLink[node] {
+				lowLink[node] = index[next]
+			}
+		}
+
+		if lowLink[node] != index[node] {
+			return
+		}
+		var component []*parser.ParsedResource
+		for {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[top] = false
+			component = append(component, top)
+			if top == node {
+				break
+			}
+		}
+		if len(component) > 1 {
+			components = append(components, component)
+		}
+	}
+
+	for _, node := range nodes {
+		if _, seen := index[node]; !seen {
+			connect(node)
+		}
+	}
+
+	var cycles [][]*parser.ParsedResource
+	for _, component := range components {
+		sort.Slice(component, func(i, j int) bool { return less(component[i], component[j]) })
+		cycles = append(cycles, cyclePath(component, edges, less))
+	}
+	sort.Slice(cycles, func(i, j int) bool { return less(cycles[i][0], cycles[j][0]) })
+	return cycles
+}
+
+// cyclePath returns a shortest path from the first member of a strongly
+// connected component back to itself, without repeating it at the end
+func cyclePath(component []*parser.ParsedResource, edges map[*parser.ParsedResource][]*parser.ParsedResource, less func(a, b *parser.ParsedResource) bool) []*parser.ParsedResource {
+	start := component[0]
+	previous := map[*parser.ParsedResource]*parser.ParsedResource{start: nil}
+	queue := []*parser.ParsedResource{start}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+
+		next := append([]*parser.ParsedResource(nil), edges[node]...)
+		sort.Slice(next, func(i, j int) bool { return less(next[i], next[j]) })
+		for _, target := range next {
+			if !containsResource(component, target) {
+				continue
+			}
+			if target == start {
+				path := []*parser.ParsedResource{node}
+				for at := previous[node]; at != nil; at = previous[at] {
+					path = append([]*parser.ParsedResource{at}, path...)
+				}
+				return path
+			}
+			if _, seen := previous[target]; !seen {
+				previous[target] = node
+				queue = append(queue, target)
+			}
+		}
+	}
+	return component
+}
+
+// containsResource reports whether resources contains resource
+func containsResource(resources []*parser.ParsedResource, resource *parser.ParsedResource) bool {
+	for _, r := range resources {
+		if r == resource {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+package checks
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/parser"
+	"github.com/moon-hex/gitops-validator/internal/types"
+)
+
+// FluxKustomizationHealthChecksCheck validates spec.healthChecks of a Flux
+// Kustomization. Every entry must name an object the Kustomization applies
+// itself, matched by kind, name, API group and namespace; a typo makes Flux
+// wait for an object that never appears instead of gating on the intended
+// one. Entries without a namespace match any namespace, and entries with
+// Flux variables are skipped as they are only known in-cluster. Nothing is
+// reported when the applied tree cannot be resolved from the repository.
+func FluxKustomizationHealthChecksCheck(kustomization *parser.ParsedResource, ctx *context.ValidationContext) []types.ValidationResult {
+	var results []types.ValidationResult
+
+	spec, _ := kustomization.Content["spec"].(map[string]interface{})
+	healthChecks, _ := spec["healthChecks"].([]interface{})
+	if len(healthChecks) == 0 {
+		return results
+	}
+
+	result := func(message string) types.ValidationResult {
+		return types.ValidationResult{
+			Type:     "flux-kustomization-health-check",
+			Severity: types.SeverityError,
+			Message:  message,
+			File:     kustomization.File,
+			Line:     kustomization.Line,
+			Column:   kustomization.Column,
+			Resource: kustomization.Name,
+		}
+	}
+
+	applied := ctx.AppliedResources(kustomization)
+
+	for i, entry := range healthChecks {
+		check, _ := entry.(map[string]interface{})
+		apiVersion, _ := check["apiVersion"].(string)
+		kind, _ := check["kind"].(string)
+		name, _ := check["name"].(string)
+		namespace, _ := check["namespace"].(string)
+
+		if kind == "" || name == "" {
+			results = append(results, result(fmt.Sprintf("healthChecks[%d] of Kustomization '%s' needs both kind and name", i, kustomization.GetResourceKey())))
+			continue
+		}
+		if strings.Contains(apiVersion+kind+name+namespace, "${") || len(applied) == 0 {
+			continue
+		}
+
+		// Objects of the same name are suggested, those of the same kind only
+		// when there are any
+		var sameName, sameKind []string
+		matched := false
+		for _, deployed := range applied {
+			resource := deployed.Resource
+			if resource.Name != name {
+				continue
+			}
+			if resource.Kind == kind && (apiVersion == "" || apiGroup(resource.APIVersion) == apiGroup(apiVersion)) &&
+				(namespace == "" || deployed.Namespace == "" || deployed.Namespace == namespace) {
+				matched = true
+				break
+			}
+			candidate := fmt.Sprintf("%s %s '%s'", resource.APIVersion, resource.Kind, resource.Name)
+			if deployed.Namespace != "" {
+				candidate = fmt.Sprintf("%s %s '%s/%s'", resource.APIVersion, resource.Kind, deployed.Namespace, resource.Name)
+			}
+			if !containsString(sameName, candidate) {
+				sameName = append(sameName, candidate)
+				if resource.Kind == kind {
+					sameKind = append(sameKind, candidate)
+				}
+			}
+		}
+		if matched {
+			continue
+		}
+		if len(sameKind) > 0 {
+			sameName = sameKind
+		}
+
+		target := name
+		if namespace != "" {
+			target = namespace + "/" + name
+		}
+		if apiVersion != "" {
+			kind = apiVersion + " " + kind
+		}
+		message := fmt.Sprintf("healthChecks entry %s '%s' of Kustomization '%s' does not match any object it applies; Flux waits for an object that never appears",
+			kind, target, kustomization.GetResourceKey())
+		if len(sameName) > 0 {
+			message += fmt.Sprintf(" (did you mean %s?)", strings.Join(sameName, ", "))
+		}
+		results = append(results, result(message))
+	}
+
+	return results
+}
+
+// apiGroup returns the group of an apiVersion: "apps" for apps/v1, "" for v1
+func apiGroup(apiVersion string) string {
+	if group, _, found := strings.Cut(apiVersion, "/"); found {
+		return group
+	}
+	return ""
+}
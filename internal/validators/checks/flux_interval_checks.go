@@ -0,0 +1,110 @@
+package checks
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/parser"
+	"github.com/moon-hex/gitops-validator/internal/types"
+)
+
+// DefaultMinimumSourceInterval is the shortest spec.interval accepted on Flux
+// sources when rules.flux-intervals.minimum-interval is not configured
+const DefaultMinimumSourceInterval = time.Minute
+
+// fluxDurationPattern is the format the Flux CRDs accept for durations
+var fluxDurationPattern = regexp.MustCompile(`^([0-9]+(\.[0-9]+)?(ms|s|m|h))+$`)
+
+// FluxIntervalCheck validates spec.interval, spec.timeout and
+// spec.retryInterval of Flux Kustomizations, HelmReleases and sources. Every
+// one of them except patches needs an interval, and values that are not Flux durations are
+// rejected by the API server. A timeout longer than the interval lets
+// reconciliations overlap the next one. Sources polled more often than the
+// configured minimum interval hammer the Git server, registry or bucket.
+func FluxIntervalCheck(ctx *context.ValidationContext) []types.ValidationResult {
+	var results []types.ValidationResult
+
+	minimum, minimumText := DefaultMinimumSourceInterval, "1m"
+	if configured := ctx.Config.GitOpsValidator.Rules.FluxIntervals.MinimumInterval; configured != "" {
+		if parsed, err := time.ParseDuration(configured); err == nil {
+			minimum, minimumText = parsed, configured
+		}
+	}
+
+	add := func(resource *parser.ParsedResource, severity types.Severity, message string) {
+		results = append(results, types.ValidationResult{
+			Type:     "flux-interval",
+			Severity: severity,
+			Message:  fmt.Sprintf("%s '%s' %s", resource.Kind, resource.GetResourceKey(), message),
+			File:     resource.File,
+			Line:     resource.Line,
+			Column:   resource.Column,
+			Resource: resource.Name,
+		})
+	}
+
+	patches := patchResources(ctx)
+
+	var resources []*parser.ParsedResource
+	resources = append(resources, ctx.Graph.GetFluxKustomizations()...)
+	resources = append(resources, ctx.Graph.GetHelmReleases()...)
+	resources = append(resources, ctx.Graph.GetFluxSources()...)
+
+	for _, resource := range resources {
+		spec, _ := resource.Content["spec"].(map[string]interface{})
+		isSource := parser.ClassifyResource(resource) == parser.ResourceTypeFluxSource
+
+		durations := make(map[string]time.Duration)
+		texts := make(map[string]string)
+		for _, field := range []string{"interval", "timeout", "retryInterval"} {
+			value, exists := spec[field]
+			if !exists {
+				continue
+			}
+			text, _ := value.(string)
+			duration, err := time.ParseDuration(text)
+			if !fluxDurationPattern.MatchString(text) || err != nil {
+				add(resource, types.SeverityError, fmt.Sprintf("has spec.%s '%v', which is not a duration such as 30s, 5m or 1h30m", field, value))
+				continue
+			}
+			durations[field] = duration
+			texts[field] = text
+		}
+
+		interval, hasInterval := durations["interval"]
+		if _, exists := spec["interval"]; !exists && !patches[resource] {
+			add(resource, types.SeverityError, "has no spec.interval; Flux requires one to schedule reconciliation")
+		}
+		if !hasInterval {
+			continue
+		}
+
+		if timeout, ok := durations["timeout"]; ok && timeout > interval {
+			add(resource, types.SeverityWarning, fmt.Sprintf("has spec.timeout %s longer than spec.interval %s; a slow reconciliation runs into the next one", texts["timeout"], texts["interval"]))
+		}
+		if isSource && interval < minimum {
+			add(resource, types.SeverityWarning, fmt.Sprintf("is polled every %s, more often than the minimum interval %s; frequent polling hammers the source and can hit rate limits", texts["interval"], minimumText))
+		}
+	}
+
+	return results
+}
+
+// patchResources returns the documents kustomization files use as patches,
+// which only carry the fields they change
+func patchResources(ctx *context.ValidationContext) map[*parser.ParsedResource]bool {
+	patches := make(map[*parser.ParsedResource]bool)
+	for _, kustomization := range ctx.Graph.GetKubernetesKustomizations() {
+		for _, dep := range kustomization.Dependencies {
+			if dep.Type != "kustomization-patch" && dep.Type != "kustomization-patch-strategic" {
+				continue
+			}
+			for _, target := range ctx.Graph.FindAllTargetResources(dep, kustomization, ctx.FluxRoot) {
+				patches[target] = true
+			}
+		}
+	}
+	return patches
+}
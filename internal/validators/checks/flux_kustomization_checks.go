@@ -2,10 +2,13 @@ package checks
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/moon-hex/gitops-validator/internal/context"
 	"github.com/moon-hex/gitops-validator/internal/parser"
+	"github.com/moon-hex/gitops-validator/internal/pathutil"
 	"github.com/moon-hex/gitops-validator/internal/types"
 	"github.com/moon-hex/gitops-validator/internal/validators/common"
 )
@@ -14,34 +17,72 @@ import (
 func FluxKustomizationPathCheck(kustomization *parser.ParsedResource, ctx *context.ValidationContext) []types.ValidationResult {
 	var results []types.ValidationResult
 
-	// Extract path from the parsed resource
+	// Extract path from the parsed resource; results point at it
 	path, err := common.ExtractStringFromContent(kustomization.Content, "spec", "path")
+	position := kustomization.FieldPosition("spec", "path")
 	if err != nil {
 		results = append(results, types.ValidationResult{
 			Type:     "flux-kustomization-path",
-			Severity: "error",
+			Severity: types.SeverityError,
 			Message:  fmt.Sprintf("Invalid path specification: %s", err.Error()),
 			File:     kustomization.File,
+			Line:     position.Line,
+			Column:   position.Column,
 			Resource: kustomization.Name,
 		})
 		return results
 	}
 
-	// spec.path is relative to the source repository named in sourceRef, not this
-	// repo. When the source is an external GitRepository/OCIRepository we cannot
-	// check the path against the local filesystem.
-	if isExternalSourceRef(kustomization, ctx) {
-		return results
-	}
+	// spec.path is relative to the root of the source named in sourceRef, which
+	// is not necessarily this repository.
+	baseDir, externalSource := resolveSourceRoot(kustomization, ctx)
 
 	// Validate path exists
-	baseDir := ctx.RepoPath
 	if err := common.PathValidationCheck(baseDir, path); err != nil {
+		if externalSource != "" {
+			// The path lives in another repository we have no checkout of, so a
+			// missing local path is expected rather than an error.
+			results = append(results, types.ValidationResult{
+				Type:     "flux-kustomization-path",
+				Severity: types.SeverityInfo,
+				Message: fmt.Sprintf("Path '%s' cannot be verified against this repository: source %s (map it to a local checkout under 'sources' in the config to validate it)",
+					path, externalSource),
+				File:     kustomization.File,
+				Line:     position.Line,
+				Column:   position.Column,
+				Resource: kustomization.Name,
+			})
+			return results
+		}
+
 		results = append(results, types.ValidationResult{
 			Type:     "flux-kustomization-path",
-			Severity: "error",
+			Severity: types.SeverityError,
 			Message:  fmt.Sprintf("Invalid path reference: %s", err.Error()),
 			File:     kustomization.File,
+			Line:     position.Line,
+			Column:   position.Column,
+			Resource: kustomization.Name,
+		})
+		return results
+	}
+
+	// Without a kustomization file, Flux generates one that includes every
+	// manifest in the directory tree, including files nobody meant to deploy
+	dir := filepath.Join(baseDir, path)
+	if target, err := common.InspectDirectoryTarget(dir); err == nil && len(target.KustomizationFiles) == 0 {
+		files := fmt.Sprintf("%d YAML files", countYAMLFiles(dir))
+		if files == "1 YAML files" {
+			files = "1 YAML file"
+		}
+		results = append(results, types.ValidationResult{
+			Type:     "flux-kustomization-generated",
+			Severity: types.SeverityWarning,
+			Message: fmt.Sprintf("Path '%s' has no kustomization.yaml; Flux generates one applying every YAML file in the directory tree, %s now (add a kustomization.yaml listing what to deploy)",
+				path, files),
+			File:     kustomization.File,
+			Line:     position.Line,
+			Column:   position.Column,
 			Resource: kustomization.Name,
 		})
 	}
@@ -49,52 +90,114 @@ func FluxKustomizationPathCheck(kustomization *parser.ParsedResource, ctx *conte
 	return results
 }
 
-// isExternalSourceRef returns true when the Flux Kustomization's sourceRef resolves
-// to a GitRepository or OCIRepository with a remote URL. In that case spec.path is
-// relative to the remote source and cannot be validated against the local filesystem.
-func isExternalSourceRef(kustomization *parser.ParsedResource, ctx *context.ValidationContext) bool {
-	sourceRefKind, _ := common.ExtractStringFromContent(kustomization.Content, "spec", "sourceRef", "kind")
-	if sourceRefKind != "GitRepository" && sourceRefKind != "OCIRepository" {
-		return false
-	}
+// countYAMLFiles counts the YAML files in a directory tree
+func countYAMLFiles(dir string) int {
+	count := 0
+	filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			lower := strings.ToLower(info.Name())
+			if strings.HasSuffix(lower, ".yaml") || strings.HasSuffix(lower, ".yml") {
+				count++
+			}
+		}
+		return nil
+	})
+	return count
+}
+
+// resolveSourceRoot returns the local directory that a Flux Kustomization's
+// spec.path is relative to. Sources mapped in the config resolve to their
+// configured checkout. When the sourceRef points at a different repository
+// that is not mapped, externalSource describes it and baseDir falls back to
+// the Flux root of the validated repository.
+func resolveSourceRoot(kustomization *parser.ParsedResource, ctx *context.ValidationContext) (baseDir string, externalSource string) {
+	baseDir = ctx.FluxRoot
 
+	sourceRefKind, _ := common.ExtractStringFromContent(kustomization.Content, "spec", "sourceRef", "kind")
 	sourceRefName, err := common.ExtractStringFromContent(kustomization.Content, "spec", "sourceRef", "name")
 	if err != nil || sourceRefName == "" {
-		return false
+		return baseDir, ""
+	}
+
+	sourceRefNamespace, _ := common.ExtractStringFromContent(kustomization.Content, "spec", "sourceRef", "namespace")
+	if sourceRefNamespace == "" {
+		sourceRefNamespace = kustomization.Namespace
 	}
 
+	if mapped, ok := ctx.Config.GetSourceMapping(sourceRefNamespace, sourceRefName); ok {
+		if filepath.IsAbs(mapped) {
+			return mapped, ""
+		}
+		return filepath.Join(ctx.RepoPath, mapped), ""
+	}
+
+	if sourceRefKind != "GitRepository" && sourceRefKind != "OCIRepository" && sourceRefKind != "Bucket" {
+		return baseDir, ""
+	}
+
+	description := fmt.Sprintf("%s '%s'", sourceRefKind, sourceRefName)
+
 	// Look up by kind+name to avoid matching a same-named Namespace or other
 	// cluster-scoped resource whose key collides in the Resources map.
-	source := findSourceByKindAndName(ctx, sourceRefKind, sourceRefName)
+	source := findSourceByKindAndName(ctx, sourceRefKind, sourceRefName, sourceRefNamespace)
 	if source == nil {
-		// Source not found locally — likely defined in another repo. Be conservative
-		// and skip path validation to avoid false positives.
-		return true
+		// Source not found locally — likely defined in another repo.
+		return baseDir, description + " is not defined here"
+	}
+
+	// Bucket contents (S3, GCS, Azure Blob, MinIO) are never the git checkout
+	// being validated
+	if sourceRefKind == "Bucket" {
+		bucketName, _ := common.ExtractStringFromContent(source.Content, "spec", "bucketName")
+		endpoint, _ := common.ExtractStringFromContent(source.Content, "spec", "endpoint")
+		if bucketName == "" {
+			return baseDir, description
+		}
+		if endpoint != "" {
+			bucketName = endpoint + "/" + bucketName
+		}
+		return baseDir, fmt.Sprintf("%s points at bucket %s", description, bucketName)
 	}
 
 	url, err := common.ExtractStringFromContent(source.Content, "spec", "url")
-	if err != nil || url == "" {
-		return false
+	if err != nil || url == "" || !pathutil.IsRemote(url) {
+		return baseDir, ""
 	}
+	description = fmt.Sprintf("%s points at %s", description, url)
 
-	return strings.HasPrefix(url, "http://") ||
-		strings.HasPrefix(url, "https://") ||
-		strings.HasPrefix(url, "ssh://") ||
-		strings.HasPrefix(url, "git@") ||
-		strings.HasPrefix(url, "git://")
+	// OCI artifacts are never the git checkout being validated
+	if sourceRefKind == "OCIRepository" {
+		return baseDir, description
+	}
+
+	for _, localURL := range common.LocalRepositoryURLs(ctx.RepoPath) {
+		if common.SameRepositoryURL(url, localURL) {
+			return baseDir, ""
+		}
+	}
+
+	return baseDir, description
 }
 
-// findSourceByKindAndName returns the first resource matching both kind and name.
-// Using GetResource(name) alone can return a wrong resource when an unrelated
-// cluster-scoped resource (e.g. a Namespace) shares the same name as the
-// GitRepository/OCIRepository being looked up.
-func findSourceByKindAndName(ctx *context.ValidationContext, kind, name string) *parser.ParsedResource {
-	for _, r := range ctx.Graph.GetResourcesByKind(kind) {
-		if r.Name == name {
+// findSourceByKindAndName returns the Flux source matching kind and name,
+// preferring one in namespace. Using GetResource(name) alone can return a
+// wrong resource when an unrelated cluster-scoped resource (e.g. a Namespace)
+// shares the same name as the GitRepository/OCIRepository/Bucket being looked
+// up, and kind alone matches other APIs' kinds such as Crossplane's Bucket.
+func findSourceByKindAndName(ctx *context.ValidationContext, kind, name, namespace string) *parser.ParsedResource {
+	var found *parser.ParsedResource
+	for _, r := range ctx.Graph.GetFluxSources() {
+		if r.Kind != kind || r.Name != name {
+			continue
+		}
+		if r.Namespace == namespace {
 			return r
 		}
+		if found == nil {
+			found = r
+		}
 	}
-	return nil
+	return found
 }
 
 // FluxKustomizationSourceCheck validates source references in Flux Kustomizations
@@ -108,13 +211,23 @@ func FluxKustomizationSourceCheck(kustomization *parser.ParsedResource, ctx *con
 		return results
 	}
 
+	// Resolve the source in the namespace of the Kustomization unless set
+	sourceRefKind, _ := common.ExtractStringFromContent(kustomization.Content, "spec", "sourceRef", "kind")
+	sourceRefNamespace, _ := common.ExtractStringFromContent(kustomization.Content, "spec", "sourceRef", "namespace")
+	if sourceRefNamespace == "" {
+		sourceRefNamespace = kustomization.Namespace
+	}
+
 	// Validate source reference
-	if err := common.SourceValidationCheck(ctx, sourceRef); err != nil {
+	if err := common.SourceValidationCheck(ctx, sourceRefKind, sourceRef, sourceRefNamespace); err != nil {
+		position := kustomization.FieldPosition("spec", "sourceRef", "name")
 		results = append(results, types.ValidationResult{
 			Type:     "flux-kustomization-source",
-			Severity: "error",
+			Severity: types.SeverityError,
 			Message:  fmt.Sprintf("Invalid source reference: %s", err.Error()),
 			File:     kustomization.File,
+			Line:     position.Line,
+			Column:   position.Column,
 			Resource: kustomization.Name,
 		})
 	}
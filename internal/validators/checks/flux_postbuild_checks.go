@@ -3,6 +3,8 @@ package checks
 import (
 	"fmt"
 	"regexp"
+	"strconv"
+	"strings"
 
 	"github.com/moon-hex/gitops-validator/internal/context"
 	"github.com/moon-hex/gitops-validator/internal/parser"
@@ -20,11 +22,12 @@ func FluxPostBuildVariableCheck(kustomization *parser.ParsedResource, ctx *conte
 		if !isValidFluxVariableName(variable.Name) {
 			results = append(results, types.ValidationResult{
 				Type:     "flux-postbuild-variables",
-				Severity: "error",
+				Severity: types.SeverityError,
 				Message: fmt.Sprintf("Invalid Flux variable name '%s': must start with underscore or letter, followed by letters, digits, or underscores only (no dashes allowed). Pattern: ^[_a-zA-Z][_a-zA-Z0-9]*$",
 					variable.Name),
 				File:     kustomization.File,
 				Line:     variable.Line,
+				Column:   variable.Column,
 				Resource: kustomization.Name,
 			})
 		}
@@ -33,26 +36,90 @@ func FluxPostBuildVariableCheck(kustomization *parser.ParsedResource, ctx *conte
 	return results
 }
 
+// FluxPostBuildSubstituteFromCheck validates postBuild.substituteFrom of a
+// Flux Kustomization. Every entry must be a ConfigMap or Secret in the
+// Kustomization's namespace that the repository creates, either as a manifest
+// or through a kustomize generator without a name hash suffix; Flux fails the
+// reconciliation until it exists. Entries marked optional are skipped, as are
+// names with Flux variables.
+func FluxPostBuildSubstituteFromCheck(kustomization *parser.ParsedResource, ctx *context.ValidationContext, available map[string]string) []types.ValidationResult {
+	var results []types.ValidationResult
+
+	spec, _ := kustomization.Content["spec"].(map[string]interface{})
+	postBuild, _ := spec["postBuild"].(map[string]interface{})
+	substituteFrom, _ := postBuild["substituteFrom"].([]interface{})
+
+	add := func(index int, message string) {
+		position := kustomization.FieldPosition("spec", "postBuild", "substituteFrom", strconv.Itoa(index))
+		results = append(results, types.ValidationResult{
+			Type:     "flux-postbuild-substitute-from",
+			Severity: types.SeverityError,
+			Message:  message,
+			File:     kustomization.File,
+			Line:     position.Line,
+			Column:   position.Column,
+			Resource: kustomization.Name,
+		})
+	}
+
+	for i, entry := range substituteFrom {
+		reference, _ := entry.(map[string]interface{})
+		kind, _ := reference["kind"].(string)
+		name, _ := reference["name"].(string)
+
+		if kind != "ConfigMap" && kind != "Secret" {
+			add(i, fmt.Sprintf("postBuild.substituteFrom[%d] of Kustomization '%s' has kind '%s'; it must be ConfigMap or Secret", i, kustomization.GetResourceKey(), kind))
+			continue
+		}
+		if name == "" {
+			add(i, fmt.Sprintf("postBuild.substituteFrom[%d] of Kustomization '%s' has no name", i, kustomization.GetResourceKey()))
+			continue
+		}
+		if reference["optional"] == "true" || strings.Contains(name, "${") {
+			continue
+		}
+
+		target := name
+		if kustomization.Namespace != "" {
+			target = kustomization.Namespace + "/" + name
+		}
+		switch origin, exists := available[configSourceKey(kind, kustomization.Namespace, name)]; {
+		case !exists:
+			add(i, fmt.Sprintf("Kustomization '%s' substitutes variables from %s '%s', which the repository does not create; reconciliation fails until it exists (set optional: true if it is created outside the repository)",
+				kustomization.GetResourceKey(), kind, target))
+		case origin != "":
+			add(i, fmt.Sprintf("Kustomization '%s' substitutes variables from %s '%s', which %s generates with a name hash suffix, so the name never matches (set generatorOptions.disableNameSuffixHash: true)",
+				kustomization.GetResourceKey(), kind, target, origin))
+		}
+	}
+
+	return results
+}
+
 // PostBuildVariable represents a postBuild substitute variable
 type PostBuildVariable struct {
-	Name string
-	Line int
+	Name   string
+	Line   int
+	Column int
 }
 
 // extractPostBuildVariables extracts postBuild substitute variable names from a parsed resource
 func extractPostBuildVariables(resource *parser.ParsedResource) []PostBuildVariable {
 	var variables []PostBuildVariable
 
-	// Navigate to postBuild.substitute
-	if postBuild, exists := resource.Content["postBuild"]; exists {
+	// Navigate to spec.postBuild.substitute
+	spec, _ := resource.Content["spec"].(map[string]interface{})
+	if postBuild, exists := spec["postBuild"]; exists {
 		if postBuildMap, ok := postBuild.(map[string]interface{}); ok {
 			if substitute, exists := postBuildMap["substitute"]; exists {
 				if substituteMap, ok := substitute.(map[string]interface{}); ok {
 					// Extract variable names from the substitute map
 					for key := range substituteMap {
+						position := resource.KeyPosition("spec", "postBuild", "substitute", key)
 						variables = append(variables, PostBuildVariable{
-							Name: key,
-							Line: resource.Line, // We don't have exact line numbers for nested values
+							Name:   key,
+							Line:   position.Line,
+							Column: position.Column,
 						})
 					}
 				}
@@ -0,0 +1,310 @@
+package checks
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/moon-hex/gitops-validator/internal/config"
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/parser"
+	"github.com/moon-hex/gitops-validator/internal/types"
+)
+
+func init() {
+	config.RegisterRuleParams("flux-postbuild-variables", config.ParamSpec{
+		Name:        "report-unused",
+		Type:        config.ParamBool,
+		Description: "also report postBuild.substitute variables no applied manifest uses",
+	})
+}
+
+// substitutionPattern matches Flux variable placeholders; a leading $ escapes
+// one, so $${VAR} is applied as ${VAR}
+var substitutionPattern = regexp.MustCompile(`\$?\$\{([^}]*)\}`)
+
+// substitutionNamePattern matches the variable name of a placeholder
+var substitutionNamePattern = regexp.MustCompile(`^[_a-zA-Z][_a-zA-Z0-9]*`)
+
+// substitutionDisabledKey is the annotation or label exempting a resource
+// from variable substitution when set to "disabled"
+const substitutionDisabledKey = "kustomize.toolkit.fluxcd.io/substitute"
+
+// FluxPostBuildUsageCheck compares the ${VAR} placeholders in the manifests
+// each Flux Kustomization with spec.postBuild applies to the variables its
+// postBuild.substitute and substituteFrom define. Flux replaces undefined
+// variables without a default (${VAR:=default}) by an empty string, so the
+// resource is applied with empty fields. Variables of substituteFrom entries
+// are read from the ConfigMaps and Secrets the repository creates; when one is
+// not in the repository, the Kustomization's variables are unknown and it is
+// skipped. With the report-unused parameter, substitute variables no manifest
+// uses are reported as well.
+func FluxPostBuildUsageCheck(ctx *context.ValidationContext) []types.ValidationResult {
+	var results []types.ValidationResult
+
+	kustomizations := fluxObjects(ctx, "Kustomization", "kustomize.toolkit.fluxcd.io")
+	if len(kustomizations) == 0 {
+		return results
+	}
+
+	reportUnused := ctx.Config.RuleParams("flux-postbuild-variables").Bool("report-unused", false)
+
+	appliedFiles := make(map[*parser.ParsedResource][]string)
+	for file, applying := range ctx.ApplyingKustomizations() {
+		for _, kustomization := range applying {
+			appliedFiles[kustomization] = append(appliedFiles[kustomization], file)
+		}
+	}
+	deployedTo := deployedNamespaces(ctx)
+
+	for _, kustomization := range kustomizations {
+		spec, _ := kustomization.Content["spec"].(map[string]interface{})
+		postBuild, ok := spec["postBuild"].(map[string]interface{})
+		if !ok {
+			// Without postBuild, Flux substitutes nothing
+			continue
+		}
+
+		defined := make(map[string]bool)
+		substitute, _ := postBuild["substitute"].(map[string]interface{})
+		for name := range substitute {
+			defined[name] = true
+		}
+		known := true
+		substituteFrom, _ := postBuild["substituteFrom"].([]interface{})
+		for _, entry := range substituteFrom {
+			reference, _ := entry.(map[string]interface{})
+			kind, _ := reference["kind"].(string)
+			name, _ := reference["name"].(string)
+			keys, found := substitutionSourceKeys(ctx, kind, kustomization.Namespace, name, deployedTo)
+			if !found {
+				known = false
+				break
+			}
+			for _, key := range keys {
+				defined[key] = true
+			}
+		}
+
+		// Placeholders by variable, with the files using them
+		used := make(map[string][]string)
+		files := appliedFiles[kustomization]
+		sort.Strings(files)
+		for _, file := range files {
+			for _, resource := range ctx.Graph.Files[file] {
+				if resource == kustomization || substitutionDisabled(resource) {
+					continue
+				}
+				for _, variable := range substitutionVariables(resource.Content) {
+					if relative := relativeFile(ctx, file); !containsString(used[variable], relative) {
+						used[variable] = append(used[variable], relative)
+					}
+				}
+			}
+		}
+
+		add := func(resultType string, severity types.Severity, position parser.Position, message string) {
+			results = append(results, types.ValidationResult{
+				Type:     resultType,
+				Severity: severity,
+				Message:  fmt.Sprintf("Kustomization '%s' %s", kustomization.GetResourceKey(), message),
+				File:     kustomization.File,
+				Line:     position.Line,
+				Column:   position.Column,
+				Resource: kustomization.Name,
+			})
+		}
+
+		if known {
+			variables := make([]string, 0, len(used))
+			for variable := range used {
+				if !defined[variable] {
+					variables = append(variables, variable)
+				}
+			}
+			sort.Strings(variables)
+			for _, variable := range variables {
+				add("flux-postbuild-undefined-variable", types.SeverityWarning, kustomization.FieldPosition("spec", "postBuild"),
+					fmt.Sprintf("applies ${%s} in %s, which neither postBuild.substitute nor substituteFrom defines; Flux substitutes an empty string (define it, give it a default with ${%s:=value}, or escape it as $${%s})",
+						variable, strings.Join(used[variable], ", "), variable, variable))
+			}
+		}
+
+		if reportUnused {
+			var unused []string
+			for name := range substitute {
+				if _, ok := used[name]; !ok {
+					unused = append(unused, name)
+				}
+			}
+			sort.Strings(unused)
+			for _, name := range unused {
+				add("flux-postbuild-unused-variable", types.SeverityInfo, kustomization.KeyPosition("spec", "postBuild", "substitute", name),
+					fmt.Sprintf("defines postBuild.substitute variable '%s', which none of the manifests it applies use", name))
+			}
+		}
+	}
+
+	return results
+}
+
+// substitutionVariables returns the variables a resource's placeholders need
+// defined, in keys and values. Placeholders with a default or alternative
+// value (${VAR:=x}, ${VAR:-x}, ${VAR:+x}) and escaped ones are left out.
+func substitutionVariables(content interface{}) []string {
+	var variables []string
+	var walk func(value interface{})
+	scan := func(text string) {
+		for _, match := range substitutionPattern.FindAllStringSubmatch(text, -1) {
+			if strings.HasPrefix(match[0], "$$") {
+				continue
+			}
+			name := substitutionNamePattern.FindString(match[1])
+			if name == "" {
+				continue
+			}
+			operator := match[1][len(name):]
+			if strings.HasPrefix(operator, ":") {
+				operator = operator[1:]
+			}
+			if operator != "" && strings.ContainsAny(operator[:1], "=-+") {
+				continue
+			}
+			if !containsString(variables, name) {
+				variables = append(variables, name)
+			}
+		}
+	}
+	walk = func(value interface{}) {
+		switch typed := value.(type) {
+		case map[string]interface{}:
+			for key, child := range typed {
+				scan(key)
+				walk(child)
+			}
+		case []interface{}:
+			for _, child := range typed {
+				walk(child)
+			}
+		case string:
+			scan(typed)
+		}
+	}
+	walk(content)
+	return variables
+}
+
+// substitutionDisabled reports whether a resource opts out of substitution
+func substitutionDisabled(resource *parser.ParsedResource) bool {
+	metadata, _ := resource.Content["metadata"].(map[string]interface{})
+	for _, field := range []string{"annotations", "labels"} {
+		values, _ := metadata[field].(map[string]interface{})
+		if values[substitutionDisabledKey] == "disabled" {
+			return true
+		}
+	}
+	return false
+}
+
+// substitutionSourceKeys returns the keys of a substituteFrom ConfigMap or
+// Secret the repository creates in a namespace, as a manifest or through a
+// kustomize generator; found is false when the repository does not create it
+// or a generator's keys cannot be read
+func substitutionSourceKeys(ctx *context.ValidationContext, kind, namespace, name string, deployedTo map[*parser.ParsedResource][]string) (keys []string, found bool) {
+	if (kind != "ConfigMap" && kind != "Secret") || name == "" || strings.Contains(name, "${") {
+		return nil, false
+	}
+	inNamespace := func(own string, resource *parser.ParsedResource) bool {
+		return namespace == "" || own == namespace || containsString(deployedTo[resource], namespace)
+	}
+
+	for _, resource := range ctx.Graph.GetResourcesByKind(kind) {
+		if resource.Name != name || !inNamespace(resource.Namespace, resource) {
+			continue
+		}
+		found = true
+		for _, field := range []string{"data", "stringData"} {
+			values, _ := resource.Content[field].(map[string]interface{})
+			for key := range values {
+				keys = append(keys, key)
+			}
+		}
+	}
+
+	field := map[string]string{"ConfigMap": "configMapGenerator", "Secret": "secretGenerator"}[kind]
+	for _, kustomization := range ctx.Graph.GetKubernetesKustomizations() {
+		ownNamespace, _ := kustomization.Content["namespace"].(string)
+		generators, _ := kustomization.Content[field].([]interface{})
+		for _, generator := range generators {
+			entry, _ := generator.(map[string]interface{})
+			entryNamespace, _ := entry["namespace"].(string)
+			if entry["name"] != name || !(inNamespace(ownNamespace, kustomization) || entryNamespace == namespace) {
+				continue
+			}
+			generated, ok := generatorKeys(entry, filepath.Dir(kustomization.File))
+			if !ok {
+				return nil, false
+			}
+			found = true
+			keys = append(keys, generated...)
+		}
+	}
+
+	return keys, found
+}
+
+// generatorKeys returns the keys of a configMapGenerator or secretGenerator
+// entry from its literals, envs files and files; ok is false when an envs
+// file cannot be read
+func generatorKeys(entry map[string]interface{}, dir string) (keys []string, ok bool) {
+	list := func(field string) []string {
+		var values []string
+		items, _ := entry[field].([]interface{})
+		for _, item := range items {
+			if text, isText := item.(string); isText {
+				values = append(values, text)
+			}
+		}
+		return values
+	}
+
+	for _, literal := range list("literals") {
+		if key, _, hasValue := strings.Cut(literal, "="); hasValue {
+			keys = append(keys, key)
+		}
+	}
+	for _, source := range list("files") {
+		if key, _, hasKey := strings.Cut(source, "="); hasKey {
+			keys = append(keys, key)
+		} else {
+			keys = append(keys, filepath.Base(source))
+		}
+	}
+
+	envs := list("envs")
+	if env, isText := entry["env"].(string); isText {
+		envs = append(envs, env)
+	}
+	for _, env := range envs {
+		file, err := os.Open(filepath.Join(dir, env))
+		if err != nil {
+			return nil, false
+		}
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			key, _, _ := strings.Cut(line, "=")
+			keys = append(keys, strings.TrimSpace(key))
+		}
+		file.Close()
+	}
+
+	return keys, true
+}
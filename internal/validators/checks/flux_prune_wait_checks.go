@@ -0,0 +1,92 @@
+package checks
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/moon-hex/gitops-validator/internal/config"
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/parser"
+	"github.com/moon-hex/gitops-validator/internal/pathutil"
+	"github.com/moon-hex/gitops-validator/internal/types"
+)
+
+// DefaultMaxWaitResources is the largest tree a Flux Kustomization with
+// wait: true may apply when rules.flux-prune-wait.max-wait-resources is unset
+const DefaultMaxWaitResources = 50
+
+// FluxPruneWaitCheck warns about Flux Kustomizations without prune: true,
+// which leave resources removed from Git running in the cluster, and about
+// wait: true on large trees, where one slow resource holds up the whole
+// Kustomization and everything depending on it. Exceptions configured for a
+// Kustomization's file or spec.path skip either check or both.
+func FluxPruneWaitCheck(ctx *context.ValidationContext) []types.ValidationResult {
+	var results []types.ValidationResult
+
+	rule := ctx.Config.GitOpsValidator.Rules.FluxPruneWait
+	maxWaitResources := rule.MaxWaitResources
+	if maxWaitResources <= 0 {
+		maxWaitResources = DefaultMaxWaitResources
+	}
+
+	add := func(resource *parser.ParsedResource, message string) {
+		results = append(results, types.ValidationResult{
+			Type:     "flux-prune-wait",
+			Severity: types.SeverityWarning,
+			Message:  fmt.Sprintf("Kustomization '%s' %s", resource.GetResourceKey(), message),
+			File:     resource.File,
+			Line:     resource.Line,
+			Column:   resource.Column,
+			Resource: resource.Name,
+		})
+	}
+
+	for _, kustomization := range ctx.Graph.GetFluxKustomizations() {
+		spec, _ := kustomization.Content["spec"].(map[string]interface{})
+		paths := []string{relativeFile(ctx, kustomization.File)}
+		if specPath, _ := spec["path"].(string); specPath != "" {
+			paths = append(paths, strings.TrimPrefix(path.Clean("/"+strings.TrimPrefix(specPath, "./")), "/"))
+		}
+
+		if !pathExcepted(rule.Exceptions, paths, "prune") {
+			switch prune, exists := spec["prune"]; {
+			case !exists:
+				add(kustomization, "does not set prune; resources removed from Git keep running in the cluster (set prune: true)")
+			case prune != "true":
+				add(kustomization, fmt.Sprintf("sets prune: %v; resources removed from Git keep running in the cluster", prune))
+			}
+		}
+
+		if spec["wait"] == "true" && !pathExcepted(rule.Exceptions, paths, "wait") {
+			applied := 0
+			for _, deployed := range ctx.AppliedResources(kustomization) {
+				if parser.ClassifyResource(deployed.Resource) != parser.ResourceTypeKubernetesKustomization {
+					applied++
+				}
+			}
+			if applied > maxWaitResources {
+				add(kustomization, fmt.Sprintf("sets wait: true on %d resources (more than %d); one slow resource holds up the whole tree and everything depending on it, so split it, or set wait: false and list the resources that matter under healthChecks",
+					applied, maxWaitResources))
+			}
+		}
+	}
+
+	return results
+}
+
+// pathExcepted reports whether an exception matching one of paths skips
+// check, such as "prune" or "wait". An exception without checks skips all.
+func pathExcepted(exceptions []config.PathExceptionConfig, paths []string, check string) bool {
+	for _, exception := range exceptions {
+		if len(exception.Checks) > 0 && !containsString(exception.Checks, check) {
+			continue
+		}
+		for _, p := range paths {
+			if pathutil.MatchPattern(p, exception.Path) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,76 @@
+package checks
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/moon-hex/gitops-validator/internal/config"
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/types"
+)
+
+func init() {
+	config.RegisterRuleParams("flux-service-accounts", config.ParamSpec{
+		Name:        "cluster-managed-service-accounts",
+		Type:        config.ParamStringList,
+		Description: "ServiceAccounts created outside the repository, as name or namespace/name",
+	})
+}
+
+// FluxServiceAccountCheck validates spec.serviceAccountName of Flux
+// Kustomizations and HelmReleases. The controllers impersonate the
+// ServiceAccount in the object's own namespace, so in multi-tenant setups a
+// tenant whose ServiceAccount the repository does not create fails every
+// reconciliation. ServiceAccounts created on the cluster can be listed in the
+// cluster-managed-service-accounts parameter. Objects applying to a remote
+// cluster through spec.kubeConfig impersonate there and are skipped.
+func FluxServiceAccountCheck(ctx *context.ValidationContext) []types.ValidationResult {
+	var results []types.ValidationResult
+
+	objects := append(fluxObjects(ctx, "Kustomization", "kustomize.toolkit.fluxcd.io"), fluxObjects(ctx, "HelmRelease", "helm.toolkit.fluxcd.io")...)
+	if len(objects) == 0 {
+		return results
+	}
+
+	clusterManaged := ctx.Config.RuleParams("flux-service-accounts").Strings("cluster-managed-service-accounts", nil)
+
+	// ServiceAccounts by namespace/name, under the namespace in their
+	// manifest, the namespaces they are deployed to, and no namespace
+	available := make(map[string]bool)
+	deployedTo := deployedNamespaces(ctx)
+	for _, account := range ctx.Graph.GetResourcesByKind("ServiceAccount") {
+		for _, namespace := range append([]string{account.Namespace, ""}, deployedTo[account]...) {
+			available[namespace+"/"+account.Name] = true
+		}
+	}
+
+	for _, object := range objects {
+		spec, _ := object.Content["spec"].(map[string]interface{})
+		name, _ := spec["serviceAccountName"].(string)
+		if name == "" || strings.Contains(name, "${") {
+			continue
+		}
+		if _, remote := spec["kubeConfig"]; remote {
+			continue
+		}
+		if containsString(clusterManaged, name) || containsString(clusterManaged, object.Namespace+"/"+name) {
+			continue
+		}
+		if available[object.Namespace+"/"+name] {
+			continue
+		}
+
+		results = append(results, types.ValidationResult{
+			Type:     "flux-service-account",
+			Severity: types.SeverityError,
+			Message: fmt.Sprintf("%s '%s' impersonates ServiceAccount '%s', which the repository does not create in namespace '%s'; list it in cluster-managed-service-accounts if it is created on the cluster",
+				object.Kind, object.GetResourceKey(), name, object.Namespace),
+			File:     object.File,
+			Line:     object.Line,
+			Column:   object.Column,
+			Resource: object.Name,
+		})
+	}
+
+	return results
+}
@@ -0,0 +1,223 @@
+package checks
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/network"
+	"github.com/moon-hex/gitops-validator/internal/parser"
+	"github.com/moon-hex/gitops-validator/internal/semver"
+	"github.com/moon-hex/gitops-validator/internal/types"
+	"github.com/moon-hex/gitops-validator/internal/validators/common"
+)
+
+// helmIndex is the part of a Helm repository index.yaml the check reads
+type helmIndex struct {
+	Entries map[string][]struct {
+		Version string `yaml:"version"`
+	} `yaml:"entries"`
+}
+
+// HelmChartVersionCheck fetches the index.yaml of every HelmRepository that
+// HelmReleases use and verifies that the chart they name exists and that a
+// version satisfies their version constraint, so typos fail here rather than
+// when helm-controller reconciles. It only runs in online mode (--online) and
+// never while offline. OCI repositories, repositories needing credentials
+// (spec.secretRef) and values with Flux variables are skipped; an unreachable
+// index is reported as network-unavailable.
+func HelmChartVersionCheck(ctx *context.ValidationContext) []types.ValidationResult {
+	var results []types.ValidationResult
+
+	settings := ctx.Config.GitOpsValidator.Network
+	if !settings.Online || settings.Offline {
+		return results
+	}
+
+	repositories := make(map[string]*parser.ParsedResource)
+	for _, repository := range ctx.Graph.GetResourcesByKind("HelmRepository") {
+		if parser.ClassifyResource(repository) == parser.ResourceTypeFluxSource {
+			repositories[repository.Namespace+"/"+repository.Name] = repository
+		}
+	}
+
+	// Each index is fetched once, however many releases use it
+	indexes := make(map[*parser.ParsedResource]*helmIndex)
+	failed := make(map[*parser.ParsedResource]bool)
+
+	for _, release := range ctx.Graph.GetHelmReleases() {
+		spec, _ := release.Content["spec"].(map[string]interface{})
+		chart, _ := spec["chart"].(map[string]interface{})
+		chartSpec, _ := chart["spec"].(map[string]interface{})
+		sourceRef, _ := chartSpec["sourceRef"].(map[string]interface{})
+		chartName, _ := chartSpec["chart"].(string)
+		version, _ := chartSpec["version"].(string)
+		if sourceRef["kind"] != "HelmRepository" || chartName == "" || strings.Contains(chartName+version, "${") {
+			continue
+		}
+
+		repoName, _ := sourceRef["name"].(string)
+		repoNamespace, _ := sourceRef["namespace"].(string)
+		if repoNamespace == "" {
+			repoNamespace = release.Namespace
+		}
+		repository := repositories[repoNamespace+"/"+repoName]
+		if repository == nil || failed[repository] {
+			continue
+		}
+
+		index, fetched := indexes[repository]
+		if !fetched {
+			var result *types.ValidationResult
+			index, result = fetchHelmIndex(ctx, repository)
+			if result != nil {
+				results = append(results, *result)
+			}
+			if index == nil {
+				failed[repository] = true
+				continue
+			}
+			indexes[repository] = index
+		}
+
+		result := func(message string) types.ValidationResult {
+			return types.ValidationResult{
+				Type:     "helm-chart-version",
+				Severity: types.SeverityError,
+				Message:  message,
+				File:     release.File,
+				Line:     release.Line,
+				Column:   release.Column,
+				Resource: release.Name,
+			}
+		}
+
+		entries, exists := index.Entries[chartName]
+		if !exists {
+			message := fmt.Sprintf("HelmRelease '%s' uses chart '%s', which HelmRepository '%s' does not serve", release.GetResourceKey(), chartName, repository.GetResourceKey())
+			if similar := similarCharts(index, chartName); len(similar) > 0 {
+				message += fmt.Sprintf(" (did you mean %s?)", strings.Join(similar, ", "))
+			}
+			results = append(results, result(message))
+			continue
+		}
+
+		constraint, err := semver.ParseConstraint(version)
+		if err != nil {
+			results = append(results, result(fmt.Sprintf("HelmRelease '%s' has an invalid chart version: %v", release.GetResourceKey(), err)))
+			continue
+		}
+
+		var versions []semver.Version
+		matched := false
+		for _, entry := range entries {
+			parsed, err := semver.Parse(entry.Version)
+			if err != nil {
+				continue
+			}
+			versions = append(versions, parsed)
+			if constraint.Check(parsed) {
+				matched = true
+				break
+			}
+		}
+		if matched {
+			continue
+		}
+
+		sort.Slice(versions, func(i, j int) bool { return semver.Compare(versions[i], versions[j]) > 0 })
+		var latest []string
+		for _, v := range versions {
+			if len(latest) == 3 {
+				break
+			}
+			if v.Prerelease == "" {
+				latest = append(latest, v.Original)
+			}
+		}
+		message := fmt.Sprintf("HelmRelease '%s' requires chart '%s' version '%s', which HelmRepository '%s' does not serve", release.GetResourceKey(), chartName, version, repository.GetResourceKey())
+		if version == "" {
+			message = fmt.Sprintf("HelmRelease '%s' uses chart '%s' without a version, but HelmRepository '%s' serves no release version of it", release.GetResourceKey(), chartName, repository.GetResourceKey())
+		}
+		if len(latest) > 0 {
+			message += fmt.Sprintf(" (latest: %s)", strings.Join(latest, ", "))
+		}
+		results = append(results, result(message))
+	}
+
+	return results
+}
+
+// fetchHelmIndex downloads and parses the index of an HTTP(S) HelmRepository.
+// It returns no index for repositories the check skips or cannot read, along
+// with the finding to report, if any.
+func fetchHelmIndex(ctx *context.ValidationContext, repository *parser.ParsedResource) (*helmIndex, *types.ValidationResult) {
+	spec, _ := repository.Content["spec"].(map[string]interface{})
+	url, _ := spec["url"].(string)
+	if url == "" || spec["type"] == "oci" || strings.HasPrefix(url, "oci://") || spec["secretRef"] != nil || strings.Contains(url, "${") {
+		return nil, nil
+	}
+
+	indexURL := strings.TrimSuffix(url, "/") + "/index.yaml"
+	failure := func(err error) *types.ValidationResult {
+		result := common.NetworkFailureResult("helm-chart-version", "HelmRepository '"+repository.GetResourceKey()+"'", repository.File, err)
+		result.Line = repository.Line
+		result.Column = repository.Column
+		return &result
+	}
+
+	resp, err := ctx.Network.Get(indexURL)
+	if err != nil {
+		if network.IsOffline(err) {
+			return nil, nil
+		}
+		return nil, failure(err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, &types.ValidationResult{
+			Type:     "helm-chart-version",
+			Severity: types.SeverityError,
+			Message:  fmt.Sprintf("HelmRepository '%s' has no index at %s (HTTP 404); check spec.url", repository.GetResourceKey(), indexURL),
+			File:     repository.File,
+			Line:     repository.Line,
+			Column:   repository.Column,
+			Resource: repository.Name,
+		}
+	case resp.StatusCode != http.StatusOK:
+		return nil, failure(fmt.Errorf("GET %s returned %s", indexURL, resp.Status))
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, failure(err)
+	}
+	var index helmIndex
+	if err := yaml.Unmarshal(data, &index); err != nil {
+		return nil, failure(fmt.Errorf("%s is not a Helm repository index: %v", indexURL, err))
+	}
+	return &index, nil
+}
+
+// similarCharts returns the charts of an index whose names contain, or are
+// contained in, name
+func similarCharts(index *helmIndex, name string) []string {
+	var similar []string
+	for chart := range index.Entries {
+		if strings.Contains(chart, name) || strings.Contains(name, chart) {
+			similar = append(similar, "'"+chart+"'")
+		}
+	}
+	sort.Strings(similar)
+	if len(similar) > 3 {
+		similar = similar[:3]
+	}
+	return similar
+}
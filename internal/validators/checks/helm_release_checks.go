@@ -0,0 +1,210 @@
+package checks
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/parser"
+	"github.com/moon-hex/gitops-validator/internal/types"
+)
+
+// helmReleaseInstance is a HelmRelease as one cluster tree deploys it
+type helmReleaseInstance struct {
+	resource         *parser.ParsedResource
+	namespace        string // namespace of the HelmRelease object
+	releaseName      string // name of the Helm release it manages
+	storageNamespace string // namespace holding the Helm release records
+	targetNamespace  string // namespace the chart is installed into
+}
+
+// HelmReleaseCollisionCheck finds HelmReleases that manage the same Helm
+// release. Two HelmReleases collide when their effective release names match
+// in the same storage namespace (they overwrite each other's release records)
+// or in the same target namespace (their charts render the same objects).
+// HelmReleases are only compared within one cluster: the tree of a root Flux
+// Kustomization. Without Flux Kustomizations, all HelmReleases are compared.
+func HelmReleaseCollisionCheck(ctx *context.ValidationContext) []types.ValidationResult {
+	var results []types.ValidationResult
+	reported := make(map[string]bool)
+
+	for _, instances := range helmReleaseScopes(ctx) {
+		for _, group := range collidingHelmReleases(instances) {
+			for i, instance := range group.instances {
+				others := make([]string, 0, len(group.instances)-1)
+				for j, other := range group.instances {
+					if j != i {
+						others = append(others, describeHelmRelease(ctx, other))
+					}
+				}
+
+				message := fmt.Sprintf("HelmRelease '%s/%s' manages Helm release '%s' in %s, as does %s; the controllers will fight over the release",
+					instance.namespace, instance.resource.Name, instance.releaseName, group.where, strings.Join(others, ", "))
+				key := fmt.Sprintf("%s:%d:%s", instance.resource.File, instance.resource.Line, message)
+				if reported[key] {
+					continue
+				}
+				reported[key] = true
+
+				results = append(results, types.ValidationResult{
+					Type:     "helm-release-collision",
+					Severity: types.SeverityError,
+					Message:  message,
+					File:     instance.resource.File,
+					Line:     instance.resource.Line,
+					Column:   instance.resource.Column,
+					Resource: instance.resource.Name,
+				})
+			}
+		}
+	}
+
+	return results
+}
+
+// helmReleaseScopes returns the HelmReleases of every cluster tree
+func helmReleaseScopes(ctx *context.ValidationContext) [][]helmReleaseInstance {
+	roots := ctx.RootKustomizations()
+	if len(roots) == 0 {
+		var instances []helmReleaseInstance
+		for _, release := range ctx.Graph.GetHelmReleases() {
+			instances = append(instances, newHelmReleaseInstance(release, release.Namespace))
+		}
+		return [][]helmReleaseInstance{instances}
+	}
+
+	var scopes [][]helmReleaseInstance
+	for _, root := range roots {
+		var instances []helmReleaseInstance
+		for _, deployed := range ctx.DeploymentTree(root) {
+			if parser.ClassifyResource(deployed.Resource) == parser.ResourceTypeHelmRelease {
+				instances = append(instances, newHelmReleaseInstance(deployed.Resource, deployed.Namespace))
+			}
+		}
+		scopes = append(scopes, instances)
+	}
+	return scopes
+}
+
+// newHelmReleaseInstance computes the release a HelmRelease deployed to
+// namespace manages, with the defaults of the Flux helm-controller: the
+// release name is spec.releaseName, else [<targetNamespace>-]<name>, and both
+// storage and target namespace default to the HelmRelease's namespace
+func newHelmReleaseInstance(release *parser.ParsedResource, namespace string) helmReleaseInstance {
+	if namespace == "" {
+		namespace = "default"
+	}
+
+	spec, _ := release.Content["spec"].(map[string]interface{})
+	releaseName, _ := spec["releaseName"].(string)
+	storageNamespace, _ := spec["storageNamespace"].(string)
+	targetNamespace, _ := spec["targetNamespace"].(string)
+
+	if releaseName == "" {
+		releaseName = release.Name
+		if targetNamespace != "" {
+			releaseName = targetNamespace + "-" + release.Name
+		}
+	}
+	if storageNamespace == "" {
+		storageNamespace = namespace
+	}
+	if targetNamespace == "" {
+		targetNamespace = namespace
+	}
+
+	return helmReleaseInstance{
+		resource:         release,
+		namespace:        namespace,
+		releaseName:      releaseName,
+		storageNamespace: storageNamespace,
+		targetNamespace:  targetNamespace,
+	}
+}
+
+// helmReleaseCollision is a set of HelmReleases managing the same release
+type helmReleaseCollision struct {
+	where     string
+	instances []helmReleaseInstance
+}
+
+// collidingHelmReleases groups distinct HelmReleases by release name and
+// storage namespace, then by release name and target namespace. Pairs
+// already colliding on storage are not reported again for the target.
+func collidingHelmReleases(instances []helmReleaseInstance) []helmReleaseCollision {
+	var collisions []helmReleaseCollision
+	paired := make(map[[2]*parser.ParsedResource]bool)
+
+	groupings := []struct {
+		where     func(helmReleaseInstance) string
+		namespace func(helmReleaseInstance) string
+	}{
+		{
+			where:     func(i helmReleaseInstance) string { return fmt.Sprintf("storage namespace '%s'", i.storageNamespace) },
+			namespace: func(i helmReleaseInstance) string { return i.storageNamespace },
+		},
+		{
+			where:     func(i helmReleaseInstance) string { return fmt.Sprintf("target namespace '%s'", i.targetNamespace) },
+			namespace: func(i helmReleaseInstance) string { return i.targetNamespace },
+		},
+	}
+
+	for _, grouping := range groupings {
+		groups := make(map[string][]helmReleaseInstance)
+		var keys []string
+		for _, instance := range instances {
+			key := grouping.namespace(instance) + "/" + instance.releaseName
+			if !containsHelmRelease(groups[key], instance.resource) {
+				if len(groups[key]) == 0 {
+					keys = append(keys, key)
+				}
+				groups[key] = append(groups[key], instance)
+			}
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			group := groups[key]
+			if len(group) < 2 || allPaired(group, paired) {
+				continue
+			}
+			for i := range group {
+				for j := range group {
+					paired[[2]*parser.ParsedResource{group[i].resource, group[j].resource}] = true
+				}
+			}
+			collisions = append(collisions, helmReleaseCollision{where: grouping.where(group[0]), instances: group})
+		}
+	}
+
+	return collisions
+}
+
+// containsHelmRelease reports whether instances already hold the resource,
+// which a tree reaches more than once through shared bases
+func containsHelmRelease(instances []helmReleaseInstance, resource *parser.ParsedResource) bool {
+	for _, instance := range instances {
+		if instance.resource == resource {
+			return true
+		}
+	}
+	return false
+}
+
+// allPaired reports whether every pair of the group was already reported
+func allPaired(group []helmReleaseInstance, paired map[[2]*parser.ParsedResource]bool) bool {
+	for i := range group {
+		for j := range group {
+			if i != j && !paired[[2]*parser.ParsedResource{group[i].resource, group[j].resource}] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// describeHelmRelease names a HelmRelease and the file defining it
+func describeHelmRelease(ctx *context.ValidationContext, instance helmReleaseInstance) string {
+	return fmt.Sprintf("'%s/%s' (%s)", instance.namespace, instance.resource.Name, relativeFile(ctx, instance.resource.File))
+}
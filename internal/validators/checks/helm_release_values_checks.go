@@ -0,0 +1,93 @@
+package checks
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/parser"
+	"github.com/moon-hex/gitops-validator/internal/types"
+)
+
+// HelmReleaseValuesFromCheck validates spec.valuesFrom of HelmReleases. Every
+// entry must be a ConfigMap or Secret in the HelmRelease's namespace that the
+// repository creates, either as a manifest or through a kustomize generator
+// without a name hash suffix; helm-controller fails the install or upgrade
+// until it exists. A HelmRelease deployed to several namespaces needs the
+// object in each. Entries marked optional are skipped, as are names with Flux
+// variables.
+func HelmReleaseValuesFromCheck(ctx *context.ValidationContext) []types.ValidationResult {
+	var results []types.ValidationResult
+
+	releases := ctx.Graph.GetHelmReleases()
+	if len(releases) == 0 {
+		return results
+	}
+
+	available := ConfigSources(ctx)
+	deployedTo := make(map[*parser.ParsedResource][]string)
+	for _, root := range ctx.RootKustomizations() {
+		for _, deployed := range ctx.DeploymentTree(root) {
+			if parser.ClassifyResource(deployed.Resource) == parser.ResourceTypeHelmRelease && !containsString(deployedTo[deployed.Resource], deployed.Namespace) {
+				deployedTo[deployed.Resource] = append(deployedTo[deployed.Resource], deployed.Namespace)
+			}
+		}
+	}
+
+	for _, release := range releases {
+		add := func(message string) {
+			results = append(results, types.ValidationResult{
+				Type:     "helm-release-values-from",
+				Severity: types.SeverityError,
+				Message:  message,
+				File:     release.File,
+				Line:     release.Line,
+				Column:   release.Column,
+				Resource: release.Name,
+			})
+		}
+
+		spec, _ := release.Content["spec"].(map[string]interface{})
+		valuesFrom, _ := spec["valuesFrom"].([]interface{})
+
+		namespaces := deployedTo[release]
+		if len(namespaces) == 0 {
+			namespaces = []string{release.Namespace}
+		}
+
+		for i, entry := range valuesFrom {
+			reference, _ := entry.(map[string]interface{})
+			kind, _ := reference["kind"].(string)
+			name, _ := reference["name"].(string)
+
+			if kind != "ConfigMap" && kind != "Secret" {
+				add(fmt.Sprintf("valuesFrom[%d] of HelmRelease '%s' has kind '%s'; it must be ConfigMap or Secret", i, release.GetResourceKey(), kind))
+				continue
+			}
+			if name == "" {
+				add(fmt.Sprintf("valuesFrom[%d] of HelmRelease '%s' has no name", i, release.GetResourceKey()))
+				continue
+			}
+			if reference["optional"] == "true" || strings.Contains(name, "${") {
+				continue
+			}
+
+			for _, namespace := range namespaces {
+				target := name
+				if namespace != "" {
+					target = namespace + "/" + name
+				}
+				switch origin, exists := available[configSourceKey(kind, namespace, name)]; {
+				case !exists:
+					add(fmt.Sprintf("HelmRelease '%s' takes values from %s '%s', which the repository does not create; the release fails to install or upgrade until it exists (set optional: true if it is created outside the repository)",
+						release.GetResourceKey(), kind, target))
+				case origin != "":
+					add(fmt.Sprintf("HelmRelease '%s' takes values from %s '%s', which %s generates with a name hash suffix, so the name never matches (set generatorOptions.disableNameSuffixHash: true)",
+						release.GetResourceKey(), kind, target, origin))
+				}
+			}
+		}
+	}
+
+	return results
+}
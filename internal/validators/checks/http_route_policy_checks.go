@@ -54,13 +54,14 @@ func checkRouteProtection(route *parser.ParsedResource, protectedNamespaces map[
 		// Emit info rather than a spurious warning.
 		results = append(results, types.ValidationResult{
 			Type:     "http-route-policy",
-			Severity: "info",
+			Severity: types.SeverityInfo,
 			Message: fmt.Sprintf(
 				"%s '%s' has no metadata.namespace — cannot verify SecurityPolicy coverage (namespace may be injected by kustomize)",
 				kind, route.Name,
 			),
 			File:     route.File,
 			Line:     route.Line,
+			Column:   route.Column,
 			Resource: route.Name,
 		})
 		return results
@@ -69,13 +70,14 @@ func checkRouteProtection(route *parser.ParsedResource, protectedNamespaces map[
 	if !protectedNamespaces[route.Namespace] {
 		results = append(results, types.ValidationResult{
 			Type:     "http-route-policy",
-			Severity: "warning",
+			Severity: types.SeverityWarning,
 			Message: fmt.Sprintf(
 				"%s '%s' in namespace '%s' is not protected by any SecurityPolicy",
 				kind, route.Name, route.Namespace,
 			),
 			File:     route.File,
 			Line:     route.Line,
+			Column:   route.Column,
 			Resource: route.Name,
 		})
 	}
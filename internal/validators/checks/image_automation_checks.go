@@ -0,0 +1,125 @@
+package checks
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/parser"
+	"github.com/moon-hex/gitops-validator/internal/types"
+)
+
+// ImageAutomationRefCheck validates the references between the Flux image
+// automation kinds: the spec.imageRepositoryRef of every ImagePolicy must name
+// an ImageRepository, which has to grant access through spec.accessFrom when
+// it is in another namespace, and the spec.sourceRef of every
+// ImageUpdateAutomation must name a GitRepository. The controllers only report
+// a broken reference as a not-ready condition, so image updates silently
+// stop. Names with Flux variables are skipped.
+func ImageAutomationRefCheck(ctx *context.ValidationContext) []types.ValidationResult {
+	var results []types.ValidationResult
+
+	add := func(resource *parser.ParsedResource, message string) {
+		results = append(results, types.ValidationResult{
+			Type:     "image-automation-ref",
+			Severity: types.SeverityError,
+			Message:  fmt.Sprintf("%s '%s' %s", resource.Kind, resource.GetResourceKey(), message),
+			File:     resource.File,
+			Line:     resource.Line,
+			Column:   resource.Column,
+			Resource: resource.Name,
+		})
+	}
+
+	for _, policy := range imageResources(ctx, "ImagePolicy") {
+		spec, _ := policy.Content["spec"].(map[string]interface{})
+		ref, _ := spec["imageRepositoryRef"].(map[string]interface{})
+		name, _ := ref["name"].(string)
+		namespace, _ := ref["namespace"].(string)
+		if name == "" {
+			add(policy, "has no spec.imageRepositoryRef.name")
+			continue
+		}
+		if strings.Contains(name+namespace, "${") {
+			continue
+		}
+		if namespace == "" {
+			namespace = policy.Namespace
+		}
+
+		repository, others := findNamed(imageResources(ctx, "ImageRepository"), name, namespace)
+		switch {
+		case repository == nil && len(others) > 0:
+			add(policy, fmt.Sprintf("references ImageRepository '%s', which is not defined in namespace '%s' (found in %s)", name, namespace, strings.Join(others, ", ")))
+		case repository == nil:
+			add(policy, fmt.Sprintf("references ImageRepository '%s', which is not defined in this repository", name))
+		case repository.Namespace != "" && policy.Namespace != "" && repository.Namespace != policy.Namespace:
+			repoSpec, _ := repository.Content["spec"].(map[string]interface{})
+			if _, granted := repoSpec["accessFrom"]; !granted {
+				add(policy, fmt.Sprintf("references ImageRepository '%s' in namespace '%s', which does not grant access to namespace '%s' (set spec.accessFrom.namespaceSelectors on the ImageRepository)",
+					name, repository.Namespace, policy.Namespace))
+			}
+		}
+	}
+
+	for _, automation := range imageResources(ctx, "ImageUpdateAutomation") {
+		spec, _ := automation.Content["spec"].(map[string]interface{})
+		ref, _ := spec["sourceRef"].(map[string]interface{})
+		kind, _ := ref["kind"].(string)
+		name, _ := ref["name"].(string)
+		namespace, _ := ref["namespace"].(string)
+		if name == "" {
+			add(automation, "has no spec.sourceRef.name")
+			continue
+		}
+		if kind != "" && kind != "GitRepository" {
+			add(automation, fmt.Sprintf("has sourceRef kind '%s'; image update automation only supports GitRepository", kind))
+			continue
+		}
+		if strings.Contains(name+namespace, "${") {
+			continue
+		}
+		if namespace == "" {
+			namespace = automation.Namespace
+		}
+		if _, mapped := ctx.Config.GetSourceMapping(namespace, name); mapped {
+			continue
+		}
+
+		var repositories []*parser.ParsedResource
+		for _, source := range ctx.Graph.GetFluxSources() {
+			if source.Kind == "GitRepository" {
+				repositories = append(repositories, source)
+			}
+		}
+		switch repository, others := findNamed(repositories, name, namespace); {
+		case repository == nil && len(others) > 0:
+			add(automation, fmt.Sprintf("references GitRepository '%s', which is not defined in namespace '%s' (found in %s)", name, namespace, strings.Join(others, ", ")))
+		case repository == nil:
+			add(automation, fmt.Sprintf("references GitRepository '%s', which is not defined in this repository (define it, or map it to a local checkout under 'sources' in the config)", name))
+		}
+	}
+
+	return results
+}
+
+// imageResources returns the Flux image automation resources of a kind
+func imageResources(ctx *context.ValidationContext, kind string) []*parser.ParsedResource {
+	return fluxObjects(ctx, kind, "image.toolkit.fluxcd.io")
+}
+
+// findNamed returns the resource named name in namespace, or the other
+// namespaces defining one
+func findNamed(resources []*parser.ParsedResource, name, namespace string) (*parser.ParsedResource, []string) {
+	var others []string
+	for _, resource := range resources {
+		if resource.Name != name {
+			continue
+		}
+		if resource.Namespace == namespace || resource.Namespace == "" || namespace == "" {
+			return resource, nil
+		}
+		others = append(others, resource.Namespace)
+	}
+	return nil, others
+}
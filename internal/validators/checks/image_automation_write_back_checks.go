@@ -0,0 +1,114 @@
+package checks
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/parser"
+	"github.com/moon-hex/gitops-validator/internal/types"
+	"github.com/moon-hex/gitops-validator/internal/validators/common"
+)
+
+// defaultGitRepositoryBranch is the branch a GitRepository without spec.ref
+// reconciles
+const defaultGitRepositoryBranch = "master"
+
+// ImageAutomationWriteBackCheck validates where ImageUpdateAutomations write
+// their updates. spec.update.path must exist in the GitRepository, or the
+// controller fails every run. Without spec.git.push.branch the updates are
+// pushed to the branch checked out, so the checkout ref must be a branch, and
+// pushing to the branch the GitRepository reconciles without a
+// spec.git.push.refspec lands every image update on the deployed branch
+// without review. GitRepositories that are not in the repository and names
+// with Flux variables are skipped.
+func ImageAutomationWriteBackCheck(ctx *context.ValidationContext) []types.ValidationResult {
+	var results []types.ValidationResult
+
+	add := func(automation *parser.ParsedResource, severity types.Severity, message string) {
+		results = append(results, types.ValidationResult{
+			Type:     "image-automation-write-back",
+			Severity: severity,
+			Message:  fmt.Sprintf("ImageUpdateAutomation '%s' %s", automation.GetResourceKey(), message),
+			File:     automation.File,
+			Line:     automation.Line,
+			Column:   automation.Column,
+			Resource: automation.Name,
+		})
+	}
+
+	for _, automation := range imageResources(ctx, "ImageUpdateAutomation") {
+		spec, _ := automation.Content["spec"].(map[string]interface{})
+		ref, _ := spec["sourceRef"].(map[string]interface{})
+		kind, _ := ref["kind"].(string)
+		name, _ := ref["name"].(string)
+		namespace, _ := ref["namespace"].(string)
+		if name == "" || (kind != "" && kind != "GitRepository") || strings.Contains(name+namespace, "${") {
+			// Left to the image-automation-refs rule
+			continue
+		}
+		if namespace == "" {
+			namespace = automation.Namespace
+		}
+
+		// spec.update.path is relative to the root of the GitRepository
+		if update, ok := spec["update"].(map[string]interface{}); ok {
+			path, _ := update["path"].(string)
+			if path != "" && !strings.Contains(path, "${") {
+				baseDir, external := resolveSourceRoot(automation, ctx)
+				if external == "" {
+					if err := common.PathValidationCheck(baseDir, path); err != nil {
+						add(automation, types.SeverityError, fmt.Sprintf("updates spec.update.path '%s', which does not exist in GitRepository '%s'", path, name))
+					}
+				}
+			}
+		}
+
+		repository := findSourceByKindAndName(ctx, "GitRepository", name, namespace)
+		if repository == nil {
+			continue
+		}
+
+		reconciled, reconciledRef := gitRef(repository.Content, "spec", "ref")
+		if reconciled == "" && reconciledRef == "" {
+			reconciled = defaultGitRepositoryBranch
+		}
+		checkout, checkoutRef := reconciled, reconciledRef
+		if branch, other := gitRef(spec, "git", "checkout", "ref"); branch != "" || other != "" {
+			checkout, checkoutRef = branch, other
+		}
+
+		push, _ := common.ExtractStringFromContent(spec, "git", "push", "branch")
+		refspec, _ := common.ExtractStringFromContent(spec, "git", "push", "refspec")
+		if refspec != "" || strings.Contains(push+checkout+reconciled, "${") {
+			continue
+		}
+
+		target := push
+		if target == "" {
+			target = checkout
+		}
+		switch {
+		case target == "":
+			add(automation, types.SeverityError, fmt.Sprintf("checks out %s and sets no spec.git.push.branch, so there is no branch to push updates to (set spec.git.push.branch)", checkoutRef))
+		case target == reconciled:
+			add(automation, types.SeverityWarning, fmt.Sprintf("pushes image updates to branch '%s', which GitRepository '%s' reconciles, so every update is deployed without review (push to another branch with spec.git.push.branch, or set spec.git.push.refspec)",
+				target, repository.GetResourceKey()))
+		}
+	}
+
+	return results
+}
+
+// gitRef returns the branch of the Git reference at path in content, or a
+// description of the reference when it is a tag, semver range, ref name or
+// commit
+func gitRef(content map[string]interface{}, path ...string) (branch, other string) {
+	for _, field := range []string{"commit", "name", "semver", "tag"} {
+		if value, err := common.ExtractStringFromContent(content, append(path, field)...); err == nil && value != "" {
+			return "", fmt.Sprintf("%s '%s'", field, value)
+		}
+	}
+	branch, _ = common.ExtractStringFromContent(content, append(path, "branch")...)
+	return branch, ""
+}
@@ -0,0 +1,123 @@
+package checks
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/moon-hex/gitops-validator/internal/config"
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/parser"
+	"github.com/moon-hex/gitops-validator/internal/types"
+)
+
+// imagePolicyMarker matches the setter comments image-automation-controller
+// updates, e.g. # {"$imagepolicy": "flux-system:podinfo:tag"}
+var imagePolicyMarker = regexp.MustCompile(`\{\s*"\$imagepolicy"\s*:\s*"([^"]*)"\s*\}`)
+
+// imagePolicyMarkerFields are the optional third part of a marker
+var imagePolicyMarkerFields = []string{"tag", "name", "digest"}
+
+func init() {
+	config.RegisterRuleParams("image-policy-markers", config.ParamSpec{
+		Name:        "report-unused",
+		Type:        config.ParamBool,
+		Description: "warn about ImagePolicies no marker uses (default true)",
+	})
+}
+
+// ImagePolicyMarkerCheck scans every YAML file, including Helm values and
+// other files that hold no Kubernetes resources, for $imagepolicy setter
+// markers. A marker must read "<namespace>:<policy>" with an optional ":tag",
+// ":name" or ":digest", and name an ImagePolicy of the repository;
+// image-automation-controller silently skips markers it cannot resolve.
+// ImagePolicies no marker uses are reported as warnings, since nothing ever
+// applies the versions they select, unless the report-unused parameter of the
+// rule is false.
+func ImagePolicyMarkerCheck(ctx *context.ValidationContext) []types.ValidationResult {
+	var results []types.ValidationResult
+
+	policies := imageResources(ctx, "ImagePolicy")
+	used := make(map[*parser.ParsedResource]bool)
+
+	add := func(severity types.Severity, message, file string, line int) {
+		results = append(results, types.ValidationResult{
+			Type:     "image-policy-marker",
+			Severity: severity,
+			Message:  message,
+			File:     file,
+			Line:     line,
+		})
+	}
+
+	filepath.Walk(ctx.RepoPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return nil
+		}
+		lower := strings.ToLower(path)
+		if !strings.HasSuffix(lower, ".yaml") && !strings.HasSuffix(lower, ".yml") {
+			return nil
+		}
+		relPath, err := filepath.Rel(ctx.RepoPath, path)
+		if err != nil || ctx.Config.ShouldIgnorePath(relPath) {
+			return nil
+		}
+
+		file, err := os.Open(path)
+		if err != nil {
+			return nil
+		}
+		defer file.Close()
+
+		scanner := bufio.NewScanner(file)
+		for line := 1; scanner.Scan(); line++ {
+			for _, match := range imagePolicyMarker.FindAllStringSubmatch(scanner.Text(), -1) {
+				reference := match[1]
+				if strings.Contains(reference, "${") {
+					continue
+				}
+				parts := strings.Split(reference, ":")
+				if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] == "" ||
+					(len(parts) == 3 && !containsString(imagePolicyMarkerFields, parts[2])) {
+					add(types.SeverityError, fmt.Sprintf("$imagepolicy marker '%s' is malformed; it must read <namespace>:<policy> with an optional :tag, :name or :digest, and image automation skips it",
+						reference), path, line)
+					continue
+				}
+
+				policy, others := findNamed(policies, parts[1], parts[0])
+				switch {
+				case policy == nil && len(others) > 0:
+					add(types.SeverityError, fmt.Sprintf("$imagepolicy marker '%s' references ImagePolicy '%s', which is not defined in namespace '%s' (found in %s)",
+						reference, parts[1], parts[0], strings.Join(others, ", ")), path, line)
+				case policy == nil:
+					add(types.SeverityError, fmt.Sprintf("$imagepolicy marker '%s' references ImagePolicy '%s', which is not defined in this repository", reference, parts[1]), path, line)
+				default:
+					used[policy] = true
+				}
+			}
+		}
+		return nil
+	})
+
+	if !ctx.Config.RuleParams("image-policy-markers").Bool("report-unused", true) {
+		return results
+	}
+	for _, policy := range policies {
+		if !used[policy] {
+			results = append(results, types.ValidationResult{
+				Type:     "image-policy-marker",
+				Severity: types.SeverityWarning,
+				Message:  fmt.Sprintf("ImagePolicy '%s' is not used by any $imagepolicy marker; image automation never applies the versions it selects", policy.GetResourceKey()),
+				File:     policy.File,
+				Line:     policy.Line,
+				Column:   policy.Column,
+				Resource: policy.Name,
+			})
+		}
+	}
+
+	return results
+}
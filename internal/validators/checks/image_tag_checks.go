@@ -0,0 +1,234 @@
+package checks
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/moon-hex/gitops-validator/internal/config"
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/parser"
+	"github.com/moon-hex/gitops-validator/internal/types"
+)
+
+// imageUse is an image reference a workload, HelmRelease or kustomization sets
+type imageUse struct {
+	image    string
+	where    string
+	resource *parser.ParsedResource
+	// chartTag is set for Helm values without a tag, which use the chart's appVersion
+	chartTag bool
+	// skipTag and skipRegistry are set when a kustomization images entry
+	// replaces the tag or name, so the entry is checked instead
+	skipTag      bool
+	skipRegistry bool
+}
+
+// ImageTagCheck applies the image policy of the rule to the images of the
+// containers and init containers of workloads, of kustomization images
+// entries and of HelmRelease values (image strings, and image maps with
+// registry, repository, tag and digest). disallow-latest flags the latest tag
+// and missing tags, which pull latest, require-digest flags images not pinned
+// by digest, and allowed-registries limits where images come from. An images
+// entry overriding a workload's image is checked in its stead. Helm values
+// without a tag use the chart's appVersion and are not flagged as untagged,
+// and images with Flux variables are skipped.
+func ImageTagCheck(ctx *context.ValidationContext) []types.ValidationResult {
+	var results []types.ValidationResult
+
+	rule := ctx.Config.GitOpsValidator.Rules.ImageTags
+	if !rule.DisallowLatest && !rule.RequireDigest && len(rule.AllowedRegistries) == 0 {
+		return results
+	}
+
+	var uses []imageUse
+
+	// kustomization images entries, by the image name they match
+	overrideTag := make(map[string]bool)
+	overrideName := make(map[string]bool)
+	kustomizations := append([]*parser.ParsedResource(nil), ctx.Graph.GetKubernetesKustomizations()...)
+	sort.SliceStable(kustomizations, func(i, j int) bool { return kustomizations[i].File < kustomizations[j].File })
+	for _, kustomization := range kustomizations {
+		entries, _ := kustomization.Content["images"].([]interface{})
+		for _, item := range entries {
+			entry, _ := item.(map[string]interface{})
+			name, _ := entry["name"].(string)
+			newName, _ := entry["newName"].(string)
+			newTag, _ := entry["newTag"].(string)
+			digest, _ := entry["digest"].(string)
+			if name == "" {
+				continue
+			}
+			key := dockerHubName(name)
+			use := imageUse{where: fmt.Sprintf("images entry '%s' of the kustomization in %s", name, relativeFile(ctx, kustomization.File)), resource: kustomization}
+			use.image = name
+			if newName != "" {
+				use.image = newName
+				overrideName[key] = true
+			} else {
+				use.skipRegistry = true
+			}
+			switch {
+			case digest != "":
+				use.image += "@" + digest
+				overrideTag[key] = true
+			case newTag != "":
+				use.image += ":" + newTag
+				overrideTag[key] = true
+			default:
+				use.skipTag = true
+			}
+			if !use.skipTag || !use.skipRegistry {
+				uses = append(uses, use)
+			}
+		}
+	}
+
+	deployedTo := deployedNamespaces(ctx)
+	for _, workload := range workloads(ctx) {
+		namespace := workload.Namespace
+		if namespaces := deployedTo[workload]; len(namespaces) > 0 {
+			namespace = namespaces[0]
+		}
+		for _, container := range podContainers(podSpec(workload)) {
+			image, _ := container.Content["image"].(string)
+			if image == "" {
+				continue
+			}
+			repository, _, _ := splitImage(image)
+			uses = append(uses, imageUse{
+				image:        image,
+				where:        fmt.Sprintf("%s of %s '%s'", container, workload.Kind, qualifiedName(namespace, workload.Name)),
+				resource:     workload,
+				skipTag:      overrideTag[dockerHubName(repository)],
+				skipRegistry: overrideName[dockerHubName(repository)],
+			})
+		}
+	}
+
+	for _, release := range ctx.Graph.GetHelmReleases() {
+		spec, _ := release.Content["spec"].(map[string]interface{})
+		for _, use := range helmValueImages(spec["values"], "values") {
+			use.where = fmt.Sprintf("%s of HelmRelease '%s'", use.where, release.GetResourceKey())
+			use.resource = release
+			uses = append(uses, use)
+		}
+	}
+
+	for _, use := range uses {
+		if strings.Contains(use.image, "${") {
+			continue
+		}
+		for _, problem := range imagePolicyProblems(rule, use) {
+			results = append(results, types.ValidationResult{
+				Type:     "image-tag",
+				Severity: types.SeverityWarning,
+				Message:  fmt.Sprintf("%s uses image '%s', %s", use.where, use.image, problem),
+				File:     use.resource.File,
+				Line:     use.resource.Line,
+				Column:   use.resource.Column,
+				Resource: use.resource.Name,
+			})
+		}
+	}
+
+	return results
+}
+
+// imagePolicyProblems returns what is wrong with an image under the policy,
+// each as the end of a finding's message
+func imagePolicyProblems(rule config.ImageTagsRuleConfig, use imageUse) []string {
+	var problems []string
+	repository, tag, digest := splitImage(use.image)
+
+	if rule.DisallowLatest && !use.skipTag && digest == "" {
+		switch {
+		case tag == "latest":
+			problems = append(problems, "whose latest tag moves, so what runs changes without a commit (pin a version)")
+		case tag == "" && !use.chartTag:
+			problems = append(problems, "which has no tag and pulls latest, so what runs changes without a commit (pin a version)")
+		}
+	}
+	if rule.RequireDigest && !use.skipTag && digest == "" {
+		problems = append(problems, "which is not pinned by digest; tags can be pushed again (append @sha256:...)")
+	}
+	if len(rule.AllowedRegistries) > 0 && !use.skipRegistry {
+		registry := imageRegistry(repository)
+		if !registryAllowed(rule.AllowedRegistries, registry, repository) {
+			problems = append(problems, fmt.Sprintf("from registry '%s', which is not in allowed-registries (%s)",
+				registry, strings.Join(quoteAll(rule.AllowedRegistries), ", ")))
+		}
+	}
+	return problems
+}
+
+// helmValueImages returns the images set in HelmRelease values: strings
+// under an image key, and maps under one with a repository and optionally a
+// registry, tag and digest
+func helmValueImages(values interface{}, where string) []imageUse {
+	var uses []imageUse
+	switch typed := values.(type) {
+	case map[string]interface{}:
+		for _, key := range sortedKeys(typed) {
+			field := where + "." + key
+			isImage := key == "image" || strings.HasSuffix(key, "Image")
+			switch value := typed[key].(type) {
+			case string:
+				if isImage && value != "" {
+					uses = append(uses, imageUse{image: value, where: field})
+				}
+			case map[string]interface{}:
+				repository, _ := value["repository"].(string)
+				if !isImage || repository == "" {
+					uses = append(uses, helmValueImages(value, field)...)
+					continue
+				}
+				image := repository
+				if registry, _ := value["registry"].(string); registry != "" {
+					image = strings.TrimSuffix(registry, "/") + "/" + repository
+				}
+				tag, _ := value["tag"].(string)
+				if tag != "" {
+					image += ":" + tag
+				}
+				if digest, _ := value["digest"].(string); digest != "" {
+					image += "@" + digest
+				}
+				uses = append(uses, imageUse{image: image, where: field, chartTag: tag == ""})
+			default:
+				uses = append(uses, helmValueImages(value, field)...)
+			}
+		}
+	case []interface{}:
+		for i, item := range typed {
+			uses = append(uses, helmValueImages(item, fmt.Sprintf("%s[%d]", where, i))...)
+		}
+	}
+	return uses
+}
+
+// imageRegistry returns the registry of an image repository: its first
+// component when that is a host name, or docker.io
+func imageRegistry(repository string) string {
+	if first, _, found := strings.Cut(repository, "/"); found && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		return first
+	}
+	return "docker.io"
+}
+
+// registryAllowed reports whether an image repository matches one of the
+// allowed registries or glob patterns, or is under an allowed repository
+// prefix
+func registryAllowed(allowed []string, registry, repository string) bool {
+	full := repository
+	if registry == "docker.io" && !strings.HasPrefix(repository, "docker.io/") {
+		full = "docker.io/" + repository
+	}
+	for _, pattern := range allowed {
+		if matched, _ := path.Match(pattern, registry); matched || strings.HasPrefix(full, strings.TrimSuffix(pattern, "/")+"/") {
+			return true
+		}
+	}
+	return false
+}
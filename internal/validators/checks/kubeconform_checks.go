@@ -0,0 +1,208 @@
+package checks
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/parser"
+	"github.com/moon-hex/gitops-validator/internal/types"
+)
+
+// defaultSchemaLocations are used when the config lists none: the Kubernetes
+// schemas built into kubeconform and the community catalog of CRD schemas
+var defaultSchemaLocations = []string{
+	"default",
+	"https://raw.githubusercontent.com/datreeio/CRDs-catalog/main/{{.Group}}/{{.ResourceKind}}_{{.ResourceAPIVersion}}.json",
+}
+
+// kubeconformOutput is the report of kubeconform -output json, which lists
+// the resources that are not valid
+type kubeconformOutput struct {
+	Resources []struct {
+		Filename         string `json:"filename"`
+		Kind             string `json:"kind"`
+		Name             string `json:"name"`
+		Version          string `json:"version"`
+		Status           string `json:"status"`
+		Msg              string `json:"msg"`
+		ValidationErrors []struct {
+			Path string `json:"path"`
+			Msg  string `json:"msg"`
+		} `json:"validationErrors"`
+	} `json:"resources"`
+}
+
+// KubeconformCheck validates every parsed file against the JSON schemas of
+// the configured Kubernetes version and of CRDs by running kubeconform. It is
+// opt-in and needs the kubeconform binary. Resources that do not match their
+// schema are errors; resources kubeconform cannot validate, such as those
+// without a schema when report-missing-schemas is set, are warnings.
+// Kustomization files, resources with Flux variables (substituted only when
+// Flux applies them) and SOPS-encrypted resources are skipped. In offline
+// mode, schemas are read from the cache directory and local schema locations
+// only, and the check is skipped when there are none.
+func KubeconformCheck(ctx *context.ValidationContext) []types.ValidationResult {
+	var results []types.ValidationResult
+
+	rule := ctx.Config.GitOpsValidator.Rules.Kubeconform
+	if !rule.Enabled {
+		return results
+	}
+	offline := ctx.Config.GitOpsValidator.Network.Offline
+	failure := func(message string) []types.ValidationResult {
+		return append(results, types.ValidationResult{
+			Type:     "schema",
+			Severity: types.SeverityWarning,
+			Message:  message,
+		})
+	}
+
+	binary := rule.Binary
+	if binary == "" {
+		binary = "kubeconform"
+	}
+	if strings.ContainsRune(binary, filepath.Separator) && !filepath.IsAbs(binary) {
+		binary = filepath.Join(ctx.RepoPath, binary)
+	}
+	executable, err := exec.LookPath(binary)
+	if err != nil {
+		return failure(fmt.Sprintf("Schema validation is enabled but kubeconform was not found (%v); install it or set rules.kubeconform.binary", err))
+	}
+
+	locations := rule.SchemaLocations
+	if len(locations) == 0 {
+		locations = defaultSchemaLocations
+	}
+	args := []string{"-output", "json"}
+	version := rule.KubernetesVersion
+	if version == "" && ctx.Config.GitOpsValidator.KubernetesVersion != "" {
+		// kubeconform wants a patch version, which kubernetes-version may leave out
+		version = strings.TrimPrefix(ctx.Config.GitOpsValidator.KubernetesVersion, "v")
+		if strings.Count(version, ".") == 1 {
+			version += ".0"
+		}
+	}
+	if version != "" {
+		args = append(args, "-kubernetes-version", version)
+	}
+	remote := false
+	for _, location := range locations {
+		if location == "default" || strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://") {
+			remote = true
+		}
+		args = append(args, "-schema-location", location)
+	}
+	if rule.Cache != "" {
+		cache := rule.Cache
+		if !filepath.IsAbs(cache) {
+			cache = filepath.Join(ctx.RepoPath, cache)
+		}
+		if err := os.MkdirAll(cache, 0o755); err != nil {
+			return failure(fmt.Sprintf("Schema validation could not create the schema cache %s: %v", cache, err))
+		}
+		args = append(args, "-cache", cache)
+	} else if offline && remote {
+		// Without a cache every remote schema would have to be downloaded
+		return results
+	}
+	if rule.Strict {
+		args = append(args, "-strict")
+	}
+	if !rule.ReportMissingSchemas {
+		args = append(args, "-ignore-missing-schemas")
+	}
+	if len(rule.SkipKinds) > 0 {
+		args = append(args, "-skip", strings.Join(rule.SkipKinds, ","))
+	}
+
+	files := make([]string, 0, len(ctx.Graph.Files))
+	for file, resources := range ctx.Graph.Files {
+		if len(resources) > 0 {
+			files = append(files, file)
+		}
+	}
+	if len(files) == 0 {
+		return results
+	}
+	sort.Strings(files)
+
+	cmd := exec.Command(executable, append(args, files...)...)
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	stdout, err := cmd.Output()
+	var report kubeconformOutput
+	if parseErr := json.Unmarshal(stdout, &report); parseErr != nil {
+		// kubeconform exits with 1 when it finds invalid resources, which
+		// it reports on stdout like valid ones
+		var exitErr *exec.ExitError
+		if err == nil || errors.As(err, &exitErr) {
+			err = fmt.Errorf("%s", strings.TrimSpace(stderr.String()+" "+parseErr.Error()))
+		}
+		return failure(fmt.Sprintf("Schema validation failed to run kubeconform: %v", err))
+	}
+
+	if version == "" {
+		version = "latest"
+	}
+	for _, item := range report.Resources {
+		resource := kubeconformResource(ctx, item.Filename, item.Kind, item.Name)
+		if resource != nil && (parser.ClassifyResource(resource) == parser.ResourceTypeKubernetesKustomization ||
+			resource.Content["sops"] != nil || strings.Contains(fmt.Sprint(resource.Content), "${")) {
+			continue
+		}
+		result := types.ValidationResult{Type: "schema", File: item.Filename, Resource: item.Name}
+		if resource != nil {
+			result.File = resource.File
+			result.Line = resource.Line
+			result.Column = resource.Column
+		}
+
+		switch item.Status {
+		case "statusInvalid":
+			var problems []string
+			for _, problem := range item.ValidationErrors {
+				where := problem.Path
+				if where == "" {
+					where = "/"
+				}
+				problems = append(problems, fmt.Sprintf("%s: %s", where, problem.Msg))
+			}
+			if len(problems) == 0 {
+				problems = append(problems, item.Msg)
+			}
+			result.Severity = types.SeverityError
+			result.Message = fmt.Sprintf("%s '%s' does not match the schema of %s (Kubernetes %s): %s",
+				item.Kind, item.Name, item.Version, version, strings.Join(problems, "; "))
+		case "statusError":
+			if offline && strings.Contains(item.Msg, "downloading schema") {
+				continue
+			}
+			result.Severity = types.SeverityWarning
+			result.Message = fmt.Sprintf("kubeconform could not validate %s '%s': %s", item.Kind, item.Name, item.Msg)
+		default:
+			continue
+		}
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// kubeconformResource returns the parsed resource kubeconform reported, by
+// file, kind and name (which kubeconform leaves empty for unnamed resources
+// such as kustomization files), or nil when there is none
+func kubeconformResource(ctx *context.ValidationContext, file, kind, name string) *parser.ParsedResource {
+	for _, resource := range ctx.Graph.Files[file] {
+		if resource.Kind == kind && (name == "" || resource.Name == name) {
+			return resource
+		}
+	}
+	return nil
+}
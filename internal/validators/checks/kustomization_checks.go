@@ -2,45 +2,51 @@ package checks
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 
 	"github.com/moon-hex/gitops-validator/internal/context"
 	"github.com/moon-hex/gitops-validator/internal/parser"
 	"github.com/moon-hex/gitops-validator/internal/types"
 	"github.com/moon-hex/gitops-validator/internal/validators/common"
+	"gopkg.in/yaml.v3"
 )
 
 // KustomizationResourceCheck validates resource references in Kubernetes Kustomizations
 func KustomizationResourceCheck(kustomization *parser.ParsedResource, ctx *context.ValidationContext) []types.ValidationResult {
 	var results []types.ValidationResult
 
-	// Extract resources list
-	resources, err := common.ExtractStringSliceFromContent(kustomization.Content, "resources")
-	if err != nil {
-		// Resources is optional, so this is not an error
-		return results
-	}
+	// Extract resources list; resources is optional, so a missing list is not an error
+	resources, positions := kustomization.ListEntries("resources", "")
 
-	// Check for duplicates
+	// Check for duplicates, reported at the repeated entry
 	duplicates := common.DuplicateCheck(resources, "resource")
 	for resourcePath, indices := range duplicates {
 		results = append(results, types.ValidationResult{
 			Type:     "kustomization-resource",
-			Severity: "error",
+			Severity: types.SeverityError,
 			Message:  fmt.Sprintf("Duplicate resource reference: '%s' (appears at indices: %v)", resourcePath, indices),
 			File:     kustomization.File,
+			Line:     positions[indices[1]].Line,
+			Column:   positions[indices[1]].Column,
 			Resource: kustomization.Name,
 		})
 	}
 
-	// Validate each resource exists
-	baseDir := ctx.RepoPath
-	for _, resourcePath := range resources {
+	// Validate each resource exists. Entries are relative to the kustomization
+	// file itself, not the repository root.
+	baseDir := filepath.Dir(kustomization.File)
+	for i, resourcePath := range resources {
 		if err := common.FileExistenceCheck(baseDir, resourcePath); err != nil {
 			results = append(results, types.ValidationResult{
 				Type:     "kustomization-resource",
-				Severity: "error",
+				Severity: types.SeverityError,
 				Message:  fmt.Sprintf("Invalid resource reference: %s", err.Error()),
 				File:     kustomization.File,
+				Line:     positions[i].Line,
+				Column:   positions[i].Column,
 				Resource: kustomization.Name,
 			})
 		}
@@ -53,9 +59,9 @@ func KustomizationResourceCheck(kustomization *parser.ParsedResource, ctx *conte
 func KustomizationPatchCheck(kustomization *parser.ParsedResource, ctx *context.ValidationContext) []types.ValidationResult {
 	var results []types.ValidationResult
 
-	// Extract patches list
-	patches, err := common.ExtractStringSliceFromContent(kustomization.Content, "patches")
-	if err != nil {
+	// Extract patch file paths; inline patches have no path and are skipped
+	patches, positions := kustomization.ListEntries("patches", "path")
+	if len(patches) == 0 {
 		// Patches is optional, so this is not an error
 		return results
 	}
@@ -65,22 +71,26 @@ func KustomizationPatchCheck(kustomization *parser.ParsedResource, ctx *context.
 	for patchPath, indices := range duplicates {
 		results = append(results, types.ValidationResult{
 			Type:     "kustomization-patch",
-			Severity: "error",
+			Severity: types.SeverityError,
 			Message:  fmt.Sprintf("Duplicate patch reference: '%s' (appears at indices: %v)", patchPath, indices),
 			File:     kustomization.File,
+			Line:     positions[indices[1]].Line,
+			Column:   positions[indices[1]].Column,
 			Resource: kustomization.Name,
 		})
 	}
 
-	// Validate each patch exists
-	baseDir := ctx.RepoPath
-	for _, patchPath := range patches {
+	// Validate each patch exists relative to the kustomization file
+	baseDir := filepath.Dir(kustomization.File)
+	for i, patchPath := range patches {
 		if err := common.FileExistenceCheck(baseDir, patchPath); err != nil {
 			results = append(results, types.ValidationResult{
 				Type:     "kustomization-patch",
-				Severity: "error",
+				Severity: types.SeverityError,
 				Message:  fmt.Sprintf("Invalid patch reference: %s", err.Error()),
 				File:     kustomization.File,
+				Line:     positions[i].Line,
+				Column:   positions[i].Column,
 				Resource: kustomization.Name,
 			})
 		}
@@ -93,22 +103,20 @@ func KustomizationPatchCheck(kustomization *parser.ParsedResource, ctx *context.
 func KustomizationStrategicMergeCheck(kustomization *parser.ParsedResource, ctx *context.ValidationContext) []types.ValidationResult {
 	var results []types.ValidationResult
 
-	// Extract patchesStrategicMerge list
-	patches, err := common.ExtractStringSliceFromContent(kustomization.Content, "patchesStrategicMerge")
-	if err != nil {
-		// patchesStrategicMerge is optional, so this is not an error
-		return results
-	}
+	// Extract patchesStrategicMerge list; it is optional, so a missing list is not an error
+	patches, positions := kustomization.ListEntries("patchesStrategicMerge", "")
 
-	// Validate each strategic merge patch exists
-	baseDir := ctx.RepoPath
-	for _, patchPath := range patches {
+	// Validate each strategic merge patch exists relative to the kustomization file
+	baseDir := filepath.Dir(kustomization.File)
+	for i, patchPath := range patches {
 		if err := common.FileExistenceCheck(baseDir, patchPath); err != nil {
 			results = append(results, types.ValidationResult{
 				Type:     "kustomization-strategic-merge",
-				Severity: "error",
+				Severity: types.SeverityError,
 				Message:  fmt.Sprintf("Invalid strategic merge patch reference: %s", err.Error()),
 				File:     kustomization.File,
+				Line:     positions[i].Line,
+				Column:   positions[i].Column,
 				Resource: kustomization.Name,
 			})
 		}
@@ -116,3 +124,105 @@ func KustomizationStrategicMergeCheck(kustomization *parser.ParsedResource, ctx
 
 	return results
 }
+
+// KustomizationJSON6902Check validates the patchesJson6902 entries of a
+// Kubernetes Kustomization: each needs a target and a path or inline patch,
+// the path must exist relative to the kustomization file, and the target
+// must select a resource the kustomization builds, or the patch changes
+// nothing. Targets are only reported as matching nothing when every resource
+// of the tree could be read, and not in Components, whose patches apply to
+// the including kustomization.
+func KustomizationJSON6902Check(kustomization *parser.ParsedResource, ctx *context.ValidationContext) []types.ValidationResult {
+	var results []types.ValidationResult
+
+	entries, _ := kustomization.Content["patchesJson6902"].([]interface{})
+	if len(entries) == 0 {
+		// patchesJson6902 is optional, so this is not an error
+		return results
+	}
+
+	var build *kustomizationBuild
+	baseDir := filepath.Dir(kustomization.File)
+	for i, item := range entries {
+		entry := kustomization.FieldPosition("patchesJson6902", strconv.Itoa(i))
+		add := func(position parser.Position, severity types.Severity, message string) {
+			results = append(results, types.ValidationResult{
+				Type:     "kustomization-json6902",
+				Severity: severity,
+				Message:  fmt.Sprintf("patchesJson6902[%d] %s", i, message),
+				File:     kustomization.File,
+				Line:     position.Line,
+				Column:   position.Column,
+				Resource: kustomization.Name,
+			})
+		}
+
+		fields, _ := item.(map[string]interface{})
+		path, hasPath := fields["path"].(string)
+		if _, hasPatch := fields["patch"]; !hasPath && !hasPatch {
+			add(entry, types.SeverityError, "has neither path nor patch, so kustomize has no operations to apply")
+		}
+		if hasPath && common.FileExistenceCheck(baseDir, path) != nil {
+			add(kustomization.FieldPosition("patchesJson6902", strconv.Itoa(i), "path"), types.SeverityError, fmt.Sprintf("reads '%s', which does not exist relative to the kustomization", path))
+		}
+
+		target, _ := fields["target"].(map[string]interface{})
+		if kind, _ := target["kind"].(string); kind == "" {
+			add(entry, types.SeverityError, "has no target kind; kustomize needs the group, version, kind and name of the resource to patch")
+			continue
+		}
+		if kustomization.Kind == "Component" || strings.Contains(fmt.Sprint(target), "${") {
+			continue
+		}
+		if build == nil {
+			build = buildKustomization(ctx, kustomization)
+		}
+		if matched, evaluated := build.selectResources(target, nil); evaluated && build.complete && len(matched) == 0 {
+			add(kustomization.FieldPosition("patchesJson6902", strconv.Itoa(i), "target"), types.SeverityWarning, fmt.Sprintf("target %s matches no resource the kustomization builds, so the patch changes nothing", describeTarget(target)))
+		}
+	}
+
+	return results
+}
+
+// describeTarget returns a patch target for messages, with its group and
+// version, e.g. "apps/v1 Deployment 'web'"
+func describeTarget(target map[string]interface{}) string {
+	group, _ := target["group"].(string)
+	version, _ := target["version"].(string)
+	apiVersion := strings.Trim(group+"/"+version, "/")
+	if apiVersion == "" {
+		return describeSelector(target)
+	}
+	return apiVersion + " " + describeSelector(target)
+}
+
+// extractPatchPaths returns the path of every file-based entry in a list of
+// patches, patches or patchesJson6902
+func extractPatchPaths(kustomization *parser.ParsedResource, field string) []string {
+	paths, _ := kustomization.ListEntries(field, "path")
+	return paths
+}
+
+// kustomizationEntryNodes returns the YAML nodes of the entries of a list
+// field of a kustomization file, for the line numbers and scalar types the
+// parsed content does not keep; aliases and merge keys are resolved
+func kustomizationEntryNodes(file, field string) []*yaml.Node {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil
+	}
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil || len(document.Content) == 0 {
+		return nil
+	}
+	list := parser.MappingValue(document.Content[0], field)
+	if list == nil || list.Kind != yaml.SequenceNode {
+		return nil
+	}
+	entries := make([]*yaml.Node, len(list.Content))
+	for i, entry := range list.Content {
+		entries[i] = parser.ResolveAlias(entry)
+	}
+	return entries
+}
@@ -0,0 +1,153 @@
+package checks
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/parser"
+	"github.com/moon-hex/gitops-validator/internal/pathutil"
+	"github.com/moon-hex/gitops-validator/internal/types"
+)
+
+// accumulationErrorPattern matches the error kustomize reports for a file
+// resource it failed to load, then failed to load as a directory
+var accumulationErrorPattern = regexp.MustCompile(`^(.*)accumulation err='(.*)': must build at directory: '[^']*': file is not directory$`)
+
+// KustomizeBuildCheck builds, in render mode (--render), the kustomizations
+// that Flux Kustomizations apply and the kustomization files no other one
+// includes, with kustomize's own library, and reports the errors kustomize
+// build fails with. Those include problems that only show once the
+// transformers run, such as a patch whose target is not built, two resources
+// with the same ID or a field a transformer cannot set. Flux path directories
+// without a kustomization file, which Flux generates one for, and Components
+// are not built, and kustomizations pulling remote resources are only built
+// in online mode, since kustomize clones or downloads them.
+func KustomizeBuildCheck(ctx *context.ValidationContext) []types.ValidationResult {
+	var results []types.ValidationResult
+
+	if !ctx.Config.GitOpsValidator.Render {
+		return results
+	}
+	settings := ctx.Config.GitOpsValidator.Network
+	online := settings.Online && !settings.Offline
+
+	for _, kustomization := range buildRoots(ctx) {
+		if !online && kustomizationHasRemote(ctx, kustomization) {
+			continue
+		}
+		dir := filepath.Dir(kustomization.File)
+		build := ctx.Renderer.Build(dir)
+		if build.Err == nil {
+			continue
+		}
+		results = append(results, types.ValidationResult{
+			Type:     "kustomize-build",
+			Severity: types.SeverityError,
+			Message:  fmt.Sprintf("kustomize build of '%s' fails: %s", relativeFile(ctx, dir), buildError(ctx, build.Err)),
+			File:     kustomization.File,
+			Line:     kustomization.Line,
+			Column:   kustomization.Column,
+			Resource: kustomization.Name,
+		})
+	}
+
+	return results
+}
+
+// buildRoots returns the kustomizations a render builds: those in the
+// spec.path of a Flux Kustomization and those no other kustomization
+// includes, sorted by file
+func buildRoots(ctx *context.ValidationContext) []*parser.ParsedResource {
+	included := make(map[*parser.ParsedResource]bool)
+	for _, kustomization := range ctx.Graph.GetKubernetesKustomizations() {
+		for _, child := range kustomizationChildren(ctx, kustomization) {
+			included[child] = true
+		}
+	}
+
+	roots := make(map[*parser.ParsedResource]bool)
+	for _, flux := range ctx.Graph.GetFluxKustomizations() {
+		if kustomization := fluxPathKustomization(ctx, flux); kustomization != nil {
+			roots[kustomization] = true
+		}
+	}
+	for _, kustomization := range ctx.Graph.GetKubernetesKustomizations() {
+		if kustomization.Kind != "Component" && !included[kustomization] {
+			roots[kustomization] = true
+		}
+	}
+
+	sorted := make([]*parser.ParsedResource, 0, len(roots))
+	for kustomization := range roots {
+		sorted = append(sorted, kustomization)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].File < sorted[j].File })
+	return sorted
+}
+
+// fluxPathKustomization returns the kustomization file in the spec.path of a
+// Flux Kustomization, or nil when Flux generates one for the path or the path
+// has Flux variables
+func fluxPathKustomization(ctx *context.ValidationContext, flux *parser.ParsedResource) *parser.ParsedResource {
+	spec, _ := flux.Content["spec"].(map[string]interface{})
+	path, _ := spec["path"].(string)
+	if path == "" {
+		// Flux builds the root of the source without a path
+		path = "."
+	}
+	dir, ok := pathutil.Resolve(ctx.FluxRoot, path)
+	if !ok || strings.Contains(path, "${") {
+		return nil
+	}
+	for _, kustomization := range ctx.Graph.GetKubernetesKustomizations() {
+		if kustomization.Kind != "Component" && filepath.Clean(filepath.Dir(kustomization.File)) == filepath.Clean(dir) {
+			return kustomization
+		}
+	}
+	return nil
+}
+
+// kustomizationHasRemote reports whether a kustomization or one it includes
+// lists a remote resource, base or component
+func kustomizationHasRemote(ctx *context.ValidationContext, kustomization *parser.ParsedResource) bool {
+	seen := map[*parser.ParsedResource]bool{kustomization: true}
+	queue := []*parser.ParsedResource{kustomization}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, child := range kustomizationChildren(ctx, current) {
+			if !seen[child] && parser.ClassifyResource(child) == parser.ResourceTypeKubernetesKustomization {
+				seen[child] = true
+				queue = append(queue, child)
+			}
+		}
+		for _, field := range []string{"resources", "bases", "components"} {
+			entries, _ := current.Content[field].([]interface{})
+			for _, item := range entries {
+				if entry, _ := item.(string); pathutil.IsRemote(entry) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// buildError returns a kustomize build error with the absolute paths it
+// names made relative to the repository, and without the error kustomize
+// adds for a file resource after retrying it as a directory
+func buildError(ctx *context.ValidationContext, err error) string {
+	message := err.Error()
+	if match := accumulationErrorPattern.FindStringSubmatch(message); match != nil {
+		message = match[1] + match[2]
+	}
+	if root, absErr := filepath.Abs(ctx.RepoPath); absErr == nil {
+		message = strings.ReplaceAll(message, root+string(filepath.Separator), "")
+		message = strings.ReplaceAll(message, root, ".")
+	}
+	return message
+}
@@ -0,0 +1,318 @@
+package checks
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/parser"
+	"github.com/moon-hex/gitops-validator/internal/types"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	// imageTagPattern is the format of an image tag
+	imageTagPattern = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_.-]{0,127}$`)
+	// imageDigestPattern is the format of an image digest: algorithm:hex
+	imageDigestPattern = regexp.MustCompile(`^[a-z0-9]+(?:[+._-][a-z0-9]+)*:[a-fA-F0-9]{32,}$`)
+)
+
+// kustomizeImageUnknownFields are kustomization fields adding resources or
+// image fields the images check cannot see, so the image names a
+// kustomization builds are unknown
+var kustomizeImageUnknownFields = []string{"bases", "components", "helmCharts", "helmChartInflationGenerator", "generators", "transformers", "configurations", "crds", "patchesJson6902"}
+
+// KustomizeImageCheck validates the images field of kustomization files.
+// kustomize replaces the images of containers and init containers whose name
+// matches an entry; an entry matching none of the images the kustomization
+// builds is a dead override, usually left behind by a renamed image or a typo.
+// Entries must also set something to change, and newTag and digest must have
+// the format the container runtime accepts. Kustomizations whose image names
+// cannot be known, because they pull in remote or missing resources,
+// components, Helm charts or generators, only get the format checks, and
+// Components, whose images apply to the including kustomization, are skipped.
+func KustomizeImageCheck(ctx *context.ValidationContext) []types.ValidationResult {
+	var results []types.ValidationResult
+
+	built := make(map[*parser.ParsedResource]*builtImages)
+	kustomizations := ctx.Graph.GetKubernetesKustomizations()
+	sort.Slice(kustomizations, func(i, j int) bool { return kustomizations[i].File < kustomizations[j].File })
+
+	for _, kustomization := range kustomizations {
+		entries, _ := kustomization.Content["images"].([]interface{})
+		if len(entries) == 0 || kustomization.Kind == "Component" {
+			continue
+		}
+
+		nodes := kustomizationImageNodes(kustomization.File)
+		input := kustomizationInputImages(ctx, kustomization, built, nil)
+		for i, item := range entries {
+			node := imageEntryNode{line: kustomization.Line, column: kustomization.Column}
+			if i < len(nodes) {
+				node = nodes[i]
+			}
+			add := func(severity types.Severity, message string) {
+				results = append(results, types.ValidationResult{
+					Type:     "kustomize-image",
+					Severity: severity,
+					Message:  message,
+					File:     kustomization.File,
+					Line:     node.line,
+					Column:   node.column,
+					Resource: kustomization.Name,
+				})
+			}
+
+			entry, _ := item.(map[string]interface{})
+			name, _ := entry["name"].(string)
+			if name == "" {
+				add(types.SeverityError, fmt.Sprintf("images[%d] has no name, so it matches no image", i))
+				continue
+			}
+			if strings.Contains(fmt.Sprint(entry), "${") {
+				continue
+			}
+			label := fmt.Sprintf("images entry '%s'", name)
+
+			newName, _ := entry["newName"].(string)
+			newTag, hasTag := entry["newTag"]
+			digest, hasDigest := entry["digest"]
+			if newName == "" && !hasTag && !hasDigest {
+				add(types.SeverityWarning, fmt.Sprintf("%s sets none of newName, newTag and digest, so it changes nothing", label))
+			}
+
+			if hasTag {
+				tag, _ := newTag.(string)
+				switch {
+				case node.newTagType != "":
+					add(types.SeverityError, fmt.Sprintf("%s has newTag %s, which YAML reads as a %s; kustomize only accepts a string (quote it)", label, tag, node.newTagType))
+				case !imageTagPattern.MatchString(tag):
+					add(types.SeverityError, fmt.Sprintf("%s has newTag '%s', which is not a valid image tag (letters, digits, '_', '.' and '-', at most 128, not starting with '.' or '-')", label, tag))
+				}
+			}
+			if hasDigest {
+				value, _ := digest.(string)
+				if !imageDigestPattern.MatchString(value) {
+					add(types.SeverityError, fmt.Sprintf("%s has digest '%v', which is not a valid image digest (algorithm:hex, such as sha256:<64 hex digits>)", label, digest))
+				} else if hasTag {
+					add(types.SeverityWarning, fmt.Sprintf("%s sets both newTag and digest; kustomize pins the digest and drops the tag", label))
+				}
+			}
+			if newName != "" {
+				if repository, _, _ := splitImage(newName); repository != newName {
+					add(types.SeverityWarning, fmt.Sprintf("%s has newName '%s', which includes a tag or digest; kustomize keeps the image's own tag after it (set newTag or digest instead)", label, newName))
+				}
+			}
+
+			if input.known && !input.matches(name) {
+				images := input.list()
+				found := "no container images"
+				if len(images) > 0 {
+					found = "only " + strings.Join(images, ", ")
+				}
+				add(types.SeverityWarning, fmt.Sprintf("%s overrides an image none of the resources it builds use (they use %s), so the override has no effect", label, found))
+			}
+		}
+	}
+
+	return results
+}
+
+// builtImages are the image names a kustomization builds
+type builtImages struct {
+	names map[string]bool
+	// known is false when the kustomization includes resources whose images
+	// cannot be read, or contains Flux variables in image names
+	known bool
+}
+
+// matches reports whether an images entry name matches one of the images.
+// Docker Hub images match with and without their docker.io/library/ prefix.
+func (b *builtImages) matches(name string) bool {
+	for image := range b.names {
+		if dockerHubName(image) == dockerHubName(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// list returns the image names, sorted and quoted
+func (b *builtImages) list() []string {
+	var images []string
+	for image := range b.names {
+		images = append(images, fmt.Sprintf("'%s'", image))
+	}
+	sort.Strings(images)
+	return images
+}
+
+// kustomizationInputImages returns the image names of the resources and
+// patches a kustomization includes, before its own images field applies
+func kustomizationInputImages(ctx *context.ValidationContext, kustomization *parser.ParsedResource, built map[*parser.ParsedResource]*builtImages, visiting map[*parser.ParsedResource]bool) *builtImages {
+	input := &builtImages{names: make(map[string]bool), known: true}
+	for _, field := range kustomizeImageUnknownFields {
+		if _, ok := kustomization.Content[field]; ok {
+			input.known = false
+		}
+	}
+	if patches, ok := kustomization.Content["patches"].([]interface{}); ok {
+		for _, patch := range patches {
+			entry, _ := patch.(map[string]interface{})
+			if inline, _ := entry["patch"].(string); strings.Contains(inline, "image") {
+				// Inline patches are not parsed
+				input.known = false
+			}
+		}
+	}
+	if visiting == nil {
+		visiting = make(map[*parser.ParsedResource]bool)
+	}
+	visiting[kustomization] = true
+	defer delete(visiting, kustomization)
+
+	for _, dep := range kustomization.Dependencies {
+		switch dep.Type {
+		case "kustomization-resource", "kustomization-patch", "kustomization-patch-strategic":
+		default:
+			continue
+		}
+		targets := ctx.Graph.FindAllTargetResources(dep, kustomization, ctx.FluxRoot)
+		if len(targets) == 0 {
+			input.known = false
+			continue
+		}
+		for _, target := range targets {
+			if parser.ClassifyResource(target) != parser.ResourceTypeKubernetesKustomization {
+				for _, image := range containerImages(target.Content) {
+					repository, _, _ := splitImage(image)
+					input.names[repository] = true
+				}
+				continue
+			}
+			if visiting[target] {
+				input.known = false
+				continue
+			}
+			child := kustomizationOutputImages(ctx, target, built, visiting)
+			input.known = input.known && child.known
+			for name := range child.names {
+				input.names[name] = true
+			}
+		}
+	}
+
+	for name := range input.names {
+		if strings.Contains(name, "${") {
+			input.known = false
+		}
+	}
+	return input
+}
+
+// kustomizationOutputImages returns the image names a kustomization builds,
+// after its images field renamed them
+func kustomizationOutputImages(ctx *context.ValidationContext, kustomization *parser.ParsedResource, built map[*parser.ParsedResource]*builtImages, visiting map[*parser.ParsedResource]bool) *builtImages {
+	if output, ok := built[kustomization]; ok {
+		return output
+	}
+
+	input := kustomizationInputImages(ctx, kustomization, built, visiting)
+	output := &builtImages{names: make(map[string]bool), known: input.known}
+	renames := make(map[string]string)
+	entries, _ := kustomization.Content["images"].([]interface{})
+	for _, item := range entries {
+		entry, _ := item.(map[string]interface{})
+		name, _ := entry["name"].(string)
+		if newName, _ := entry["newName"].(string); name != "" && newName != "" {
+			renames[dockerHubName(name)] = newName
+		}
+	}
+	for name := range input.names {
+		if newName, ok := renames[dockerHubName(name)]; ok {
+			output.names[newName] = true
+		} else {
+			output.names[name] = true
+		}
+	}
+
+	built[kustomization] = output
+	return output
+}
+
+// containerImages returns the images of the containers and init containers
+// anywhere in a resource, as kustomize's images transformer finds them
+func containerImages(content interface{}) []string {
+	var images []string
+	switch typed := content.(type) {
+	case map[string]interface{}:
+		for key, value := range typed {
+			if list, ok := value.([]interface{}); ok && (key == "containers" || key == "initContainers") {
+				for _, item := range list {
+					container, _ := item.(map[string]interface{})
+					if image, _ := container["image"].(string); image != "" {
+						images = append(images, image)
+					}
+				}
+			}
+			images = append(images, containerImages(value)...)
+		}
+	case []interface{}:
+		for _, item := range typed {
+			images = append(images, containerImages(item)...)
+		}
+	}
+	return images
+}
+
+// splitImage splits an image reference into its repository, tag and digest
+func splitImage(image string) (repository, tag, digest string) {
+	repository, digest, _ = strings.Cut(image, "@")
+	if colon := strings.LastIndex(repository, ":"); colon > strings.LastIndex(repository, "/") {
+		repository, tag = repository[:colon], repository[colon+1:]
+	}
+	return repository, tag, digest
+}
+
+// dockerHubName returns an image repository without the Docker Hub registry
+// and library/ prefix, which the container runtime adds to short names
+func dockerHubName(repository string) string {
+	for _, prefix := range []string{"docker.io/", "index.docker.io/"} {
+		repository = strings.TrimPrefix(repository, prefix)
+	}
+	if strings.Count(repository, "/") == 1 && strings.HasPrefix(repository, "library/") {
+		repository = strings.TrimPrefix(repository, "library/")
+	}
+	return repository
+}
+
+// imageEntryNode is where an images entry of a kustomization file is
+type imageEntryNode struct {
+	line   int
+	column int
+	// newTagType is the YAML type newTag has when it is not a string
+	newTagType string
+}
+
+// kustomizationImageNodes returns the position and newTag type of each images
+// entry of a kustomization file, which the parsed content does not keep
+func kustomizationImageNodes(file string) []imageEntryNode {
+	var nodes []imageEntryNode
+	for _, entry := range kustomizationEntryNodes(file, "images") {
+		node := imageEntryNode{line: entry.Line, column: entry.Column}
+		if value := parser.MappingValue(entry, "newTag"); value != nil && value.Kind == yaml.ScalarNode {
+			switch value.Tag {
+			case "!!int", "!!float":
+				node.newTagType = "number"
+			case "!!bool":
+				node.newTagType = "boolean"
+			case "!!null":
+				node.newTagType = "null"
+			}
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
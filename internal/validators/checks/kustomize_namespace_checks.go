@@ -0,0 +1,189 @@
+package checks
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/parser"
+	"github.com/moon-hex/gitops-validator/internal/types"
+)
+
+// kustomizeClusterScopedKinds are the cluster-scoped kinds kustomize knows
+// from its built-in schema; its namespace transformer leaves them alone
+var kustomizeClusterScopedKinds = []string{
+	"APIService", "CertificateSigningRequest", "ClusterRole", "ClusterRoleBinding", "ComponentStatus",
+	"CSIDriver", "CSINode", "CustomResourceDefinition", "FlowSchema", "IngressClass",
+	"MutatingWebhookConfiguration", "Namespace", "Node", "PersistentVolume", "PodSecurityPolicy",
+	"PriorityClass", "PriorityLevelConfiguration", "RuntimeClass", "StorageClass",
+	"ValidatingAdmissionPolicy", "ValidatingAdmissionPolicyBinding", "ValidatingWebhookConfiguration",
+	"VolumeAttachment",
+}
+
+// KustomizeClusterScoped reports whether kustomize knows kind as
+// cluster-scoped and leaves it out of the namespace transformer
+func KustomizeClusterScoped(kind string) bool {
+	return containsString(kustomizeClusterScopedKinds, kind)
+}
+
+// wellKnownClusterScopedKinds are cluster-scoped custom resources of common
+// add-ons, whose CRDs are often installed from outside the repository.
+// kustomize does not know them and sets metadata.namespace on them.
+var wellKnownClusterScopedKinds = []string{
+	"ClusterIssuer", "ClusterSecretStore", "ClusterExternalSecret", "ClusterPolicy",
+	"ClusterCleanupPolicy", "ConstraintTemplate", "GatewayClass",
+}
+
+// KustomizeNamespaceCheck validates the namespace field of kustomization
+// files. kustomize writes it into every resource of the tree except the
+// cluster-scoped kinds it knows, so cluster-scoped custom resources get a
+// namespace they cannot have; those are warned about. When a kustomization
+// with a namespace is included by one setting a different namespace, the
+// outer one wins and the inner one's resources silently end up in another
+// namespace than it declares; that is an error.
+func KustomizeNamespaceCheck(ctx *context.ValidationContext) []types.ValidationResult {
+	var results []types.ValidationResult
+
+	clusterScoped := make(map[string]bool)
+	for _, crd := range ctx.Graph.GetResourcesByKind("CustomResourceDefinition") {
+		spec, _ := crd.Content["spec"].(map[string]interface{})
+		names, _ := spec["names"].(map[string]interface{})
+		if kind, _ := names["kind"].(string); kind != "" && spec["scope"] == "Cluster" {
+			clusterScoped[kind] = true
+		}
+	}
+	for _, kind := range append(append([]string(nil), wellKnownClusterScopedKinds...), ctx.Config.GitOpsValidator.Rules.KustomizeNamespaces.ClusterScopedKinds...) {
+		clusterScoped[kind] = true
+	}
+
+	included := make(map[*parser.ParsedResource]bool)
+	for _, kustomization := range ctx.Graph.GetKubernetesKustomizations() {
+		for _, child := range kustomizationChildren(ctx, kustomization) {
+			if parser.ClassifyResource(child) == parser.ResourceTypeKubernetesKustomization {
+				included[child] = true
+			}
+		}
+	}
+
+	for _, kustomization := range ctx.Graph.GetKubernetesKustomizations() {
+		namespace := kustomizationNamespace(kustomization)
+		if namespace == "" {
+			continue
+		}
+		for _, resource := range kustomizationTree(ctx, kustomization) {
+			if !clusterScoped[resource.Kind] || containsString(kustomizeClusterScopedKinds, resource.Kind) {
+				continue
+			}
+			results = append(results, types.ValidationResult{
+				Type:     "kustomize-namespace",
+				Severity: types.SeverityWarning,
+				Message: fmt.Sprintf("Kustomization sets namespace '%s', which kustomize also writes into cluster-scoped %s '%s' (%s) as it does not know the kind; remove the namespace from that resource's kustomization or move the resource out of it",
+					namespace, resource.Kind, resource.Name, relativeFile(ctx, resource.File)),
+				File:     kustomization.File,
+				Line:     kustomization.Line,
+				Column:   kustomization.Column,
+				Resource: resource.Name,
+			})
+		}
+	}
+
+	reported := make(map[string]bool)
+	isNamespaced := func(resource *parser.ParsedResource) bool {
+		return parser.ClassifyResource(resource) != parser.ResourceTypeKubernetesKustomization && !clusterScoped[resource.Kind] &&
+			!containsString(kustomizeClusterScopedKinds, resource.Kind)
+	}
+
+	var walk func(kustomization, outer *parser.ParsedResource, outerNamespace string, visiting map[*parser.ParsedResource]bool)
+	walk = func(kustomization, outer *parser.ParsedResource, outerNamespace string, visiting map[*parser.ParsedResource]bool) {
+		if visiting[kustomization] {
+			return
+		}
+		visiting[kustomization] = true
+		defer delete(visiting, kustomization)
+
+		namespace := kustomizationNamespace(kustomization)
+		if namespace != "" && outerNamespace != "" && namespace != outerNamespace {
+			namespaced := 0
+			for _, resource := range kustomizationTree(ctx, kustomization) {
+				if isNamespaced(resource) {
+					namespaced++
+				}
+			}
+			key := kustomization.File + "\x00" + outer.File
+			if namespaced > 0 && !reported[key] {
+				reported[key] = true
+				results = append(results, types.ValidationResult{
+					Type:     "kustomize-namespace",
+					Severity: types.SeverityError,
+					Message: fmt.Sprintf("Kustomization sets namespace '%s', but %s, which includes it, sets namespace '%s'; the outer namespace wins, so its namespaced resources (%d) end up in '%s'",
+						namespace, relativeFile(ctx, outer.File), outerNamespace, namespaced, outerNamespace),
+					File:   kustomization.File,
+					Line:   kustomization.Line,
+					Column: kustomization.Column,
+				})
+			}
+		}
+		if outerNamespace == "" && namespace != "" {
+			outer, outerNamespace = kustomization, namespace
+		}
+
+		for _, child := range kustomizationChildren(ctx, kustomization) {
+			if parser.ClassifyResource(child) == parser.ResourceTypeKubernetesKustomization {
+				walk(child, outer, outerNamespace, visiting)
+			}
+		}
+	}
+	for _, kustomization := range ctx.Graph.GetKubernetesKustomizations() {
+		if !included[kustomization] {
+			walk(kustomization, nil, "", make(map[*parser.ParsedResource]bool))
+		}
+	}
+
+	return results
+}
+
+// kustomizationNamespace returns the namespace field of a kustomization file,
+// or "" when it is unset or holds a Flux variable only known in-cluster
+func kustomizationNamespace(kustomization *parser.ParsedResource) string {
+	namespace, _ := kustomization.Content["namespace"].(string)
+	if strings.Contains(namespace, "${") {
+		return ""
+	}
+	return namespace
+}
+
+// kustomizationChildren returns the documents listed under a kustomization's
+// resources
+func kustomizationChildren(ctx *context.ValidationContext, kustomization *parser.ParsedResource) []*parser.ParsedResource {
+	var children []*parser.ParsedResource
+	for _, dep := range kustomization.Dependencies {
+		if dep.Type == "kustomization-resource" {
+			children = append(children, ctx.Graph.FindAllTargetResources(dep, kustomization, ctx.FluxRoot)...)
+		}
+	}
+	return children
+}
+
+// kustomizationTree returns the resources a kustomization builds, following
+// nested kustomizations; the kustomization files themselves are left out
+func kustomizationTree(ctx *context.ValidationContext, kustomization *parser.ParsedResource) []*parser.ParsedResource {
+	var tree []*parser.ParsedResource
+	seen := map[*parser.ParsedResource]bool{kustomization: true}
+	queue := []*parser.ParsedResource{kustomization}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, child := range kustomizationChildren(ctx, current) {
+			if seen[child] {
+				continue
+			}
+			seen[child] = true
+			if parser.ClassifyResource(child) == parser.ResourceTypeKubernetesKustomization {
+				queue = append(queue, child)
+				continue
+			}
+			tree = append(tree, child)
+		}
+	}
+	return tree
+}
@@ -0,0 +1,351 @@
+package checks
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/network"
+	"github.com/moon-hex/gitops-validator/internal/parser"
+	"github.com/moon-hex/gitops-validator/internal/pathutil"
+	"github.com/moon-hex/gitops-validator/internal/types"
+	"github.com/moon-hex/gitops-validator/internal/validators/common"
+)
+
+var (
+	// gitRefPattern matches a git ref advertised by a repository's info/refs
+	gitRefPattern = regexp.MustCompile(`[0-9a-f]{40} (refs/[^\x00\s^]+)`)
+	// commitPattern matches an abbreviated or full commit SHA
+	commitPattern = regexp.MustCompile(`^[0-9a-f]{7,40}$`)
+)
+
+// gitHosts are hosts whose URLs kustomize reads as git repositories, with
+// the organization and repository as the first two path segments
+var gitHosts = []string{"github.com", "gitlab.com", "bitbucket.org"}
+
+// remoteQueryParameters are the query parameters kustomize reads from a
+// remote git resource
+var remoteQueryParameters = []string{"ref", "version", "timeout", "submodules"}
+
+// movingRefs are branch names a ref commonly names, which move with every push
+var movingRefs = []string{"main", "master", "HEAD", "develop", "trunk"}
+
+// remoteTarget is a remote entry of a kustomization's resources, bases or
+// components
+type remoteTarget struct {
+	kind string // git, http or oci
+	// repository is the URL of a git repository, without the path inside it
+	repository string
+	// cloneURL is the HTTPS URL of a git repository, from which the check
+	// reads its refs in online mode; empty when it cannot be derived
+	cloneURL string
+	// file is the URL of a remote file
+	file  string
+	query url.Values
+}
+
+// KustomizeRemoteResourceCheck validates the remote entries of the
+// resources, bases and components of kustomization files, which the file
+// checks skip: git repositories, HTTP(S) files and OCI artifacts. Offline it
+// checks the URL syntax and that git repositories are pinned to a ref that
+// does not move; in online mode (--online) it also verifies that the
+// repository and ref, or the file, exist. Entries with Flux variables are
+// skipped, as are repositories needing credentials, and an unreachable host
+// is reported as network-unavailable.
+func KustomizeRemoteResourceCheck(ctx *context.ValidationContext) []types.ValidationResult {
+	var results []types.ValidationResult
+
+	settings := ctx.Config.GitOpsValidator.Network
+	online := settings.Online && !settings.Offline
+	refs := make(map[string]*remoteRefs)
+	files := make(map[string]int)
+
+	kustomizations := ctx.Graph.GetKubernetesKustomizations()
+	sort.Slice(kustomizations, func(i, j int) bool { return kustomizations[i].File < kustomizations[j].File })
+
+	for _, kustomization := range kustomizations {
+		for _, field := range []string{"resources", "bases", "components"} {
+			entries, _ := kustomization.Content[field].([]interface{})
+			for i, item := range entries {
+				entry, _ := item.(string)
+				if !pathutil.IsRemote(entry) || strings.Contains(entry, "${") {
+					continue
+				}
+				position := kustomization.FieldPosition(field, strconv.Itoa(i))
+				add := func(severity types.Severity, message string) {
+					results = append(results, types.ValidationResult{
+						Type:     "kustomize-remote-resource",
+						Severity: severity,
+						Message:  fmt.Sprintf("%s entry '%s' %s", field, entry, message),
+						File:     kustomization.File,
+						Line:     position.Line,
+						Column:   position.Column,
+						Resource: kustomization.Name,
+					})
+				}
+
+				target, err := parseRemoteTarget(entry)
+				if err != nil {
+					add(types.SeverityError, err.Error())
+					continue
+				}
+				for _, problem := range target.problems() {
+					add(problem.severity, problem.message)
+				}
+				if !online {
+					continue
+				}
+
+				switch target.kind {
+				case "git":
+					if target.cloneURL == "" {
+						continue
+					}
+					known, fetched := refs[target.cloneURL]
+					if !fetched {
+						var failure *types.ValidationResult
+						known, failure = fetchRemoteRefs(ctx, target.cloneURL, kustomization)
+						if failure != nil {
+							results = append(results, *failure)
+						}
+						refs[target.cloneURL] = known
+					}
+					if known == nil {
+						continue
+					}
+					if !known.exists {
+						add(types.SeverityError, fmt.Sprintf("names repository %s, which does not exist", target.repository))
+					} else if ref := target.ref(); ref != "" && !known.has(ref) {
+						add(types.SeverityError, fmt.Sprintf("is pinned to ref '%s', which is neither a branch nor a tag of %s", ref, target.repository))
+					}
+				case "http":
+					status, fetched := files[target.file]
+					if !fetched {
+						var failure *types.ValidationResult
+						status, failure = checkRemoteFile(ctx, target.file, kustomization)
+						if failure != nil {
+							results = append(results, *failure)
+						}
+						files[target.file] = status
+					}
+					if status == http.StatusNotFound || status == http.StatusGone {
+						add(types.SeverityError, fmt.Sprintf("does not exist (HTTP %d)", status))
+					}
+				}
+			}
+		}
+	}
+
+	return results
+}
+
+// parseRemoteTarget parses a remote entry the way kustomize does: a git
+// repository, with an optional path after // or the organization and
+// repository of a known host, or otherwise a file fetched over HTTP(S)
+func parseRemoteTarget(entry string) (*remoteTarget, error) {
+	raw := entry
+	forcedGit := false
+	if rest, ok := strings.CutPrefix(raw, "git::"); ok {
+		raw, forcedGit = rest, true
+	}
+	if strings.HasPrefix(raw, "oci://") {
+		return &remoteTarget{kind: "oci"}, nil
+	}
+	// scp-like git@host:org/repo and scheme-less host/org/repo
+	if rest, ok := strings.CutPrefix(raw, "git@"); ok {
+		host, path, found := strings.Cut(rest, ":")
+		if !found {
+			return nil, fmt.Errorf("is not a valid git URL; expected git@host:org/repo")
+		}
+		raw, forcedGit = "ssh://git@"+host+"/"+path, true
+	}
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("is not a valid URL: %v", err)
+	}
+	if parsed.Host == "" {
+		return nil, fmt.Errorf("is not a valid URL: it has no host")
+	}
+
+	target := &remoteTarget{kind: "http", query: parsed.Query()}
+	path := strings.TrimPrefix(parsed.Path, "/")
+	repository, _, hasSubdir := strings.Cut(path, "//")
+	if !hasSubdir {
+		if index := strings.Index(path, ".git/"); index >= 0 {
+			repository, hasSubdir = path[:index+len(".git")], true
+		} else if strings.HasSuffix(path, ".git") {
+			repository, hasSubdir = path, true
+		}
+	}
+	knownHost := containsString(gitHosts, parsed.Host)
+	switch {
+	case forcedGit, hasSubdir, knownHost, parsed.Scheme == "ssh", parsed.Scheme == "git":
+	default:
+		target.file = parsed.Scheme + "://" + parsed.Host + parsed.EscapedPath()
+		if parsed.RawQuery != "" {
+			target.file += "?" + parsed.RawQuery
+		}
+		return target, nil
+	}
+
+	target.kind = "git"
+	if knownHost {
+		segments := strings.SplitN(repository, "/", 3)
+		if len(segments) < 2 || segments[0] == "" || segments[1] == "" || segments[1] == ".git" {
+			return nil, fmt.Errorf("names no repository; expected %s/org/repo", parsed.Host)
+		}
+		repository = segments[0] + "/" + segments[1]
+	} else if repository == "" {
+		return nil, fmt.Errorf("names no repository")
+	}
+	target.repository = parsed.Host + "/" + strings.TrimSuffix(repository, ".git")
+	// Only the known hosts are assumed to serve SSH repositories over HTTPS too
+	switch {
+	case parsed.Scheme == "https":
+		target.cloneURL = "https://" + parsed.Host + "/" + repository
+	case parsed.Scheme == "ssh" && knownHost:
+		target.cloneURL = "https://" + parsed.Hostname() + "/" + repository
+	}
+	return target, nil
+}
+
+// ref returns the ref a git target is pinned to, from ref or the
+// deprecated version parameter
+func (t *remoteTarget) ref() string {
+	if ref := t.query.Get("ref"); ref != "" {
+		return ref
+	}
+	return t.query.Get("version")
+}
+
+// problems returns what the URL of a target tells is wrong, without
+// reaching the network
+func (t *remoteTarget) problems() []entryProblem {
+	var problems []entryProblem
+	add := func(severity types.Severity, message string) {
+		problems = append(problems, entryProblem{severity: severity, message: message})
+	}
+
+	switch t.kind {
+	case "oci":
+		add(types.SeverityError, "is an OCI artifact, which kustomize cannot load as a resource (deploy it with a Flux OCIRepository and Kustomization instead)")
+		return problems
+	case "http":
+		if strings.HasPrefix(t.file, "http://") {
+			add(types.SeverityWarning, "is fetched over plain HTTP, so its content can be changed in transit; use HTTPS")
+		}
+		return problems
+	}
+
+	var unknown []string
+	for parameter := range t.query {
+		if !containsString(remoteQueryParameters, parameter) {
+			unknown = append(unknown, parameter)
+		}
+	}
+	sort.Strings(unknown)
+	for _, parameter := range unknown {
+		add(types.SeverityWarning, fmt.Sprintf("has query parameter '%s', which kustomize ignores (it reads ref, timeout and submodules)", parameter))
+	}
+
+	switch ref := t.ref(); {
+	case ref == "":
+		add(types.SeverityWarning, fmt.Sprintf("is not pinned with ?ref=, so every build uses the default branch of %s; pin a tag or commit", t.repository))
+	case t.query.Get("ref") == "":
+		add(types.SeverityWarning, "pins its ref with ?version=, which kustomize deprecated; use ?ref=")
+	case containsString(movingRefs, ref):
+		add(types.SeverityWarning, fmt.Sprintf("is pinned to branch '%s', which moves with every push; pin a tag or commit", ref))
+	}
+	return problems
+}
+
+// remoteRefs are the branches and tags of a git repository
+type remoteRefs struct {
+	exists bool
+	refs   map[string]bool
+}
+
+// has reports whether ref is a branch, tag or full ref of the repository.
+// Commit SHAs are not advertised and are assumed to exist.
+func (r *remoteRefs) has(ref string) bool {
+	return commitPattern.MatchString(ref) || r.refs[ref] || r.refs["refs/heads/"+ref] || r.refs["refs/tags/"+ref]
+}
+
+// fetchRemoteRefs reads the refs of a git repository over the smart HTTP
+// protocol. It returns nil for repositories needing credentials or that
+// cannot be reached, along with the finding to report, if any.
+func fetchRemoteRefs(ctx *context.ValidationContext, cloneURL string, kustomization *parser.ParsedResource) (*remoteRefs, *types.ValidationResult) {
+	refsURL := strings.TrimSuffix(cloneURL, "/") + "/info/refs?service=git-upload-pack"
+	failure := func(err error) *types.ValidationResult {
+		result := common.NetworkFailureResult("kustomize-remote-resource", "git repository "+cloneURL, kustomization.File, err)
+		return &result
+	}
+
+	resp, err := ctx.Network.Get(refsURL)
+	if err != nil {
+		if network.IsOffline(err) {
+			return nil, nil
+		}
+		return nil, failure(err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return &remoteRefs{}, nil
+	case http.StatusUnauthorized, http.StatusForbidden:
+		// Private repositories cannot be read without credentials
+		return nil, nil
+	default:
+		return nil, failure(fmt.Errorf("GET %s returned %s", refsURL, resp.Status))
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, failure(err)
+	}
+	known := &remoteRefs{exists: true, refs: make(map[string]bool)}
+	for _, match := range gitRefPattern.FindAllSubmatch(data, -1) {
+		known.refs[string(match[1])] = true
+	}
+	return known, nil
+}
+
+// checkRemoteFile requests a remote file and returns the HTTP status, or 0
+// when it could not be requested, along with the finding to report, if any
+func checkRemoteFile(ctx *context.ValidationContext, file string, kustomization *parser.ParsedResource) (int, *types.ValidationResult) {
+	failure := func(err error) *types.ValidationResult {
+		result := common.NetworkFailureResult("kustomize-remote-resource", file, kustomization.File, err)
+		return &result
+	}
+
+	resp, err := ctx.Network.Get(file)
+	if err != nil {
+		if network.IsOffline(err) {
+			return 0, nil
+		}
+		return 0, failure(err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNotFound, http.StatusGone:
+		return resp.StatusCode, nil
+	case http.StatusUnauthorized, http.StatusForbidden:
+		// Files behind authentication cannot be checked
+		return resp.StatusCode, nil
+	default:
+		return 0, failure(fmt.Errorf("GET %s returned %s", file, resp.Status))
+	}
+}
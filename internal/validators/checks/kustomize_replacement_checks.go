@@ -0,0 +1,535 @@
+package checks
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/parser"
+	"github.com/moon-hex/gitops-validator/internal/types"
+	"gopkg.in/yaml.v3"
+)
+
+// kustomizeUnknownResourceFields are kustomization fields adding resources
+// the checks cannot read
+var kustomizeUnknownResourceFields = []string{"bases", "components", "helmCharts", "helmChartInflationGenerator", "generators"}
+
+// buildResource is a resource a kustomization builds, from a manifest or a
+// generator
+type buildResource struct {
+	apiVersion string
+	kind       string
+	name       string
+	// names are the name and the names namePrefix and nameSuffix give it;
+	// kustomize selectors match any of them
+	names []string
+	// namespaces are the namespaces a selector may name the resource by:
+	// its own and those the kustomizations of the tree set
+	namespaces []string
+	content    map[string]interface{}
+	// partial is set for generated resources whose data keys are unknown
+	partial bool
+}
+
+// String returns the resource for messages, e.g. "ConfigMap 'settings'"
+func (r buildResource) String() string {
+	return fmt.Sprintf("%s '%s'", r.kind, r.name)
+}
+
+// kustomizationBuild is what a kustomization builds
+type kustomizationBuild struct {
+	resources []buildResource
+	// complete is false when the kustomization tree includes resources that
+	// cannot be read: remote or missing ones, components, Helm charts or
+	// generator plugins
+	complete bool
+}
+
+// KustomizeReplacementCheck validates the replacements field of
+// kustomization files against the resources the kustomization builds. A
+// source must select exactly one resource and its fieldPath must exist, and
+// a target fieldPath must exist unless options.create is set; kustomize fails
+// the build otherwise. A target selecting no resource is not an error for
+// kustomize, but the replacement then changes nothing. Replacements loaded
+// with path are read from their file. Selectors are only reported as matching
+// nothing when every resource of the tree could be read, label and
+// annotation selectors are not evaluated, and Components, whose replacements
+// apply to the including kustomization, are skipped.
+func KustomizeReplacementCheck(ctx *context.ValidationContext) []types.ValidationResult {
+	var results []types.ValidationResult
+
+	kustomizations := ctx.Graph.GetKubernetesKustomizations()
+	sort.Slice(kustomizations, func(i, j int) bool { return kustomizations[i].File < kustomizations[j].File })
+
+	for _, kustomization := range kustomizations {
+		entries, _ := kustomization.Content["replacements"].([]interface{})
+		if len(entries) == 0 || kustomization.Kind == "Component" {
+			continue
+		}
+
+		build := buildKustomization(ctx, kustomization)
+		for i, item := range entries {
+			position := kustomization.FieldPosition("replacements", strconv.Itoa(i))
+			add := func(severity types.Severity, message string) {
+				results = append(results, types.ValidationResult{
+					Type:     "kustomize-replacement",
+					Severity: severity,
+					Message:  fmt.Sprintf("replacements[%d] %s", i, message),
+					File:     kustomization.File,
+					Line:     position.Line,
+					Column:   position.Column,
+					Resource: kustomization.Name,
+				})
+			}
+
+			entry, _ := item.(map[string]interface{})
+			replacements := []map[string]interface{}{entry}
+			if path, ok := entry["path"].(string); ok {
+				loaded, err := loadReplacements(filepath.Join(filepath.Dir(kustomization.File), path))
+				if err != nil {
+					add(types.SeverityError, fmt.Sprintf("loads '%s', which %s", path, err))
+					continue
+				}
+				replacements = loaded
+			}
+			if strings.Contains(fmt.Sprint(replacements), "${") {
+				continue
+			}
+
+			for _, replacement := range replacements {
+				for _, problem := range checkReplacement(replacement, build) {
+					add(problem.severity, problem.message)
+				}
+			}
+		}
+	}
+
+	return results
+}
+
+// entryProblem is a problem with one entry of a kustomization field, such as
+// a replacement
+type entryProblem struct {
+	severity types.Severity
+	message  string
+}
+
+// checkReplacement checks the source and targets of a replacement
+func checkReplacement(replacement map[string]interface{}, build *kustomizationBuild) []entryProblem {
+	var problems []entryProblem
+	add := func(severity types.Severity, format string, args ...interface{}) {
+		problems = append(problems, entryProblem{severity, fmt.Sprintf(format, args...)})
+	}
+
+	source, _ := replacement["source"].(map[string]interface{})
+	if source == nil {
+		add(types.SeverityError, "has no source")
+	} else if matched, evaluated := build.selectResources(source, nil); evaluated {
+		fieldPath, _ := source["fieldPath"].(string)
+		if fieldPath == "" {
+			fieldPath = "metadata.name"
+		}
+		switch {
+		case len(matched) == 0 && build.complete:
+			add(types.SeverityError, "source selects %s, which the kustomization does not build", describeSelector(source))
+		case len(matched) > 1:
+			var names []string
+			for _, resource := range matched {
+				names = append(names, resource.String())
+			}
+			add(types.SeverityError, "source selects %d resources (%s); it must select exactly one", len(matched), strings.Join(names, ", "))
+		case len(matched) == 1 && !matched[0].partial && !fieldPathExists(matched[0].content, fieldPath):
+			add(types.SeverityError, "source fieldPath '%s' does not exist in %s", fieldPath, matched[0])
+		}
+	}
+
+	targets, _ := replacement["targets"].([]interface{})
+	if len(targets) == 0 {
+		add(types.SeverityWarning, "has no targets, so it changes nothing")
+	}
+	for j, item := range targets {
+		target, _ := item.(map[string]interface{})
+		selector, _ := target["select"].(map[string]interface{})
+		rejects, _ := target["reject"].([]interface{})
+		matched, evaluated := build.selectResources(selector, rejects)
+		if !evaluated {
+			continue
+		}
+		if len(matched) == 0 {
+			if build.complete {
+				add(types.SeverityWarning, "targets[%d] selects %s, which the kustomization does not build, so it changes nothing", j, describeSelector(selector))
+			}
+			continue
+		}
+
+		options, _ := target["options"].(map[string]interface{})
+		if options["create"] == "true" {
+			continue
+		}
+		fieldPaths, _ := target["fieldPaths"].([]interface{})
+		for _, path := range fieldPaths {
+			fieldPath, _ := path.(string)
+			for _, resource := range matched {
+				if !resource.partial && !fieldPathExists(resource.content, fieldPath) {
+					add(types.SeverityError, "targets[%d] fieldPath '%s' does not exist in %s (set options.create: true to add it)", j, fieldPath, resource)
+				}
+			}
+		}
+	}
+
+	return problems
+}
+
+// loadReplacements reads the replacements of a file replacements entries
+// load with path: one replacement or a list of them
+func loadReplacements(file string) ([]map[string]interface{}, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("does not exist relative to the kustomization")
+	}
+	var content interface{}
+	if err := yaml.Unmarshal(data, &content); err != nil {
+		return nil, fmt.Errorf("is not valid YAML: %v", err)
+	}
+
+	var replacements []map[string]interface{}
+	switch typed := content.(type) {
+	case map[string]interface{}:
+		replacements = append(replacements, typed)
+	case []interface{}:
+		for _, item := range typed {
+			if replacement, ok := item.(map[string]interface{}); ok {
+				replacements = append(replacements, replacement)
+			}
+		}
+	}
+	// Scalars are compared as strings, as the parsed manifests hold them
+	for _, replacement := range replacements {
+		stringifyScalars(replacement)
+	}
+	return replacements, nil
+}
+
+// stringifyScalars replaces the non-string scalars of decoded YAML by their
+// text, in place
+func stringifyScalars(value interface{}) {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		for key, child := range typed {
+			switch child.(type) {
+			case map[string]interface{}, []interface{}, string, nil:
+				stringifyScalars(child)
+			default:
+				typed[key] = fmt.Sprint(child)
+			}
+		}
+	case []interface{}:
+		for i, child := range typed {
+			switch child.(type) {
+			case map[string]interface{}, []interface{}, string, nil:
+				stringifyScalars(child)
+			default:
+				typed[i] = fmt.Sprint(child)
+			}
+		}
+	}
+}
+
+// buildKustomization returns the resources a kustomization builds: the
+// manifests of its tree and the ConfigMaps and Secrets its generators create
+func buildKustomization(ctx *context.ValidationContext, kustomization *parser.ParsedResource) *kustomizationBuild {
+	build := &kustomizationBuild{complete: true}
+
+	// A kustomization's namePrefix and nameSuffix, and those of the
+	// kustomizations including it, innermost first
+	type queued struct {
+		kustomization *parser.ParsedResource
+		renames       [][2]string
+	}
+
+	var namespaces []string
+	seen := map[*parser.ParsedResource]bool{kustomization: true}
+	generated := make(map[string]int)
+	queue := []queued{{kustomization: kustomization}}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		content := current.kustomization.Content
+
+		for _, field := range kustomizeUnknownResourceFields {
+			if _, ok := content[field]; ok {
+				build.complete = false
+			}
+		}
+		if namespace := kustomizationNamespace(current.kustomization); namespace != "" && !containsString(namespaces, namespace) {
+			namespaces = append(namespaces, namespace)
+		}
+		prefix, _ := content["namePrefix"].(string)
+		suffix, _ := content["nameSuffix"].(string)
+		renames := append([][2]string{{prefix, suffix}}, current.renames...)
+
+		// Generators with behavior merge or replace change a ConfigMap or
+		// Secret an included kustomization generates
+		for _, resource := range generatedResources(current.kustomization) {
+			key := resource.kind + "/" + resource.name
+			if index, ok := generated[key]; ok {
+				existing := &build.resources[index]
+				data, _ := existing.content["data"].(map[string]interface{})
+				for dataKey := range resource.content["data"].(map[string]interface{}) {
+					data[dataKey] = ""
+				}
+				existing.partial = existing.partial || resource.partial
+				continue
+			}
+			resource.names = renamed(resource.name, renames)
+			generated[key] = len(build.resources)
+			build.resources = append(build.resources, resource)
+		}
+
+		for _, dep := range current.kustomization.Dependencies {
+			if dep.Type != "kustomization-resource" {
+				continue
+			}
+			targets := ctx.Graph.FindAllTargetResources(dep, current.kustomization, ctx.FluxRoot)
+			if len(targets) == 0 {
+				build.complete = false
+			}
+			for _, target := range targets {
+				if seen[target] {
+					continue
+				}
+				seen[target] = true
+				if parser.ClassifyResource(target) == parser.ResourceTypeKubernetesKustomization {
+					queue = append(queue, queued{kustomization: target, renames: renames})
+					continue
+				}
+				build.resources = append(build.resources, buildResource{
+					apiVersion: target.APIVersion,
+					kind:       target.Kind,
+					name:       target.Name,
+					names:      renamed(target.Name, renames),
+					namespaces: []string{target.Namespace},
+					content:    target.Content,
+				})
+			}
+		}
+	}
+
+	for i := range build.resources {
+		for _, namespace := range namespaces {
+			if !containsString(build.resources[i].namespaces, namespace) {
+				build.resources[i].namespaces = append(build.resources[i].namespaces, namespace)
+			}
+		}
+	}
+	return build
+}
+
+// renamed returns a name and the names it gets as the namePrefix and
+// nameSuffix of the including kustomizations apply, innermost first
+func renamed(name string, renames [][2]string) []string {
+	names := []string{name}
+	for i := len(renames) - 1; i >= 0; i-- {
+		name = renames[i][0] + name + renames[i][1]
+		if !containsString(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// generatedResources returns the ConfigMaps and Secrets the generators of a
+// kustomization create, with the keys of their data
+func generatedResources(kustomization *parser.ParsedResource) []buildResource {
+	var resources []buildResource
+	for _, generator := range []struct{ field, kind string }{{"configMapGenerator", "ConfigMap"}, {"secretGenerator", "Secret"}} {
+		entries, _ := kustomization.Content[generator.field].([]interface{})
+		for _, item := range entries {
+			entry, _ := item.(map[string]interface{})
+			name, _ := entry["name"].(string)
+			if name == "" {
+				continue
+			}
+			namespace, _ := entry["namespace"].(string)
+			keys, ok := generatorKeys(entry, filepath.Dir(kustomization.File))
+			data := make(map[string]interface{})
+			for _, key := range keys {
+				data[key] = ""
+			}
+			resources = append(resources, buildResource{
+				apiVersion: "v1",
+				kind:       generator.kind,
+				name:       name,
+				namespaces: []string{namespace},
+				content: map[string]interface{}{
+					"apiVersion": "v1",
+					"kind":       generator.kind,
+					"metadata":   map[string]interface{}{"name": name, "namespace": namespace},
+					"data":       data,
+				},
+				partial: !ok,
+			})
+		}
+	}
+	return resources
+}
+
+// selectResources returns the resources a selector matches and a reject list
+// does not; evaluated is false for selectors the check cannot evaluate
+func (b *kustomizationBuild) selectResources(selector map[string]interface{}, rejects []interface{}) (matched []buildResource, evaluated bool) {
+	if selector == nil || selector["labelSelector"] != nil || selector["annotationSelector"] != nil {
+		return nil, false
+	}
+	for _, resource := range b.resources {
+		if !resource.matches(selector) {
+			continue
+		}
+		rejected := false
+		for _, item := range rejects {
+			reject, _ := item.(map[string]interface{})
+			if reject["labelSelector"] != nil || reject["annotationSelector"] != nil {
+				return nil, false
+			}
+			if len(reject) > 0 && resource.matches(reject) {
+				rejected = true
+			}
+		}
+		if !rejected {
+			matched = append(matched, resource)
+		}
+	}
+	return matched, true
+}
+
+// matches reports whether a resource matches the group, version, kind, name
+// and namespace of a selector. kustomize matches each as an anchored regular
+// expression.
+func (r buildResource) matches(selector map[string]interface{}) bool {
+	group, version := "", r.apiVersion
+	if slash := strings.Index(r.apiVersion, "/"); slash >= 0 {
+		group, version = r.apiVersion[:slash], r.apiVersion[slash+1:]
+	}
+	candidates := map[string][]string{
+		"group":     {group},
+		"version":   {version},
+		"kind":      {r.kind},
+		"name":      r.names,
+		"namespace": r.namespaces,
+	}
+	for field, values := range candidates {
+		wanted, _ := selector[field].(string)
+		if wanted == "" {
+			continue
+		}
+		pattern, err := regexp.Compile("^(?:" + wanted + ")$")
+		matched := false
+		for _, value := range values {
+			if value == wanted || (err == nil && pattern.MatchString(value)) {
+				matched = true
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// describeSelector returns a selector for messages, e.g. "ConfigMap 'settings'"
+func describeSelector(selector map[string]interface{}) string {
+	kind, _ := selector["kind"].(string)
+	if kind == "" {
+		kind = "any kind"
+	}
+	description := kind
+	if name, _ := selector["name"].(string); name != "" {
+		description += fmt.Sprintf(" '%s'", name)
+	}
+	if namespace, _ := selector["namespace"].(string); namespace != "" {
+		description += fmt.Sprintf(" in namespace '%s'", namespace)
+	}
+	return description
+}
+
+// fieldPathExists reports whether a kustomize fieldPath, such as
+// spec.template.spec.containers.[name=web].image, exists in content. A *
+// segment matches every entry, and exists when one of them has the rest.
+func fieldPathExists(content interface{}, fieldPath string) bool {
+	segments := splitFieldPath(fieldPath)
+	var exists func(value interface{}, segments []string) bool
+	exists = func(value interface{}, segments []string) bool {
+		if len(segments) == 0 {
+			return true
+		}
+		segment, rest := segments[0], segments[1:]
+		switch typed := value.(type) {
+		case map[string]interface{}:
+			if segment == "*" {
+				for _, child := range typed {
+					if exists(child, rest) {
+						return true
+					}
+				}
+				return false
+			}
+			child, ok := typed[strings.TrimSuffix(strings.TrimPrefix(segment, "["), "]")]
+			return ok && exists(child, rest)
+		case []interface{}:
+			if segment == "*" {
+				for _, child := range typed {
+					if exists(child, rest) {
+						return true
+					}
+				}
+				return false
+			}
+			if index, err := strconv.Atoi(strings.Trim(segment, "[]")); err == nil {
+				return index >= 0 && index < len(typed) && exists(typed[index], rest)
+			}
+			key, value, ok := strings.Cut(strings.TrimSuffix(strings.TrimPrefix(segment, "["), "]"), "=")
+			if !ok {
+				return false
+			}
+			for _, child := range typed {
+				if key == "" {
+					if child == value {
+						return exists(child, rest)
+					}
+					continue
+				}
+				if fields, isMap := child.(map[string]interface{}); isMap && fields[key] == value {
+					return exists(child, rest)
+				}
+			}
+			return false
+		default:
+			return false
+		}
+	}
+	return exists(content, segments)
+}
+
+// splitFieldPath splits a fieldPath at the dots outside brackets
+func splitFieldPath(fieldPath string) []string {
+	var segments []string
+	depth, start := 0, 0
+	for i, char := range fieldPath {
+		switch char {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case '.':
+			if depth == 0 {
+				segments = append(segments, fieldPath[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(segments, fieldPath[start:])
+}
@@ -0,0 +1,176 @@
+package checks
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/parser"
+	"github.com/moon-hex/gitops-validator/internal/pathutil"
+	"github.com/moon-hex/gitops-validator/internal/types"
+)
+
+// LabelPolicyCheck applies the label and annotation policies of the rule to
+// the resources they select by kind and file: each required key must be set,
+// and its value must match the policy's regular expression, if any. Labels and
+// annotations added by the kustomizations building a resource (commonLabels,
+// labels, commonAnnotations) and by the Flux Kustomizations applying it
+// (spec.commonMetadata) count as set. Kustomization files are not checked,
+// and values with Flux variables are not matched.
+func LabelPolicyCheck(ctx *context.ValidationContext) []types.ValidationResult {
+	var results []types.ValidationResult
+
+	policies := ctx.Config.GitOpsValidator.Rules.LabelPolicies.Policies
+	if len(policies) == 0 {
+		return results
+	}
+
+	// Metadata the including kustomizations add, by resource
+	added := map[string]map[*parser.ParsedResource]map[string]string{
+		"labels":      make(map[*parser.ParsedResource]map[string]string),
+		"annotations": make(map[*parser.ParsedResource]map[string]string),
+	}
+	add := func(field string, resource *parser.ParsedResource, values map[string]interface{}) {
+		for key, value := range values {
+			if added[field][resource] == nil {
+				added[field][resource] = make(map[string]string)
+			}
+			added[field][resource][key], _ = value.(string)
+		}
+	}
+	for _, kustomization := range ctx.Graph.GetKubernetesKustomizations() {
+		commonLabels, _ := kustomization.Content["commonLabels"].(map[string]interface{})
+		commonAnnotations, _ := kustomization.Content["commonAnnotations"].(map[string]interface{})
+		entries, _ := kustomization.Content["labels"].([]interface{})
+		if len(commonLabels) == 0 && len(commonAnnotations) == 0 && len(entries) == 0 {
+			continue
+		}
+		for _, resource := range kustomizationTree(ctx, kustomization) {
+			add("labels", resource, commonLabels)
+			add("annotations", resource, commonAnnotations)
+			for _, item := range entries {
+				entry, _ := item.(map[string]interface{})
+				pairs, _ := entry["pairs"].(map[string]interface{})
+				add("labels", resource, pairs)
+			}
+		}
+	}
+	for _, kustomization := range ctx.Graph.GetFluxKustomizations() {
+		spec, _ := kustomization.Content["spec"].(map[string]interface{})
+		metadata, _ := spec["commonMetadata"].(map[string]interface{})
+		if len(metadata) == 0 {
+			continue
+		}
+		labels, _ := metadata["labels"].(map[string]interface{})
+		annotations, _ := metadata["annotations"].(map[string]interface{})
+		for _, deployed := range ctx.AppliedResources(kustomization) {
+			add("labels", deployed.Resource, labels)
+			add("annotations", deployed.Resource, annotations)
+		}
+	}
+
+	// Value patterns match whole values; the config validation rejects invalid ones
+	compiled := make(map[string]*regexp.Regexp)
+	for _, policy := range policies {
+		for _, patterns := range []map[string]string{policy.Labels, policy.Annotations} {
+			for _, pattern := range patterns {
+				if expression, err := regexp.Compile("^(?:" + pattern + ")$"); err == nil {
+					compiled[pattern] = expression
+				}
+			}
+		}
+	}
+
+	files := make([]string, 0, len(ctx.Graph.Files))
+	for file := range ctx.Graph.Files {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	deployedTo := deployedNamespaces(ctx)
+
+	for _, file := range files {
+		relative := relativeFile(ctx, file)
+		for _, resource := range ctx.Graph.Files[file] {
+			if resource.Kind == "" || parser.ClassifyResource(resource) == parser.ResourceTypeKubernetesKustomization {
+				continue
+			}
+			namespace := resource.Namespace
+			if namespaces := deployedTo[resource]; len(namespaces) > 0 {
+				namespace = namespaces[0]
+			}
+			metadata, _ := resource.Content["metadata"].(map[string]interface{})
+
+			for i, policy := range policies {
+				if len(policy.Kinds) > 0 && !containsString(policy.Kinds, resource.Kind) {
+					continue
+				}
+				if len(policy.Paths) > 0 && !matchesAnyPath(relative, policy.Paths) {
+					continue
+				}
+				name := policy.Name
+				if name == "" {
+					name = fmt.Sprintf("policies[%d]", i)
+				}
+				because := fmt.Sprintf("required by label policy '%s'", name)
+				if policy.Message != "" {
+					because += ": " + policy.Message
+				}
+
+				for _, field := range []string{"labels", "annotations"} {
+					required := policy.Labels
+					if field == "annotations" {
+						required = policy.Annotations
+					}
+					values, _ := metadata[field].(map[string]interface{})
+					keys := make([]string, 0, len(required))
+					for key := range required {
+						keys = append(keys, key)
+					}
+					sort.Strings(keys)
+					for _, key := range keys {
+						value, set := values[key]
+						text, _ := value.(string)
+						if !set {
+							text, set = added[field][resource][key]
+						}
+						noun := strings.TrimSuffix(field, "s")
+
+						var problem string
+						switch pattern := required[key]; {
+						case !set:
+							problem = fmt.Sprintf("has no %s '%s'", noun, key)
+						case pattern != "" && !strings.Contains(text, "${") && compiled[pattern] != nil && !compiled[pattern].MatchString(text):
+							problem = fmt.Sprintf("has %s '%s' set to '%s', which does not match '%s'", noun, key, text, pattern)
+						default:
+							continue
+						}
+						results = append(results, types.ValidationResult{
+							Type:     "label-policy",
+							Severity: types.SeverityWarning,
+							Message:  fmt.Sprintf("%s '%s' %s (%s)", resource.Kind, qualifiedName(namespace, resource.Name), problem, because),
+							File:     resource.File,
+							Line:     resource.Line,
+							Column:   resource.Column,
+							Resource: resource.Name,
+						})
+					}
+				}
+			}
+		}
+	}
+
+	return results
+}
+
+// matchesAnyPath reports whether a file relative to the repository root
+// matches one of the globs
+func matchesAnyPath(file string, globs []string) bool {
+	for _, glob := range globs {
+		if pathutil.MatchPattern(file, glob) {
+			return true
+		}
+	}
+	return false
+}
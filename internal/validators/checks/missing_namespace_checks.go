@@ -0,0 +1,152 @@
+package checks
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/parser"
+	"github.com/moon-hex/gitops-validator/internal/types"
+)
+
+// fluxNamespace is the namespace Flux bootstrap installs Flux into
+const fluxNamespace = "flux-system"
+
+// namespaceUse is a resource applied to a namespace
+type namespaceUse struct {
+	namespace string
+	resource  *parser.ParsedResource
+}
+
+// MissingNamespaceCheck flags namespaces resources are applied to that no
+// Namespace manifest in the repository creates, nor a HelmRelease with
+// spec.install.createNamespace. Resources a Flux Kustomization applies are
+// in the namespace the kustomization namespace fields and spec.targetNamespace
+// move them to; the others are in their metadata.namespace, or the namespace
+// of the kustomization including them. Cluster-scoped kinds, values with Flux
+// variables, the built-in namespaces and flux-system are skipped, as are
+// spec.targetNamespace values, which TargetNamespaceCheck covers. Namespaces
+// created outside the repository are declared with the rule's allowed list.
+// Each namespace is reported once, at its first use.
+func MissingNamespaceCheck(ctx *context.ValidationContext) []types.ValidationResult {
+	var results []types.ValidationResult
+
+	defined := make(map[string]bool)
+	for _, namespace := range ctx.Graph.GetResourcesByKind("Namespace") {
+		defined[namespace.Name] = true
+	}
+	for _, release := range ctx.Graph.GetHelmReleases() {
+		spec, _ := release.Content["spec"].(map[string]interface{})
+		if install, ok := spec["install"].(map[string]interface{}); ok && install["createNamespace"] == "true" {
+			namespace, _ := spec["targetNamespace"].(string)
+			if namespace == "" {
+				namespace = release.Namespace
+			}
+			defined[namespace] = true
+		}
+	}
+	// Missing target namespaces are reported by TargetNamespaceCheck
+	for _, resource := range append(ctx.Graph.GetFluxKustomizations(), ctx.Graph.GetHelmReleases()...) {
+		spec, _ := resource.Content["spec"].(map[string]interface{})
+		if namespace, _ := spec["targetNamespace"].(string); namespace != "" {
+			defined[namespace] = true
+		}
+	}
+	allowed := append(append([]string(nil), builtinNamespaces...), fluxNamespace)
+	allowed = append(allowed, ctx.Config.GitOpsValidator.Rules.MissingNamespaces.Allowed...)
+
+	clusterScoped := make(map[string]bool)
+	for _, crd := range ctx.Graph.GetResourcesByKind("CustomResourceDefinition") {
+		spec, _ := crd.Content["spec"].(map[string]interface{})
+		names, _ := spec["names"].(map[string]interface{})
+		if kind, _ := names["kind"].(string); kind != "" && spec["scope"] == "Cluster" {
+			clusterScoped[kind] = true
+		}
+	}
+	for _, kind := range append(append(append([]string(nil), kustomizeClusterScopedKinds...), wellKnownClusterScopedKinds...), ctx.Config.GitOpsValidator.Rules.KustomizeNamespaces.ClusterScopedKinds...) {
+		clusterScoped[kind] = true
+	}
+
+	var uses []namespaceUse
+	applied := make(map[*parser.ParsedResource]bool)
+	for _, kustomization := range ctx.Graph.GetFluxKustomizations() {
+		for _, deployed := range ctx.AppliedResources(kustomization) {
+			applied[deployed.Resource] = true
+			if parser.ClassifyResource(deployed.Resource) != parser.ResourceTypeKubernetesKustomization && !clusterScoped[deployed.Resource.Kind] {
+				uses = append(uses, namespaceUse{namespace: deployed.Namespace, resource: deployed.Resource})
+			}
+		}
+	}
+
+	included := make(map[*parser.ParsedResource]bool)
+	for _, kustomization := range ctx.Graph.GetKubernetesKustomizations() {
+		for _, child := range kustomizationChildren(ctx, kustomization) {
+			included[child] = true
+		}
+	}
+	for _, resources := range ctx.Graph.Files {
+		for _, resource := range resources {
+			if applied[resource] {
+				continue
+			}
+			if parser.ClassifyResource(resource) == parser.ResourceTypeKubernetesKustomization {
+				// A kustomization moves what it includes into its namespace
+				if !included[resource] {
+					uses = append(uses, namespaceUse{namespace: kustomizationNamespace(resource), resource: resource})
+				}
+				continue
+			}
+			if !included[resource] && !clusterScoped[resource.Kind] {
+				uses = append(uses, namespaceUse{namespace: resource.Namespace, resource: resource})
+			}
+		}
+	}
+
+	sort.SliceStable(uses, func(i, j int) bool {
+		if uses[i].resource.File != uses[j].resource.File {
+			return uses[i].resource.File < uses[j].resource.File
+		}
+		return uses[i].resource.Line < uses[j].resource.Line
+	})
+
+	var order []string
+	byNamespace := make(map[string][]*parser.ParsedResource)
+	for _, use := range uses {
+		namespace := use.namespace
+		if namespace == "" || strings.Contains(namespace, "${") || defined[namespace] || namespaceAllowed(namespace, allowed) {
+			continue
+		}
+		if containsResource(byNamespace[namespace], use.resource) {
+			continue
+		}
+		if len(byNamespace[namespace]) == 0 {
+			order = append(order, namespace)
+		}
+		byNamespace[namespace] = append(byNamespace[namespace], use.resource)
+	}
+
+	for _, namespace := range order {
+		resources := byNamespace[namespace]
+		first := resources[0]
+		used := fmt.Sprintf("%s '%s' in %s", first.Kind, first.Name, relativeFile(ctx, first.File))
+		if parser.ClassifyResource(first) == parser.ResourceTypeKubernetesKustomization {
+			used = fmt.Sprintf("the kustomization in %s", relativeFile(ctx, first.File))
+		}
+		if len(resources) > 1 {
+			used = fmt.Sprintf("%d resources (%s first)", len(resources), used)
+		}
+		results = append(results, types.ValidationResult{
+			Type:     "missing-namespace",
+			Severity: types.SeverityWarning,
+			Message: fmt.Sprintf("Namespace '%s' is used by %s, but no Namespace manifest in the repository creates it (add one, or list it under rules.missing-namespaces.allowed)",
+				namespace, used),
+			File:     first.File,
+			Line:     first.Line,
+			Column:   first.Column,
+			Resource: first.Name,
+		})
+	}
+
+	return results
+}
@@ -0,0 +1,62 @@
+package checks
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/parser"
+	"github.com/moon-hex/gitops-validator/internal/types"
+)
+
+// MultipleInclusionCheck reports files that a Flux Kustomization reaches
+// through more than one kustomization, such as a base listed by two
+// intermediate bases of the same overlay. kustomize adds the file's resources
+// once per inclusion, and the build fails with "may not add resource with an
+// already registered id", so the Kustomization never applies. Inclusions
+// under different namePrefix, nameSuffix or namespace settings render
+// distinct objects and are left alone.
+func MultipleInclusionCheck(ctx *context.ValidationContext) []types.ValidationResult {
+	var results []types.ValidationResult
+
+	for _, kustomization := range ctx.Graph.GetFluxKustomizations() {
+		for _, included := range ctx.MultipleInclusions(kustomization) {
+			resources := ctx.Graph.Files[included.File]
+			if len(resources) == 0 {
+				continue
+			}
+
+			parents := make([]string, 0, len(included.Parents))
+			for _, parent := range included.Parents {
+				parents = append(parents, includingKustomization(ctx, parent))
+			}
+			name, what := relativeFile(ctx, included.File), "its resources"
+			if parser.ClassifyResource(resources[0]) == parser.ResourceTypeKubernetesKustomization {
+				name, what = path.Dir(name), "everything it builds"
+			}
+
+			results = append(results, types.ValidationResult{
+				Type:     "multiple-inclusion",
+				Severity: types.SeverityError,
+				Message: fmt.Sprintf("%s is included by %s under Kustomization '%s'; kustomize adds %s twice and the build fails (keep one inclusion, e.g. in their common parent)",
+					name, strings.Join(parents, " and "), kustomization.GetResourceKey(), what),
+				File:     resources[0].File,
+				Line:     resources[0].Line,
+				Column:   resources[0].Column,
+				Resource: resources[0].Name,
+			})
+		}
+	}
+
+	return results
+}
+
+// includingKustomization names the directory of a kustomization, or the
+// spec.path of a Flux Kustomization, for messages
+func includingKustomization(ctx *context.ValidationContext, parent *parser.ParsedResource) string {
+	if parser.ClassifyResource(parent) == parser.ResourceTypeFluxKustomization {
+		return fmt.Sprintf("the spec.path of Kustomization '%s'", parent.GetResourceKey())
+	}
+	return path.Dir(relativeFile(ctx, parent.File))
+}
@@ -0,0 +1,145 @@
+package checks
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/parser"
+	"github.com/moon-hex/gitops-validator/internal/types"
+)
+
+// namespaceDefinition is a Namespace document and who applies it
+type namespaceDefinition struct {
+	resource *parser.ParsedResource
+	owners   []string
+}
+
+// NamespaceCollisionCheck flags Namespaces applied by more than one owner in
+// the same cluster. An owner is the configured tenant whose root Flux
+// Kustomizations deploy the Namespace, or else the Flux Kustomization that
+// applies it. With several owners, pruning by one deletes the Namespace and
+// everything in it for all of them, and their labels overwrite each other.
+func NamespaceCollisionCheck(ctx *context.ValidationContext) []types.ValidationResult {
+	var results []types.ValidationResult
+	reported := make(map[string]bool)
+
+	applying := ctx.ApplyingKustomizations()
+	for _, root := range ctx.RootKustomizations() {
+		byName := make(map[string][]namespaceDefinition)
+		for _, definition := range namespaceDefinitions(ctx, root, applying) {
+			byName[definition.resource.Name] = append(byName[definition.resource.Name], definition)
+		}
+
+		names := make([]string, 0, len(byName))
+		for name := range byName {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			definitions := byName[name]
+
+			owners := make(map[string]bool)
+			var described []string
+			for _, definition := range definitions {
+				for _, owner := range definition.owners {
+					owners[owner] = true
+					described = append(described, fmt.Sprintf("%s in %s", owner, relativeFile(ctx, definition.resource.File)))
+				}
+			}
+			if len(owners) < 2 {
+				continue
+			}
+
+			message := fmt.Sprintf("Namespace '%s' is applied by several owners: %s; each can prune or relabel it for the others",
+				name, strings.Join(described, ", "))
+			for _, definition := range definitions {
+				key := fmt.Sprintf("%s:%d:%s", definition.resource.File, definition.resource.Line, message)
+				if reported[key] {
+					continue
+				}
+				reported[key] = true
+
+				results = append(results, types.ValidationResult{
+					Type:     "namespace-collision",
+					Severity: types.SeverityError,
+					Message:  message,
+					File:     definition.resource.File,
+					Line:     definition.resource.Line,
+					Column:   definition.resource.Column,
+					Resource: name,
+				})
+			}
+		}
+	}
+
+	return results
+}
+
+// namespaceDefinitions returns the Namespace documents a cluster root deploys
+// with their owners: the tenants whose roots deploy them or, for Namespaces no
+// tenant deploys, the cluster's Flux Kustomizations applying their file
+func namespaceDefinitions(ctx *context.ValidationContext, root *parser.ParsedResource, applying map[string][]*parser.ParsedResource) []namespaceDefinition {
+	tree := ctx.DeploymentTree(root)
+
+	inCluster := make(map[*parser.ParsedResource]bool)
+	tenants := make(map[*parser.ParsedResource][]string)
+	for _, deployed := range tree {
+		inCluster[deployed.Resource] = true
+		if parser.ClassifyResource(deployed.Resource) != parser.ResourceTypeFluxKustomization {
+			continue
+		}
+
+		tenant, ok := ctx.Config.GetTenantForRoot(relativeFile(ctx, deployed.Resource.File))
+		if !ok {
+			continue
+		}
+		for _, owned := range ctx.DeploymentTree(deployed.Resource) {
+			if !containsString(tenants[owned.Resource], "tenant '"+tenant+"'") {
+				tenants[owned.Resource] = append(tenants[owned.Resource], "tenant '"+tenant+"'")
+			}
+		}
+	}
+
+	var definitions []namespaceDefinition
+	seen := make(map[*parser.ParsedResource]bool)
+	for _, deployed := range tree {
+		resource := deployed.Resource
+		if resource.Kind != "Namespace" || resource.APIVersion != "v1" || seen[resource] {
+			continue
+		}
+		seen[resource] = true
+
+		owners := tenants[resource]
+		if len(owners) == 0 {
+			for _, kustomization := range applying[resource.File] {
+				if inCluster[kustomization] {
+					owners = append(owners, fmt.Sprintf("Flux Kustomization '%s'", kustomization.GetResourceKey()))
+				}
+			}
+		}
+		definitions = append(definitions, namespaceDefinition{resource: resource, owners: owners})
+	}
+	return definitions
+}
+
+// relativeFile returns a file path relative to the repository root
+func relativeFile(ctx *context.ValidationContext, file string) string {
+	if rel, err := filepath.Rel(ctx.RepoPath, file); err == nil {
+		return filepath.ToSlash(rel)
+	}
+	return file
+}
+
+// containsString reports whether values contains value
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
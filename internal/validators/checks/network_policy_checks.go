@@ -0,0 +1,209 @@
+package checks
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/parser"
+	"github.com/moon-hex/gitops-validator/internal/types"
+)
+
+// networkPolicyKinds are the policy kinds that count as covering a namespace:
+// the built-in NetworkPolicy (Calico's namesake included) and Cilium's
+var networkPolicyKinds = []string{"NetworkPolicy", "CiliumNetworkPolicy"}
+
+// NetworkPolicyCheck reports NetworkPolicies whose podSelector matches the
+// pod template labels of no workload deployed to the policy's namespace; such
+// a policy has no effect, usually because of a typo or a renamed label. An
+// empty podSelector selects every pod and is not reported, nor are policies in
+// namespaces a HelmRelease installs into, since the chart may create the pods.
+// With require-policies set, namespaces whose workloads no NetworkPolicy or
+// CiliumNetworkPolicy covers are reported too, as all traffic to and from
+// their pods is allowed; the built-in namespaces and the rule's
+// exclude-namespaces are left out. Findings are informational.
+func NetworkPolicyCheck(ctx *context.ValidationContext) []types.ValidationResult {
+	var results []types.ValidationResult
+
+	targets := workloads(ctx)
+	if len(targets) == 0 {
+		return results
+	}
+	rule := ctx.Config.GitOpsValidator.Rules.NetworkPolicies
+
+	deployedTo := deployedNamespaces(ctx)
+	namespacesOf := func(resource *parser.ParsedResource) []string {
+		if namespaces := deployedTo[resource]; len(namespaces) > 0 {
+			return namespaces
+		}
+		return []string{resource.Namespace}
+	}
+
+	pods := make(map[string][]*parser.ParsedResource)
+	for _, workload := range targets {
+		for _, namespace := range namespacesOf(workload) {
+			pods[namespace] = append(pods[namespace], workload)
+		}
+	}
+	charted := chartNamespaces(ctx, namespacesOf)
+
+	covered := make(map[string]bool)
+	for _, kind := range networkPolicyKinds {
+		for _, policy := range ctx.Graph.GetResourcesByKind(kind) {
+			for _, namespace := range namespacesOf(policy) {
+				covered[namespace] = true
+			}
+		}
+	}
+
+	policies := append([]*parser.ParsedResource(nil), ctx.Graph.GetResourcesByKind("NetworkPolicy")...)
+	sort.SliceStable(policies, func(i, j int) bool { return policies[i].File < policies[j].File })
+	for _, policy := range policies {
+		if apiGroup(policy.APIVersion) != "networking.k8s.io" {
+			continue
+		}
+		spec, _ := policy.Content["spec"].(map[string]interface{})
+		selector, _ := spec["podSelector"].(map[string]interface{})
+		if len(selector) == 0 || strings.Contains(fmt.Sprint(selector), "${") {
+			continue
+		}
+
+		for _, namespace := range namespacesOf(policy) {
+			if namespace == "" || charted[namespace] {
+				continue
+			}
+			matched := false
+			for _, workload := range pods[namespace] {
+				if labelSelectorMatches(selector, podLabels(workload)) {
+					matched = true
+					break
+				}
+			}
+			if matched {
+				continue
+			}
+			results = append(results, types.ValidationResult{
+				Type:     "network-policy",
+				Severity: types.SeverityInfo,
+				Message: fmt.Sprintf("NetworkPolicy '%s' selects pods with %s, which no workload of the repository in namespace '%s' has; the policy has no effect (fix the podSelector or the pod template labels)",
+					qualifiedName(namespace, policy.Name), describePodSelector(selector), namespace),
+				File:     policy.File,
+				Line:     policy.Line,
+				Column:   policy.Column,
+				Resource: policy.Name,
+			})
+		}
+	}
+
+	if !rule.RequirePolicies {
+		return results
+	}
+
+	namespaceManifests := make(map[string]*parser.ParsedResource)
+	for _, manifest := range ctx.Graph.GetResourcesByKind("Namespace") {
+		namespaceManifests[manifest.Name] = manifest
+	}
+	names := make([]string, 0, len(pods))
+	for namespace := range pods {
+		names = append(names, namespace)
+	}
+	sort.Strings(names)
+	for _, namespace := range names {
+		if namespace == "" || covered[namespace] || containsString(builtinNamespaces, namespace) || namespaceAllowed(namespace, rule.ExcludeNamespaces) {
+			continue
+		}
+		var running []string
+		for _, workload := range pods[namespace] {
+			running = append(running, fmt.Sprintf("%s '%s'", workload.Kind, workload.Name))
+		}
+		// Reported on the Namespace manifest, or the first workload without one
+		anchor := pods[namespace][0]
+		if manifest, ok := namespaceManifests[namespace]; ok {
+			anchor = manifest
+		}
+		results = append(results, types.ValidationResult{
+			Type:     "network-policy",
+			Severity: types.SeverityInfo,
+			Message: fmt.Sprintf("Namespace '%s' runs %s, but no NetworkPolicy applies in it; all traffic to and from its pods is allowed (add a NetworkPolicy, or list the namespace under rules.network-policies.exclude-namespaces)",
+				namespace, strings.Join(running, ", ")),
+			File:     anchor.File,
+			Line:     anchor.Line,
+			Column:   anchor.Column,
+			Resource: anchor.Name,
+		})
+	}
+
+	return results
+}
+
+// labelSelectorMatches reports whether labels satisfy a metav1.LabelSelector:
+// its matchLabels and the In, NotIn, Exists and DoesNotExist operators of its
+// matchExpressions
+func labelSelectorMatches(selector, labels map[string]interface{}) bool {
+	matchLabels, _ := selector["matchLabels"].(map[string]interface{})
+	if !labelsMatch(matchLabels, labels) {
+		return false
+	}
+	expressions, _ := selector["matchExpressions"].([]interface{})
+	for _, item := range expressions {
+		expression, _ := item.(map[string]interface{})
+		key, _ := expression["key"].(string)
+		operator, _ := expression["operator"].(string)
+		label, exists := labels[key]
+		var values []string
+		list, _ := expression["values"].([]interface{})
+		for _, value := range list {
+			values = append(values, fmt.Sprint(value))
+		}
+		switch operator {
+		case "In":
+			if !exists || !containsString(values, fmt.Sprint(label)) {
+				return false
+			}
+		case "NotIn":
+			if exists && containsString(values, fmt.Sprint(label)) {
+				return false
+			}
+		case "Exists":
+			if !exists {
+				return false
+			}
+		case "DoesNotExist":
+			if exists {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// describePodSelector renders a metav1.LabelSelector for messages, e.g.
+// "'app=web,tier in (api,worker)'"
+func describePodSelector(selector map[string]interface{}) string {
+	var terms []string
+	matchLabels, _ := selector["matchLabels"].(map[string]interface{})
+	for _, key := range sortedKeys(matchLabels) {
+		terms = append(terms, fmt.Sprintf("%s=%v", key, matchLabels[key]))
+	}
+	expressions, _ := selector["matchExpressions"].([]interface{})
+	for _, item := range expressions {
+		expression, _ := item.(map[string]interface{})
+		key, _ := expression["key"].(string)
+		operator, _ := expression["operator"].(string)
+		var values []string
+		list, _ := expression["values"].([]interface{})
+		for _, value := range list {
+			values = append(values, fmt.Sprint(value))
+		}
+		switch operator {
+		case "Exists":
+			terms = append(terms, key)
+		case "DoesNotExist":
+			terms = append(terms, "!"+key)
+		default:
+			terms = append(terms, fmt.Sprintf("%s %s (%s)", key, strings.ToLower(operator), strings.Join(values, ",")))
+		}
+	}
+	return "'" + strings.Join(terms, ",") + "'"
+}
@@ -0,0 +1,258 @@
+package checks
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/moon-hex/gitops-validator/internal/config"
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/parser"
+	"github.com/moon-hex/gitops-validator/internal/semver"
+	"github.com/moon-hex/gitops-validator/internal/types"
+)
+
+// notificationObjectGroups are the kinds Alerts can watch and Receivers can
+// reconcile, and the Flux API group of each
+var notificationObjectGroups = map[string]string{
+	"Kustomization":         "kustomize.toolkit.fluxcd.io",
+	"HelmRelease":           "helm.toolkit.fluxcd.io",
+	"GitRepository":         "source.toolkit.fluxcd.io",
+	"OCIRepository":         "source.toolkit.fluxcd.io",
+	"HelmRepository":        "source.toolkit.fluxcd.io",
+	"HelmChart":             "source.toolkit.fluxcd.io",
+	"Bucket":                "source.toolkit.fluxcd.io",
+	"ImageRepository":       "image.toolkit.fluxcd.io",
+	"ImagePolicy":           "image.toolkit.fluxcd.io",
+	"ImageUpdateAutomation": "image.toolkit.fluxcd.io",
+}
+
+// receiverTypes are the Receiver types notification-controller supports and
+// the first Flux version supporting each
+var receiverTypes = map[string]string{
+	"generic":      "2.0.0",
+	"generic-hmac": "2.0.0",
+	"github":       "2.0.0",
+	"gitlab":       "2.0.0",
+	"bitbucket":    "2.0.0",
+	"harbor":       "2.0.0",
+	"dockerhub":    "2.0.0",
+	"quay":         "2.0.0",
+	"gcr":          "2.0.0",
+	"nexus":        "2.0.0",
+	"acr":          "2.0.0",
+	"cdevents":     "2.4.0",
+}
+
+func init() {
+	config.RegisterRuleParams("notification-refs", config.ParamSpec{
+		Name:        "flux-version",
+		Type:        config.ParamVersion,
+		Description: "Flux version of the clusters, e.g. 2.3.0, to report Receiver types it does not support",
+	})
+}
+
+// NotificationRefCheck validates the references of notification-controller
+// Alerts and Receivers. An Alert's spec.providerRef must name a Provider in
+// the Alert's namespace, and every spec.eventSources entry must name a kind
+// Alerts support and, unless it is the "*" wildcard, an object of that kind
+// in the entry's namespace (the Alert's when unset). An Alert whose provider
+// or sources do not exist never sends a notification and reports nothing
+// about it. Receivers are checked by receiverRefs. HelmCharts are created by
+// helm-controller, so only their kind is checked, and names with Flux
+// variables are skipped.
+func NotificationRefCheck(ctx *context.ValidationContext) []types.ValidationResult {
+	results := receiverRefs(ctx)
+
+	add := func(alert *parser.ParsedResource, message string) {
+		results = append(results, types.ValidationResult{
+			Type:     "notification-ref",
+			Severity: types.SeverityError,
+			Message:  fmt.Sprintf("Alert '%s' %s", alert.GetResourceKey(), message),
+			File:     alert.File,
+			Line:     alert.Line,
+			Column:   alert.Column,
+			Resource: alert.Name,
+		})
+	}
+
+	providers := fluxObjects(ctx, "Provider", "notification.toolkit.fluxcd.io")
+
+	for _, alert := range fluxObjects(ctx, "Alert", "notification.toolkit.fluxcd.io") {
+		spec, _ := alert.Content["spec"].(map[string]interface{})
+
+		providerRef, _ := spec["providerRef"].(map[string]interface{})
+		providerName, _ := providerRef["name"].(string)
+		switch provider, others := findNamed(providers, providerName, alert.Namespace); {
+		case providerName == "":
+			add(alert, "has no spec.providerRef.name")
+		case strings.Contains(providerName, "${"):
+		case provider == nil && len(others) > 0:
+			add(alert, fmt.Sprintf("references Provider '%s', which is not defined in namespace '%s' (found in %s); providers must be in the Alert's namespace",
+				providerName, alert.Namespace, strings.Join(others, ", ")))
+		case provider == nil:
+			add(alert, fmt.Sprintf("references Provider '%s', which is not defined in this repository", providerName))
+		}
+
+		eventSources, _ := spec["eventSources"].([]interface{})
+		for i, entry := range eventSources {
+			source, _ := entry.(map[string]interface{})
+			kind, _ := source["kind"].(string)
+			name, _ := source["name"].(string)
+			namespace, _ := source["namespace"].(string)
+			where := fmt.Sprintf("eventSources[%d]", i)
+
+			group, supported := notificationObjectGroups[kind]
+			switch {
+			case !supported:
+				kinds := make([]string, 0, len(notificationObjectGroups))
+				for k := range notificationObjectGroups {
+					kinds = append(kinds, k)
+				}
+				sort.Strings(kinds)
+				add(alert, fmt.Sprintf("has %s kind '%s', which Alerts cannot watch (supported: %s)", where, kind, strings.Join(kinds, ", ")))
+				continue
+			case name == "":
+				add(alert, fmt.Sprintf("has %s without a name; use '*' to watch every %s", where, kind))
+				continue
+			case name == "*" || kind == "HelmChart" || strings.Contains(name+namespace, "${"):
+				continue
+			}
+
+			if namespace == "" {
+				namespace = alert.Namespace
+			}
+			switch object, others := findNamed(fluxObjects(ctx, kind, group), name, namespace); {
+			case object == nil && len(others) > 0:
+				add(alert, fmt.Sprintf("has %s %s '%s', which is not defined in namespace '%s' (found in %s)", where, kind, name, namespace, strings.Join(others, ", ")))
+			case object == nil:
+				add(alert, fmt.Sprintf("has %s %s '%s', which is not defined in this repository", where, kind, name))
+			}
+		}
+	}
+
+	return results
+}
+
+// receiverRefs validates Receivers: spec.type must be a type the configured
+// Flux version supports, spec.secretRef must name a Secret the repository
+// creates in the Receiver's namespace, and every spec.resources entry must
+// name a kind Receivers reconcile and, unless it is "*", an object of that
+// kind in the entry's namespace (the Receiver's when unset). A Receiver
+// without its Secret never serves its webhook, and one naming missing objects
+// accepts webhooks that trigger nothing.
+func receiverRefs(ctx *context.ValidationContext) []types.ValidationResult {
+	var results []types.ValidationResult
+
+	receivers := fluxObjects(ctx, "Receiver", "notification.toolkit.fluxcd.io")
+	if len(receivers) == 0 {
+		return results
+	}
+
+	add := func(receiver *parser.ParsedResource, message string) {
+		results = append(results, types.ValidationResult{
+			Type:     "notification-ref",
+			Severity: types.SeverityError,
+			Message:  fmt.Sprintf("Receiver '%s' %s", receiver.GetResourceKey(), message),
+			File:     receiver.File,
+			Line:     receiver.Line,
+			Column:   receiver.Column,
+			Resource: receiver.Name,
+		})
+	}
+
+	fluxVersion, pinned := ctx.Config.RuleParams("notification-refs").Version("flux-version")
+	available := ConfigSources(ctx)
+
+	for _, receiver := range receivers {
+		spec, _ := receiver.Content["spec"].(map[string]interface{})
+
+		receiverType, _ := spec["type"].(string)
+		switch since, supported := receiverTypes[receiverType]; {
+		case receiverType == "":
+			add(receiver, "has no spec.type")
+		case !supported:
+			supportedTypes := make([]string, 0, len(receiverTypes))
+			for t := range receiverTypes {
+				supportedTypes = append(supportedTypes, t)
+			}
+			sort.Strings(supportedTypes)
+			add(receiver, fmt.Sprintf("has type '%s', which notification-controller does not support (supported: %s)", receiverType, strings.Join(supportedTypes, ", ")))
+		case pinned:
+			if minimum, _ := semver.Parse(since); semver.Compare(fluxVersion, minimum) < 0 {
+				add(receiver, fmt.Sprintf("has type '%s', which needs Flux %s or later; the configured flux-version is %s", receiverType, since, fluxVersion.Original))
+			}
+		}
+
+		secretRef, _ := spec["secretRef"].(map[string]interface{})
+		secretName, _ := secretRef["name"].(string)
+		switch origin, exists := available[configSourceKey("Secret", receiver.Namespace, secretName)]; {
+		case secretName == "":
+			add(receiver, "has no spec.secretRef.name; the webhook token is required")
+		case strings.Contains(secretName, "${"):
+		case !exists:
+			add(receiver, fmt.Sprintf("references Secret '%s', which the repository does not create in namespace '%s'; the webhook is not served until it exists", secretName, receiver.Namespace))
+		case origin != "":
+			add(receiver, fmt.Sprintf("references Secret '%s', which %s generates with a name hash suffix, so the name never matches (set generatorOptions.disableNameSuffixHash: true)", secretName, origin))
+		}
+
+		resources, _ := spec["resources"].([]interface{})
+		if len(resources) == 0 {
+			add(receiver, "has no spec.resources, so its webhook triggers nothing")
+		}
+		for i, entry := range resources {
+			resource, _ := entry.(map[string]interface{})
+			kind, _ := resource["kind"].(string)
+			name, _ := resource["name"].(string)
+			namespace, _ := resource["namespace"].(string)
+			where := fmt.Sprintf("resources[%d]", i)
+
+			group, supported := notificationObjectGroups[kind]
+			switch {
+			case !supported:
+				kinds := make([]string, 0, len(notificationObjectGroups))
+				for k := range notificationObjectGroups {
+					kinds = append(kinds, k)
+				}
+				sort.Strings(kinds)
+				add(receiver, fmt.Sprintf("has %s kind '%s', which Receivers cannot reconcile (supported: %s)", where, kind, strings.Join(kinds, ", ")))
+				continue
+			case name == "":
+				add(receiver, fmt.Sprintf("has %s without a name; use '*' with matchLabels to reconcile several %s objects", where, kind))
+				continue
+			case name == "*" || kind == "HelmChart" || strings.Contains(name+namespace, "${"):
+				continue
+			}
+
+			if namespace == "" {
+				namespace = receiver.Namespace
+			}
+			switch object, others := findNamed(fluxObjects(ctx, kind, group), name, namespace); {
+			case object == nil && len(others) > 0:
+				add(receiver, fmt.Sprintf("has %s %s '%s', which is not defined in namespace '%s' (found in %s)", where, kind, name, namespace, strings.Join(others, ", ")))
+			case object == nil:
+				add(receiver, fmt.Sprintf("has %s %s '%s', which is not defined in this repository", where, kind, name))
+			}
+		}
+	}
+
+	return results
+}
+
+// fluxObjects returns the resources of a kind in a Flux API group, in file
+// order
+func fluxObjects(ctx *context.ValidationContext, kind, group string) []*parser.ParsedResource {
+	var found []*parser.ParsedResource
+	for _, resource := range ctx.Graph.GetResourcesByKind(kind) {
+		if apiGroup(resource.APIVersion) == group {
+			found = append(found, resource)
+		}
+	}
+	sort.Slice(found, func(i, j int) bool {
+		if found[i].File != found[j].File {
+			return found[i].File < found[j].File
+		}
+		return found[i].Line < found[j].Line
+	})
+	return found
+}
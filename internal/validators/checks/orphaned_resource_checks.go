@@ -3,10 +3,10 @@ package checks
 import (
 	"fmt"
 	"path/filepath"
-	"strings"
 
 	"github.com/moon-hex/gitops-validator/internal/config"
 	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/pathutil"
 	"github.com/moon-hex/gitops-validator/internal/types"
 )
 
@@ -36,7 +36,7 @@ func OrphanedResourceCheck(ctx *context.ValidationContext) []types.ValidationRes
 
 		results = append(results, types.ValidationResult{
 			Type:     "orphaned-resource",
-			Severity: "warning",
+			Severity: types.SeverityWarning,
 			Message:  fmt.Sprintf("File '%s' is not referenced by any kustomization and is not an entry point", filepath.Base(orphaned.File)),
 			File:     orphaned.File,
 			Resource: orphaned.Name,
@@ -50,27 +50,12 @@ func OrphanedResourceCheck(ctx *context.ValidationContext) []types.ValidationRes
 // matchOrphanedCategory returns the name of the first category whose path patterns
 // match relPath (forward-slash normalised, relative to repo root), or "" if none match.
 func matchOrphanedCategory(relPath string, categories []config.OrphanedResourceCategoryConfig) string {
-	norm := filepath.ToSlash(relPath)
 	for _, cat := range categories { // already sorted by priority
 		for _, pattern := range cat.Paths {
-			normPattern := filepath.ToSlash(pattern)
-			if matchPathPattern(norm, normPattern) {
+			if pathutil.MatchPattern(relPath, pattern) {
 				return cat.Name
 			}
 		}
 	}
 	return ""
 }
-
-// matchPathPattern matches a normalised relative path against a glob pattern.
-// Patterns ending with /** match any file whose path starts with the prefix.
-// Other patterns are matched using filepath.Match against the full path and
-// also against just the leading path components for prefix-style matches.
-func matchPathPattern(path, pattern string) bool {
-	if strings.HasSuffix(pattern, "/**") {
-		prefix := strings.TrimSuffix(pattern, "/**")
-		return strings.HasPrefix(path, prefix+"/") || path == prefix
-	}
-	matched, _ := filepath.Match(pattern, path)
-	return matched
-}
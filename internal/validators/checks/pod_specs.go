@@ -0,0 +1,202 @@
+package checks
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/parser"
+)
+
+// podTemplatePaths are where the built-in workload kinds keep their pod spec
+var podTemplatePaths = map[string][]string{
+	"Pod":                   {"spec"},
+	"PodTemplate":           {"template", "spec"},
+	"Deployment":            {"spec", "template", "spec"},
+	"StatefulSet":           {"spec", "template", "spec"},
+	"DaemonSet":             {"spec", "template", "spec"},
+	"ReplicaSet":            {"spec", "template", "spec"},
+	"ReplicationController": {"spec", "template", "spec"},
+	"Job":                   {"spec", "template", "spec"},
+	"CronJob":               {"spec", "jobTemplate", "spec", "template", "spec"},
+}
+
+// podContainer is a container of a pod spec
+type podContainer struct {
+	// Field is "containers", "initContainers" or "ephemeralContainers"
+	Field   string
+	Index   int
+	Name    string
+	Content map[string]interface{}
+}
+
+// String returns the container's position for messages, e.g. "container 'web'"
+func (c podContainer) String() string {
+	kind := map[string]string{"initContainers": "init container", "ephemeralContainers": "ephemeral container"}[c.Field]
+	if kind == "" {
+		kind = "container"
+	}
+	if c.Name == "" {
+		return fmt.Sprintf("%s %s[%d]", kind, c.Field, c.Index)
+	}
+	return fmt.Sprintf("%s '%s'", kind, c.Name)
+}
+
+// podSpec returns the pod spec of a built-in workload, or nil for other kinds
+// and workloads without one
+func podSpec(resource *parser.ParsedResource) map[string]interface{} {
+	path, ok := podTemplatePaths[resource.Kind]
+	if !ok {
+		return nil
+	}
+	current := resource.Content
+	for _, key := range path {
+		next, _ := current[key].(map[string]interface{})
+		if next == nil {
+			return nil
+		}
+		current = next
+	}
+	return current
+}
+
+// podLabels returns the labels of the pods a built-in workload creates, from
+// the metadata of its pod template (or of the Pod itself)
+func podLabels(resource *parser.ParsedResource) map[string]interface{} {
+	path, ok := podTemplatePaths[resource.Kind]
+	if !ok {
+		return nil
+	}
+	current := resource.Content
+	for _, key := range path[:len(path)-1] {
+		current, _ = current[key].(map[string]interface{})
+	}
+	metadata, _ := current["metadata"].(map[string]interface{})
+	labels, _ := metadata["labels"].(map[string]interface{})
+	return labels
+}
+
+// workloads returns the resources with a pod spec, in file order
+func workloads(ctx *context.ValidationContext) []*parser.ParsedResource {
+	files := make([]string, 0, len(ctx.Graph.Files))
+	for file := range ctx.Graph.Files {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	var found []*parser.ParsedResource
+	for _, file := range files {
+		for _, resource := range ctx.Graph.Files[file] {
+			if podSpec(resource) != nil {
+				found = append(found, resource)
+			}
+		}
+	}
+	return found
+}
+
+// podContainers returns the containers, init containers and ephemeral
+// containers of a pod spec
+func podContainers(spec map[string]interface{}) []podContainer {
+	var containers []podContainer
+	for _, field := range []string{"initContainers", "containers", "ephemeralContainers"} {
+		list, _ := spec[field].([]interface{})
+		for i, entry := range list {
+			content, _ := entry.(map[string]interface{})
+			if content == nil {
+				continue
+			}
+			name, _ := content["name"].(string)
+			containers = append(containers, podContainer{Field: field, Index: i, Name: name, Content: content})
+		}
+	}
+	return containers
+}
+
+// podObjectRef is a reference of a pod spec to a ConfigMap or Secret
+type podObjectRef struct {
+	Kind     string
+	Name     string
+	Optional bool
+	// Keys are the keys the reference reads, for single values and volume
+	// items; none when it reads every key
+	Keys []string
+	// Where is the referencing field, for messages
+	Where string
+}
+
+// podObjectRefs returns the ConfigMaps and Secrets a pod spec references:
+// from envFrom and env valueFrom of its containers, configMap, secret and
+// projected volumes, and imagePullSecrets
+func podObjectRefs(spec map[string]interface{}) []podObjectRef {
+	var refs []podObjectRef
+	add := func(kind string, source map[string]interface{}, nameField, where string) {
+		if source == nil {
+			return
+		}
+		name, _ := source[nameField].(string)
+		ref := podObjectRef{Kind: kind, Name: name, Optional: source["optional"] == "true", Where: where}
+		if key, _ := source["key"].(string); key != "" {
+			ref.Keys = append(ref.Keys, key)
+		}
+		items, _ := source["items"].([]interface{})
+		for _, entry := range items {
+			item, _ := entry.(map[string]interface{})
+			if key, _ := item["key"].(string); key != "" {
+				ref.Keys = append(ref.Keys, key)
+			}
+		}
+		refs = append(refs, ref)
+	}
+
+	for _, container := range podContainers(spec) {
+		envFrom, _ := container.Content["envFrom"].([]interface{})
+		for _, entry := range envFrom {
+			source, _ := entry.(map[string]interface{})
+			where := fmt.Sprintf("envFrom of %s", container)
+			configMapRef, _ := source["configMapRef"].(map[string]interface{})
+			add("ConfigMap", configMapRef, "name", where)
+			secretRef, _ := source["secretRef"].(map[string]interface{})
+			add("Secret", secretRef, "name", where)
+		}
+		env, _ := container.Content["env"].([]interface{})
+		for _, entry := range env {
+			variable, _ := entry.(map[string]interface{})
+			name, _ := variable["name"].(string)
+			valueFrom, _ := variable["valueFrom"].(map[string]interface{})
+			where := fmt.Sprintf("env var '%s' of %s", name, container)
+			configMapKeyRef, _ := valueFrom["configMapKeyRef"].(map[string]interface{})
+			add("ConfigMap", configMapKeyRef, "name", where)
+			secretKeyRef, _ := valueFrom["secretKeyRef"].(map[string]interface{})
+			add("Secret", secretKeyRef, "name", where)
+		}
+	}
+
+	volumes, _ := spec["volumes"].([]interface{})
+	for _, entry := range volumes {
+		volume, _ := entry.(map[string]interface{})
+		name, _ := volume["name"].(string)
+		where := fmt.Sprintf("volume '%s'", name)
+		configMap, _ := volume["configMap"].(map[string]interface{})
+		add("ConfigMap", configMap, "name", where)
+		secret, _ := volume["secret"].(map[string]interface{})
+		add("Secret", secret, "secretName", where)
+
+		projected, _ := volume["projected"].(map[string]interface{})
+		sources, _ := projected["sources"].([]interface{})
+		for _, item := range sources {
+			source, _ := item.(map[string]interface{})
+			configMap, _ := source["configMap"].(map[string]interface{})
+			add("ConfigMap", configMap, "name", "projected "+where)
+			secret, _ := source["secret"].(map[string]interface{})
+			add("Secret", secret, "name", "projected "+where)
+		}
+	}
+
+	pullSecrets, _ := spec["imagePullSecrets"].([]interface{})
+	for _, entry := range pullSecrets {
+		reference, _ := entry.(map[string]interface{})
+		add("Secret", reference, "name", "imagePullSecrets")
+	}
+	return refs
+}
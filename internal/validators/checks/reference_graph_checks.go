@@ -0,0 +1,87 @@
+package checks
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/moon-hex/gitops-validator/internal/config"
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/parser"
+	"github.com/moon-hex/gitops-validator/internal/types"
+)
+
+func init() {
+	config.RegisterRuleParams("reference-graph", config.ParamSpec{
+		Name:        "max-depth",
+		Type:        config.ParamInt,
+		Description: fmt.Sprintf("longest reference chain from an entry point before a warning (default %d)", context.DefaultMaxReferenceDepth),
+	})
+}
+
+// ReferenceGraphCheck reports kustomization.yaml files including each other
+// in a cycle, which kustomize build rejects, and reference chains from an
+// entry point deeper than the max-depth parameter of the rule (0 disables
+// the depth check). Deep chains are reported once per entry point, with the
+// shortest chain crossing the limit.
+func ReferenceGraphCheck(ctx *context.ValidationContext) []types.ValidationResult {
+	var results []types.ValidationResult
+
+	for _, cycle := range ctx.KustomizationCycles() {
+		described := make([]string, 0, len(cycle)+1)
+		for _, kustomization := range cycle {
+			described = append(described, relativeFile(ctx, kustomization.File))
+		}
+		described = append(described, described[0])
+		results = append(results, types.ValidationResult{
+			Type:     "reference-graph",
+			Severity: types.SeverityError,
+			Message:  fmt.Sprintf("kustomization include cycle: %s; kustomize build fails on it", strings.Join(described, " -> ")),
+			File:     cycle[0].File,
+			Line:     cycle[0].Line,
+			Column:   cycle[0].Column,
+		})
+	}
+
+	maxDepth := ctx.Config.RuleParams("reference-graph").Int("max-depth", context.DefaultMaxReferenceDepth)
+	if maxDepth < 1 {
+		return results
+	}
+	traversal := ctx.TraverseReferences(ctx.FindEntryPoints())
+
+	var boundary []*parser.ParsedResource
+	for resource, depth := range traversal.Depth {
+		if depth == maxDepth+1 {
+			boundary = append(boundary, resource)
+		}
+	}
+	sort.Slice(boundary, func(i, j int) bool {
+		if boundary[i].File != boundary[j].File {
+			return boundary[i].File < boundary[j].File
+		}
+		return boundary[i].Line < boundary[j].Line
+	})
+
+	reported := make(map[*parser.ParsedResource]bool)
+	for _, resource := range boundary {
+		chain := traversal.Chain(resource)
+		entryPoint := chain[0]
+		if reported[entryPoint] {
+			continue
+		}
+		reported[entryPoint] = true
+
+		results = append(results, types.ValidationResult{
+			Type:     "reference-graph",
+			Severity: types.SeverityWarning,
+			Message: fmt.Sprintf("Reference chain from %s '%s' is deeper than max-depth %d: it reaches '%s' through %d references (via '%s')",
+				entryPoint.Kind, entryPoint.Name, maxDepth, relativeFile(ctx, resource.File), len(chain)-1, relativeFile(ctx, chain[1].File)),
+			File:     entryPoint.File,
+			Line:     entryPoint.Line,
+			Column:   entryPoint.Column,
+			Resource: entryPoint.Name,
+		})
+	}
+
+	return results
+}
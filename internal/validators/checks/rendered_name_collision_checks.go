@@ -0,0 +1,193 @@
+package checks
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/parser"
+	"github.com/moon-hex/gitops-validator/internal/types"
+)
+
+// renderedObject is a resource as a Flux Kustomization applies it
+type renderedObject struct {
+	apiVersion    string
+	kind          string
+	deployed      context.DeployedResource
+	kustomization *parser.ParsedResource
+	// build is the directory whose kustomize build output has the object
+	// when render mode found no manifest it comes from; deployed.Resource is
+	// then the Flux Kustomization
+	build string
+}
+
+// RenderedNameCollisionCheck flags objects of the same kind that render to
+// the same name in the same namespace once the namePrefix, nameSuffix and
+// namespace transformers of the including kustomizations and the Flux
+// spec.targetNamespace are applied. Within one Flux Kustomization, kustomize
+// refuses to build two resources with the same ID; across the Flux
+// Kustomizations of a cluster, each overwrites the object and pruning by one
+// deletes it for the others. Namespaces are left to the namespace-collisions
+// rule, and names with Flux variables are skipped. In render mode (--render),
+// the Flux Kustomizations whose kustomization builds are compared by the
+// objects kustomize builds, which also catches names set by replacements,
+// patches and the transformers the graph does not model.
+func RenderedNameCollisionCheck(ctx *context.ValidationContext) []types.ValidationResult {
+	var results []types.ValidationResult
+	reported := make(map[string]bool)
+
+	for _, root := range ctx.RootKustomizations() {
+		var kustomizations []*parser.ParsedResource
+		for _, deployed := range ctx.DeploymentTree(root) {
+			if parser.ClassifyResource(deployed.Resource) == parser.ResourceTypeFluxKustomization && !containsResource(kustomizations, deployed.Resource) {
+				kustomizations = append(kustomizations, deployed.Resource)
+			}
+		}
+
+		byID := make(map[string][]renderedObject)
+		for _, kustomization := range kustomizations {
+			objects, ok := renderedObjects(ctx, kustomization)
+			if !ok {
+				objects = nil
+				for _, deployed := range ctx.AppliedResources(kustomization) {
+					if parser.ClassifyResource(deployed.Resource) != parser.ResourceTypeKubernetesKustomization {
+						resource := deployed.Resource
+						objects = append(objects, renderedObject{apiVersion: resource.APIVersion, kind: resource.Kind, deployed: deployed, kustomization: kustomization})
+					}
+				}
+			}
+			for _, object := range objects {
+				deployed := object.deployed
+				if object.kind == "Namespace" || strings.Contains(deployed.Name+deployed.Namespace, "${") {
+					continue
+				}
+				id := strings.Join([]string{apiGroup(object.apiVersion), object.kind, deployed.Namespace, deployed.Name}, "\x00")
+				byID[id] = append(byID[id], object)
+			}
+		}
+
+		ids := make([]string, 0, len(byID))
+		for id, objects := range byID {
+			if len(objects) > 1 {
+				ids = append(ids, id)
+			}
+		}
+		sort.Strings(ids)
+
+		for _, id := range ids {
+			objects := byID[id]
+			first := objects[0].deployed
+
+			perKustomization := make(map[*parser.ParsedResource]int)
+			var sources []string
+			for _, object := range objects {
+				perKustomization[object.kustomization]++
+				source := relativeFile(ctx, object.deployed.Resource.File)
+				if object.build != "" {
+					source = fmt.Sprintf("the kustomize build of '%s'", object.build)
+				} else if object.deployed.Name != object.deployed.Resource.Name {
+					source += fmt.Sprintf(" (named '%s')", object.deployed.Resource.Name)
+				}
+				sources = append(sources, fmt.Sprintf("%s via Flux Kustomization '%s'", source, object.kustomization.GetResourceKey()))
+			}
+
+			var consequences []string
+			for _, count := range perKustomization {
+				if count > 1 {
+					consequences = append(consequences, "kustomize cannot build two resources with the same ID, so the Kustomization fails")
+					break
+				}
+			}
+			if len(perKustomization) > 1 {
+				consequences = append(consequences, "the Flux Kustomizations overwrite it for each other and pruning by one deletes it for the rest")
+			}
+
+			name := first.Name
+			if first.Namespace != "" {
+				name = first.Namespace + "/" + name
+			}
+			message := fmt.Sprintf("%s '%s' is rendered more than once: %s; %s (change a namePrefix, nameSuffix or namespace, or apply it once)",
+				objects[0].kind, name, strings.Join(sources, ", "), strings.Join(consequences, ", and "))
+
+			for _, object := range objects {
+				resource := object.deployed.Resource
+				key := fmt.Sprintf("%s:%d:%s", resource.File, resource.Line, message)
+				if reported[key] {
+					continue
+				}
+				reported[key] = true
+
+				results = append(results, types.ValidationResult{
+					Type:     "rendered-name-collision",
+					Severity: types.SeverityError,
+					Message:  message,
+					File:     resource.File,
+					Line:     resource.Line,
+					Column:   resource.Column,
+					Resource: resource.Name,
+				})
+			}
+		}
+	}
+
+	return results
+}
+
+// renderedObjects returns, in render mode, the objects kustomize builds for
+// the kustomization in the spec.path of a Flux Kustomization, in the
+// spec.targetNamespace it sets. Each is located at the manifest the graph
+// renders to the same ID, or else at the Flux Kustomization. ok is false when
+// the path has no kustomization file, pulls remote resources offline or fails
+// to build.
+func renderedObjects(ctx *context.ValidationContext, flux *parser.ParsedResource) (objects []renderedObject, ok bool) {
+	if !ctx.Config.GitOpsValidator.Render {
+		return nil, false
+	}
+	kustomization := fluxPathKustomization(ctx, flux)
+	if kustomization == nil {
+		return nil, false
+	}
+	settings := ctx.Config.GitOpsValidator.Network
+	if !(settings.Online && !settings.Offline) && kustomizationHasRemote(ctx, kustomization) {
+		return nil, false
+	}
+	dir := filepath.Dir(kustomization.File)
+	build := ctx.Renderer.Build(dir)
+	if build.Err != nil {
+		return nil, false
+	}
+
+	manifests := make(map[string]*parser.ParsedResource)
+	for _, deployed := range ctx.AppliedResources(flux) {
+		resource := deployed.Resource
+		id := strings.Join([]string{apiGroup(resource.APIVersion), resource.Kind, deployed.Namespace, deployed.Name}, "\x00")
+		if _, ok := manifests[id]; !ok {
+			manifests[id] = resource
+		}
+	}
+
+	spec, _ := flux.Content["spec"].(map[string]interface{})
+	targetNamespace, _ := spec["targetNamespace"].(string)
+	for _, rendered := range build.Objects {
+		namespace := rendered.Namespace
+		if targetNamespace != "" && !KustomizeClusterScoped(rendered.Kind) {
+			namespace = targetNamespace
+		}
+		object := renderedObject{
+			apiVersion:    rendered.APIVersion,
+			kind:          rendered.Kind,
+			deployed:      context.DeployedResource{Resource: flux, Namespace: namespace, Name: rendered.Name},
+			kustomization: flux,
+			build:         relativeFile(ctx, dir),
+		}
+		id := strings.Join([]string{apiGroup(rendered.APIVersion), rendered.Kind, namespace, rendered.Name}, "\x00")
+		if manifest, ok := manifests[id]; ok {
+			object.deployed.Resource = manifest
+			object.build = ""
+		}
+		objects = append(objects, object)
+	}
+	return objects, true
+}
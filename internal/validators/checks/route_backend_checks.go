@@ -0,0 +1,284 @@
+package checks
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/moon-hex/gitops-validator/internal/config"
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/parser"
+	"github.com/moon-hex/gitops-validator/internal/types"
+)
+
+func init() {
+	config.RegisterRuleParams("route-backends", config.ParamSpec{
+		Name:        "external-services",
+		Type:        config.ParamStringList,
+		Description: "Services created outside the repository, as name or namespace/name; glob patterns are allowed",
+	})
+}
+
+// gatewayRouteKinds are the Gateway API routes whose rules list backendRefs
+var gatewayRouteKinds = []string{"HTTPRoute", "GRPCRoute"}
+
+// serviceBackend is a Service a route sends traffic to
+type serviceBackend struct {
+	namespace string
+	name      string
+	// port is the port number or name, "" when unset
+	port string
+	// where is the referencing field, for messages
+	where string
+}
+
+// RouteBackendCheck validates the Service backends of Ingresses and of
+// Gateway API HTTPRoutes and GRPCRoutes. The Service must exist in the
+// namespace the backend is in, the Ingress's or route's own unless a
+// backendRef sets another, and expose the port it names, by number or, for
+// Ingresses, by name; otherwise the controller answers with errors. A
+// backendRef to another namespace also needs a ReferenceGrant there. Services
+// in namespaces a HelmRelease installs into are not checked, since charts
+// create Services the repository does not show, nor names with Flux
+// variables; Services created outside the repository can be listed in the
+// external-services parameter.
+func RouteBackendCheck(ctx *context.ValidationContext) []types.ValidationResult {
+	var results []types.ValidationResult
+
+	var routes []*parser.ParsedResource
+	for _, ingress := range ctx.Graph.GetResourcesByKind("Ingress") {
+		routes = append(routes, ingress)
+	}
+	for _, kind := range gatewayRouteKinds {
+		for _, route := range ctx.Graph.GetResourcesByKind(kind) {
+			if apiGroup(route.APIVersion) == "gateway.networking.k8s.io" {
+				routes = append(routes, route)
+			}
+		}
+	}
+	if len(routes) == 0 {
+		return results
+	}
+
+	deployedTo := deployedNamespaces(ctx)
+	namespacesOf := func(resource *parser.ParsedResource) []string {
+		if namespaces := deployedTo[resource]; len(namespaces) > 0 {
+			return namespaces
+		}
+		return []string{resource.Namespace}
+	}
+
+	services := make(map[string]*parser.ParsedResource)
+	for _, service := range ctx.Graph.GetResourcesByKind("Service") {
+		for _, namespace := range append(namespacesOf(service), "") {
+			services[namespace+"/"+service.Name] = service
+		}
+	}
+	charted := chartNamespaces(ctx, namespacesOf)
+	external := ctx.Config.RuleParams("route-backends").Strings("external-services", nil)
+
+	for _, route := range routes {
+		for _, namespace := range namespacesOf(route) {
+			owner := qualifiedName(namespace, route.Name)
+			add := func(message string) {
+				results = append(results, types.ValidationResult{
+					Type:     "route-backend",
+					Severity: types.SeverityError,
+					Message:  fmt.Sprintf("%s '%s' %s", route.Kind, owner, message),
+					File:     route.File,
+					Line:     route.Line,
+					Column:   route.Column,
+					Resource: route.Name,
+				})
+			}
+
+			var backends []serviceBackend
+			if route.Kind == "Ingress" {
+				backends = ingressBackends(route, namespace)
+			} else {
+				backends = gatewayBackends(route, namespace)
+			}
+
+			for _, backend := range backends {
+				if backend.name == "" || strings.Contains(backend.namespace+backend.name+backend.port, "${") {
+					continue
+				}
+				if charted[backend.namespace] || matchesExternal(external, backend.namespace, backend.name) {
+					continue
+				}
+				target := qualifiedName(backend.namespace, backend.name)
+
+				if backend.namespace != namespace && !referenceGranted(ctx, namespacesOf, route.Kind, namespace, backend.namespace, backend.name) {
+					add(fmt.Sprintf("sends %s to Service '%s' in another namespace, which no ReferenceGrant in namespace '%s' allows; the backend is not resolved (add a ReferenceGrant from %s in namespace '%s')",
+						backend.where, target, backend.namespace, route.Kind, namespace))
+				}
+
+				service, exists := services[backend.namespace+"/"+backend.name]
+				if !exists {
+					add(fmt.Sprintf("sends %s to Service '%s', which the repository does not create; requests fail until it exists (list it in external-services if it is created outside the repository)",
+						backend.where, target))
+					continue
+				}
+				if backend.port != "" {
+					if ports := servicePorts(service); len(ports) > 0 && !containsString(ports, backend.port) {
+						add(fmt.Sprintf("sends %s to port '%s' of Service '%s', which only exposes %s; requests fail",
+							backend.where, backend.port, target, strings.Join(quoteAll(ports), ", ")))
+					}
+				}
+			}
+		}
+	}
+
+	return results
+}
+
+// ingressBackends returns the Service backends of an Ingress: its default
+// backend and the backends of its rules' paths, in the networking.k8s.io/v1
+// format or the serviceName and servicePort of the older APIs
+func ingressBackends(ingress *parser.ParsedResource, namespace string) []serviceBackend {
+	var backends []serviceBackend
+	add := func(backend map[string]interface{}, where string) {
+		if backend == nil {
+			return
+		}
+		if service, ok := backend["service"].(map[string]interface{}); ok {
+			name, _ := service["name"].(string)
+			port, _ := service["port"].(map[string]interface{})
+			number, _ := port["number"].(string)
+			portName, _ := port["name"].(string)
+			backends = append(backends, serviceBackend{namespace: namespace, name: name, port: number + portName, where: where})
+			return
+		}
+		if name, ok := backend["serviceName"].(string); ok {
+			port, _ := backend["servicePort"].(string)
+			backends = append(backends, serviceBackend{namespace: namespace, name: name, port: port, where: where})
+		}
+	}
+
+	spec, _ := ingress.Content["spec"].(map[string]interface{})
+	if backend, ok := spec["defaultBackend"].(map[string]interface{}); ok {
+		add(backend, "the default backend")
+	} else if backend, ok := spec["backend"].(map[string]interface{}); ok {
+		add(backend, "the default backend")
+	}
+	rules, _ := spec["rules"].([]interface{})
+	for _, item := range rules {
+		rule, _ := item.(map[string]interface{})
+		host, _ := rule["host"].(string)
+		http, _ := rule["http"].(map[string]interface{})
+		paths, _ := http["paths"].([]interface{})
+		for _, entry := range paths {
+			route, _ := entry.(map[string]interface{})
+			backend, _ := route["backend"].(map[string]interface{})
+			where, _ := route["path"].(string)
+			if where == "" {
+				where = "/"
+			}
+			add(backend, fmt.Sprintf("path '%s%s'", host, where))
+		}
+	}
+	return backends
+}
+
+// gatewayBackends returns the Service backendRefs of the rules of a Gateway
+// API route; refs to other kinds are left out
+func gatewayBackends(route *parser.ParsedResource, namespace string) []serviceBackend {
+	var backends []serviceBackend
+	spec, _ := route.Content["spec"].(map[string]interface{})
+	rules, _ := spec["rules"].([]interface{})
+	for i, item := range rules {
+		rule, _ := item.(map[string]interface{})
+		refs, _ := rule["backendRefs"].([]interface{})
+		for _, entry := range refs {
+			ref, _ := entry.(map[string]interface{})
+			group, _ := ref["group"].(string)
+			kind, _ := ref["kind"].(string)
+			if group != "" || (kind != "" && kind != "Service") {
+				continue
+			}
+			name, _ := ref["name"].(string)
+			port, _ := ref["port"].(string)
+			target := namespace
+			if ns, _ := ref["namespace"].(string); ns != "" {
+				target = ns
+			}
+			backends = append(backends, serviceBackend{namespace: target, name: name, port: port, where: fmt.Sprintf("rules[%d]", i)})
+		}
+	}
+	return backends
+}
+
+// servicePorts returns the port numbers and names a Service exposes
+func servicePorts(service *parser.ParsedResource) []string {
+	var ports []string
+	spec, _ := service.Content["spec"].(map[string]interface{})
+	list, _ := spec["ports"].([]interface{})
+	for _, item := range list {
+		port, _ := item.(map[string]interface{})
+		if number, _ := port["port"].(string); number != "" {
+			ports = append(ports, number)
+		}
+		if name, _ := port["name"].(string); name != "" {
+			ports = append(ports, name)
+		}
+	}
+	return ports
+}
+
+// referenceGranted reports whether a ReferenceGrant deployed to namespace to
+// lets routes of kind in namespace from reference Service name
+func referenceGranted(ctx *context.ValidationContext, namespacesOf func(*parser.ParsedResource) []string, kind, from, to, name string) bool {
+	for _, grant := range ctx.Graph.GetResourcesByKind("ReferenceGrant") {
+		if !containsString(namespacesOf(grant), to) {
+			continue
+		}
+		spec, _ := grant.Content["spec"].(map[string]interface{})
+		fromOK := false
+		sources, _ := spec["from"].([]interface{})
+		for _, item := range sources {
+			source, _ := item.(map[string]interface{})
+			group, _ := source["group"].(string)
+			sourceKind, _ := source["kind"].(string)
+			namespace, _ := source["namespace"].(string)
+			if group == "gateway.networking.k8s.io" && sourceKind == kind && namespace == from {
+				fromOK = true
+			}
+		}
+		targets, _ := spec["to"].([]interface{})
+		for _, item := range targets {
+			target, _ := item.(map[string]interface{})
+			group, _ := target["group"].(string)
+			targetKind, _ := target["kind"].(string)
+			targetName, _ := target["name"].(string)
+			if fromOK && group == "" && targetKind == "Service" && (targetName == "" || targetName == name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// chartNamespaces returns the namespaces HelmReleases install into, their
+// targetNamespace or the namespaces they are deployed to
+func chartNamespaces(ctx *context.ValidationContext, namespacesOf func(*parser.ParsedResource) []string) map[string]bool {
+	charted := make(map[string]bool)
+	for _, release := range ctx.Graph.GetHelmReleases() {
+		spec, _ := release.Content["spec"].(map[string]interface{})
+		if namespace, _ := spec["targetNamespace"].(string); namespace != "" {
+			charted[namespace] = true
+			continue
+		}
+		for _, namespace := range namespacesOf(release) {
+			charted[namespace] = true
+		}
+	}
+	return charted
+}
+
+// quoteAll returns values in single quotes
+func quoteAll(values []string) []string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = fmt.Sprintf("'%s'", value)
+	}
+	return quoted
+}
@@ -0,0 +1,126 @@
+package checks
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/types"
+	"github.com/moon-hex/gitops-validator/internal/validators/common"
+)
+
+// secretCreators are the kinds of controllers that create a Secret, with the
+// API group they belong to and the fields naming the Secret, in order of
+// precedence; metadata.name is used when none is set
+var secretCreators = []struct {
+	group  string
+	kind   string
+	fields [][]string
+}{
+	{"external-secrets.io", "ExternalSecret", [][]string{{"spec", "target", "name"}}},
+	{"bitnami.com", "SealedSecret", [][]string{{"spec", "template", "metadata", "name"}}},
+	{"cert-manager.io", "Certificate", [][]string{{"spec", "secretName"}}},
+}
+
+// SecretRefCheck validates the Secrets the pod specs of workloads reference
+// through envFrom, env valueFrom.secretKeyRef, secret and projected volumes
+// and imagePullSecrets. Each must exist in the namespace the workload is
+// deployed to: as a Secret manifest, SOPS-encrypted or not, a secretGenerator,
+// or a Secret an ExternalSecret, SealedSecret or cert-manager Certificate
+// creates. Otherwise pods do not start (a missing image pull Secret only
+// fails pulls from private registries, so it is a warning). References marked
+// optional and names with Flux variables are skipped, and Secrets created
+// outside the repository are declared with the rule's external list.
+func SecretRefCheck(ctx *context.ValidationContext) []types.ValidationResult {
+	var results []types.ValidationResult
+
+	targets := workloads(ctx)
+	if len(targets) == 0 {
+		return results
+	}
+
+	available := ConfigSources(ctx)
+	deployedTo := deployedNamespaces(ctx)
+	for _, creator := range secretCreators {
+		for _, resource := range ctx.Graph.GetResourcesByKind(creator.kind) {
+			if apiGroup(resource.APIVersion) != creator.group {
+				continue
+			}
+			name := resource.Name
+			for _, field := range creator.fields {
+				if value, err := common.ExtractStringFromContent(resource.Content, field...); err == nil && value != "" {
+					name = value
+					break
+				}
+			}
+			for _, namespace := range append([]string{resource.Namespace, ""}, deployedTo[resource]...) {
+				available[configSourceKey("Secret", namespace, name)] = ""
+			}
+		}
+	}
+	external := ctx.Config.GitOpsValidator.Rules.SecretRefs.External
+
+	for _, workload := range targets {
+		namespaces := deployedTo[workload]
+		if len(namespaces) == 0 {
+			namespaces = []string{workload.Namespace}
+		}
+
+		var order []string
+		uses := make(map[string][]string)
+		for _, ref := range podObjectRefs(podSpec(workload)) {
+			if ref.Kind != "Secret" || ref.Name == "" || ref.Optional || strings.Contains(ref.Name, "${") {
+				continue
+			}
+			if !containsString(uses[ref.Name], ref.Where) {
+				if len(uses[ref.Name]) == 0 {
+					order = append(order, ref.Name)
+				}
+				uses[ref.Name] = append(uses[ref.Name], ref.Where)
+			}
+		}
+
+		for _, name := range order {
+			for _, namespace := range namespaces {
+				if _, exists := available[configSourceKey("Secret", namespace, name)]; exists || matchesExternal(external, namespace, name) {
+					continue
+				}
+
+				severity := types.SeverityError
+				consequence := "pods do not start until it exists (create it, mark the reference optional, or list it under rules.secret-refs.external)"
+				if len(uses[name]) == 1 && uses[name][0] == "imagePullSecrets" {
+					severity = types.SeverityWarning
+					consequence = "images from private registries fail to pull until it exists (create it, or list it under rules.secret-refs.external)"
+				}
+				results = append(results, types.ValidationResult{
+					Type:     "secret-ref",
+					Severity: severity,
+					Message: fmt.Sprintf("%s '%s' references Secret '%s' (%s), which the repository does not create; %s",
+						workload.Kind, qualifiedName(namespace, workload.Name), qualifiedName(namespace, name), strings.Join(uses[name], ", "), consequence),
+					File:     workload.File,
+					Line:     workload.Line,
+					Column:   workload.Column,
+					Resource: workload.Name,
+				})
+			}
+		}
+	}
+
+	return results
+}
+
+// matchesExternal reports whether an object matches one of the external
+// names or glob patterns, given as name or namespace/name
+func matchesExternal(external []string, namespace, name string) bool {
+	for _, pattern := range external {
+		candidate := name
+		if strings.Contains(pattern, "/") {
+			candidate = namespace + "/" + name
+		}
+		if matched, _ := path.Match(pattern, candidate); matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,130 @@
+package checks
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/moon-hex/gitops-validator/internal/config"
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/parser"
+	"github.com/moon-hex/gitops-validator/internal/types"
+)
+
+func init() {
+	config.RegisterRuleParams("service-selectors", config.ParamSpec{
+		Name:        "external-workloads",
+		Type:        config.ParamStringList,
+		Description: "Services whose pods are created outside the repository, such as by operators, as name or namespace/name; glob patterns are allowed",
+	})
+}
+
+// ServiceSelectorCheck matches the selector of each Service against the pod
+// template labels of the workloads deployed to its namespace. A selector no
+// workload matches leaves the Service without endpoints; one matching the pods
+// of several workloads spreads traffic across them, which is rarely intended
+// outside of canaries. Services without a selector (whose endpoints are
+// managed by hand) and ExternalName Services are skipped, and so are selectors
+// with Flux variables. Pods in namespaces a HelmRelease installs into may come
+// from the chart, so dead Services there are not reported; Services whose
+// pods an operator creates can be listed in the external-workloads parameter.
+// Labels are compared as written in the manifests, so kustomize labels added
+// to only one side are not seen.
+func ServiceSelectorCheck(ctx *context.ValidationContext) []types.ValidationResult {
+	var results []types.ValidationResult
+
+	services := append([]*parser.ParsedResource(nil), ctx.Graph.GetResourcesByKind("Service")...)
+	if len(services) == 0 {
+		return results
+	}
+	sort.SliceStable(services, func(i, j int) bool { return services[i].File < services[j].File })
+
+	deployedTo := deployedNamespaces(ctx)
+	namespacesOf := func(resource *parser.ParsedResource) []string {
+		if namespaces := deployedTo[resource]; len(namespaces) > 0 {
+			return namespaces
+		}
+		return []string{resource.Namespace}
+	}
+
+	// The workloads of each namespace Services are deployed to, from a view
+	// of the namespace
+	pods := make(map[string][]*parser.ParsedResource)
+	podsIn := func(namespace string) []*parser.ParsedResource {
+		if found, ok := pods[namespace]; ok {
+			return found
+		}
+		pods[namespace] = workloads(ctx.ScopeToNamespace(namespace))
+		return pods[namespace]
+	}
+	charted := chartNamespaces(ctx, namespacesOf)
+	external := ctx.Config.RuleParams("service-selectors").Strings("external-workloads", nil)
+
+	for _, service := range services {
+		spec, _ := service.Content["spec"].(map[string]interface{})
+		selector, _ := spec["selector"].(map[string]interface{})
+		if serviceType, _ := spec["type"].(string); serviceType == "ExternalName" || len(selector) == 0 {
+			continue
+		}
+		if strings.Contains(fmt.Sprint(selector), "${") {
+			continue
+		}
+
+		for _, namespace := range namespacesOf(service) {
+			if matchesExternal(external, namespace, service.Name) {
+				continue
+			}
+			owner := qualifiedName(namespace, service.Name)
+
+			var matched []string
+			for _, workload := range podsIn(namespace) {
+				if labelsMatch(selector, podLabels(workload)) {
+					matched = append(matched, fmt.Sprintf("%s '%s'", workload.Kind, workload.Name))
+				}
+			}
+
+			var message string
+			switch {
+			case len(matched) == 0 && !charted[namespace]:
+				message = fmt.Sprintf("Service '%s' selects %s, which no workload of the repository in namespace '%s' labels its pods with; the Service has no endpoints (fix the selector, or list the Service in external-workloads if an operator creates its pods)",
+					owner, describeLabelSelector(selector), namespace)
+			case len(matched) > 1:
+				message = fmt.Sprintf("Service '%s' selects %s, which matches the pods of %d workloads (%s); traffic is spread across all of them (narrow the selector unless that is intended)",
+					owner, describeLabelSelector(selector), len(matched), strings.Join(matched, ", "))
+			default:
+				continue
+			}
+			results = append(results, types.ValidationResult{
+				Type:     "service-selector",
+				Severity: types.SeverityWarning,
+				Message:  message,
+				File:     service.File,
+				Line:     service.Line,
+				Column:   service.Column,
+				Resource: service.Name,
+			})
+		}
+	}
+
+	return results
+}
+
+// labelsMatch reports whether labels carry every key and value of selector
+func labelsMatch(selector, labels map[string]interface{}) bool {
+	for key, value := range selector {
+		if label, ok := labels[key]; !ok || fmt.Sprint(label) != fmt.Sprint(value) {
+			return false
+		}
+	}
+	return true
+}
+
+// describeLabelSelector renders a label selector for messages, e.g. "app=web"
+func describeLabelSelector(selector map[string]interface{}) string {
+	pairs := make([]string, 0, len(selector))
+	for key, value := range selector {
+		pairs = append(pairs, fmt.Sprintf("%s=%v", key, value))
+	}
+	sort.Strings(pairs)
+	return "'" + strings.Join(pairs, ",") + "'"
+}
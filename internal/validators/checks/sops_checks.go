@@ -0,0 +1,342 @@
+package checks
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/parser"
+	"github.com/moon-hex/gitops-validator/internal/types"
+	"gopkg.in/yaml.v3"
+)
+
+// SOPSConfigFile is the sops configuration at the repository root, which sops
+// finds when run from there
+const SOPSConfigFile = ".sops.yaml"
+
+// Key formats accepted by sops, per key type
+var (
+	pgpFingerprintPattern = regexp.MustCompile(`^[0-9A-Fa-f]{40}$`)
+	ageRecipientPattern   = regexp.MustCompile(`^(age1[qpzry9x8gf2tvdw0s3jn54khce6mua7l]{58}|ssh-(ed25519|rsa) \S+.*)$`)
+	kmsARNPattern         = regexp.MustCompile(`^arn:aws[a-z-]*:kms:[a-z0-9-]+:\d{12}:(key|alias)/\S+$`)
+	gcpKMSPattern         = regexp.MustCompile(`^projects/[^/\s]+/locations/[^/\s]+/keyRings/[^/\s]+/cryptoKeys/[^/\s]+$`)
+	azureKeyVaultPattern  = regexp.MustCompile(`^https://[^/\s]+/keys/[^/\s]+(/[^/\s]+)?$`)
+	vaultTransitPattern   = regexp.MustCompile(`^https?://[^/\s]+/v1/\S+/keys/[^/\s]+$`)
+)
+
+// sopsCreationRule is an entry of creation_rules in .sops.yaml
+type sopsCreationRule struct {
+	PathRegex     string          `yaml:"path_regex"`
+	FilenameRegex string          `yaml:"filename_regex"`
+	PGP           string          `yaml:"pgp"`
+	Age           string          `yaml:"age"`
+	KMS           string          `yaml:"kms"`
+	GCPKMS        string          `yaml:"gcp_kms"`
+	AzureKeyVault string          `yaml:"azure_keyvault"`
+	VaultTransit  string          `yaml:"hc_vault_transit_uri"`
+	KeyGroups     []sopsKeyGroup  `yaml:"key_groups"`
+	line          int             // line of the rule in .sops.yaml
+	pattern       *regexp.Regexp  // compiled path_regex, nil matches every file
+	keys          map[string]bool // "type:key" of every valid key, for comparing with encrypted files
+	malformed     bool            // some key is malformed, so keys cannot be compared
+}
+
+// sopsKeyGroup is an entry of key_groups in a creation rule
+type sopsKeyGroup struct {
+	PGP []string `yaml:"pgp"`
+	Age []string `yaml:"age"`
+	KMS []struct {
+		ARN string `yaml:"arn"`
+	} `yaml:"kms"`
+	GCPKMS []struct {
+		ResourceID string `yaml:"resource_id"`
+	} `yaml:"gcp_kms"`
+	AzureKeyVault []struct {
+		VaultURL string `yaml:"vaultUrl"`
+		Key      string `yaml:"key"`
+		Version  string `yaml:"version"`
+	} `yaml:"azure_keyvault"`
+	VaultTransit []string `yaml:"hc_vault"`
+}
+
+// SOPSConfigCheck validates .sops.yaml at the repository root. Every creation
+// rule needs a valid path_regex and at least one key, and every key must have
+// the format of its type: PGP fingerprints of 40 hex digits, age recipients,
+// AWS KMS ARNs, GCP KMS resource IDs, Azure Key Vault and Vault transit URLs.
+// Every file encrypted with sops must match a creation rule, or sops cannot
+// re-encrypt it, and should be encrypted for the keys of the rule it
+// matches; otherwise a key rotation has not reached it and the cluster may
+// lack the key to decrypt it. Nothing is checked without .sops.yaml.
+func SOPSConfigCheck(ctx *context.ValidationContext) []types.ValidationResult {
+	var results []types.ValidationResult
+
+	configPath := filepath.Join(ctx.RepoPath, SOPSConfigFile)
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return results
+	}
+
+	add := func(severity types.Severity, message, file string, line int) {
+		results = append(results, types.ValidationResult{
+			Type:     "sops-config",
+			Severity: severity,
+			Message:  message,
+			File:     file,
+			Line:     line,
+		})
+	}
+
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		add(types.SeverityError, fmt.Sprintf("%s cannot be parsed: %v", SOPSConfigFile, err), configPath, 0)
+		return results
+	}
+
+	var ruleNodes []*yaml.Node
+	if len(document.Content) > 0 {
+		if list := parser.MappingValue(document.Content[0], "creation_rules"); list != nil && list.Kind == yaml.SequenceNode {
+			ruleNodes = list.Content
+		}
+	}
+	if len(ruleNodes) == 0 {
+		add(types.SeverityError, fmt.Sprintf("%s has no creation_rules; sops cannot encrypt any file", SOPSConfigFile), configPath, 1)
+		return results
+	}
+
+	var rules []*sopsCreationRule
+	for i, node := range ruleNodes {
+		rule := &sopsCreationRule{line: node.Line, keys: make(map[string]bool)}
+		if err := node.Decode(rule); err != nil {
+			add(types.SeverityError, fmt.Sprintf("creation_rules[%d] cannot be parsed: %v", i, err), configPath, node.Line)
+			continue
+		}
+		rules = append(rules, rule)
+
+		pathRegex := rule.PathRegex
+		if pathRegex == "" {
+			pathRegex = rule.FilenameRegex
+		}
+		if pathRegex != "" {
+			pattern, err := regexp.Compile(pathRegex)
+			if err != nil {
+				add(types.SeverityError, fmt.Sprintf("creation_rules[%d] has an invalid path_regex '%s': %v", i, pathRegex, err), configPath, rule.line)
+				rule.pattern = regexp.MustCompile(`$^`)
+			} else {
+				rule.pattern = pattern
+			}
+		}
+
+		problems := rule.collectKeys()
+		for _, problem := range problems {
+			add(types.SeverityError, fmt.Sprintf("creation_rules[%d] %s", i, problem), configPath, rule.line)
+		}
+		rule.malformed = len(problems) > 0
+		if len(rule.keys) == 0 && !rule.malformed {
+			add(types.SeverityError, fmt.Sprintf("creation_rules[%d] lists no keys; sops cannot encrypt the files it matches", i), configPath, rule.line)
+		}
+	}
+
+	var files []string
+	for file := range ctx.Graph.Files {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	for _, file := range files {
+		var encrypted *parser.ParsedResource
+		for _, resource := range ctx.Graph.Files[file] {
+			if _, ok := resource.Content["sops"].(map[string]interface{}); ok {
+				encrypted = resource
+				break
+			}
+		}
+		if encrypted == nil {
+			continue
+		}
+
+		relative := relativeFile(ctx, file)
+		var matched *sopsCreationRule
+		index := 0
+		for i, rule := range rules {
+			if rule.pattern == nil || rule.pattern.MatchString(relative) {
+				matched, index = rule, i
+				break
+			}
+		}
+		if matched == nil {
+			add(types.SeverityError, fmt.Sprintf("%s is encrypted with sops but matches no creation rule in %s; sops cannot re-encrypt it or update its keys", relative, SOPSConfigFile), file, encrypted.Line)
+			continue
+		}
+
+		fileKeys := encryptedFileKeys(encrypted)
+		if len(fileKeys) == 0 || matched.malformed {
+			continue
+		}
+		var missing, extra []string
+		for key := range matched.keys {
+			if !fileKeys[key] {
+				missing = append(missing, sopsKeyLabel(key))
+			}
+		}
+		for key := range fileKeys {
+			if !matched.keys[key] {
+				extra = append(extra, sopsKeyLabel(key))
+			}
+		}
+		sort.Strings(missing)
+		sort.Strings(extra)
+
+		var differences []string
+		if len(missing) > 0 {
+			differences = append(differences, fmt.Sprintf("is not encrypted for %s", strings.Join(missing, ", ")))
+		}
+		if len(extra) > 0 {
+			differences = append(differences, fmt.Sprintf("is still encrypted for %s", strings.Join(extra, ", ")))
+		}
+		if len(differences) > 0 {
+			add(types.SeverityWarning, fmt.Sprintf("%s %s, unlike creation_rules[%d] of %s; run sops updatekeys so the cluster's key can decrypt it",
+				relative, strings.Join(differences, " and "), index, SOPSConfigFile), file, encrypted.Line)
+		}
+	}
+
+	return results
+}
+
+// sopsKeyLabel turns a "type:key" key into "type key" for messages
+func sopsKeyLabel(key string) string {
+	keyType, value, _ := strings.Cut(key, ":")
+	return keyType + " key " + value
+}
+
+// collectKeys fills rule.keys from the comma-separated key fields and the
+// key groups, returning a description of every malformed key
+func (rule *sopsCreationRule) collectKeys() []string {
+	var problems []string
+	add := func(keyType, key string, pattern *regexp.Regexp, description string) {
+		key = strings.TrimSpace(key)
+		if key == "" {
+			return
+		}
+		candidate := key
+		switch keyType {
+		case "pgp":
+			candidate = strings.ReplaceAll(key, " ", "")
+			key = strings.ToUpper(candidate)
+		case "kms":
+			// An ARN may be followed by +<role ARN> to assume
+			candidate, _, _ = strings.Cut(key, "+")
+			key = candidate
+		}
+		if !pattern.MatchString(candidate) {
+			problems = append(problems, fmt.Sprintf("has a malformed %s key '%s'; expected %s", keyType, key, description))
+			return
+		}
+		rule.keys[keyType+":"+key] = true
+	}
+	split := func(keyType, keys string, pattern *regexp.Regexp, description string) {
+		for _, key := range strings.Split(keys, ",") {
+			add(keyType, key, pattern, description)
+		}
+	}
+
+	const (
+		pgpFormat     = "a fingerprint of 40 hex digits"
+		ageFormat     = "an age1... recipient or SSH public key"
+		kmsFormat     = "an ARN such as arn:aws:kms:<region>:<account>:key/<id>"
+		gcpFormat     = "projects/<project>/locations/<location>/keyRings/<ring>/cryptoKeys/<key>"
+		azureFormat   = "https://<vault>.vault.azure.net/keys/<key>/<version>"
+		transitFormat = "https://<vault>/v1/<engine>/keys/<key>"
+	)
+
+	split("pgp", rule.PGP, pgpFingerprintPattern, pgpFormat)
+	split("age", rule.Age, ageRecipientPattern, ageFormat)
+	split("kms", rule.KMS, kmsARNPattern, kmsFormat)
+	split("gcp_kms", rule.GCPKMS, gcpKMSPattern, gcpFormat)
+	split("azure_keyvault", rule.AzureKeyVault, azureKeyVaultPattern, azureFormat)
+	split("hc_vault", rule.VaultTransit, vaultTransitPattern, transitFormat)
+	for _, group := range rule.KeyGroups {
+		for _, key := range group.PGP {
+			add("pgp", key, pgpFingerprintPattern, pgpFormat)
+		}
+		for _, key := range group.Age {
+			add("age", key, ageRecipientPattern, ageFormat)
+		}
+		for _, key := range group.KMS {
+			add("kms", key.ARN, kmsARNPattern, kmsFormat)
+		}
+		for _, key := range group.GCPKMS {
+			add("gcp_kms", key.ResourceID, gcpKMSPattern, gcpFormat)
+		}
+		for _, key := range group.AzureKeyVault {
+			url := strings.TrimSuffix(key.VaultURL, "/") + "/keys/" + key.Key
+			if key.Version != "" {
+				url += "/" + key.Version
+			}
+			add("azure_keyvault", url, azureKeyVaultPattern, azureFormat)
+		}
+		for _, key := range group.VaultTransit {
+			add("hc_vault", key, vaultTransitPattern, transitFormat)
+		}
+	}
+
+	return problems
+}
+
+// encryptedFileKeys returns the "type:key" of every key a sops-encrypted
+// resource is encrypted for, as recorded in its sops metadata
+func encryptedFileKeys(resource *parser.ParsedResource) map[string]bool {
+	keys := make(map[string]bool)
+	metadata, _ := resource.Content["sops"].(map[string]interface{})
+
+	groups := []map[string]interface{}{metadata}
+	keyGroups, _ := metadata["key_groups"].([]interface{})
+	for _, group := range keyGroups {
+		if values, ok := group.(map[string]interface{}); ok {
+			groups = append(groups, values)
+		}
+	}
+
+	for _, group := range groups {
+		for keyType, entries := range group {
+			list, _ := entries.([]interface{})
+			for _, entry := range list {
+				values, _ := entry.(map[string]interface{})
+				var key string
+				switch keyType {
+				case "pgp":
+					fingerprint, _ := values["fp"].(string)
+					key = strings.ToUpper(strings.ReplaceAll(fingerprint, " ", ""))
+				case "age":
+					recipient, _ := values["recipient"].(string)
+					key = strings.TrimSpace(recipient)
+				case "kms":
+					key, _ = values["arn"].(string)
+				case "gcp_kms":
+					key, _ = values["resource_id"].(string)
+				case "azure_kv":
+					url, _ := values["vault_url"].(string)
+					name, _ := values["name"].(string)
+					version, _ := values["version"].(string)
+					keyType, key = "azure_keyvault", strings.TrimSuffix(url, "/")+"/keys/"+name
+					if version != "" {
+						key += "/" + version
+					}
+				case "hc_vault":
+					address, _ := values["vault_address"].(string)
+					engine, _ := values["engine_path"].(string)
+					name, _ := values["key_name"].(string)
+					key = strings.TrimSuffix(address, "/") + "/v1/" + engine + "/keys/" + name
+				}
+				if key != "" {
+					keys[keyType+":"+key] = true
+				}
+			}
+		}
+	}
+
+	return keys
+}
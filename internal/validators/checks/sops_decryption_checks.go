@@ -0,0 +1,101 @@
+package checks
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/moon-hex/gitops-validator/internal/config"
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/types"
+)
+
+func init() {
+	config.RegisterRuleParams("sops-decryption", config.ParamSpec{
+		Name:        "cluster-managed-secrets",
+		Type:        config.ParamStringList,
+		Description: "decryption Secrets created outside the repository, as name or namespace/name",
+	})
+}
+
+// SOPSDecryptionCheck validates the decryption settings of Flux
+// Kustomizations. With spec.decryption, the provider must be sops and a
+// spec.decryption.secretRef must name a Secret the repository creates in the
+// Kustomization's namespace, or one listed in the cluster-managed-secrets
+// parameter, as decryption keys are often created by hand. Without
+// spec.decryption, a Kustomization applying sops-encrypted files applies them
+// still encrypted, which the API server rejects.
+func SOPSDecryptionCheck(ctx *context.ValidationContext) []types.ValidationResult {
+	var results []types.ValidationResult
+
+	kustomizations := fluxObjects(ctx, "Kustomization", "kustomize.toolkit.fluxcd.io")
+	if len(kustomizations) == 0 {
+		return results
+	}
+
+	clusterManaged := ctx.Config.RuleParams("sops-decryption").Strings("cluster-managed-secrets", nil)
+	available := ConfigSources(ctx)
+
+	for _, kustomization := range kustomizations {
+		add := func(severity types.Severity, message string) {
+			results = append(results, types.ValidationResult{
+				Type:     "sops-decryption",
+				Severity: severity,
+				Message:  fmt.Sprintf("Flux Kustomization '%s' %s", kustomization.GetResourceKey(), message),
+				File:     kustomization.File,
+				Line:     kustomization.Line,
+				Column:   kustomization.Column,
+				Resource: kustomization.Name,
+			})
+		}
+
+		spec, _ := kustomization.Content["spec"].(map[string]interface{})
+		decryption, hasDecryption := spec["decryption"].(map[string]interface{})
+
+		if !hasDecryption {
+			var encrypted []string
+			for _, applied := range ctx.AppliedResources(kustomization) {
+				if _, ok := applied.Resource.Content["sops"].(map[string]interface{}); !ok {
+					continue
+				}
+				if file := relativeFile(ctx, applied.Resource.File); !containsString(encrypted, file) {
+					encrypted = append(encrypted, file)
+				}
+			}
+			if len(encrypted) > 0 {
+				sort.Strings(encrypted)
+				add(types.SeverityWarning, fmt.Sprintf("applies sops-encrypted files without spec.decryption, so they are applied still encrypted: %s", strings.Join(encrypted, ", ")))
+			}
+			continue
+		}
+
+		switch provider, _ := decryption["provider"].(string); provider {
+		case "sops":
+		case "":
+			add(types.SeverityError, "has spec.decryption without a provider; set provider: sops")
+			continue
+		default:
+			add(types.SeverityError, fmt.Sprintf("has spec.decryption.provider '%s'; kustomize-controller only supports sops", provider))
+			continue
+		}
+
+		secretRef, _ := decryption["secretRef"].(map[string]interface{})
+		secretName, _ := secretRef["name"].(string)
+		if secretName == "" || strings.Contains(secretName, "${") {
+			// Without a secretRef the controller decrypts with its own
+			// identity, such as cloud KMS workload identity
+			continue
+		}
+		if containsString(clusterManaged, secretName) || containsString(clusterManaged, kustomization.Namespace+"/"+secretName) {
+			continue
+		}
+		switch origin, exists := available[configSourceKey("Secret", kustomization.Namespace, secretName)]; {
+		case !exists:
+			add(types.SeverityError, fmt.Sprintf("references decryption Secret '%s', which the repository does not create in namespace '%s'; list it in cluster-managed-secrets if it is created on the cluster", secretName, kustomization.Namespace))
+		case origin != "":
+			add(types.SeverityError, fmt.Sprintf("references decryption Secret '%s', which %s generates with a name hash suffix, so the name never matches (set generatorOptions.disableNameSuffixHash: true)", secretName, origin))
+		}
+	}
+
+	return results
+}
@@ -0,0 +1,96 @@
+package checks
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/moon-hex/gitops-validator/internal/config"
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/parser"
+	"github.com/moon-hex/gitops-validator/internal/types"
+)
+
+// storageClassAnnotation is the beta annotation that named the StorageClass
+// of a claim before spec.storageClassName
+const storageClassAnnotation = "volume.beta.kubernetes.io/storage-class"
+
+func init() {
+	config.RegisterRuleParams("storage-classes", config.ParamSpec{
+		Name:        "cluster-storage-classes",
+		Type:        config.ParamStringList,
+		Description: "StorageClasses the cluster provides without a manifest in the repository",
+	})
+}
+
+// StorageClassCheck validates the StorageClass of PersistentVolumeClaims and
+// of the volumeClaimTemplates of StatefulSets. A class that is neither
+// created by a StorageClass manifest in the repository nor listed in the
+// cluster-storage-classes parameter leaves the claim Pending, and the pods
+// mounting it with it. Claims without a class use the cluster's default one,
+// an empty class binds a pre-provisioned volume, and names with Flux
+// variables are skipped.
+func StorageClassCheck(ctx *context.ValidationContext) []types.ValidationResult {
+	var results []types.ValidationResult
+
+	type claim struct {
+		resource *parser.ParsedResource
+		where    string
+		content  map[string]interface{}
+	}
+	var claims []claim
+	for _, resource := range ctx.Graph.GetResourcesByKind("PersistentVolumeClaim") {
+		claims = append(claims, claim{resource: resource, where: fmt.Sprintf("PersistentVolumeClaim '%s'", resource.GetResourceKey()), content: resource.Content})
+	}
+	for _, resource := range ctx.Graph.GetResourcesByKind("StatefulSet") {
+		spec, _ := resource.Content["spec"].(map[string]interface{})
+		templates, _ := spec["volumeClaimTemplates"].([]interface{})
+		for i, item := range templates {
+			template, _ := item.(map[string]interface{})
+			metadata, _ := template["metadata"].(map[string]interface{})
+			name, _ := metadata["name"].(string)
+			if name == "" {
+				name = fmt.Sprintf("[%d]", i)
+			}
+			claims = append(claims, claim{resource: resource, where: fmt.Sprintf("volumeClaimTemplate '%s' of StatefulSet '%s'", name, resource.GetResourceKey()), content: template})
+		}
+	}
+	if len(claims) == 0 {
+		return results
+	}
+	sort.SliceStable(claims, func(i, j int) bool { return claims[i].resource.File < claims[j].resource.File })
+
+	defined := make(map[string]bool)
+	for _, class := range ctx.Graph.GetResourcesByKind("StorageClass") {
+		defined[class.Name] = true
+	}
+	for _, name := range ctx.Config.RuleParams("storage-classes").Strings("cluster-storage-classes", nil) {
+		defined[name] = true
+	}
+
+	for _, claim := range claims {
+		spec, _ := claim.content["spec"].(map[string]interface{})
+		class, set := spec["storageClassName"].(string)
+		if !set {
+			metadata, _ := claim.content["metadata"].(map[string]interface{})
+			annotations, _ := metadata["annotations"].(map[string]interface{})
+			class, set = annotations[storageClassAnnotation].(string)
+		}
+		if !set || class == "" || strings.Contains(class, "${") || defined[class] {
+			continue
+		}
+
+		results = append(results, types.ValidationResult{
+			Type:     "storage-class",
+			Severity: types.SeverityWarning,
+			Message: fmt.Sprintf("%s uses StorageClass '%s', which no StorageClass manifest in the repository creates; the claim stays Pending unless the cluster provides it (list it in cluster-storage-classes if it does)",
+				claim.where, class),
+			File:     claim.resource.File,
+			Line:     claim.resource.Line,
+			Column:   claim.resource.Column,
+			Resource: claim.resource.Name,
+		})
+	}
+
+	return results
+}
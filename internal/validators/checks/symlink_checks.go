@@ -0,0 +1,145 @@
+package checks
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/parser"
+	"github.com/moon-hex/gitops-validator/internal/pathutil"
+	"github.com/moon-hex/gitops-validator/internal/types"
+	"github.com/moon-hex/gitops-validator/internal/validators/common"
+)
+
+// SymlinkCheck validates symlinks in the repository. Broken symlinks and
+// symlinks that resolve outside the validated root are errors: CI checkouts
+// and Flux source artifacts only contain the repository itself. For symlinked
+// directories, every kustomization inside is checked to resolve its relative
+// references identically whether they are read through the link or from the
+// real directory; kustomize uses the real directory.
+func SymlinkCheck(ctx *context.ValidationContext) []types.ValidationResult {
+	var results []types.ValidationResult
+
+	root, err := filepath.EvalSymlinks(ctx.RepoPath)
+	if err != nil {
+		return results
+	}
+	root, _ = filepath.Abs(root)
+
+	filepath.Walk(ctx.RepoPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.Mode()&os.ModeSymlink == 0 {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(ctx.RepoPath, path)
+		if err != nil || ctx.Config.ShouldIgnorePath(relPath) {
+			return nil
+		}
+
+		link, _ := os.Readlink(path)
+		target, err := filepath.EvalSymlinks(path)
+		if err != nil {
+			results = append(results, types.ValidationResult{
+				Type:     "symlink-target",
+				Severity: types.SeverityError,
+				Message:  fmt.Sprintf("Symlink '%s' is broken: target '%s' does not exist", relPath, link),
+				File:     path,
+			})
+			return nil
+		}
+		target, _ = filepath.Abs(target)
+
+		targetRel, err := filepath.Rel(root, target)
+		if err != nil || targetRel == ".." || strings.HasPrefix(targetRel, ".."+string(filepath.Separator)) {
+			results = append(results, types.ValidationResult{
+				Type:     "symlink-target",
+				Severity: types.SeverityError,
+				Message:  fmt.Sprintf("Symlink '%s' points outside the validated root (target '%s')", relPath, link),
+				File:     path,
+			})
+			return nil
+		}
+
+		if targetInfo, err := os.Stat(target); err == nil && targetInfo.IsDir() {
+			realDir := filepath.Join(ctx.RepoPath, targetRel)
+			results = append(results, symlinkedTreeConsistencyCheck(path, relPath, realDir, root, ctx)...)
+		}
+		return nil
+	})
+
+	return results
+}
+
+// symlinkedTreeConsistencyCheck compares how the kustomizations below a
+// symlinked directory resolve their references through the link (linkDir,
+// linkRel relative to the repository) and from the real directory (realDir)
+func symlinkedTreeConsistencyCheck(linkDir, linkRel, realDir, root string, ctx *context.ValidationContext) []types.ValidationResult {
+	var results []types.ValidationResult
+
+	for _, kustomization := range ctx.Graph.GetKubernetesKustomizations() {
+		viaReal := filepath.Dir(kustomization.File)
+		sub, err := filepath.Rel(realDir, viaReal)
+		if err != nil || sub == ".." || strings.HasPrefix(sub, ".."+string(filepath.Separator)) {
+			continue
+		}
+		viaLink := filepath.Join(linkDir, sub)
+
+		for _, ref := range kustomizationReferences(kustomization) {
+			if filepath.IsAbs(ref) || pathutil.IsRemote(ref) {
+				continue
+			}
+
+			linkTarget := resolvedPath(filepath.Join(viaLink, ref))
+			realTarget := resolvedPath(filepath.Join(viaReal, ref))
+			if linkTarget == realTarget {
+				continue
+			}
+
+			results = append(results, types.ValidationResult{
+				Type:     "symlink-target",
+				Severity: types.SeverityError,
+				Message: fmt.Sprintf("'%s' resolves to %s through symlink '%s' but to %s from the real directory, which kustomize uses",
+					ref, describeResolvedPath(root, linkTarget), filepath.ToSlash(linkRel), describeResolvedPath(root, realTarget)),
+				File:     kustomization.File,
+				Resource: kustomization.Name,
+			})
+		}
+	}
+
+	return results
+}
+
+// kustomizationReferences returns the local paths a kustomization references
+func kustomizationReferences(kustomization *parser.ParsedResource) []string {
+	var refs []string
+	for _, field := range []string{"resources", "components", "patchesStrategicMerge"} {
+		if values, err := common.ExtractStringSliceFromContent(kustomization.Content, field); err == nil {
+			refs = append(refs, values...)
+		}
+	}
+	refs = append(refs, extractPatchPaths(kustomization, "patches")...)
+	return append(refs, extractPatchPaths(kustomization, "patchesJson6902")...)
+}
+
+// resolvedPath returns the real path a reference ends up at, or "" if it does not exist
+func resolvedPath(path string) string {
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return ""
+	}
+	resolved, _ = filepath.Abs(resolved)
+	return resolved
+}
+
+// describeResolvedPath names a resolved path relative to the validated root
+func describeResolvedPath(root, path string) string {
+	if path == "" {
+		return "nothing (missing)"
+	}
+	if rel, err := filepath.Rel(root, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return "'" + filepath.ToSlash(rel) + "'"
+	}
+	return "'" + path + "'"
+}
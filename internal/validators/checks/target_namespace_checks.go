@@ -0,0 +1,73 @@
+package checks
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/parser"
+	"github.com/moon-hex/gitops-validator/internal/types"
+)
+
+// builtinNamespaces exist in every cluster without a manifest
+var builtinNamespaces = []string{"default", "kube-system", "kube-public", "kube-node-lease"}
+
+// TargetNamespaceCheck flags Flux Kustomizations and HelmReleases whose
+// spec.targetNamespace is not created by a Namespace manifest anywhere in the
+// repository. Reconciling them fails until something else creates the
+// namespace. Namespaces created outside the repository (by cluster
+// provisioning, for example) are declared with the rule's allowed list.
+// HelmReleases with spec.install.createNamespace create their own, and
+// values with Flux variables are skipped as they are only known in-cluster.
+func TargetNamespaceCheck(ctx *context.ValidationContext) []types.ValidationResult {
+	var results []types.ValidationResult
+
+	defined := make(map[string]bool)
+	for _, namespace := range ctx.Graph.GetResourcesByKind("Namespace") {
+		defined[namespace.Name] = true
+	}
+	allowed := append(append([]string(nil), builtinNamespaces...), ctx.Config.GitOpsValidator.Rules.TargetNamespaces.Allowed...)
+
+	var resources []*parser.ParsedResource
+	resources = append(resources, ctx.Graph.GetFluxKustomizations()...)
+	resources = append(resources, ctx.Graph.GetHelmReleases()...)
+
+	for _, resource := range resources {
+		spec, _ := resource.Content["spec"].(map[string]interface{})
+		targetNamespace, _ := spec["targetNamespace"].(string)
+		if targetNamespace == "" || strings.Contains(targetNamespace, "${") || defined[targetNamespace] {
+			continue
+		}
+		if namespaceAllowed(targetNamespace, allowed) {
+			continue
+		}
+		if install, ok := spec["install"].(map[string]interface{}); ok && install["createNamespace"] == "true" {
+			continue
+		}
+
+		results = append(results, types.ValidationResult{
+			Type:     "target-namespace",
+			Severity: types.SeverityWarning,
+			Message: fmt.Sprintf("%s '%s' deploys into namespace '%s', which no Namespace manifest in the repository creates (add one, or list it under rules.target-namespaces.allowed)",
+				resource.Kind, resource.GetResourceKey(), targetNamespace),
+			File:     resource.File,
+			Line:     resource.Line,
+			Column:   resource.Column,
+			Resource: resource.Name,
+		})
+	}
+
+	return results
+}
+
+// namespaceAllowed reports whether namespace matches one of the allowed
+// names or glob patterns (e.g. "tenant-*")
+func namespaceAllowed(namespace string, allowed []string) bool {
+	for _, pattern := range allowed {
+		if matched, _ := path.Match(pattern, namespace); matched {
+			return true
+		}
+	}
+	return false
+}
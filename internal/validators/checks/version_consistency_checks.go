@@ -6,6 +6,7 @@ import (
 
 	"github.com/moon-hex/gitops-validator/internal/context"
 	"github.com/moon-hex/gitops-validator/internal/parser"
+	"github.com/moon-hex/gitops-validator/internal/pathutil"
 	"github.com/moon-hex/gitops-validator/internal/types"
 	"github.com/moon-hex/gitops-validator/internal/validators/common"
 )
@@ -32,7 +33,7 @@ func KustomizationVersionConsistencyCheck(ctx *context.ValidationContext) []type
 		resources := extractResources(kustomization)
 		for _, resourcePath := range resources {
 			// Resolve the full path
-			fullPath, shouldProcess := common.ResolvePath(baseDir, resourcePath)
+			fullPath, shouldProcess := pathutil.Resolve(baseDir, resourcePath)
 			if !shouldProcess {
 				continue // Skip remote resources
 			}
@@ -48,7 +49,7 @@ func KustomizationVersionConsistencyCheck(ctx *context.ValidationContext) []type
 				if !areVersionsCompatible(kustomization.APIVersion, referencedKust.APIVersion) {
 					results = append(results, types.ValidationResult{
 						Type:     "kustomization-version-consistency",
-						Severity: "error",
+						Severity: types.SeverityError,
 						Message: fmt.Sprintf(
 							"Kustomization apiVersion mismatch: '%s' references '%s' (version: %s) but uses version %s",
 							kustomization.File,
@@ -0,0 +1,98 @@
+package checks
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/moon-hex/gitops-validator/internal/config"
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/types"
+)
+
+func init() {
+	config.RegisterRuleParams("workload-service-accounts", config.ParamSpec{
+		Name:        "cluster-managed-service-accounts",
+		Type:        config.ParamStringList,
+		Description: "ServiceAccounts created outside the repository, as name or namespace/name",
+	})
+}
+
+// WorkloadServiceAccountCheck validates the ServiceAccount of the pod specs
+// of workloads. A serviceAccountName (or the deprecated serviceAccount) the
+// repository does not create in the namespace the workload is deployed to
+// keeps its pods from being created. A workload that sets none runs as the
+// namespace's default ServiceAccount; when the repository creates a
+// ServiceAccount named after the workload in that namespace, the RBAC granted
+// to it is most likely meant for the workload, which is warned about.
+// ServiceAccounts created on the cluster can be listed in the
+// cluster-managed-service-accounts parameter.
+func WorkloadServiceAccountCheck(ctx *context.ValidationContext) []types.ValidationResult {
+	var results []types.ValidationResult
+
+	targets := workloads(ctx)
+	if len(targets) == 0 {
+		return results
+	}
+
+	clusterManaged := ctx.Config.RuleParams("workload-service-accounts").Strings("cluster-managed-service-accounts", nil)
+
+	// ServiceAccounts by namespace/name, under the namespace in their
+	// manifest, the namespaces they are deployed to, and no namespace
+	available := make(map[string]bool)
+	deployedTo := deployedNamespaces(ctx)
+	for _, account := range ctx.Graph.GetResourcesByKind("ServiceAccount") {
+		for _, namespace := range append([]string{account.Namespace, ""}, deployedTo[account]...) {
+			available[namespace+"/"+account.Name] = true
+		}
+	}
+
+	for _, workload := range targets {
+		spec := podSpec(workload)
+		name, _ := spec["serviceAccountName"].(string)
+		field := "serviceAccountName"
+		if name == "" {
+			name, _ = spec["serviceAccount"].(string)
+			field = "serviceAccount"
+		}
+		if strings.Contains(name, "${") {
+			continue
+		}
+
+		namespaces := deployedTo[workload]
+		if len(namespaces) == 0 {
+			namespaces = []string{workload.Namespace}
+		}
+		for _, namespace := range namespaces {
+			owner := qualifiedName(namespace, workload.Name)
+			add := func(severity types.Severity, message string) {
+				results = append(results, types.ValidationResult{
+					Type:     "workload-service-account",
+					Severity: severity,
+					Message:  message,
+					File:     workload.File,
+					Line:     workload.Line,
+					Column:   workload.Column,
+					Resource: workload.Name,
+				})
+			}
+
+			if name == "" {
+				if namespace != "" && available[namespace+"/"+workload.Name] {
+					add(types.SeverityWarning, fmt.Sprintf("%s '%s' sets no serviceAccountName, so its pods run as ServiceAccount 'default', although the repository creates ServiceAccount '%s' in namespace '%s' (set serviceAccountName: %s)",
+						workload.Kind, owner, workload.Name, namespace, workload.Name))
+				}
+				continue
+			}
+			if name == "default" || available[namespace+"/"+name] {
+				continue
+			}
+			if containsString(clusterManaged, name) || containsString(clusterManaged, namespace+"/"+name) {
+				continue
+			}
+			add(types.SeverityError, fmt.Sprintf("%s '%s' runs as ServiceAccount '%s' (%s), which the repository does not create in namespace '%s'; its pods are not created until it exists (list it in cluster-managed-service-accounts if it is created on the cluster)",
+				workload.Kind, owner, name, field, namespace))
+		}
+	}
+
+	return results
+}
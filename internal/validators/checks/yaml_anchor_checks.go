@@ -0,0 +1,27 @@
+package checks
+
+import (
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/types"
+)
+
+// YAMLAnchorCheck reports the anchor problems the parser found: aliases of
+// undefined anchors, aliases used inside their own anchor, and merge keys
+// not referring to mappings. kustomize and Flux reject such documents, while
+// the parser can only validate what it managed to expand.
+func YAMLAnchorCheck(ctx *context.ValidationContext) []types.ValidationResult {
+	var results []types.ValidationResult
+
+	for _, issue := range ctx.Graph.ParseIssues {
+		results = append(results, types.ValidationResult{
+			Type:     "yaml-anchor",
+			Severity: types.SeverityError,
+			Message:  issue.Message,
+			File:     issue.File,
+			Line:     issue.Line,
+			Column:   issue.Column,
+		})
+	}
+
+	return results
+}
@@ -30,7 +30,7 @@ func (b *BaseValidator) RepoPath() string {
 }
 
 // CreateResult creates a validation result with consistent formatting
-func (b *BaseValidator) CreateResult(resultType, severity, message, file, resource string, line int) types.ValidationResult {
+func (b *BaseValidator) CreateResult(resultType string, severity types.Severity, message, file, resource string, line int) types.ValidationResult {
 	return types.ValidationResult{
 		Type:     resultType,
 		Severity: severity,
@@ -43,17 +43,17 @@ func (b *BaseValidator) CreateResult(resultType, severity, message, file, resour
 
 // CreateErrorResult creates an error result
 func (b *BaseValidator) CreateErrorResult(resultType, message, file, resource string) types.ValidationResult {
-	return b.CreateResult(resultType, "error", message, file, resource, 0)
+	return b.CreateResult(resultType, types.SeverityError, message, file, resource, 0)
 }
 
 // CreateWarningResult creates a warning result
 func (b *BaseValidator) CreateWarningResult(resultType, message, file, resource string) types.ValidationResult {
-	return b.CreateResult(resultType, "warning", message, file, resource, 0)
+	return b.CreateResult(resultType, types.SeverityWarning, message, file, resource, 0)
 }
 
 // CreateInfoResult creates an info result
 func (b *BaseValidator) CreateInfoResult(resultType, message, file, resource string) types.ValidationResult {
-	return b.CreateResult(resultType, "info", message, file, resource, 0)
+	return b.CreateResult(resultType, types.SeverityInfo, message, file, resource, 0)
 }
 
 // ValidateContext provides a common interface for validation context
@@ -69,11 +69,11 @@ type ValidationCheck struct {
 	Name        string
 	Description string
 	CheckFunc   ValidatorFunc
-	Severity    string
+	Severity    types.Severity
 }
 
 // NewValidationCheck creates a new validation check
-func NewValidationCheck(name, description string, checkFunc ValidatorFunc, severity string) *ValidationCheck {
+func NewValidationCheck(name, description string, checkFunc ValidatorFunc, severity types.Severity) *ValidationCheck {
 	return &ValidationCheck{
 		Name:        name,
 		Description: description,
@@ -3,16 +3,17 @@ package common
 import (
 	"fmt"
 	"os"
-	"path/filepath"
+	"strings"
 
 	"github.com/moon-hex/gitops-validator/internal/context"
 	"github.com/moon-hex/gitops-validator/internal/parser"
+	"github.com/moon-hex/gitops-validator/internal/pathutil"
 	"github.com/moon-hex/gitops-validator/internal/types"
 )
 
 // FileExistenceCheck validates that a file exists at the given path
 func FileExistenceCheck(baseDir, filePath string) error {
-	fullPath, shouldProcess := ResolvePath(baseDir, filePath)
+	fullPath, shouldProcess := pathutil.Resolve(baseDir, filePath)
 	if !shouldProcess {
 		return nil // Skip remote URLs and other non-processable paths
 	}
@@ -33,16 +34,60 @@ func PathValidationCheck(baseDir, path string) error {
 	return FileExistenceCheck(baseDir, path)
 }
 
-// SourceValidationCheck validates that a source reference is valid
-func SourceValidationCheck(ctx *context.ValidationContext, sourceName string) error {
-	if sourceName == "" {
+// kustomizationSourceKinds are the source kinds a Flux Kustomization can reference
+var kustomizationSourceKinds = []string{"GitRepository", "OCIRepository", "Bucket"}
+
+// SourceValidationCheck validates that a Flux Kustomization sourceRef names a
+// GitRepository, OCIRepository or Bucket defined in the repository, or one
+// mapped to a local checkout under sources in the config. An empty kind
+// matches any of them. namespace is the sourceRef namespace, defaulting to
+// the Kustomization's; sources without a namespace match any.
+func SourceValidationCheck(ctx *context.ValidationContext, kind, name, namespace string) error {
+	if name == "" {
 		return fmt.Errorf("source name cannot be empty")
 	}
 
-	// Check if the source exists in the repository
-	// This could be enhanced to check against actual Flux sources
-	// For now, we'll do a basic validation
-	return nil
+	kinds := kustomizationSourceKinds
+	if kind != "" {
+		if !containsKind(kustomizationSourceKinds, kind) {
+			return fmt.Errorf("source kind '%s' is not supported, must be one of %s", kind, strings.Join(kustomizationSourceKinds, ", "))
+		}
+		kinds = []string{kind}
+	}
+
+	if _, mapped := ctx.Config.GetSourceMapping(namespace, name); mapped {
+		return nil
+	}
+
+	var otherNamespaces []string
+	for _, source := range ctx.Graph.GetFluxSources() {
+		if source.Name != name || !containsKind(kinds, source.Kind) {
+			continue
+		}
+		if source.Namespace == "" || namespace == "" || source.Namespace == namespace {
+			return nil
+		}
+		otherNamespaces = append(otherNamespaces, source.Namespace)
+	}
+
+	description := strings.Join(kinds, ", ")
+	if kind == "" {
+		description = "source"
+	}
+	if len(otherNamespaces) > 0 {
+		return fmt.Errorf("%s '%s' is not defined in namespace '%s' (found in %s)", description, name, namespace, strings.Join(otherNamespaces, ", "))
+	}
+	return fmt.Errorf("%s '%s' is not defined in this repository (define it, or map it to a local checkout under 'sources' in the config)", description, name)
+}
+
+// containsKind reports whether kinds contains kind
+func containsKind(kinds []string, kind string) bool {
+	for _, k := range kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
 }
 
 // ResourceValidationCheck validates a Kubernetes resource
@@ -53,30 +98,33 @@ func ResourceValidationCheck(resource *parser.ParsedResource) []types.Validation
 	if resource.APIVersion == "" {
 		results = append(results, types.ValidationResult{
 			Type:     "resource-validation",
-			Severity: "error",
+			Severity: types.SeverityError,
 			Message:  "Resource missing apiVersion",
 			File:     resource.File,
 			Line:     resource.Line,
+			Column:   resource.Column,
 		})
 	}
 
 	if resource.Kind == "" {
 		results = append(results, types.ValidationResult{
 			Type:     "resource-validation",
-			Severity: "error",
+			Severity: types.SeverityError,
 			Message:  "Resource missing kind",
 			File:     resource.File,
 			Line:     resource.Line,
+			Column:   resource.Column,
 		})
 	}
 
 	if resource.Name == "" {
 		results = append(results, types.ValidationResult{
 			Type:     "resource-validation",
-			Severity: "error",
+			Severity: types.SeverityError,
 			Message:  "Resource missing metadata.name",
 			File:     resource.File,
 			Line:     resource.Line,
+			Column:   resource.Column,
 		})
 	}
 
@@ -170,7 +218,32 @@ func ExtractStringSliceFromContent(content map[string]interface{}, path ...strin
 	return nil, fmt.Errorf("unexpected end of path extraction")
 }
 
-// ResolvePath resolves a path relative to a base directory
-func ResolvePath(baseDir, path string) (string, bool) {
-	return filepath.Join(baseDir, path), true
+// DirectoryTarget summarises the contents of a directory referenced as a kustomization resource
+type DirectoryTarget struct {
+	KustomizationFiles []string // kustomization.yaml, kustomization.yml or Kustomization files in the directory
+	Manifests          []string // other YAML files directly inside the directory
+}
+
+// InspectDirectoryTarget lists the kustomization files and plain YAML manifests directly inside dir
+func InspectDirectoryTarget(dir string) (*DirectoryTarget, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	target := &DirectoryTarget{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		name := entry.Name()
+		switch {
+		case name == "kustomization.yaml" || name == "kustomization.yml" || name == "Kustomization":
+			target.KustomizationFiles = append(target.KustomizationFiles, name)
+		case strings.HasSuffix(strings.ToLower(name), ".yaml") || strings.HasSuffix(strings.ToLower(name), ".yml"):
+			target.Manifests = append(target.Manifests, name)
+		}
+	}
+
+	return target, nil
 }
@@ -0,0 +1,21 @@
+package common
+
+import (
+	"fmt"
+
+	"github.com/moon-hex/gitops-validator/internal/types"
+)
+
+// NetworkFailureResult reports that a check could not reach target after
+// retrying. It is a warning rather than an error: the repository may well be
+// valid, and the check runs again on the next validation. Checks return nil
+// instead while offline (see network.IsOffline).
+func NetworkFailureResult(check, target, file string, err error) types.ValidationResult {
+	return types.ValidationResult{
+		Type:     "network-unavailable",
+		Severity: types.SeverityWarning,
+		Message:  fmt.Sprintf("%s could not check %s: %v (skip network checks with --offline)", check, target, err),
+		File:     file,
+		Category: check,
+	}
+}
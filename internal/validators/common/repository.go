@@ -0,0 +1,60 @@
+package common
+
+import (
+	"strings"
+
+	"github.com/moon-hex/gitops-validator/internal/gitrepo"
+)
+
+// LocalRepositoryURLs returns the remote URLs configured in the git checkout
+// containing repoPath. It returns nil when repoPath is not inside a git work tree.
+func LocalRepositoryURLs(repoPath string) []string {
+	repo := gitrepo.Find(repoPath)
+	if repo == nil {
+		return nil
+	}
+
+	remotes, _ := repo.Remotes()
+	var urls []string
+	for _, remote := range remotes {
+		urls = append(urls, remote.URL)
+	}
+	return urls
+}
+
+// SameRepositoryURL reports whether two git URLs refer to the same repository,
+// ignoring scheme, credentials, a trailing ".git" and scp-style "host:path" syntax.
+func SameRepositoryURL(a, b string) bool {
+	return normalizeRepositoryURL(a) == normalizeRepositoryURL(b)
+}
+
+func normalizeRepositoryURL(url string) string {
+	url = strings.ToLower(strings.TrimSpace(url))
+	if i := strings.Index(url, "://"); i >= 0 {
+		url = url[i+3:]
+	}
+	if at := strings.Index(url, "@"); at >= 0 {
+		url = url[at+1:]
+	}
+	// scp-style git@host:org/repo → host/org/repo (but keep host:port/... intact)
+	if colon := strings.Index(url, ":"); colon >= 0 && !strings.Contains(url[:colon], "/") {
+		rest := url[colon+1:]
+		if port, _, _ := strings.Cut(rest, "/"); !isNumeric(port) {
+			url = url[:colon] + "/" + rest
+		}
+	}
+	url = strings.TrimSuffix(url, "/")
+	return strings.TrimSuffix(url, ".git")
+}
+
+func isNumeric(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
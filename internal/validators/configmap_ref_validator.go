@@ -0,0 +1,26 @@
+package validators
+
+import (
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/types"
+	"github.com/moon-hex/gitops-validator/internal/validators/checks"
+	"github.com/moon-hex/gitops-validator/internal/validators/common"
+)
+
+// ConfigMapRefValidator detects workloads referencing ConfigMaps, or ConfigMap
+// keys, the repository does not create.
+type ConfigMapRefValidator struct {
+	*common.BaseValidator
+}
+
+func NewConfigMapRefValidator(repoPath string) *ConfigMapRefValidator {
+	return &ConfigMapRefValidator{
+		BaseValidator: common.NewBaseValidator("ConfigMap Ref Validator", repoPath),
+	}
+}
+
+// Validate implements the GraphValidator interface
+func (v *ConfigMapRefValidator) Validate(ctx *context.ValidationContext) ([]types.ValidationResult, error) {
+	results := checks.ConfigMapRefCheck(ctx)
+	return results, nil
+}
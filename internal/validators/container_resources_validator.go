@@ -0,0 +1,26 @@
+package validators
+
+import (
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/types"
+	"github.com/moon-hex/gitops-validator/internal/validators/checks"
+	"github.com/moon-hex/gitops-validator/internal/validators/common"
+)
+
+// ContainerResourcesValidator enforces the opt-in policy that containers set resource requests
+// and limits.
+type ContainerResourcesValidator struct {
+	*common.BaseValidator
+}
+
+func NewContainerResourcesValidator(repoPath string) *ContainerResourcesValidator {
+	return &ContainerResourcesValidator{
+		BaseValidator: common.NewBaseValidator("Container Resources Validator", repoPath),
+	}
+}
+
+// Validate implements the GraphValidator interface
+func (v *ContainerResourcesValidator) Validate(ctx *context.ValidationContext) ([]types.ValidationResult, error) {
+	results := checks.ContainerResourcesCheck(ctx)
+	return results, nil
+}
@@ -0,0 +1,31 @@
+package validators
+
+import (
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/types"
+	"github.com/moon-hex/gitops-validator/internal/validators/common"
+	"github.com/moon-hex/gitops-validator/sdk"
+)
+
+// ContribValidator runs the rules registered through the sdk package.
+// Contributed rules are opt-in: each runs when its config entry enables it.
+type ContribValidator struct {
+	*common.BaseValidator
+}
+
+func NewContribValidator(repoPath string) *ContribValidator {
+	return &ContribValidator{
+		BaseValidator: common.NewBaseValidator("Contrib Validator", repoPath),
+	}
+}
+
+// Validate implements the GraphValidator interface
+func (v *ContribValidator) Validate(ctx *context.ValidationContext) ([]types.ValidationResult, error) {
+	var results []types.ValidationResult
+	for _, rule := range sdk.Rules() {
+		if ctx.Config.IsRuleEnabled(rule.Name) {
+			results = append(results, rule.Check(ctx)...)
+		}
+	}
+	return results, nil
+}
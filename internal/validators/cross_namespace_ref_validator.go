@@ -0,0 +1,26 @@
+package validators
+
+import (
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/types"
+	"github.com/moon-hex/gitops-validator/internal/validators/checks"
+	"github.com/moon-hex/gitops-validator/internal/validators/common"
+)
+
+// CrossNamespaceRefValidator reports Flux references to objects in other
+// namespaces, for clusters running Flux with --no-cross-namespace-refs.
+type CrossNamespaceRefValidator struct {
+	*common.BaseValidator
+}
+
+func NewCrossNamespaceRefValidator(repoPath string) *CrossNamespaceRefValidator {
+	return &CrossNamespaceRefValidator{
+		BaseValidator: common.NewBaseValidator("Cross Namespace Ref Validator", repoPath),
+	}
+}
+
+// Validate implements the GraphValidator interface
+func (v *CrossNamespaceRefValidator) Validate(ctx *context.ValidationContext) ([]types.ValidationResult, error) {
+	results := checks.CrossNamespaceRefCheck(ctx)
+	return results, nil
+}
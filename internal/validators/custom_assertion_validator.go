@@ -0,0 +1,26 @@
+package validators
+
+import (
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/types"
+	"github.com/moon-hex/gitops-validator/internal/validators/checks"
+	"github.com/moon-hex/gitops-validator/internal/validators/common"
+)
+
+// CustomAssertionValidator checks resources against the assertions
+// configured under assertions: in the config.
+type CustomAssertionValidator struct {
+	*common.BaseValidator
+}
+
+func NewCustomAssertionValidator(repoPath string) *CustomAssertionValidator {
+	return &CustomAssertionValidator{
+		BaseValidator: common.NewBaseValidator("Custom Assertion Validator", repoPath),
+	}
+}
+
+// Validate implements the GraphValidator interface
+func (v *CustomAssertionValidator) Validate(ctx *context.ValidationContext) ([]types.ValidationResult, error) {
+	results := checks.CustomAssertionCheck(ctx)
+	return results, nil
+}
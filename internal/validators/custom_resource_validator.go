@@ -0,0 +1,26 @@
+package validators
+
+import (
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/types"
+	"github.com/moon-hex/gitops-validator/internal/validators/checks"
+	"github.com/moon-hex/gitops-validator/internal/validators/common"
+)
+
+// CustomResourceValidator detects custom resources whose CustomResourceDefinition is neither
+// in the repository nor declared known.
+type CustomResourceValidator struct {
+	*common.BaseValidator
+}
+
+func NewCustomResourceValidator(repoPath string) *CustomResourceValidator {
+	return &CustomResourceValidator{
+		BaseValidator: common.NewBaseValidator("Custom Resource Validator", repoPath),
+	}
+}
+
+// Validate implements the GraphValidator interface
+func (v *CustomResourceValidator) Validate(ctx *context.ValidationContext) ([]types.ValidationResult, error) {
+	results := checks.CustomResourceCheck(ctx)
+	return results, nil
+}
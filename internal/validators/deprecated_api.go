@@ -40,7 +40,7 @@ func (v *DeprecatedAPIValidator) Validate(ctx *context.ValidationContext) ([]typ
 // DeprecatedAPIInfo represents information about a deprecated API
 type DeprecatedAPIInfo struct {
 	DeprecationInfo  string
-	Severity         string
+	Severity         types.Severity
 	OperatorCategory string
 }
 
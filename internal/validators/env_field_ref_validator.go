@@ -0,0 +1,26 @@
+package validators
+
+import (
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/types"
+	"github.com/moon-hex/gitops-validator/internal/validators/checks"
+	"github.com/moon-hex/gitops-validator/internal/validators/common"
+)
+
+// EnvFieldRefValidator checks the downward API references of pod specs:
+// fieldRef and resourceFieldRef of container env vars and downwardAPI volumes.
+type EnvFieldRefValidator struct {
+	*common.BaseValidator
+}
+
+func NewEnvFieldRefValidator(repoPath string) *EnvFieldRefValidator {
+	return &EnvFieldRefValidator{
+		BaseValidator: common.NewBaseValidator("Env Field Ref Validator", repoPath),
+	}
+}
+
+// Validate implements the GraphValidator interface
+func (v *EnvFieldRefValidator) Validate(ctx *context.ValidationContext) ([]types.ValidationResult, error) {
+	results := checks.EnvFieldRefCheck(ctx)
+	return results, nil
+}
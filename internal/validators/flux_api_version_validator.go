@@ -0,0 +1,26 @@
+package validators
+
+import (
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/types"
+	"github.com/moon-hex/gitops-validator/internal/validators/checks"
+	"github.com/moon-hex/gitops-validator/internal/validators/common"
+)
+
+// FluxAPIVersionValidator advises upgrading Flux objects to the API
+// versions of the target Flux release.
+type FluxAPIVersionValidator struct {
+	*common.BaseValidator
+}
+
+func NewFluxAPIVersionValidator(repoPath string) *FluxAPIVersionValidator {
+	return &FluxAPIVersionValidator{
+		BaseValidator: common.NewBaseValidator("Flux API Version Validator", repoPath),
+	}
+}
+
+// Validate implements the GraphValidator interface
+func (v *FluxAPIVersionValidator) Validate(ctx *context.ValidationContext) ([]types.ValidationResult, error) {
+	results := checks.FluxAPIVersionCheck(ctx)
+	return results, nil
+}
@@ -0,0 +1,26 @@
+package validators
+
+import (
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/types"
+	"github.com/moon-hex/gitops-validator/internal/validators/checks"
+	"github.com/moon-hex/gitops-validator/internal/validators/common"
+)
+
+// FluxIntervalValidator checks the reconciliation intervals and timeouts of
+// Flux Kustomizations, HelmReleases and sources.
+type FluxIntervalValidator struct {
+	*common.BaseValidator
+}
+
+func NewFluxIntervalValidator(repoPath string) *FluxIntervalValidator {
+	return &FluxIntervalValidator{
+		BaseValidator: common.NewBaseValidator("Flux Interval Validator", repoPath),
+	}
+}
+
+// Validate implements the GraphValidator interface
+func (v *FluxIntervalValidator) Validate(ctx *context.ValidationContext) ([]types.ValidationResult, error) {
+	results := checks.FluxIntervalCheck(ctx)
+	return results, nil
+}
@@ -32,7 +32,19 @@ func (v *FluxKustomizationValidator) Validate(ctx *context.ValidationContext) ([
 		// Run source validation checks
 		sourceResults := checks.FluxKustomizationSourceCheck(kustomization, ctx)
 		results = append(results, sourceResults...)
+
+		// Run commonMetadata validation checks
+		commonMetadataResults := checks.FluxKustomizationCommonMetadataCheck(kustomization, ctx)
+		results = append(results, commonMetadataResults...)
+
+		// Run healthChecks validation checks
+		healthCheckResults := checks.FluxKustomizationHealthChecksCheck(kustomization, ctx)
+		results = append(results, healthCheckResults...)
 	}
 
+	// Run dependsOn validation checks, which need every Kustomization of a cluster
+	dependsOnResults := checks.FluxKustomizationDependsOnCheck(ctx)
+	results = append(results, dependsOnResults...)
+
 	return results, nil
 }
@@ -7,6 +7,7 @@ import (
 	"github.com/moon-hex/gitops-validator/internal/context"
 	"github.com/moon-hex/gitops-validator/internal/parser"
 	"github.com/moon-hex/gitops-validator/internal/types"
+	"github.com/moon-hex/gitops-validator/internal/validators/checks"
 )
 
 type FluxPostBuildVariablesValidator struct {
@@ -34,7 +35,13 @@ func (v *FluxPostBuildVariablesValidator) Validate(ctx *context.ValidationContex
 	// Get all Flux Kustomization resources from the graph
 	fluxKustomizations := ctx.Graph.GetFluxKustomizations()
 
+	// ConfigMaps and Secrets substituteFrom entries may reference
+	substituteFromSources := checks.ConfigSources(ctx)
+
 	for _, kustomization := range fluxKustomizations {
+		// Run substituteFrom reference checks
+		results = append(results, checks.FluxPostBuildSubstituteFromCheck(kustomization, ctx, substituteFromSources)...)
+
 		// Extract postBuild substitute variable names from the parsed content
 		variables := v.extractPostBuildVariables(kustomization)
 
@@ -42,23 +49,28 @@ func (v *FluxPostBuildVariablesValidator) Validate(ctx *context.ValidationContex
 			if !fluxVariableNamePattern.MatchString(variable.Name) {
 				results = append(results, types.ValidationResult{
 					Type:     "flux-postbuild-variables",
-					Severity: "error",
+					Severity: types.SeverityError,
 					Message: fmt.Sprintf("Invalid Flux variable name '%s': must start with underscore or letter, followed by letters, digits, or underscores only (no dashes allowed). Pattern: ^[_a-zA-Z][_a-zA-Z0-9]*$",
 						variable.Name),
 					File:     kustomization.File,
 					Line:     variable.Line,
+					Column:   variable.Column,
 					Resource: kustomization.Name,
 				})
 			}
 		}
 	}
 
+	// Compare the variables the applied manifests use to those defined
+	results = append(results, checks.FluxPostBuildUsageCheck(ctx)...)
+
 	return results, nil
 }
 
 type VariableInfo struct {
-	Name string
-	Line int
+	Name   string
+	Line   int
+	Column int
 }
 
 // extractPostBuildVariables extracts postBuild substitute variable names from a parsed Flux Kustomization
@@ -100,9 +112,11 @@ func (v *FluxPostBuildVariablesValidator) extractPostBuildVariables(kustomizatio
 
 	// Extract variable names from substitute map
 	for varName := range substituteMap {
+		position := kustomization.KeyPosition("spec", "postBuild", "substitute", varName)
 		variables = append(variables, VariableInfo{
-			Name: varName,
-			Line: 0, // Line number not available from parsed content
+			Name:   varName,
+			Line:   position.Line,
+			Column: position.Column,
 		})
 	}
 
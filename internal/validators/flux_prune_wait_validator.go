@@ -0,0 +1,26 @@
+package validators
+
+import (
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/types"
+	"github.com/moon-hex/gitops-validator/internal/validators/checks"
+	"github.com/moon-hex/gitops-validator/internal/validators/common"
+)
+
+// FluxPruneWaitValidator checks the prune and wait settings of Flux
+// Kustomizations against best practice.
+type FluxPruneWaitValidator struct {
+	*common.BaseValidator
+}
+
+func NewFluxPruneWaitValidator(repoPath string) *FluxPruneWaitValidator {
+	return &FluxPruneWaitValidator{
+		BaseValidator: common.NewBaseValidator("Flux Prune Wait Validator", repoPath),
+	}
+}
+
+// Validate implements the GraphValidator interface
+func (v *FluxPruneWaitValidator) Validate(ctx *context.ValidationContext) ([]types.ValidationResult, error) {
+	results := checks.FluxPruneWaitCheck(ctx)
+	return results, nil
+}
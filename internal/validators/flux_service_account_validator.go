@@ -0,0 +1,26 @@
+package validators
+
+import (
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/types"
+	"github.com/moon-hex/gitops-validator/internal/validators/checks"
+	"github.com/moon-hex/gitops-validator/internal/validators/common"
+)
+
+// FluxServiceAccountValidator checks that the ServiceAccounts Flux
+// Kustomizations and HelmReleases impersonate exist in the repository.
+type FluxServiceAccountValidator struct {
+	*common.BaseValidator
+}
+
+func NewFluxServiceAccountValidator(repoPath string) *FluxServiceAccountValidator {
+	return &FluxServiceAccountValidator{
+		BaseValidator: common.NewBaseValidator("Flux Service Account Validator", repoPath),
+	}
+}
+
+// Validate implements the GraphValidator interface
+func (v *FluxServiceAccountValidator) Validate(ctx *context.ValidationContext) ([]types.ValidationResult, error) {
+	results := checks.FluxServiceAccountCheck(ctx)
+	return results, nil
+}
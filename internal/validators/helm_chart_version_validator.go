@@ -0,0 +1,26 @@
+package validators
+
+import (
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/types"
+	"github.com/moon-hex/gitops-validator/internal/validators/checks"
+	"github.com/moon-hex/gitops-validator/internal/validators/common"
+)
+
+// HelmChartVersionValidator checks HelmRelease charts and versions against
+// the indexes of their HelmRepositories (online mode only).
+type HelmChartVersionValidator struct {
+	*common.BaseValidator
+}
+
+func NewHelmChartVersionValidator(repoPath string) *HelmChartVersionValidator {
+	return &HelmChartVersionValidator{
+		BaseValidator: common.NewBaseValidator("Helm Chart Version Validator", repoPath),
+	}
+}
+
+// Validate implements the GraphValidator interface
+func (v *HelmChartVersionValidator) Validate(ctx *context.ValidationContext) ([]types.ValidationResult, error) {
+	results := checks.HelmChartVersionCheck(ctx)
+	return results, nil
+}
@@ -0,0 +1,26 @@
+package validators
+
+import (
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/types"
+	"github.com/moon-hex/gitops-validator/internal/validators/checks"
+	"github.com/moon-hex/gitops-validator/internal/validators/common"
+)
+
+// HelmReleaseCollisionValidator detects HelmReleases in one cluster that
+// manage the same Helm release.
+type HelmReleaseCollisionValidator struct {
+	*common.BaseValidator
+}
+
+func NewHelmReleaseCollisionValidator(repoPath string) *HelmReleaseCollisionValidator {
+	return &HelmReleaseCollisionValidator{
+		BaseValidator: common.NewBaseValidator("HelmRelease Collision Validator", repoPath),
+	}
+}
+
+// Validate implements the GraphValidator interface
+func (v *HelmReleaseCollisionValidator) Validate(ctx *context.ValidationContext) ([]types.ValidationResult, error) {
+	results := checks.HelmReleaseCollisionCheck(ctx)
+	return results, nil
+}
@@ -0,0 +1,26 @@
+package validators
+
+import (
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/types"
+	"github.com/moon-hex/gitops-validator/internal/validators/checks"
+	"github.com/moon-hex/gitops-validator/internal/validators/common"
+)
+
+// HelmReleaseValuesFromValidator checks that the ConfigMaps and Secrets
+// HelmReleases take values from exist in the repository.
+type HelmReleaseValuesFromValidator struct {
+	*common.BaseValidator
+}
+
+func NewHelmReleaseValuesFromValidator(repoPath string) *HelmReleaseValuesFromValidator {
+	return &HelmReleaseValuesFromValidator{
+		BaseValidator: common.NewBaseValidator("HelmRelease Values From Validator", repoPath),
+	}
+}
+
+// Validate implements the GraphValidator interface
+func (v *HelmReleaseValuesFromValidator) Validate(ctx *context.ValidationContext) ([]types.ValidationResult, error) {
+	results := checks.HelmReleaseValuesFromCheck(ctx)
+	return results, nil
+}
@@ -0,0 +1,26 @@
+package validators
+
+import (
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/types"
+	"github.com/moon-hex/gitops-validator/internal/validators/checks"
+	"github.com/moon-hex/gitops-validator/internal/validators/common"
+)
+
+// ImageAutomationRefValidator checks the ImageRepository references of
+// ImagePolicies and the GitRepository references of ImageUpdateAutomations.
+type ImageAutomationRefValidator struct {
+	*common.BaseValidator
+}
+
+func NewImageAutomationRefValidator(repoPath string) *ImageAutomationRefValidator {
+	return &ImageAutomationRefValidator{
+		BaseValidator: common.NewBaseValidator("Image Automation Ref Validator", repoPath),
+	}
+}
+
+// Validate implements the GraphValidator interface
+func (v *ImageAutomationRefValidator) Validate(ctx *context.ValidationContext) ([]types.ValidationResult, error) {
+	results := checks.ImageAutomationRefCheck(ctx)
+	return results, nil
+}
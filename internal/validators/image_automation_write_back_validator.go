@@ -0,0 +1,26 @@
+package validators
+
+import (
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/types"
+	"github.com/moon-hex/gitops-validator/internal/validators/checks"
+	"github.com/moon-hex/gitops-validator/internal/validators/common"
+)
+
+// ImageAutomationWriteBackValidator checks the update path and push branch of
+// ImageUpdateAutomations.
+type ImageAutomationWriteBackValidator struct {
+	*common.BaseValidator
+}
+
+func NewImageAutomationWriteBackValidator(repoPath string) *ImageAutomationWriteBackValidator {
+	return &ImageAutomationWriteBackValidator{
+		BaseValidator: common.NewBaseValidator("Image Automation Write-Back Validator", repoPath),
+	}
+}
+
+// Validate implements the GraphValidator interface
+func (v *ImageAutomationWriteBackValidator) Validate(ctx *context.ValidationContext) ([]types.ValidationResult, error) {
+	results := checks.ImageAutomationWriteBackCheck(ctx)
+	return results, nil
+}
@@ -0,0 +1,26 @@
+package validators
+
+import (
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/types"
+	"github.com/moon-hex/gitops-validator/internal/validators/checks"
+	"github.com/moon-hex/gitops-validator/internal/validators/common"
+)
+
+// ImagePolicyMarkerValidator checks $imagepolicy setter markers against the
+// ImagePolicies of the repository.
+type ImagePolicyMarkerValidator struct {
+	*common.BaseValidator
+}
+
+func NewImagePolicyMarkerValidator(repoPath string) *ImagePolicyMarkerValidator {
+	return &ImagePolicyMarkerValidator{
+		BaseValidator: common.NewBaseValidator("Image Policy Marker Validator", repoPath),
+	}
+}
+
+// Validate implements the GraphValidator interface
+func (v *ImagePolicyMarkerValidator) Validate(ctx *context.ValidationContext) ([]types.ValidationResult, error) {
+	results := checks.ImagePolicyMarkerCheck(ctx)
+	return results, nil
+}
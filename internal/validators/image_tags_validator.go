@@ -0,0 +1,26 @@
+package validators
+
+import (
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/types"
+	"github.com/moon-hex/gitops-validator/internal/validators/checks"
+	"github.com/moon-hex/gitops-validator/internal/validators/common"
+)
+
+// ImageTagValidator applies the image tag, digest and registry policy to container
+// images.
+type ImageTagValidator struct {
+	*common.BaseValidator
+}
+
+func NewImageTagValidator(repoPath string) *ImageTagValidator {
+	return &ImageTagValidator{
+		BaseValidator: common.NewBaseValidator("Image Tag Validator", repoPath),
+	}
+}
+
+// Validate implements the GraphValidator interface
+func (v *ImageTagValidator) Validate(ctx *context.ValidationContext) ([]types.ValidationResult, error) {
+	results := checks.ImageTagCheck(ctx)
+	return results, nil
+}
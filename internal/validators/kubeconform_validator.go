@@ -0,0 +1,26 @@
+package validators
+
+import (
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/types"
+	"github.com/moon-hex/gitops-validator/internal/validators/checks"
+	"github.com/moon-hex/gitops-validator/internal/validators/common"
+)
+
+// KubeconformValidator validates resources against the JSON schemas of Kubernetes and
+// CRDs with kubeconform.
+type KubeconformValidator struct {
+	*common.BaseValidator
+}
+
+func NewKubeconformValidator(repoPath string) *KubeconformValidator {
+	return &KubeconformValidator{
+		BaseValidator: common.NewBaseValidator("Kubeconform Schema Validator", repoPath),
+	}
+}
+
+// Validate implements the GraphValidator interface
+func (v *KubeconformValidator) Validate(ctx *context.ValidationContext) ([]types.ValidationResult, error) {
+	results := checks.KubeconformCheck(ctx)
+	return results, nil
+}
@@ -12,6 +12,7 @@ type KubernetesKustomizationValidator struct {
 	resourceValidator       *KustomizationResourceValidator
 	patchValidator          *KustomizationPatchValidator
 	strategicMergeValidator *KustomizationStrategicMergeValidator
+	json6902Validator       *KustomizationJSON6902Validator
 }
 
 func NewKubernetesKustomizationValidator(repoPath string) *KubernetesKustomizationValidator {
@@ -19,6 +20,7 @@ func NewKubernetesKustomizationValidator(repoPath string) *KubernetesKustomizati
 		resourceValidator:       NewKustomizationResourceValidator(repoPath),
 		patchValidator:          NewKustomizationPatchValidator(repoPath),
 		strategicMergeValidator: NewKustomizationStrategicMergeValidator(repoPath),
+		json6902Validator:       NewKustomizationJSON6902Validator(repoPath),
 	}
 }
 
@@ -38,6 +40,7 @@ func (v *KubernetesKustomizationValidator) Validate(ctx *context.ValidationConte
 		{v.resourceValidator.Name(), v.resourceValidator.Validate},
 		{v.patchValidator.Name(), v.patchValidator.Validate},
 		{v.strategicMergeValidator.Name(), v.strategicMergeValidator.Validate},
+		{v.json6902Validator.Name(), v.json6902Validator.Validate},
 	}
 
 	for _, validator := range validators {
@@ -46,7 +49,7 @@ func (v *KubernetesKustomizationValidator) Validate(ctx *context.ValidationConte
 			// Add error as validation result instead of failing completely
 			results = append(results, types.ValidationResult{
 				Type:     "kubernetes-kustomization",
-				Severity: "error",
+				Severity: types.SeverityError,
 				Message:  fmt.Sprintf("Validator %s failed: %s", validator.name, err.Error()),
 			})
 			continue
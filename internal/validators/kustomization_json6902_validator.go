@@ -0,0 +1,36 @@
+package validators
+
+import (
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/types"
+	"github.com/moon-hex/gitops-validator/internal/validators/checks"
+)
+
+// KustomizationJSON6902Validator validates patchesJson6902 entries in kustomization files
+type KustomizationJSON6902Validator struct {
+	parser *KustomizationParser
+}
+
+// NewKustomizationJSON6902Validator creates a new KustomizationJSON6902Validator
+func NewKustomizationJSON6902Validator(repoPath string) *KustomizationJSON6902Validator {
+	return &KustomizationJSON6902Validator{
+		parser: NewKustomizationParser(repoPath),
+	}
+}
+
+func (v *KustomizationJSON6902Validator) Name() string {
+	return "Kustomization JSON 6902 Patch Validator"
+}
+
+// Validate implements the GraphValidator interface
+func (v *KustomizationJSON6902Validator) Validate(ctx *context.ValidationContext) ([]types.ValidationResult, error) {
+	var results []types.ValidationResult
+
+	// Targets are matched against the resources each kustomization builds,
+	// so the check works on the graph rather than a single file
+	for _, kustomization := range ctx.Graph.GetKubernetesKustomizations() {
+		results = append(results, checks.KustomizationJSON6902Check(kustomization, ctx)...)
+	}
+
+	return results, nil
+}
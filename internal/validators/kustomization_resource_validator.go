@@ -33,14 +33,17 @@ func (v *KustomizationResourceValidator) Validate(ctx *context.ValidationContext
 	// Create validation rule set
 	ruleSet := NewValidationRuleSet()
 	ruleSet.AddRule(&ResourceReferenceRule{})
+	ruleSet.AddRule(&DirectoryTargetRule{})
+	ruleSet.AddRule(&GeneratorFileRule{})
 
 	// Validate each kustomization
 	for _, kustomization := range kustomizations {
 		// Convert ParsedResource to KustomizationFile format for compatibility
 		kustomizationFile := &KustomizationFile{
-			Path:    kustomization.File,
-			Content: kustomization.Content,
-			BaseDir: filepath.Dir(kustomization.File),
+			Path:     kustomization.File,
+			Content:  kustomization.Content,
+			BaseDir:  filepath.Dir(kustomization.File),
+			Resource: kustomization,
 		}
 
 		// Run validation rules
@@ -38,9 +38,10 @@ func (v *KustomizationStrategicMergeValidator) Validate(ctx *context.ValidationC
 	for _, kustomization := range kustomizations {
 		// Convert ParsedResource to KustomizationFile format for compatibility
 		kustomizationFile := &KustomizationFile{
-			Path:    kustomization.File,
-			Content: kustomization.Content,
-			BaseDir: filepath.Dir(kustomization.File),
+			Path:     kustomization.File,
+			Content:  kustomization.Content,
+			BaseDir:  filepath.Dir(kustomization.File),
+			Resource: kustomization,
 		}
 
 		// Run validation rules
@@ -5,14 +5,17 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/moon-hex/gitops-validator/internal/parser"
+	"github.com/moon-hex/gitops-validator/internal/pathutil"
 	"gopkg.in/yaml.v3"
 )
 
 // KustomizationFile represents a parsed kustomization file
 type KustomizationFile struct {
-	Path    string
-	Content map[string]interface{}
-	BaseDir string
+	Path     string
+	Content  map[string]interface{}
+	BaseDir  string
+	Resource *parser.ParsedResource // Parsed resource, for positions; nil when read with ParseKustomizationFile
 }
 
 // KustomizationParser handles parsing of kustomization files
@@ -73,54 +76,48 @@ func (p *KustomizationParser) ParseKustomizationFile(filePath string) (*Kustomiz
 
 // GetResources returns the resources list from a kustomization file
 func (k *KustomizationFile) GetResources() []string {
-	var resources []string
-
-	if resourcesList, ok := k.Content["resources"].([]interface{}); ok {
-		for _, resource := range resourcesList {
-			if resourcePath, ok := resource.(string); ok {
-				resources = append(resources, resourcePath)
-			}
-		}
-	}
-
+	resources, _ := k.entries("resources", "")
 	return resources
 }
 
 // GetPatches returns the patches list from a kustomization file
 func (k *KustomizationFile) GetPatches() []string {
-	var patches []string
-
-	if patchesList, ok := k.Content["patches"].([]interface{}); ok {
-		for _, patch := range patchesList {
-			if patchMap, ok := patch.(map[string]interface{}); ok {
-				if path, ok := patchMap["path"].(string); ok {
-					patches = append(patches, path)
-				}
-			}
-		}
-	}
-
+	patches, _ := k.entries("patches", "path")
 	return patches
 }
 
 // GetStrategicMergePatches returns the patchesStrategicMerge list from a kustomization file
 func (k *KustomizationFile) GetStrategicMergePatches() []string {
-	var patches []string
+	patches, _ := k.entries("patchesStrategicMerge", "")
+	return patches
+}
 
-	if patchesList, ok := k.Content["patchesStrategicMerge"].([]interface{}); ok {
-		for _, patch := range patchesList {
-			if patchPath, ok := patch.(string); ok {
-				patches = append(patches, patchPath)
-			}
-		}
+// GetJSON6902Patches returns the path of every file-based entry in the
+// patchesJson6902 list from a kustomization file
+func (k *KustomizationFile) GetJSON6902Patches() []string {
+	patches, _ := k.entries("patchesJson6902", "path")
+	return patches
+}
+
+// entries returns the string entries of a list field, or the values of key
+// in its mapping entries, with their positions; they are 0 without a Resource
+func (k *KustomizationFile) entries(field, key string) ([]string, []parser.Position) {
+	resource := k.Resource
+	if resource == nil {
+		resource = &parser.ParsedResource{Content: k.Content}
 	}
+	return resource.ListEntries(field, key)
+}
 
-	return patches
+// GetGeneratorFiles returns the files read by the configMapGenerator and
+// secretGenerator entries of a kustomization file
+func (k *KustomizationFile) GetGeneratorFiles() []parser.GeneratorFile {
+	return parser.KustomizationGeneratorFiles(k.Content)
 }
 
 // ValidateFileExists checks if a file exists relative to the kustomization base directory
 func (k *KustomizationFile) ValidateFileExists(filePath string) error {
-	fullPath, shouldProcess := ResolvePath(k.BaseDir, filePath)
+	fullPath, shouldProcess := pathutil.Resolve(k.BaseDir, filePath)
 	if shouldProcess {
 		if _, err := os.Stat(fullPath); os.IsNotExist(err) {
 			return fmt.Errorf("file '%s' does not exist", filePath)
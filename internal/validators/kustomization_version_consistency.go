@@ -7,6 +7,7 @@ import (
 
 	"github.com/moon-hex/gitops-validator/internal/context"
 	"github.com/moon-hex/gitops-validator/internal/parser"
+	"github.com/moon-hex/gitops-validator/internal/pathutil"
 	"github.com/moon-hex/gitops-validator/internal/types"
 )
 
@@ -46,7 +47,7 @@ func (v *KustomizationVersionConsistencyValidator) Validate(ctx *context.Validat
 		resources := v.extractResources(kustomization)
 		for _, resourcePath := range resources {
 			// Resolve the full path
-			fullPath, shouldProcess := ResolvePath(baseDir, resourcePath)
+			fullPath, shouldProcess := pathutil.Resolve(baseDir, resourcePath)
 			if !shouldProcess {
 				continue // Skip remote resources
 			}
@@ -62,7 +63,7 @@ func (v *KustomizationVersionConsistencyValidator) Validate(ctx *context.Validat
 				if !v.areVersionsCompatible(kustomization.APIVersion, referencedKust.APIVersion) {
 					results = append(results, types.ValidationResult{
 						Type:     "kustomization-version-consistency",
-						Severity: "error",
+						Severity: types.SeverityError,
 						Message: fmt.Sprintf(
 							"Kustomization apiVersion mismatch: '%s' references '%s' (version: %s) but uses version %s",
 							kustomization.File,
@@ -0,0 +1,26 @@
+package validators
+
+import (
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/types"
+	"github.com/moon-hex/gitops-validator/internal/validators/checks"
+	"github.com/moon-hex/gitops-validator/internal/validators/common"
+)
+
+// KustomizeBuildValidator builds the kustomizations Flux applies with kustomize in
+// render mode (--render) and reports the errors the build fails with.
+type KustomizeBuildValidator struct {
+	*common.BaseValidator
+}
+
+func NewKustomizeBuildValidator(repoPath string) *KustomizeBuildValidator {
+	return &KustomizeBuildValidator{
+		BaseValidator: common.NewBaseValidator("Kustomize Build Validator", repoPath),
+	}
+}
+
+// Validate implements the GraphValidator interface
+func (v *KustomizeBuildValidator) Validate(ctx *context.ValidationContext) ([]types.ValidationResult, error) {
+	results := checks.KustomizeBuildCheck(ctx)
+	return results, nil
+}
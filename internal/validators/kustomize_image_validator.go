@@ -0,0 +1,25 @@
+package validators
+
+import (
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/types"
+	"github.com/moon-hex/gitops-validator/internal/validators/checks"
+	"github.com/moon-hex/gitops-validator/internal/validators/common"
+)
+
+// KustomizeImageValidator checks the images overrides of kustomization files.
+type KustomizeImageValidator struct {
+	*common.BaseValidator
+}
+
+func NewKustomizeImageValidator(repoPath string) *KustomizeImageValidator {
+	return &KustomizeImageValidator{
+		BaseValidator: common.NewBaseValidator("Kustomize Image Validator", repoPath),
+	}
+}
+
+// Validate implements the GraphValidator interface
+func (v *KustomizeImageValidator) Validate(ctx *context.ValidationContext) ([]types.ValidationResult, error) {
+	results := checks.KustomizeImageCheck(ctx)
+	return results, nil
+}
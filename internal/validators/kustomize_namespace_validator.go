@@ -0,0 +1,26 @@
+package validators
+
+import (
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/types"
+	"github.com/moon-hex/gitops-validator/internal/validators/checks"
+	"github.com/moon-hex/gitops-validator/internal/validators/common"
+)
+
+// KustomizeNamespaceValidator checks the namespace field of kustomization
+// files against cluster-scoped kinds and nested overlays.
+type KustomizeNamespaceValidator struct {
+	*common.BaseValidator
+}
+
+func NewKustomizeNamespaceValidator(repoPath string) *KustomizeNamespaceValidator {
+	return &KustomizeNamespaceValidator{
+		BaseValidator: common.NewBaseValidator("Kustomize Namespace Validator", repoPath),
+	}
+}
+
+// Validate implements the GraphValidator interface
+func (v *KustomizeNamespaceValidator) Validate(ctx *context.ValidationContext) ([]types.ValidationResult, error) {
+	results := checks.KustomizeNamespaceCheck(ctx)
+	return results, nil
+}
@@ -0,0 +1,26 @@
+package validators
+
+import (
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/types"
+	"github.com/moon-hex/gitops-validator/internal/validators/checks"
+	"github.com/moon-hex/gitops-validator/internal/validators/common"
+)
+
+// KustomizeRemoteResourceValidator checks the remote resources, bases and components of
+// kustomization files: URL syntax and ref pinning, and in online mode that they exist.
+type KustomizeRemoteResourceValidator struct {
+	*common.BaseValidator
+}
+
+func NewKustomizeRemoteResourceValidator(repoPath string) *KustomizeRemoteResourceValidator {
+	return &KustomizeRemoteResourceValidator{
+		BaseValidator: common.NewBaseValidator("Kustomize Remote Resource Validator", repoPath),
+	}
+}
+
+// Validate implements the GraphValidator interface
+func (v *KustomizeRemoteResourceValidator) Validate(ctx *context.ValidationContext) ([]types.ValidationResult, error) {
+	results := checks.KustomizeRemoteResourceCheck(ctx)
+	return results, nil
+}
@@ -0,0 +1,26 @@
+package validators
+
+import (
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/types"
+	"github.com/moon-hex/gitops-validator/internal/validators/checks"
+	"github.com/moon-hex/gitops-validator/internal/validators/common"
+)
+
+// KustomizeReplacementValidator checks the replacements of kustomization files against
+// the resources they build.
+type KustomizeReplacementValidator struct {
+	*common.BaseValidator
+}
+
+func NewKustomizeReplacementValidator(repoPath string) *KustomizeReplacementValidator {
+	return &KustomizeReplacementValidator{
+		BaseValidator: common.NewBaseValidator("Kustomize Replacement Validator", repoPath),
+	}
+}
+
+// Validate implements the GraphValidator interface
+func (v *KustomizeReplacementValidator) Validate(ctx *context.ValidationContext) ([]types.ValidationResult, error) {
+	results := checks.KustomizeReplacementCheck(ctx)
+	return results, nil
+}
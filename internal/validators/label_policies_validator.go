@@ -0,0 +1,26 @@
+package validators
+
+import (
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/types"
+	"github.com/moon-hex/gitops-validator/internal/validators/checks"
+	"github.com/moon-hex/gitops-validator/internal/validators/common"
+)
+
+// LabelPolicyValidator requires the labels and annotations of the configured policies on
+// the resources they select.
+type LabelPolicyValidator struct {
+	*common.BaseValidator
+}
+
+func NewLabelPolicyValidator(repoPath string) *LabelPolicyValidator {
+	return &LabelPolicyValidator{
+		BaseValidator: common.NewBaseValidator("Label Policy Validator", repoPath),
+	}
+}
+
+// Validate implements the GraphValidator interface
+func (v *LabelPolicyValidator) Validate(ctx *context.ValidationContext) ([]types.ValidationResult, error) {
+	results := checks.LabelPolicyCheck(ctx)
+	return results, nil
+}
@@ -0,0 +1,26 @@
+package validators
+
+import (
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/types"
+	"github.com/moon-hex/gitops-validator/internal/validators/checks"
+	"github.com/moon-hex/gitops-validator/internal/validators/common"
+)
+
+// MissingNamespaceValidator detects namespaces resources are applied to that no
+// Namespace manifest in the repository creates.
+type MissingNamespaceValidator struct {
+	*common.BaseValidator
+}
+
+func NewMissingNamespaceValidator(repoPath string) *MissingNamespaceValidator {
+	return &MissingNamespaceValidator{
+		BaseValidator: common.NewBaseValidator("Missing Namespace Validator", repoPath),
+	}
+}
+
+// Validate implements the GraphValidator interface
+func (v *MissingNamespaceValidator) Validate(ctx *context.ValidationContext) ([]types.ValidationResult, error) {
+	results := checks.MissingNamespaceCheck(ctx)
+	return results, nil
+}
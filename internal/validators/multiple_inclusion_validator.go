@@ -0,0 +1,26 @@
+package validators
+
+import (
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/types"
+	"github.com/moon-hex/gitops-validator/internal/validators/checks"
+	"github.com/moon-hex/gitops-validator/internal/validators/common"
+)
+
+// MultipleInclusionValidator checks for files a Flux Kustomization reaches
+// through more than one kustomization.
+type MultipleInclusionValidator struct {
+	*common.BaseValidator
+}
+
+func NewMultipleInclusionValidator(repoPath string) *MultipleInclusionValidator {
+	return &MultipleInclusionValidator{
+		BaseValidator: common.NewBaseValidator("Multiple Inclusion Validator", repoPath),
+	}
+}
+
+// Validate implements the GraphValidator interface
+func (v *MultipleInclusionValidator) Validate(ctx *context.ValidationContext) ([]types.ValidationResult, error) {
+	results := checks.MultipleInclusionCheck(ctx)
+	return results, nil
+}
@@ -0,0 +1,26 @@
+package validators
+
+import (
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/types"
+	"github.com/moon-hex/gitops-validator/internal/validators/checks"
+	"github.com/moon-hex/gitops-validator/internal/validators/common"
+)
+
+// NamespaceCollisionValidator detects Namespaces applied by more than one
+// tenant or Flux Kustomization in the same cluster.
+type NamespaceCollisionValidator struct {
+	*common.BaseValidator
+}
+
+func NewNamespaceCollisionValidator(repoPath string) *NamespaceCollisionValidator {
+	return &NamespaceCollisionValidator{
+		BaseValidator: common.NewBaseValidator("Namespace Collision Validator", repoPath),
+	}
+}
+
+// Validate implements the GraphValidator interface
+func (v *NamespaceCollisionValidator) Validate(ctx *context.ValidationContext) ([]types.ValidationResult, error) {
+	results := checks.NamespaceCollisionCheck(ctx)
+	return results, nil
+}
@@ -0,0 +1,26 @@
+package validators
+
+import (
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/types"
+	"github.com/moon-hex/gitops-validator/internal/validators/checks"
+	"github.com/moon-hex/gitops-validator/internal/validators/common"
+)
+
+// NetworkPolicyValidator reports NetworkPolicies selecting no workload of the repository
+// and, optionally, namespaces no NetworkPolicy covers.
+type NetworkPolicyValidator struct {
+	*common.BaseValidator
+}
+
+func NewNetworkPolicyValidator(repoPath string) *NetworkPolicyValidator {
+	return &NetworkPolicyValidator{
+		BaseValidator: common.NewBaseValidator("NetworkPolicy Validator", repoPath),
+	}
+}
+
+// Validate implements the GraphValidator interface
+func (v *NetworkPolicyValidator) Validate(ctx *context.ValidationContext) ([]types.ValidationResult, error) {
+	results := checks.NetworkPolicyCheck(ctx)
+	return results, nil
+}
@@ -0,0 +1,26 @@
+package validators
+
+import (
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/types"
+	"github.com/moon-hex/gitops-validator/internal/validators/checks"
+	"github.com/moon-hex/gitops-validator/internal/validators/common"
+)
+
+// NotificationRefValidator checks the references of notification-controller
+// Alerts and Receivers.
+type NotificationRefValidator struct {
+	*common.BaseValidator
+}
+
+func NewNotificationRefValidator(repoPath string) *NotificationRefValidator {
+	return &NotificationRefValidator{
+		BaseValidator: common.NewBaseValidator("Notification Ref Validator", repoPath),
+	}
+}
+
+// Validate implements the GraphValidator interface
+func (v *NotificationRefValidator) Validate(ctx *context.ValidationContext) ([]types.ValidationResult, error) {
+	results := checks.NotificationRefCheck(ctx)
+	return results, nil
+}
@@ -18,18 +18,26 @@ type ValidationPipeline struct {
 
 // PipelineStage represents a stage in the validation pipeline
 type PipelineStage struct {
-	Name        string
-	Description string
-	Validators  []string // Validator names to run in this stage
-	Parallel    bool     // Whether to run validators in this stage in parallel
-	Required    bool     // Whether this stage must succeed for the pipeline to continue
-	Condition   string   // Optional condition for running this stage
+	Name         string
+	Description  string
+	Validators   []string // Validator names to run in this stage
+	Parallel     bool     // Whether to run validators in this stage in parallel
+	Required     bool     // Whether this stage must succeed for the pipeline to continue
+	Condition    string   // Optional condition for running this stage
+	SkipOnErrors bool     // Whether to skip this stage once an earlier required stage produced errors
+	RunAlways    bool     // Whether to run this stage even after a required stage failed or produced errors
 }
 
 // PipelineExecutor executes validation pipelines
 type PipelineExecutor struct {
 	validators map[string]GraphValidator
 	verbose    bool
+	// OnResults, when set, is called with each batch of results as soon as it is produced
+	OnResults func(results []types.ValidationResult)
+	// HasErrors, when set, decides whether a stage's results block the stages
+	// marked SkipOnErrors, e.g. after severity overrides and suppressions;
+	// by default any result with severity error does
+	HasErrors func(results []types.ValidationResult) bool
 }
 
 // NewPipelineExecutor creates a new pipeline executor
@@ -51,7 +59,24 @@ func (pe *PipelineExecutor) ExecutePipeline(pipeline *ValidationPipeline, ctx *c
 		}
 	}
 
+	// The first required stage that failed, and the first that produced
+	// errors; later stages run only if they are marked RunAlways, or, for
+	// blocking errors, are not marked SkipOnErrors
+	var failed error
+	blockingStage := ""
+
 	for stageIndex, stage := range pipeline.Stages {
+		if !stage.RunAlways && (failed != nil || (stage.SkipOnErrors && blockingStage != "")) {
+			if pe.verbose {
+				if failed != nil {
+					fmt.Printf("Skipping stage '%s' after a required stage failed\n", stage.Name)
+				} else {
+					fmt.Printf("Skipping stage '%s' due to errors from required stage '%s'\n", stage.Name, blockingStage)
+				}
+			}
+			continue
+		}
+
 		if pe.verbose {
 			fmt.Printf("Executing stage %d: %s\n", stageIndex+1, stage.Name)
 		}
@@ -59,15 +84,20 @@ func (pe *PipelineExecutor) ExecutePipeline(pipeline *ValidationPipeline, ctx *c
 		stageResults, err := pe.executeStage(&stage, ctx)
 		if err != nil {
 			if stage.Required {
-				return allResults, fmt.Errorf("required stage '%s' failed: %w", stage.Name, err)
+				if failed == nil {
+					failed = fmt.Errorf("required stage '%s' failed: %w", stage.Name, err)
+				}
+				continue
 			}
 
 			// Add stage failure as a validation result
-			allResults = append(allResults, types.ValidationResult{
+			stageError := types.ValidationResult{
 				Type:     "pipeline-stage-error",
-				Severity: "error",
+				Severity: types.SeverityError,
 				Message:  fmt.Sprintf("Stage '%s' failed: %s", stage.Name, err.Error()),
-			})
+			}
+			pe.report([]types.ValidationResult{stageError})
+			allResults = append(allResults, stageError)
 
 			if pe.verbose {
 				fmt.Printf("Stage '%s' failed (non-required): %v\n", stage.Name, err)
@@ -78,10 +108,26 @@ func (pe *PipelineExecutor) ExecutePipeline(pipeline *ValidationPipeline, ctx *c
 			if pe.verbose {
 				fmt.Printf("Stage '%s' completed with %d results\n", stage.Name, len(stageResults))
 			}
+			if stage.Required && blockingStage == "" && pe.hasErrors(stageResults) {
+				blockingStage = stage.Name
+			}
 		}
 	}
 
-	return allResults, nil
+	return allResults, failed
+}
+
+// hasErrors reports whether results block the stages marked SkipOnErrors
+func (pe *PipelineExecutor) hasErrors(results []types.ValidationResult) bool {
+	if pe.HasErrors != nil {
+		return pe.HasErrors(results)
+	}
+	for _, result := range results {
+		if result.Severity == types.SeverityError {
+			return true
+		}
+	}
+	return false
 }
 
 // executeStage executes a single pipeline stage
@@ -132,27 +178,62 @@ func (pe *PipelineExecutor) executeValidatorsSequential(validators []GraphValida
 		}
 
 		validatorResults, err := validator.Validate(ctx)
-		if err != nil {
-			results = append(results, types.ValidationResult{
-				Type:     "validator-error",
-				Severity: "error",
-				Message:  fmt.Sprintf("Validator %s failed: %s", validator.Name(), err.Error()),
-			})
-			continue
-		}
-
-		results = append(results, validatorResults...)
+		results = append(results, pe.collect(validator, validatorResults, err)...)
 	}
 
 	return results
 }
 
-// executeValidatorsParallel runs validators in parallel
+// collect reports the results of a validator, or a result for its error,
+// and returns them
+func (pe *PipelineExecutor) collect(validator GraphValidator, results []types.ValidationResult, err error) []types.ValidationResult {
+	if err != nil {
+		results = []types.ValidationResult{{
+			Type:     "validator-error",
+			Severity: types.SeverityError,
+			Message:  fmt.Sprintf("Validator %s failed: %s", validator.Name(), err.Error()),
+		}}
+	}
+	pe.report(results)
+	return results
+}
+
+// report forwards a batch of results to the OnResults callback, if any
+func (pe *PipelineExecutor) report(results []types.ValidationResult) {
+	if pe.OnResults != nil && len(results) > 0 {
+		pe.OnResults(results)
+	}
+}
+
+// executeValidatorsParallel runs validators concurrently. Results are
+// reported and returned in the order of validators, as sequential execution
+// would, each batch as soon as the validators before it finished.
 func (pe *PipelineExecutor) executeValidatorsParallel(validators []GraphValidator, ctx *context.ValidationContext) []types.ValidationResult {
-	// This would use the same parallel execution logic as the main validator
-	// For now, we'll use sequential execution
-	// In a full implementation, this would use goroutines and channels
-	return pe.executeValidatorsSequential(validators, ctx)
+	type outcome struct {
+		results []types.ValidationResult
+		err     error
+	}
+	outcomes := make([]chan outcome, len(validators))
+
+	for i, validator := range validators {
+		if pe.verbose {
+			fmt.Printf("  Running validator: %s\n", validator.Name())
+		}
+
+		outcomes[i] = make(chan outcome, 1)
+		go func(validator GraphValidator, done chan<- outcome) {
+			results, err := validator.Validate(ctx)
+			done <- outcome{results: results, err: err}
+		}(validator, outcomes[i])
+	}
+
+	var results []types.ValidationResult
+	for i, validator := range validators {
+		done := <-outcomes[i]
+		results = append(results, pe.collect(validator, done.results, done.err)...)
+	}
+
+	return results
 }
 
 // evaluateCondition evaluates a condition string
@@ -205,24 +286,25 @@ func GetDefaultPipeline() *ValidationPipeline {
 			{
 				Name:        "basic-validation",
 				Description: "Basic resource validation",
-				Validators:  []string{"flux-kustomization", "kubernetes-kustomization", "deprecated-api"},
+				Validators:  []string{"flux-kustomization", "kubernetes-kustomization", "deprecated-api", "symlink", "yaml-anchor"},
 				Parallel:    true,
 				Required:    true,
 			},
 			{
 				Name:        "advanced-validation",
 				Description: "Advanced validation and consistency checks",
-				Validators:  []string{"kustomization-version-consistency", "flux-postbuild-variables"},
+				Validators:  []string{"kustomization-version-consistency", "flux-postbuild-variables", "helm-release-collision", "namespace-collision", "target-namespace", "flux-interval", "flux-prune-wait", "kustomize-namespace", "helm-release-values-from", "sops-config", "helm-chart-version", "env-field-ref", "multiple-inclusion", "image-automation-ref", "image-policy-marker", "notification-ref", "cross-namespace-ref", "reference-graph", "sops-decryption", "flux-api-version", "flux-service-account", "image-automation-write-back", "rendered-name-collision", "kustomize-image", "kustomize-replacement", "kustomize-remote-resource", "kustomize-build", "missing-namespace", "secret-ref", "configmap-ref", "workload-service-account", "storage-class", "route-backend", "service-selector", "network-policy", "custom-resource", "kubeconform", "container-resources", "image-tags", "label-policies", "contrib", "custom-assertion"},
 				Parallel:    true,
 				Required:    false,
 			},
 			{
-				Name:        "cleanup-validation",
-				Description: "Cleanup and orphaned resource detection",
-				Validators:  []string{"orphaned-resource"},
-				Parallel:    false,
-				Required:    false,
-				Condition:   "resource_count > 10", // Only run for larger repositories
+				Name:         "cleanup-validation",
+				Description:  "Cleanup and orphaned resource detection",
+				Validators:   []string{"orphaned-resource"},
+				Parallel:     false,
+				Required:     false,
+				Condition:    "resource_count > 10", // Only run for larger repositories
+				SkipOnErrors: true,
 			},
 		},
 		Parallel: true,
@@ -238,7 +320,7 @@ func GetFastPipeline() *ValidationPipeline {
 			{
 				Name:        "critical-validation",
 				Description: "Critical validations only",
-				Validators:  []string{"flux-kustomization", "kubernetes-kustomization"},
+				Validators:  []string{"flux-kustomization", "kubernetes-kustomization", "yaml-anchor"},
 				Parallel:    true,
 				Required:    true,
 			},
@@ -256,23 +338,24 @@ func GetComprehensivePipeline() *ValidationPipeline {
 			{
 				Name:        "syntax-validation",
 				Description: "Syntax and basic structure validation",
-				Validators:  []string{"flux-kustomization", "kubernetes-kustomization", "deprecated-api"},
+				Validators:  []string{"flux-kustomization", "kubernetes-kustomization", "deprecated-api", "symlink", "yaml-anchor"},
 				Parallel:    true,
 				Required:    true,
 			},
 			{
 				Name:        "consistency-validation",
 				Description: "Consistency and version validation",
-				Validators:  []string{"kustomization-version-consistency", "flux-postbuild-variables"},
+				Validators:  []string{"kustomization-version-consistency", "flux-postbuild-variables", "helm-release-collision", "namespace-collision", "target-namespace", "flux-interval", "flux-prune-wait", "kustomize-namespace", "helm-release-values-from", "sops-config", "helm-chart-version", "env-field-ref", "multiple-inclusion", "image-automation-ref", "image-policy-marker", "notification-ref", "cross-namespace-ref", "reference-graph", "sops-decryption", "flux-api-version", "flux-service-account", "image-automation-write-back", "rendered-name-collision", "kustomize-image", "kustomize-replacement", "kustomize-remote-resource", "kustomize-build", "missing-namespace", "secret-ref", "configmap-ref", "workload-service-account", "storage-class", "route-backend", "service-selector", "network-policy", "custom-resource", "kubeconform", "container-resources", "image-tags", "label-policies", "contrib", "custom-assertion"},
 				Parallel:    true,
 				Required:    true,
 			},
 			{
-				Name:        "cleanup-validation",
-				Description: "Cleanup and optimization validation",
-				Validators:  []string{"orphaned-resource"},
-				Parallel:    false,
-				Required:    false,
+				Name:         "cleanup-validation",
+				Description:  "Cleanup and optimization validation",
+				Validators:   []string{"orphaned-resource"},
+				Parallel:     false,
+				Required:     false,
+				SkipOnErrors: true,
 			},
 		},
 		Parallel: true,
@@ -0,0 +1,26 @@
+package validators
+
+import (
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/types"
+	"github.com/moon-hex/gitops-validator/internal/validators/checks"
+	"github.com/moon-hex/gitops-validator/internal/validators/common"
+)
+
+// ReferenceGraphValidator reports kustomization cycles and reference chains
+// deeper than the configured maximum.
+type ReferenceGraphValidator struct {
+	*common.BaseValidator
+}
+
+func NewReferenceGraphValidator(repoPath string) *ReferenceGraphValidator {
+	return &ReferenceGraphValidator{
+		BaseValidator: common.NewBaseValidator("Reference Graph Validator", repoPath),
+	}
+}
+
+// Validate implements the GraphValidator interface
+func (v *ReferenceGraphValidator) Validate(ctx *context.ValidationContext) ([]types.ValidationResult, error) {
+	results := checks.ReferenceGraphCheck(ctx)
+	return results, nil
+}
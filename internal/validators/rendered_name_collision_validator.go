@@ -0,0 +1,26 @@
+package validators
+
+import (
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/types"
+	"github.com/moon-hex/gitops-validator/internal/validators/checks"
+	"github.com/moon-hex/gitops-validator/internal/validators/common"
+)
+
+// RenderedNameCollisionValidator checks that no two objects of a kind render to the same
+// name in the same namespace.
+type RenderedNameCollisionValidator struct {
+	*common.BaseValidator
+}
+
+func NewRenderedNameCollisionValidator(repoPath string) *RenderedNameCollisionValidator {
+	return &RenderedNameCollisionValidator{
+		BaseValidator: common.NewBaseValidator("Rendered Name Collision Validator", repoPath),
+	}
+}
+
+// Validate implements the GraphValidator interface
+func (v *RenderedNameCollisionValidator) Validate(ctx *context.ValidationContext) ([]types.ValidationResult, error) {
+	results := checks.RenderedNameCollisionCheck(ctx)
+	return results, nil
+}
@@ -0,0 +1,26 @@
+package validators
+
+import (
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/types"
+	"github.com/moon-hex/gitops-validator/internal/validators/checks"
+	"github.com/moon-hex/gitops-validator/internal/validators/common"
+)
+
+// RouteBackendValidator detects Ingresses and Gateway API routes sending traffic to
+// Services or ports the repository does not create.
+type RouteBackendValidator struct {
+	*common.BaseValidator
+}
+
+func NewRouteBackendValidator(repoPath string) *RouteBackendValidator {
+	return &RouteBackendValidator{
+		BaseValidator: common.NewBaseValidator("Route Backend Validator", repoPath),
+	}
+}
+
+// Validate implements the GraphValidator interface
+func (v *RouteBackendValidator) Validate(ctx *context.ValidationContext) ([]types.ValidationResult, error) {
+	results := checks.RouteBackendCheck(ctx)
+	return results, nil
+}
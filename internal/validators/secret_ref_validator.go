@@ -0,0 +1,26 @@
+package validators
+
+import (
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/types"
+	"github.com/moon-hex/gitops-validator/internal/validators/checks"
+	"github.com/moon-hex/gitops-validator/internal/validators/common"
+)
+
+// SecretRefValidator detects workloads referencing Secrets the repository does
+// not create.
+type SecretRefValidator struct {
+	*common.BaseValidator
+}
+
+func NewSecretRefValidator(repoPath string) *SecretRefValidator {
+	return &SecretRefValidator{
+		BaseValidator: common.NewBaseValidator("Secret Ref Validator", repoPath),
+	}
+}
+
+// Validate implements the GraphValidator interface
+func (v *SecretRefValidator) Validate(ctx *context.ValidationContext) ([]types.ValidationResult, error) {
+	results := checks.SecretRefCheck(ctx)
+	return results, nil
+}
@@ -0,0 +1,26 @@
+package validators
+
+import (
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/types"
+	"github.com/moon-hex/gitops-validator/internal/validators/checks"
+	"github.com/moon-hex/gitops-validator/internal/validators/common"
+)
+
+// ServiceSelectorValidator detects Services whose selector matches no workload of the
+// repository, or the pods of several workloads.
+type ServiceSelectorValidator struct {
+	*common.BaseValidator
+}
+
+func NewServiceSelectorValidator(repoPath string) *ServiceSelectorValidator {
+	return &ServiceSelectorValidator{
+		BaseValidator: common.NewBaseValidator("Service Selector Validator", repoPath),
+	}
+}
+
+// Validate implements the GraphValidator interface
+func (v *ServiceSelectorValidator) Validate(ctx *context.ValidationContext) ([]types.ValidationResult, error) {
+	results := checks.ServiceSelectorCheck(ctx)
+	return results, nil
+}
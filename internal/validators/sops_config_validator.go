@@ -0,0 +1,26 @@
+package validators
+
+import (
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/types"
+	"github.com/moon-hex/gitops-validator/internal/validators/checks"
+	"github.com/moon-hex/gitops-validator/internal/validators/common"
+)
+
+// SOPSConfigValidator checks .sops.yaml creation rules and the keys of
+// sops-encrypted files against them.
+type SOPSConfigValidator struct {
+	*common.BaseValidator
+}
+
+func NewSOPSConfigValidator(repoPath string) *SOPSConfigValidator {
+	return &SOPSConfigValidator{
+		BaseValidator: common.NewBaseValidator("SOPS Config Validator", repoPath),
+	}
+}
+
+// Validate implements the GraphValidator interface
+func (v *SOPSConfigValidator) Validate(ctx *context.ValidationContext) ([]types.ValidationResult, error) {
+	results := checks.SOPSConfigCheck(ctx)
+	return results, nil
+}
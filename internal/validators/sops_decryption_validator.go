@@ -0,0 +1,26 @@
+package validators
+
+import (
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/types"
+	"github.com/moon-hex/gitops-validator/internal/validators/checks"
+	"github.com/moon-hex/gitops-validator/internal/validators/common"
+)
+
+// SOPSDecryptionValidator checks the decryption settings of Flux
+// Kustomizations applying sops-encrypted files.
+type SOPSDecryptionValidator struct {
+	*common.BaseValidator
+}
+
+func NewSOPSDecryptionValidator(repoPath string) *SOPSDecryptionValidator {
+	return &SOPSDecryptionValidator{
+		BaseValidator: common.NewBaseValidator("SOPS Decryption Validator", repoPath),
+	}
+}
+
+// Validate implements the GraphValidator interface
+func (v *SOPSDecryptionValidator) Validate(ctx *context.ValidationContext) ([]types.ValidationResult, error) {
+	results := checks.SOPSDecryptionCheck(ctx)
+	return results, nil
+}
@@ -0,0 +1,26 @@
+package validators
+
+import (
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/types"
+	"github.com/moon-hex/gitops-validator/internal/validators/checks"
+	"github.com/moon-hex/gitops-validator/internal/validators/common"
+)
+
+// StorageClassValidator detects PersistentVolumeClaims using StorageClasses that
+// are neither in the repository nor provided by the cluster.
+type StorageClassValidator struct {
+	*common.BaseValidator
+}
+
+func NewStorageClassValidator(repoPath string) *StorageClassValidator {
+	return &StorageClassValidator{
+		BaseValidator: common.NewBaseValidator("Storage Class Validator", repoPath),
+	}
+}
+
+// Validate implements the GraphValidator interface
+func (v *StorageClassValidator) Validate(ctx *context.ValidationContext) ([]types.ValidationResult, error) {
+	results := checks.StorageClassCheck(ctx)
+	return results, nil
+}
@@ -0,0 +1,26 @@
+package validators
+
+import (
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/types"
+	"github.com/moon-hex/gitops-validator/internal/validators/checks"
+	"github.com/moon-hex/gitops-validator/internal/validators/common"
+)
+
+// SymlinkValidator checks that symlinks stay inside the repository and that
+// symlinked overlay trees resolve their references consistently.
+type SymlinkValidator struct {
+	*common.BaseValidator
+}
+
+func NewSymlinkValidator(repoPath string) *SymlinkValidator {
+	return &SymlinkValidator{
+		BaseValidator: common.NewBaseValidator("Symlink Validator", repoPath),
+	}
+}
+
+// Validate implements the GraphValidator interface
+func (v *SymlinkValidator) Validate(ctx *context.ValidationContext) ([]types.ValidationResult, error) {
+	results := checks.SymlinkCheck(ctx)
+	return results, nil
+}
@@ -0,0 +1,26 @@
+package validators
+
+import (
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/types"
+	"github.com/moon-hex/gitops-validator/internal/validators/checks"
+	"github.com/moon-hex/gitops-validator/internal/validators/common"
+)
+
+// TargetNamespaceValidator detects Flux Kustomizations and HelmReleases
+// deploying into namespaces that the repository never creates.
+type TargetNamespaceValidator struct {
+	*common.BaseValidator
+}
+
+func NewTargetNamespaceValidator(repoPath string) *TargetNamespaceValidator {
+	return &TargetNamespaceValidator{
+		BaseValidator: common.NewBaseValidator("Target Namespace Validator", repoPath),
+	}
+}
+
+// Validate implements the GraphValidator interface
+func (v *TargetNamespaceValidator) Validate(ctx *context.ValidationContext) ([]types.ValidationResult, error) {
+	results := checks.TargetNamespaceCheck(ctx)
+	return results, nil
+}
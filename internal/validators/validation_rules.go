@@ -2,8 +2,14 @@ package validators
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 
+	"github.com/moon-hex/gitops-validator/internal/parser"
+	"github.com/moon-hex/gitops-validator/internal/pathutil"
 	"github.com/moon-hex/gitops-validator/internal/types"
+	"github.com/moon-hex/gitops-validator/internal/validators/common"
 )
 
 // ValidationRule represents a single validation rule
@@ -23,14 +29,17 @@ func (r *ResourceReferenceRule) Validate(kustomization *KustomizationFile) []typ
 	var results []types.ValidationResult
 	seenResources := make(map[string]bool)
 
-	for _, resourcePath := range kustomization.GetResources() {
+	resources, positions := kustomization.entries("resources", "")
+	for i, resourcePath := range resources {
 		// Check for duplicate resource references
 		if seenResources[resourcePath] {
 			results = append(results, types.ValidationResult{
 				Type:     "kubernetes-kustomization",
-				Severity: "error",
+				Severity: types.SeverityError,
 				Message:  fmt.Sprintf("duplicate resource reference: '%s'", resourcePath),
 				File:     kustomization.Path,
+				Line:     positions[i].Line,
+				Column:   positions[i].Column,
 			})
 			continue
 		}
@@ -40,9 +49,11 @@ func (r *ResourceReferenceRule) Validate(kustomization *KustomizationFile) []typ
 		if err := kustomization.ValidateFileExists(resourcePath); err != nil {
 			results = append(results, types.ValidationResult{
 				Type:     "kubernetes-kustomization",
-				Severity: "error",
+				Severity: types.SeverityError,
 				Message:  fmt.Sprintf("Invalid resource references: %s", err.Error()),
 				File:     kustomization.Path,
+				Line:     positions[i].Line,
+				Column:   positions[i].Column,
 			})
 		}
 	}
@@ -61,14 +72,17 @@ func (r *PatchReferenceRule) Validate(kustomization *KustomizationFile) []types.
 	var results []types.ValidationResult
 	seenPatches := make(map[string]bool)
 
-	for _, patchPath := range kustomization.GetPatches() {
+	patches, positions := kustomization.entries("patches", "path")
+	for i, patchPath := range patches {
 		// Check for duplicate patch references
 		if seenPatches[patchPath] {
 			results = append(results, types.ValidationResult{
 				Type:     "kubernetes-kustomization",
-				Severity: "error",
+				Severity: types.SeverityError,
 				Message:  fmt.Sprintf("duplicate patch reference: '%s'", patchPath),
 				File:     kustomization.Path,
+				Line:     positions[i].Line,
+				Column:   positions[i].Column,
 			})
 			continue
 		}
@@ -78,9 +92,11 @@ func (r *PatchReferenceRule) Validate(kustomization *KustomizationFile) []types.
 		if err := kustomization.ValidateFileExists(patchPath); err != nil {
 			results = append(results, types.ValidationResult{
 				Type:     "kubernetes-kustomization",
-				Severity: "error",
+				Severity: types.SeverityError,
 				Message:  fmt.Sprintf("Invalid patch references: %s", err.Error()),
 				File:     kustomization.Path,
+				Line:     positions[i].Line,
+				Column:   positions[i].Column,
 			})
 		}
 	}
@@ -98,14 +114,17 @@ func (r *StrategicMergePatchReferenceRule) Name() string {
 func (r *StrategicMergePatchReferenceRule) Validate(kustomization *KustomizationFile) []types.ValidationResult {
 	var results []types.ValidationResult
 
-	for _, patchPath := range kustomization.GetStrategicMergePatches() {
+	patches, positions := kustomization.entries("patchesStrategicMerge", "")
+	for i, patchPath := range patches {
 		// Check if file exists
 		if err := kustomization.ValidateFileExists(patchPath); err != nil {
 			results = append(results, types.ValidationResult{
 				Type:     "kubernetes-kustomization",
-				Severity: "error",
+				Severity: types.SeverityError,
 				Message:  fmt.Sprintf("Invalid patch references: %s", err.Error()),
 				File:     kustomization.Path,
+				Line:     positions[i].Line,
+				Column:   positions[i].Column,
 			})
 		}
 	}
@@ -113,6 +132,133 @@ func (r *StrategicMergePatchReferenceRule) Validate(kustomization *Kustomization
 	return results
 }
 
+// GeneratorFileRule validates that the files configMapGenerator and
+// secretGenerator entries read exist; kustomize build fails on a missing one
+type GeneratorFileRule struct{}
+
+func (r *GeneratorFileRule) Name() string {
+	return "Generator File Rule"
+}
+
+func (r *GeneratorFileRule) Validate(kustomization *KustomizationFile) []types.ValidationResult {
+	var results []types.ValidationResult
+
+	for _, file := range kustomization.GetGeneratorFiles() {
+		if kustomization.ValidateFileExists(file.Path) != nil {
+			results = append(results, types.ValidationResult{
+				Type:     "kustomization-generator",
+				Severity: types.SeverityError,
+				Message:  fmt.Sprintf("%s '%s' reads '%s', which does not exist relative to the kustomization", file.Generator, file.Name, file.Path),
+				File:     kustomization.Path,
+			})
+		}
+	}
+
+	return results
+}
+
+// DirectoryTargetRule validates directories referenced from resources entries.
+// kustomize builds a directory target, so it must hold exactly one
+// kustomization file; a kustomization that leaves sibling manifests out is
+// flagged as ambiguous. Unlike a Flux spec.path, a directory of plain
+// manifests is not accepted: only the Flux controller generates a
+// kustomization for those.
+type DirectoryTargetRule struct{}
+
+func (r *DirectoryTargetRule) Name() string {
+	return "Directory Target Rule"
+}
+
+func (r *DirectoryTargetRule) Validate(kustomization *KustomizationFile) []types.ValidationResult {
+	var results []types.ValidationResult
+
+	resources, positions := kustomization.entries("resources", "")
+	for i, resourcePath := range resources {
+		fullPath, shouldProcess := pathutil.Resolve(kustomization.BaseDir, resourcePath)
+		if !shouldProcess {
+			continue
+		}
+		if info, err := os.Stat(fullPath); err != nil || !info.IsDir() {
+			continue // Missing paths are reported by ResourceReferenceRule
+		}
+
+		target, err := common.InspectDirectoryTarget(fullPath)
+		if err != nil {
+			continue
+		}
+
+		switch {
+		case len(target.KustomizationFiles) > 1:
+			results = append(results, types.ValidationResult{
+				Type:     "kustomization-directory-target",
+				Severity: types.SeverityError,
+				Message:  fmt.Sprintf("directory '%s' contains multiple kustomization files (%s)", resourcePath, strings.Join(target.KustomizationFiles, ", ")),
+				File:     kustomization.Path,
+				Line:     positions[i].Line,
+				Column:   positions[i].Column,
+			})
+		case len(target.KustomizationFiles) == 0 && len(target.Manifests) == 0:
+			results = append(results, types.ValidationResult{
+				Type:     "kustomization-directory-target",
+				Severity: types.SeverityError,
+				Message:  fmt.Sprintf("directory '%s' contains neither a kustomization file nor any YAML manifests", resourcePath),
+				File:     kustomization.Path,
+				Line:     positions[i].Line,
+				Column:   positions[i].Column,
+			})
+		case len(target.KustomizationFiles) == 0:
+			results = append(results, types.ValidationResult{
+				Type:     "kustomization-directory-target",
+				Severity: types.SeverityError,
+				Message: fmt.Sprintf("directory '%s' contains manifests (%s) but no kustomization file; kustomize build fails to load it as a resource (add a kustomization.yaml listing them, or list the files instead of the directory)",
+					resourcePath, strings.Join(target.Manifests, ", ")),
+				File:   kustomization.Path,
+				Line:   positions[i].Line,
+				Column: positions[i].Column,
+			})
+		case len(target.KustomizationFiles) == 1:
+			unlisted := r.unlistedManifests(filepath.Join(fullPath, target.KustomizationFiles[0]), target.Manifests)
+			if len(unlisted) > 0 {
+				results = append(results, types.ValidationResult{
+					Type:     "kustomization-directory-target",
+					Severity: types.SeverityWarning,
+					Message: fmt.Sprintf("directory '%s' mixes a kustomization file with manifests it does not include (%s); only the manifests listed in %s will be built",
+						resourcePath, strings.Join(unlisted, ", "), target.KustomizationFiles[0]),
+					File:   kustomization.Path,
+					Line:   positions[i].Line,
+					Column: positions[i].Column,
+				})
+			}
+		}
+	}
+
+	return results
+}
+
+// unlistedManifests returns the manifests in the kustomization's directory
+// that no field of it reads
+func (r *DirectoryTargetRule) unlistedManifests(kustomizationPath string, manifests []string) []string {
+	nested, err := NewKustomizationParser(filepath.Dir(kustomizationPath)).ParseKustomizationFile(kustomizationPath)
+	if err != nil {
+		return nil
+	}
+
+	referenced := make(map[string]bool)
+	for _, entry := range parser.KustomizationFileEntries(nested.Content) {
+		if fullPath, ok := pathutil.Resolve(nested.BaseDir, entry); ok {
+			referenced[filepath.Clean(fullPath)] = true
+		}
+	}
+
+	var unlisted []string
+	for _, manifest := range manifests {
+		if !referenced[filepath.Clean(filepath.Join(nested.BaseDir, manifest))] {
+			unlisted = append(unlisted, manifest)
+		}
+	}
+	return unlisted
+}
+
 // ValidationRuleSet manages a collection of validation rules
 type ValidationRuleSet struct {
 	rules []ValidationRule
@@ -0,0 +1,26 @@
+package validators
+
+import (
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/types"
+	"github.com/moon-hex/gitops-validator/internal/validators/checks"
+	"github.com/moon-hex/gitops-validator/internal/validators/common"
+)
+
+// WorkloadServiceAccountValidator detects workloads running as ServiceAccounts the
+// repository does not create.
+type WorkloadServiceAccountValidator struct {
+	*common.BaseValidator
+}
+
+func NewWorkloadServiceAccountValidator(repoPath string) *WorkloadServiceAccountValidator {
+	return &WorkloadServiceAccountValidator{
+		BaseValidator: common.NewBaseValidator("Workload Service Account Validator", repoPath),
+	}
+}
+
+// Validate implements the GraphValidator interface
+func (v *WorkloadServiceAccountValidator) Validate(ctx *context.ValidationContext) ([]types.ValidationResult, error) {
+	results := checks.WorkloadServiceAccountCheck(ctx)
+	return results, nil
+}
@@ -0,0 +1,26 @@
+package validators
+
+import (
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/types"
+	"github.com/moon-hex/gitops-validator/internal/validators/checks"
+	"github.com/moon-hex/gitops-validator/internal/validators/common"
+)
+
+// YAMLAnchorValidator reports YAML aliases and merge keys that kustomize
+// cannot resolve.
+type YAMLAnchorValidator struct {
+	*common.BaseValidator
+}
+
+func NewYAMLAnchorValidator(repoPath string) *YAMLAnchorValidator {
+	return &YAMLAnchorValidator{
+		BaseValidator: common.NewBaseValidator("YAML Anchor Validator", repoPath),
+	}
+}
+
+// Validate implements the GraphValidator interface
+func (v *YAMLAnchorValidator) Validate(ctx *context.ValidationContext) ([]types.ValidationResult, error) {
+	results := checks.YAMLAnchorCheck(ctx)
+	return results, nil
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 
+	_ "github.com/moon-hex/gitops-validator/contrib"
 	"github.com/moon-hex/gitops-validator/internal/cli"
 )
 
@@ -0,0 +1,51 @@
+package sdk
+
+// Lookup returns the value at a path of fields in a manifest's content, and
+// whether every field along the path exists
+func Lookup(content map[string]interface{}, path ...string) (interface{}, bool) {
+	var value interface{} = content
+	for _, field := range path {
+		fields, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if value, ok = fields[field]; !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// String returns the string at a path, or "" when it is missing or not a
+// string
+func String(content map[string]interface{}, path ...string) string {
+	value, _ := Lookup(content, path...)
+	text, _ := value.(string)
+	return text
+}
+
+// Strings returns the strings of the list at a path; other items are skipped
+func Strings(content map[string]interface{}, path ...string) []string {
+	var values []string
+	for _, item := range List(content, path...) {
+		if text, ok := item.(string); ok {
+			values = append(values, text)
+		}
+	}
+	return values
+}
+
+// Map returns the mapping at a path, or nil when it is missing or not a
+// mapping
+func Map(content map[string]interface{}, path ...string) map[string]interface{} {
+	value, _ := Lookup(content, path...)
+	fields, _ := value.(map[string]interface{})
+	return fields
+}
+
+// List returns the list at a path, or nil when it is missing or not a list
+func List(content map[string]interface{}, path ...string) []interface{} {
+	value, _ := Lookup(content, path...)
+	items, _ := value.([]interface{})
+	return items
+}
@@ -0,0 +1,30 @@
+package sdk
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/moon-hex/gitops-validator/internal/pathutil"
+)
+
+// ResolvePath resolves a reference found in a resource's file. Relative
+// references, as kustomization resources and patches are, resolve against the
+// directory of the file; others, as Flux spec.path is, against the repository
+// root. Remote references are not resolved (the second return value is false).
+func ResolvePath(ctx *Context, resource *Resource, path string, relative bool) (string, bool) {
+	return pathutil.ResolveReference(path, relative, resource.File, ctx.RepoPath)
+}
+
+// FileExists reports whether a resolved path exists
+func FileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// RelativeFile returns a file relative to the repository root, for messages
+func RelativeFile(ctx *Context, file string) string {
+	if rel, err := filepath.Rel(ctx.RepoPath, file); err == nil {
+		return filepath.ToSlash(rel)
+	}
+	return file
+}
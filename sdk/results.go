@@ -0,0 +1,31 @@
+package sdk
+
+import "fmt"
+
+// NewResult returns a result of a rule at a resource
+func NewResult(rule string, severity Severity, resource *Resource, format string, args ...interface{}) Result {
+	return Result{
+		Type:     rule,
+		Severity: severity,
+		Message:  fmt.Sprintf(format, args...),
+		File:     resource.File,
+		Line:     resource.Line,
+		Column:   resource.Column,
+		Resource: resource.Name,
+	}
+}
+
+// Error returns an error of a rule at a resource
+func Error(rule string, resource *Resource, format string, args ...interface{}) Result {
+	return NewResult(rule, SeverityError, resource, format, args...)
+}
+
+// Warning returns a warning of a rule at a resource
+func Warning(rule string, resource *Resource, format string, args ...interface{}) Result {
+	return NewResult(rule, SeverityWarning, resource, format, args...)
+}
+
+// Info returns an informational result of a rule at a resource
+func Info(rule string, resource *Resource, format string, args ...interface{}) Result {
+	return NewResult(rule, SeverityInfo, resource, format, args...)
+}
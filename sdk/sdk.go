@@ -0,0 +1,143 @@
+// Package sdk is the interface for contributed rules: checks that live in
+// their own package, register themselves with Register when imported, and
+// run when enabled under gitops-validator.rules like the built-in rules.
+// `gitops-validator sdk new-rule <name>` scaffolds one.
+//
+// The helpers cover what most checks need: reading fields from manifests
+// (String, Strings, Map, List), resolving paths the way kustomize and Flux do
+// (ResolvePath, FileExists, RelativeFile) and building results (Error,
+// Warning, Info).
+package sdk
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/moon-hex/gitops-validator/internal/config"
+	"github.com/moon-hex/gitops-validator/internal/context"
+	"github.com/moon-hex/gitops-validator/internal/parser"
+	"github.com/moon-hex/gitops-validator/internal/types"
+)
+
+// Context gives a check the parsed repository and the configuration
+type Context = context.ValidationContext
+
+// Resource is a parsed manifest: one YAML document
+type Resource = parser.ParsedResource
+
+// Result is a finding reported by a check
+type Result = types.ValidationResult
+
+// Severity is the severity of a Result
+type Severity = types.Severity
+
+// Severities of results
+const (
+	SeverityError   = types.SeverityError
+	SeverityWarning = types.SeverityWarning
+	SeverityInfo    = types.SeverityInfo
+)
+
+// ParamSpec describes a parameter a rule accepts under its config entry
+type ParamSpec = config.ParamSpec
+
+// Parameter types of a ParamSpec
+const (
+	ParamString     = config.ParamString
+	ParamStringList = config.ParamStringList
+	ParamInt        = config.ParamInt
+	ParamBool       = config.ParamBool
+	ParamDuration   = config.ParamDuration
+	ParamRegexp     = config.ParamRegexp
+	ParamVersion    = config.ParamVersion
+)
+
+// RuleParams are the parameters configured for a rule, read with typed
+// accessors such as String, Bool and Int
+type RuleParams = config.RuleParams
+
+// Rule is a contributed rule
+type Rule struct {
+	// ID is the stable rule ID, GVC followed by four digits
+	ID string
+	// Name is the config rule name under gitops-validator.rules and the
+	// Type of the results the rule reports
+	Name string
+	// Description is a one-line summary of what the rule detects
+	Description string
+	// Fix is a one-line remediation hint for compact outputs
+	Fix string
+	// Docs is the documentation of the rule, relative to the repository root
+	Docs string
+	// Params are the parameters the rule accepts, read with Params
+	Params []ParamSpec
+	// Check reports the rule's findings
+	Check func(ctx *Context) []Result
+}
+
+var (
+	rulesMu sync.RWMutex
+	rules   = make(map[string]Rule)
+)
+
+// Register adds a rule; contributed rule packages call it from init. It
+// panics on an incomplete rule or a name or ID that is already registered,
+// so mistakes surface when the binary starts.
+func Register(rule Rule) {
+	if rule.Name == "" || rule.Check == nil {
+		panic("sdk: a rule needs a Name and a Check")
+	}
+	if !strings.HasPrefix(rule.ID, "GVC") || len(rule.ID) != len("GVC0000") {
+		panic(fmt.Sprintf("sdk: rule '%s' has ID '%s'; contributed rule IDs are GVC followed by four digits", rule.Name, rule.ID))
+	}
+	for _, known := range types.Rules {
+		if known.Type == rule.Name || known.Rule == rule.Name || known.ID == rule.ID {
+			panic(fmt.Sprintf("sdk: rule '%s' (%s) is already registered", rule.Name, rule.ID))
+		}
+	}
+
+	rulesMu.Lock()
+	defer rulesMu.Unlock()
+	rules[rule.Name] = rule
+
+	info := types.RuleInfo{ID: rule.ID, Type: rule.Name, Rule: rule.Name, Description: rule.Description, Fix: rule.Fix}
+	if rule.Docs != "" {
+		info.DocsURL = strings.TrimSuffix(types.RulesDocsURL, "docs/RULES.md") + rule.Docs
+	}
+	types.Rules = append(types.Rules, info)
+	if len(rule.Params) > 0 {
+		config.RegisterRuleParams(rule.Name, rule.Params...)
+	}
+}
+
+// Rules returns the registered rules, sorted by name
+func Rules() []Rule {
+	rulesMu.RLock()
+	defer rulesMu.RUnlock()
+
+	registered := make([]Rule, 0, len(rules))
+	for _, rule := range rules {
+		registered = append(registered, rule)
+	}
+	sort.Slice(registered, func(i, j int) bool { return registered[i].Name < registered[j].Name })
+	return registered
+}
+
+// Params returns the parameters configured for a rule
+func Params(ctx *Context, rule string) RuleParams {
+	return ctx.Config.RuleParams(rule)
+}
+
+// Resources returns the resources of a kind, in file and line order
+func Resources(ctx *Context, kind string) []*Resource {
+	resources := append([]*Resource(nil), ctx.Graph.GetResourcesByKind(kind)...)
+	sort.Slice(resources, func(i, j int) bool {
+		if resources[i].File != resources[j].File {
+			return resources[i].File < resources[j].File
+		}
+		return resources[i].Line < resources[j].Line
+	})
+	return resources
+}